@@ -4,10 +4,14 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"sort"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,14 +19,24 @@ import (
 	"github.com/shopspring/decimal"
 
 	fraudapp "fraud-detecction-system/internal/application/fraud"
+	txapp "fraud-detecction-system/internal/application/transaction"
 	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/domain/transaction"
 	"fraud-detecction-system/internal/infrastructure/cache/redis"
+	"fraud-detecction-system/internal/infrastructure/database/boltdb"
 	"fraud-detecction-system/internal/infrastructure/database/postgres"
+	"fraud-detecction-system/internal/infrastructure/http/middleware"
 	"fraud-detecction-system/internal/infrastructure/http/router"
+	"fraud-detecction-system/internal/infrastructure/messaging/kafka"
+	"fraud-detecction-system/internal/infrastructure/messaging/webhook"
 	"fraud-detecction-system/internal/infrastructure/ml"
 	"fraud-detecction-system/internal/infrastructure/rules"
 	"fraud-detecction-system/internal/interfaces/http/handler"
 	"fraud-detecction-system/internal/pkg/config"
+	"fraud-detecction-system/internal/pkg/logger"
+	"fraud-detecction-system/internal/pkg/metrics"
+	"fraud-detecction-system/internal/pkg/retry"
+	"fraud-detecction-system/migrations"
 )
 
 const version = "1.0.0"
@@ -33,25 +47,37 @@ func main() {
 	flag.Parse()
 
 	// Load configuration
-	cfg, err := config.Load(*configPath)
-	if err != nil {
-		log.Printf("Warning: Could not load config file, using defaults: %v", err)
+	cfg, cfgErr := config.Load(*configPath)
+	if cfgErr != nil {
 		cfg = config.DefaultConfig()
 	}
 
-	log.Printf("Starting Fraud Detection API v%s", version)
-	log.Printf("Server will listen on %s:%d", cfg.Server.Host, cfg.Server.Port)
+	// Structured logging; anything still written through the log package goes through it too
+	log := logger.New(cfg.Log.Level, cfg.Log.Format)
+	slog.SetDefault(log)
+	if cfgErr != nil {
+		log.Warn("could not load config file, using defaults", logger.Err(cfgErr))
+	}
+	if err := cfg.Validate(); err != nil {
+		fatal(log, "invalid config", logger.Err(err))
+	}
+
+	log.Info("starting fraud detection API", slog.String("version", version))
+	log.Info("server will listen", slog.String("host", cfg.Server.Host), slog.Int("port", cfg.Server.Port))
 
 	// Initialize dependencies
 	ctx := context.Background()
 
 	// Database connection
 	var dbClient *postgres.Client
-	var decisionRepo *postgres.DecisionRepository
-	var caseRepo *postgres.CaseRepository
-	var ruleRepo *postgres.RuleRepository
+	var decisionRepo fraud.DecisionRepository
+	var caseRepo fraud.CaseRepository
+	var ruleRepo fraud.RuleRepository
+	var listRepo fraud.ListRepository
+	var chargebackRepo fraud.ChargebackRepository
+	var txRepo transaction.Repository
 
-	dbClient, err = postgres.NewClient(postgres.Config{
+	dbClient, err := postgres.NewClient(ctx, postgres.Config{
 		Host:            cfg.Database.Host,
 		Port:            cfg.Database.Port,
 		User:            cfg.Database.User,
@@ -61,15 +87,54 @@ func main() {
 		MaxOpenConns:    cfg.Database.MaxOpenConns,
 		MaxIdleConns:    cfg.Database.MaxIdleConns,
 		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		Connect:         connectBackoff(cfg.Database.Connect),
+		Logger:          log,
 	})
 	if err != nil {
-		log.Printf("Warning: Database connection failed (running in limited mode): %v", err)
+		log.Warn("database connection failed, running in limited mode", logger.Err(err))
 		dbClient = nil
 	} else {
-		log.Printf("Connected to PostgreSQL at %s:%d", cfg.Database.Host, cfg.Database.Port)
+		log.Info("connected to PostgreSQL", slog.String("host", cfg.Database.Host), slog.Int("port", cfg.Database.Port))
+		if cfg.Database.AutoMigrate {
+			// Running on a half-migrated schema would fail later and less clearly, so stop here
+			schema, _ := fs.Sub(migrations.Postgres, "postgres")
+			applied, err := dbClient.Migrate(ctx, schema, log)
+			if err != nil {
+				fatal(log, "database migration failed", logger.Err(err))
+			}
+			log.Info("database schema up to date", slog.Int("migrations_applied", applied))
+		}
 		decisionRepo = postgres.NewDecisionRepository(dbClient)
 		caseRepo = postgres.NewCaseRepository(dbClient)
 		ruleRepo = postgres.NewRuleRepository(dbClient)
+		listRepo = postgres.NewListRepository(dbClient)
+		chargebackRepo = postgres.NewChargebackRepository(dbClient)
+		txRepo = postgres.NewTransactionRepository(dbClient)
+	}
+
+	// Without PostgreSQL, decisions, cases and rules can be kept in a local file so they survive restarts
+	var embeddedClient *boltdb.Client
+	if dbClient == nil && cfg.Standalone.Store == "embedded" {
+		embeddedClient, err = boltdb.NewClient(boltdb.Config{
+			Path:        cfg.Standalone.Path,
+			OpenTimeout: 5 * time.Second,
+		})
+		if err != nil {
+			fatal(log, "failed to open embedded store", logger.Err(err))
+		}
+		log.Info("using embedded store", slog.String("path", cfg.Standalone.Path))
+		embeddedRules := boltdb.NewRuleRepository(embeddedClient)
+		if empty, err := embeddedRules.Empty(ctx); err != nil {
+			fatal(log, "failed to read embedded store", logger.Err(err))
+		} else if empty {
+			if err := embeddedRules.CreateBatch(ctx, defaultRules()); err != nil {
+				fatal(log, "failed to seed default rules", logger.Err(err))
+			}
+		}
+		decisionRepo = boltdb.NewDecisionRepository(embeddedClient)
+		caseRepo = boltdb.NewCaseRepository(embeddedClient)
+		ruleRepo = embeddedRules
+		listRepo = NewMockListRepository()
 	}
 
 	// Redis connection
@@ -77,8 +142,11 @@ func main() {
 	var velocityCache *redis.VelocityCache
 	var deviceCache *redis.DeviceCache
 	var locationCache *redis.LocationCache
+	var merchantCache *redis.MerchantCache
+	var cardTestingCache *redis.CardTestingCache
+	var linkingCache *redis.LinkingCache
 
-	redisClient, err = redis.NewClient(redis.Config{
+	redisClient, err = redis.NewClient(ctx, redis.Config{
 		Host:         cfg.Redis.Host,
 		Port:         cfg.Redis.Port,
 		Password:     cfg.Redis.Password,
@@ -86,25 +154,69 @@ func main() {
 		PoolSize:     cfg.Redis.PoolSize,
 		ReadTimeout:  cfg.Redis.ReadTimeout,
 		WriteTimeout: cfg.Redis.WriteTimeout,
+		Connect:      connectBackoff(cfg.Redis.Connect),
+		Logger:       log,
 	})
 	if err != nil {
-		log.Printf("Warning: Redis connection failed (velocity checks disabled): %v", err)
+		log.Warn("redis connection failed, velocity checks disabled", logger.Err(err))
 		redisClient = nil
 	} else {
-		log.Printf("Connected to Redis at %s:%d", cfg.Redis.Host, cfg.Redis.Port)
+		log.Info("connected to Redis", slog.String("host", cfg.Redis.Host), slog.Int("port", cfg.Redis.Port))
 		velocityCache = redis.NewVelocityCache(redisClient)
+		velocityCache.SetRetention(cfg.Fraud.VelocityRetention)
 		deviceCache = redis.NewDeviceCache(redisClient)
 		locationCache = redis.NewLocationCache(redisClient)
+		merchantCache = redis.NewMerchantCache(redisClient)
+		cardTestingCache = redis.NewCardTestingCache(redisClient)
+		linkingCache = redis.NewLinkingCache(redisClient)
+	}
+
+	// Chargeback history is shared by the rule engine and the fraud service
+	if chargebackRepo == nil {
+		chargebackRepo = NewMockChargebackRepository()
 	}
 
 	// Initialize rule engine
+	// In standalone mode the engine and the fraud service share one mock rule repository
+	// so rules created or updated over HTTP take effect
+	var mockRuleRepo *MockRuleRepository
 	var ruleEngine *rules.Engine
 	if ruleRepo != nil {
-		ruleEngine = rules.NewEngine(ruleRepo, velocityCache, deviceCache, locationCache)
+		ruleEngine = rules.NewEngine(ruleRepo, velocityCache, deviceCache, locationCache, merchantCache)
 	} else {
 		// Create a mock rule repository for standalone mode
-		ruleEngine = rules.NewEngine(NewMockRuleRepository(), velocityCache, deviceCache, locationCache)
+		mockRuleRepo = NewMockRuleRepository()
+		ruleEngine = rules.NewEngine(mockRuleRepo, velocityCache, deviceCache, locationCache, merchantCache)
+	}
+	if cfg.Fraud.GeoIPDatabase != "" {
+		resolver, err := rules.LoadCIDRGeoIPResolver(cfg.Fraud.GeoIPDatabase)
+		if err != nil {
+			fatal(log, "failed to load geoip database", logger.Err(err))
+		}
+		ruleEngine.SetGeoIPResolver(resolver)
+	}
+	ruleEngine.SetLogger(log)
+	ruleEngine.SetCardTestingCache(cardTestingCache)
+	ruleEngine.SetLinkingCache(linkingCache)
+	ruleEngine.SetChargebackRepository(chargebackRepo)
+	currencyConverter := rules.NewStaticCurrencyConverter(cfg.Fraud.BaseCurrency, cfg.Fraud.GetExchangeRates())
+	ruleEngine.SetCurrencyConverter(currencyConverter, cfg.Fraud.BaseCurrency)
+	ruleTimeouts := make(map[fraud.RuleType]rules.RuleTimeout, len(cfg.Fraud.RuleTimeouts))
+	for ruleType, timeout := range cfg.Fraud.RuleTimeouts {
+		ruleTimeouts[fraud.RuleType(ruleType)] = rules.RuleTimeout{
+			Timeout: timeout.Timeout,
+			Policy:  rules.TimeoutPolicy(timeout.Policy),
+		}
 	}
+	ruleEngine.SetRuleTimeouts(ruleTimeouts)
+	if cfg.Fraud.LoadShedding.Enabled {
+		ruleEngine.SetLoadShedding(rules.LoadShedding{
+			ConcurrencyThreshold: cfg.Fraud.LoadShedding.ConcurrencyThreshold,
+			SampleRate:           cfg.Fraud.LoadShedding.SampleRate,
+		})
+	}
+	ruleEngine.SetMaxRulesPerTransaction(cfg.Fraud.MaxRulesPerTransaction)
+	ruleEngine.SetStopOnCriticalBlock(cfg.Fraud.StopOnCriticalBlock)
 
 	// Initialize ML predictor
 	featureExtractor := ml.NewFeatureExtractor(
@@ -112,38 +224,122 @@ func main() {
 		cfg.Fraud.BlockedCountries,
 	)
 	mlPredictor := ml.NewPredictor(featureExtractor, cfg.ML.ModelVersion, cfg.ML.Enabled)
+	mlPredictor.SetModelDir(cfg.ML.ModelDir)
+	if err := mlPredictor.LoadModel(cfg.ML.ModelPath, cfg.ML.RuntimeLibraryPath); err != nil {
+		log.Warn("ML model not loaded, using heuristic weights", logger.Err(err))
+	} else if mlPredictor.HasModel() {
+		log.Info("loaded ML model", slog.String("path", cfg.ML.ModelPath))
+	}
+	metrics.SetMLEnabled(mlPredictor.IsEnabled())
 
 	// Initialize fraud service
 	var fraudService *fraud.Service
 	if decisionRepo != nil && caseRepo != nil && ruleRepo != nil {
-		fraudService = fraud.NewService(decisionRepo, caseRepo, ruleRepo, ruleEngine, nil)
+		// Repeated "already decided?" lookups are served from Redis when it's available
+		cachedDecisions := redis.NewCachedDecisionRepository(decisionRepo, redisClient, cfg.Redis.DecisionCacheTTL)
+		fraudService = fraud.NewService(cachedDecisions, caseRepo, ruleRepo, ruleEngine, nil)
+		fraudService.SetListRepository(redis.NewCachedListRepository(listRepo, redisClient, cfg.Redis.ListCacheTTL))
 	} else {
 		// Create with mock repositories for standalone mode
 		fraudService = fraud.NewService(
-			NewMockDecisionRepository(),
-			NewMockCaseRepository(),
-			NewMockRuleRepository(),
+			NewMockDecisionRepository(cfg.Standalone.MaxEntries),
+			NewMockCaseRepository(cfg.Standalone.MaxEntries),
+			mockRuleRepo,
 			ruleEngine,
 			nil,
 		)
+		fraudService.SetListRepository(NewMockListRepository())
 	}
 
+	fraudService.SetChargebackRepository(chargebackRepo)
+	fraudService.SetLogger(log)
+	fraudService.SetCurrencyConverter(currencyConverter, cfg.Fraud.BaseCurrency)
+
 	// Set custom thresholds
-	fraudService.SetDecisionThresholds(fraud.DecisionThresholds{
-		BlockThreshold:     decimal.NewFromFloat(cfg.Fraud.BlockThreshold),
-		ReviewThreshold:    decimal.NewFromFloat(cfg.Fraud.ReviewThreshold),
-		ChallengeThreshold: decimal.NewFromFloat(cfg.Fraud.ChallengeThreshold),
-	})
+	fraudService.SetDecisionThresholds(decisionThresholds(&cfg.Fraud))
+	if err := fraudService.SetScoreWeights(scoreWeights(&cfg.Fraud)); err != nil {
+		fatal(log, "invalid score weights", logger.Err(err))
+	}
+
+	// Per-tenant overrides
+	for _, tenant := range cfg.Fraud.Tenants {
+		if tenant.ID == "" {
+			fatal(log, "tenant scoring config is missing an id")
+		}
+		tenantCfg := cfg.Fraud.ForTenant(tenant)
+		if err := fraudService.SetTenantScoringConfig(tenant.ID, fraud.TenantScoringConfig{
+			Weights:    scoreWeights(&tenantCfg),
+			Thresholds: decisionThresholds(&tenantCfg),
+		}); err != nil {
+			fatal(log, "invalid tenant score weights", slog.String("tenant_id", tenant.ID), logger.Err(err))
+		}
+	}
 
-	fraudService.SetScoreWeights(fraud.ScoreWeights{
-		Velocity:   decimal.NewFromFloat(cfg.Fraud.VelocityWeight),
-		Amount:     decimal.NewFromFloat(cfg.Fraud.AmountWeight),
-		Geographic: decimal.NewFromFloat(cfg.Fraud.GeographicWeight),
-		Device:     decimal.NewFromFloat(cfg.Fraud.DeviceWeight),
-		Merchant:   decimal.NewFromFloat(cfg.Fraud.MerchantWeight),
-		Behavioral: decimal.NewFromFloat(cfg.Fraud.BehavioralWeight),
-		MLModel:    decimal.NewFromFloat(cfg.Fraud.MLWeight),
+	fraudService.SetContextRiskConfig(fraud.ContextRiskConfig{
+		Enabled:             cfg.Fraud.ContextRiskEnabled,
+		ScorePerMissing:     decimal.NewFromFloat(cfg.Fraud.ContextRiskScorePerMissing),
+		MaxScore:            decimal.NewFromFloat(cfg.Fraud.ContextRiskMaxScore),
+		ChallengeMinMissing: cfg.Fraud.ContextRiskChallengeMinMissing,
+	})
+	fraudService.SetScoringStrategy(fraud.ScoringStrategy(cfg.Fraud.ScoringStrategy))
+	fraudService.SetTypeAggregation(fraud.TypeAggregation(cfg.Fraud.TypeAggregation))
+	fraudService.SetEvaluationRetryBackoff(cfg.Fraud.EvaluationRetryBackoff)
+	fraudService.SetMinConfidence(decimal.NewFromFloat(cfg.Fraud.MinDecisionConfidence))
+	fraudService.SetConfidenceConfig(fraud.ConfidenceConfig{
+		CoverageWeight:        decimal.NewFromFloat(cfg.Fraud.Confidence.CoverageWeight),
+		AgreementWeight:       decimal.NewFromFloat(cfg.Fraud.Confidence.AgreementWeight),
+		SignalWeight:          decimal.NewFromFloat(cfg.Fraud.Confidence.SignalWeight),
+		EstablishedAccountAge: cfg.Fraud.Confidence.EstablishedAccountAge,
+	})
+	fraudService.SetMinDataCoverage(decimal.NewFromFloat(cfg.Fraud.MinDataCoverage))
+	alwaysStore := make([]fraud.DecisionType, 0, len(cfg.Fraud.BreakdownSampling.AlwaysFor))
+	for _, decision := range cfg.Fraud.BreakdownSampling.AlwaysFor {
+		alwaysStore = append(alwaysStore, fraud.DecisionType(decision))
+	}
+	fraudService.SetBreakdownSampling(fraud.BreakdownSampling{
+		SampleRate: cfg.Fraud.BreakdownSampling.SampleRate,
+		AlwaysFor:  alwaysStore,
+	})
+	fraudService.SetRiskProfileConfig(fraud.RiskProfileConfig{
+		BlockedHalfLife: cfg.Fraud.RiskProfile.BlockedHalfLife,
+		DecisionWeight:  decimal.NewFromFloat(cfg.Fraud.RiskProfile.DecisionWeight),
 	})
+	fraudService.SetCaseSLAConfig(fraud.CaseSLAConfig{
+		Targets: map[fraud.RiskLevel]time.Duration{
+			fraud.RiskLevelCritical: cfg.Fraud.CaseSLA.Critical,
+			fraud.RiskLevelHigh:     cfg.Fraud.CaseSLA.High,
+			fraud.RiskLevelMedium:   cfg.Fraud.CaseSLA.Medium,
+			fraud.RiskLevelLow:      cfg.Fraud.CaseSLA.Low,
+		},
+		WarningFraction: cfg.Fraud.CaseSLA.WarningFraction,
+	})
+	fraudService.SetRiskProfileCache(redis.NewRiskProfileCache(redisClient, cfg.Redis.RiskProfileCacheTTL))
+
+	// Publish alerts for blocked and flagged transactions
+	var alertPublisher *kafka.AlertPublisher
+	if cfg.Kafka.Enabled {
+		alertPublisher = kafka.NewAlertPublisher(cfg.Kafka.Brokers, cfg.Kafka.FraudAlertsTopic)
+		fraudService.SetAlertPublisher(alertPublisher)
+	}
+
+	// Call back the client's webhook on subscribed decisions and case resolutions
+	if cfg.Webhook.URL != "" {
+		events := make([]fraud.WebhookEventType, 0, len(cfg.Webhook.Events))
+		for _, event := range cfg.Webhook.Events {
+			events = append(events, fraud.WebhookEventType(event))
+		}
+		fraudService.SetWebhookNotifier(webhook.NewNotifier(webhook.Config{
+			URL:          cfg.Webhook.URL,
+			Secret:       cfg.Webhook.Secret,
+			Events:       events,
+			Timeout:      cfg.Webhook.Timeout,
+			MaxAttempts:  cfg.Webhook.MaxAttempts,
+			RetryBackoff: cfg.Webhook.RetryBackoff,
+		}), fraud.WebhookDeliveryConfig{
+			Workers:   cfg.Webhook.Workers,
+			QueueSize: cfg.Webhook.QueueSize,
+		})
+	}
 
 	// Initialize use case
 	detectFraudUseCase := fraudapp.NewDetectFraudUseCase(
@@ -153,11 +349,41 @@ func main() {
 		velocityCache,
 		deviceCache,
 		locationCache,
+		merchantCache,
 		cfg.Fraud.AnalysisTimeout,
 	)
+	detectFraudUseCase.SetLogger(log)
+	detectFraudUseCase.SetCardTestingCache(cardTestingCache)
+	detectFraudUseCase.SetLinkingCache(linkingCache)
+	detectFraudUseCase.SetRecentHistoryWindow(cfg.Fraud.RecentHistoryWindow)
+	detectFraudUseCase.SetBatchConcurrency(cfg.Fraud.BatchConcurrency)
+
+	// Transactions are stored and scored through the process transaction use case
+	if txRepo == nil {
+		txRepo = NewMockTransactionRepository(cfg.Standalone.MaxEntries)
+	}
+	txService := transaction.NewService(txRepo)
+	fraudService.SetTransactionReviewer(txService)
+	fraudService.SetTransactionOverrider(txService)
+	processTransactionUseCase := txapp.NewProcessTransactionUseCase(txService, fraudService)
+	processTransactionUseCase.SetLogger(log)
+	processTransactionUseCase.SetUserProfileConfig(txapp.UserProfileConfig{
+		TypicalMerchantLimit: cfg.Fraud.UserProfile.TypicalMerchantLimit,
+		TrustedDeviceMinUses: cfg.Fraud.UserProfile.TrustedDeviceMinUses,
+	})
 
 	// Initialize handlers
 	fraudHandler := handler.NewFraudHandler(detectFraudUseCase, fraudService)
+	txHandler := handler.NewTransactionHandler(processTransactionUseCase, txapp.NewListUserTransactionsUseCase(txService, fraudService), txService)
+	if cfg.Fraud.ReportSigningKey != "" {
+		fraudHandler.SetReportSigningKey([]byte(cfg.Fraud.ReportSigningKey))
+	}
+	if cfg.Fraud.DecisionSigningKey != "" {
+		fraudHandler.SetDecisionSigningKey([]byte(cfg.Fraud.DecisionSigningKey))
+	}
+	fraudHandler.SetGenerateTransactionIDs(cfg.Fraud.GenerateTransactionIDs)
+	fraudHandler.SetModelReloader(mlPredictor)
+	fraudHandler.SetBacktestUseCase(txapp.NewBacktestUseCase(txService, fraudService))
 
 	var dbHealthChecker handler.HealthChecker
 	var redisHealthChecker handler.HealthChecker
@@ -168,71 +394,235 @@ func main() {
 		redisHealthChecker = redisClient
 	}
 	healthHandler := handler.NewHealthHandler(dbHealthChecker, redisHealthChecker, version)
+	healthHandler.SetMLStatus(mlPredictor)
+	if embeddedClient != nil {
+		healthHandler.SetEmbeddedStore(embeddedClient)
+	}
+	metrics.SetDegradedMode(dbClient == nil || redisClient == nil)
 
 	// Create router
-	r := router.NewRouter(fraudHandler, healthHandler)
+	r := router.NewRouter(fraudHandler, txHandler, healthHandler)
+	if cfg.Auth.Enabled {
+		keys := make([]middleware.APIKey, 0, len(cfg.Auth.APIKeys))
+		for _, k := range cfg.Auth.APIKeys {
+			userID, err := uuid.Parse(k.UserID)
+			if err != nil {
+				fatal(log, "invalid user_id for API key", slog.String(logger.KeyUserID, k.UserID), logger.Err(err))
+			}
+			roles := make([]middleware.Role, 0, len(k.Roles))
+			for _, name := range k.Roles {
+				role := middleware.Role(name)
+				if !role.IsValid() {
+					fatal(log, "invalid role for API key", slog.String("role", name), slog.String(logger.KeyUserID, k.UserID))
+				}
+				roles = append(roles, role)
+			}
+			keys = append(keys, middleware.APIKey{Key: k.Key, UserID: userID, Roles: roles, TenantID: k.TenantID})
+		}
+		r.SetAuthenticator(middleware.NewStaticKeyAuthenticator(keys))
+		log.Info("API authentication enabled", slog.Int("keys", len(keys)))
+	} else {
+		log.Warn("API authentication disabled - mutating endpoints are open")
+	}
+
+	if cfg.Metrics.Enabled {
+		r.EnableMetrics(cfg.Metrics.Path)
+	}
+	r.EnableRequestLogging(log)
 
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
-		Handler:      r,
+		Handler:      r.Handler(),
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
 
 	// Start server in goroutine
 	go func() {
-		log.Printf("HTTP server listening on %s", server.Addr)
+		log.Info("HTTP server listening", slog.String("addr", server.Addr))
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", err)
+			fatal(log, "server error", logger.Err(err))
 		}
 	}()
 
+	// Start Kafka transaction consumer
+	var consumer *kafka.TransactionConsumer
+	consumerCtx, stopConsumer := context.WithCancel(ctx)
+	defer stopConsumer()
+	if cfg.Kafka.Enabled {
+		consumer = kafka.NewTransactionConsumer(kafka.ConsumerConfig{
+			Brokers:         cfg.Kafka.Brokers,
+			Topic:           cfg.Kafka.TransactionsTopic,
+			GroupID:         cfg.Kafka.ConsumerGroup,
+			DeadLetterTopic: cfg.Kafka.DeadLetterTopic,
+			MaxAttempts:     cfg.Kafka.MaxAttempts,
+			RetryBackoff:    cfg.Kafka.RetryBackoff,
+		}, detectFraudUseCase)
+		consumer.SetLogger(log)
+
+		go func() {
+			log.Info("kafka consumer started", slog.String("topic", cfg.Kafka.TransactionsTopic), slog.String("group", cfg.Kafka.ConsumerGroup))
+			if err := consumer.Run(consumerCtx); err != nil {
+				log.Error("kafka consumer error", logger.Err(err))
+			}
+		}()
+	}
+
+	// Start case SLA monitor
+	monitorCtx, stopMonitor := context.WithCancel(ctx)
+	defer stopMonitor()
+	if cfg.Fraud.CaseSLA.CheckInterval > 0 {
+		go fraudService.MonitorCaseSLAs(monitorCtx, cfg.Fraud.CaseSLA.CheckInterval)
+		log.Info("case SLA monitor started", slog.Duration("interval", cfg.Fraud.CaseSLA.CheckInterval))
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("Shutting down server...")
+	log.Info("shutting down server")
+	stopMonitor()
+
+	// Stop consuming and let the in-flight message finish
+	if consumer != nil {
+		stopConsumer()
+		if err := consumer.Close(); err != nil {
+			log.Error("kafka consumer shutdown error", logger.Err(err))
+		}
+	}
 
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(ctx, cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Printf("Server shutdown error: %v", err)
+		log.Error("server shutdown error", logger.Err(err))
+	}
+
+	// Deliver queued webhook events; what's left at the timeout is dead-lettered
+	if err := fraudService.DrainWebhooks(ctx); err != nil {
+		log.Error("webhook queue not drained before shutdown", logger.Err(err))
 	}
 
 	// Close connections
+	mlPredictor.Close()
+	if alertPublisher != nil {
+		alertPublisher.Close()
+	}
 	if dbClient != nil {
 		dbClient.Close()
 	}
+	if embeddedClient != nil {
+		embeddedClient.Close()
+	}
 	if redisClient != nil {
 		redisClient.Close()
 	}
 
-	log.Println("Server stopped")
+	log.Info("server stopped")
+}
+
+// fatal logs an error and exits; it stands in for log.Fatal with a structured logger
+func fatal(log *slog.Logger, msg string, args ...any) {
+	log.Error(msg, args...)
+	os.Exit(1)
+}
+
+// decisionThresholds converts configured thresholds to the domain type
+func decisionThresholds(c *config.FraudConfig) fraud.DecisionThresholds {
+	return fraud.DecisionThresholds{
+		BlockThreshold:     decimal.NewFromFloat(c.BlockThreshold),
+		ReviewThreshold:    decimal.NewFromFloat(c.ReviewThreshold),
+		ChallengeThreshold: decimal.NewFromFloat(c.ChallengeThreshold),
+	}
+}
+
+// scoreWeights converts configured weights to the domain type
+func scoreWeights(c *config.FraudConfig) fraud.ScoreWeights {
+	return fraud.ScoreWeights{
+		Velocity:   decimal.NewFromFloat(c.VelocityWeight),
+		Amount:     decimal.NewFromFloat(c.AmountWeight),
+		Geographic: decimal.NewFromFloat(c.GeographicWeight),
+		Device:     decimal.NewFromFloat(c.DeviceWeight),
+		Merchant:   decimal.NewFromFloat(c.MerchantWeight),
+		Behavioral: decimal.NewFromFloat(c.BehavioralWeight),
+		MLModel:    decimal.NewFromFloat(c.MLWeight),
+	}
+}
+
+// connectBackoff converts a dependency's startup retry settings
+func connectBackoff(c config.ConnectConfig) retry.Backoff {
+	return retry.Backoff{
+		Attempts: c.Attempts,
+		Initial:  c.Backoff,
+		Timeout:  c.Timeout,
+	}
 }
 
 // Mock repositories for standalone mode (when DB is not available)
+// Decisions, cases and transactions are bounded by standalone.max_entries, oldest evicted first
+
+// evictionQueue tracks insertion order so a bounded mock can drop its oldest entries
+type evictionQueue struct {
+	max  int // 0 means no limit
+	keys []string
+}
+
+// push records a new key and returns the oldest key once the queue is over its limit
+func (q *evictionQueue) push(key string) (string, bool) {
+	q.keys = append(q.keys, key)
+	if q.max <= 0 || len(q.keys) <= q.max {
+		return "", false
+	}
+	oldest := q.keys[0]
+	q.keys = q.keys[1:]
+	return oldest, true
+}
+
+// The standalone repositories must keep up with the interfaces they stand in for
+var (
+	_ fraud.DecisionRepository   = (*MockDecisionRepository)(nil)
+	_ fraud.CaseRepository       = (*MockCaseRepository)(nil)
+	_ fraud.RuleRepository       = (*MockRuleRepository)(nil)
+	_ fraud.ListRepository       = (*MockListRepository)(nil)
+	_ fraud.ChargebackRepository = (*MockChargebackRepository)(nil)
+	_ transaction.Repository     = (*MockTransactionRepository)(nil)
+)
 
 // MockDecisionRepository implements fraud.DecisionRepository for standalone mode
 type MockDecisionRepository struct {
+	mu        sync.RWMutex
 	decisions map[string]*fraud.FraudDecision
+	feedback  []*fraud.DecisionFeedback
+	overrides []*fraud.DecisionOverride
+	order     evictionQueue
 }
 
-func NewMockDecisionRepository() *MockDecisionRepository {
+func NewMockDecisionRepository(maxEntries int) *MockDecisionRepository {
 	return &MockDecisionRepository{
 		decisions: make(map[string]*fraud.FraudDecision),
+		order:     evictionQueue{max: maxEntries},
 	}
 }
 
 func (r *MockDecisionRepository) Create(ctx context.Context, decision *fraud.FraudDecision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.findByTransactionID(decision.TransactionID); ok {
+		return fraud.ErrDuplicateDecision
+	}
 	r.decisions[decision.ID.String()] = decision
+	if oldest, ok := r.order.push(decision.ID.String()); ok {
+		delete(r.decisions, oldest)
+	}
 	return nil
 }
 
 func (r *MockDecisionRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.FraudDecision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	if d, ok := r.decisions[id.String()]; ok {
 		return d, nil
 	}
@@ -240,51 +630,209 @@ func (r *MockDecisionRepository) GetByID(ctx context.Context, id uuid.UUID) (*fr
 }
 
 func (r *MockDecisionRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*fraud.FraudDecision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if d, ok := r.findByTransactionID(transactionID); ok {
+		return d, nil
+	}
+	return nil, fraud.ErrDecisionNotFound
+}
+
+func (r *MockDecisionRepository) GetByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) (map[uuid.UUID]*fraud.FraudDecision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	decisions := make(map[uuid.UUID]*fraud.FraudDecision, len(transactionIDs))
+	for _, id := range transactionIDs {
+		if d, ok := r.findByTransactionID(id); ok {
+			decisions[id] = d
+		}
+	}
+	return decisions, nil
+}
+
+// findByTransactionID scans for a transaction's decision; callers hold the lock
+func (r *MockDecisionRepository) findByTransactionID(transactionID uuid.UUID) (*fraud.FraudDecision, bool) {
 	for _, d := range r.decisions {
 		if d.TransactionID == transactionID {
-			return d, nil
+			return d, true
 		}
 	}
-	return nil, fraud.ErrDecisionNotFound
+	return nil, false
 }
 
 func (r *MockDecisionRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*fraud.FraudDecision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var results []*fraud.FraudDecision
 	for _, d := range r.decisions {
 		if d.UserID == userID {
 			results = append(results, d)
 		}
 	}
+
+	// Newest first, matching the database repository
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(results) {
+		return []*fraud.FraudDecision{}, nil
+	}
+	results = results[offset:]
+	if limit = fraud.DecisionListLimit(limit); limit < len(results) {
+		results = results[:limit]
+	}
 	return results, nil
 }
 
+func (r *MockDecisionRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var count int64
+	for _, d := range r.decisions {
+		if d.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (r *MockDecisionRepository) GetBlockedCount(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var count int64
 	for _, d := range r.decisions {
-		if d.UserID == userID && d.Decision == fraud.DecisionBlock && d.CreatedAt.After(since) {
+		if d.UserID == userID && d.EffectiveDecision() == fraud.DecisionBlock && d.CreatedAt.After(since) {
 			count++
 		}
 	}
 	return count, nil
 }
 
+func (r *MockDecisionRepository) ListBlockTimes(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var times []time.Time
+	for _, d := range r.decisions {
+		if d.UserID == userID && d.EffectiveDecision() == fraud.DecisionBlock && d.CreatedAt.After(since) {
+			times = append(times, d.CreatedAt)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].After(times[j]) })
+	return times, nil
+}
+
+func (r *MockDecisionRepository) RecordFeedback(ctx context.Context, feedback *fraud.DecisionFeedback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.feedback = append(r.feedback, feedback)
+	if limit := r.order.max; limit > 0 && len(r.feedback) > limit {
+		r.feedback = r.feedback[len(r.feedback)-limit:]
+	}
+	return nil
+}
+
+func (r *MockDecisionRepository) ListFeedback(ctx context.Context, from, to time.Time) ([]*fraud.DecisionFeedback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var results []*fraud.DecisionFeedback
+	for _, f := range r.feedback {
+		if !f.DecidedAt.Before(from) && f.DecidedAt.Before(to) {
+			results = append(results, f)
+		}
+	}
+	return results, nil
+}
+
+func (r *MockDecisionRepository) RecordOverride(ctx context.Context, override *fraud.DecisionOverride) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	decision, ok := r.decisions[override.DecisionID.String()]
+	if !ok {
+		return fraud.ErrDecisionNotFound
+	}
+	if decision.EffectiveDecision() != override.PreviousDecision {
+		return fraud.ErrOverrideConflict
+	}
+
+	// Replace rather than modify, since readers may hold the old pointer
+	overridden := *decision
+	overridden.OverriddenDecision = override.Decision
+	r.decisions[decision.ID.String()] = &overridden
+	r.overrides = append(r.overrides, override)
+	if limit := r.order.max; limit > 0 && len(r.overrides) > limit {
+		r.overrides = r.overrides[len(r.overrides)-limit:]
+	}
+	return nil
+}
+
+func (r *MockDecisionRepository) MarkOverrideApplied(ctx context.Context, overrideID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, o := range r.overrides {
+		if o.ID == overrideID {
+			applied := *o
+			applied.TransactionUpdated = true
+			r.overrides[i] = &applied
+		}
+	}
+	return nil
+}
+
+func (r *MockDecisionRepository) ListOverrides(ctx context.Context, decisionID uuid.UUID) ([]*fraud.DecisionOverride, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var results []*fraud.DecisionOverride
+	for _, o := range r.overrides {
+		if o.DecisionID == decisionID {
+			results = append(results, o)
+		}
+	}
+	return results, nil
+}
+
+func (r *MockDecisionRepository) ScoreHistogram(ctx context.Context, from, to time.Time) ([]fraud.ScoreCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var scores []decimal.Decimal
+	for _, d := range r.decisions {
+		if !d.ProcessedAt.Before(from) && d.ProcessedAt.Before(to) {
+			scores = append(scores, d.Score)
+		}
+	}
+	return fraud.NewScoreHistogram(scores), nil
+}
+
 // MockCaseRepository implements fraud.CaseRepository for standalone mode
 type MockCaseRepository struct {
+	mu    sync.RWMutex
 	cases map[string]*fraud.FraudCase
+	order evictionQueue
 }
 
-func NewMockCaseRepository() *MockCaseRepository {
+func NewMockCaseRepository(maxEntries int) *MockCaseRepository {
 	return &MockCaseRepository{
 		cases: make(map[string]*fraud.FraudCase),
+		order: evictionQueue{max: maxEntries},
 	}
 }
 
 func (r *MockCaseRepository) Create(ctx context.Context, fraudCase *fraud.FraudCase) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.cases[fraudCase.ID.String()] = fraudCase
+	if oldest, ok := r.order.push(fraudCase.ID.String()); ok {
+		delete(r.cases, oldest)
+	}
 	return nil
 }
 
 func (r *MockCaseRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.FraudCase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	if c, ok := r.cases[id.String()]; ok {
 		return c, nil
 	}
@@ -292,21 +840,47 @@ func (r *MockCaseRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.
 }
 
 func (r *MockCaseRepository) Update(ctx context.Context, fraudCase *fraud.FraudCase) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.cases[fraudCase.ID.String()] = fraudCase
 	return nil
 }
 
 func (r *MockCaseRepository) ListByStatus(ctx context.Context, status fraud.CaseStatus, limit, offset int) ([]*fraud.FraudCase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var results []*fraud.FraudCase
 	for _, c := range r.cases {
 		if c.Status == status {
 			results = append(results, c)
 		}
 	}
-	return results, nil
+
+	// Newest first, matching the database repository
+	slices.SortFunc(results, func(a, b *fraud.FraudCase) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if offset >= len(results) {
+		return []*fraud.FraudCase{}, nil
+	}
+	return results[offset:min(offset+limit, len(results))], nil
+}
+
+func (r *MockCaseRepository) CountByStatus(ctx context.Context, status fraud.CaseStatus) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var count int64
+	for _, c := range r.cases {
+		if c.Status == status {
+			count++
+		}
+	}
+	return count, nil
 }
 
 func (r *MockCaseRepository) ListByAssignee(ctx context.Context, assigneeID uuid.UUID, limit, offset int) ([]*fraud.FraudCase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var results []*fraud.FraudCase
 	for _, c := range r.cases {
 		if c.AssignedTo != nil && *c.AssignedTo == assigneeID {
@@ -317,6 +891,8 @@ func (r *MockCaseRepository) ListByAssignee(ctx context.Context, assigneeID uuid
 }
 
 func (r *MockCaseRepository) GetOpenCasesByUser(ctx context.Context, userID uuid.UUID) ([]*fraud.FraudCase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var results []*fraud.FraudCase
 	for _, c := range r.cases {
 		if c.UserID == userID && c.IsOpen() {
@@ -326,21 +902,96 @@ func (r *MockCaseRepository) GetOpenCasesByUser(ctx context.Context, userID uuid
 	return results, nil
 }
 
+func (r *MockCaseRepository) unassigned() []*fraud.FraudCase {
+	var results []*fraud.FraudCase
+	for _, c := range r.cases {
+		if c.IsQueued() {
+			results = append(results, c)
+		}
+	}
+	slices.SortFunc(results, fraud.CompareCaseQueue)
+	return results
+}
+
+func (r *MockCaseRepository) ListUnassigned(ctx context.Context, limit, offset int) ([]*fraud.FraudCase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	results := r.unassigned()
+	if offset >= len(results) {
+		return []*fraud.FraudCase{}, nil
+	}
+	return results[offset:min(offset+limit, len(results))], nil
+}
+
+func (r *MockCaseRepository) CountUnassigned(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return int64(len(r.unassigned())), nil
+}
+
+func (r *MockCaseRepository) ClaimNext(ctx context.Context, investigatorID uuid.UUID) (*fraud.FraudCase, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queue := r.unassigned()
+	if len(queue) == 0 {
+		return nil, fraud.ErrCaseQueueEmpty
+	}
+	if err := queue[0].Assign(investigatorID); err != nil {
+		return nil, err
+	}
+	return queue[0], nil
+}
+
+func (r *MockCaseRepository) ListOpenCreatedBefore(ctx context.Context, riskLevel fraud.RiskLevel, before time.Time, limit int) ([]*fraud.FraudCase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var results []*fraud.FraudCase
+	for _, c := range r.cases {
+		if c.RiskLevel == riskLevel && c.IsOpen() && c.CreatedAt.Before(before) {
+			results = append(results, c)
+		}
+	}
+	slices.SortFunc(results, func(a, b *fraud.FraudCase) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return results[:min(limit, len(results))], nil
+}
+
 // MockRuleRepository implements fraud.RuleRepository for standalone mode
 type MockRuleRepository struct {
-	rules map[string]*fraud.Rule
+	mu       sync.RWMutex
+	rules    map[string]*fraud.Rule
+	versions map[string][]*fraud.RuleVersion
 }
 
 func NewMockRuleRepository() *MockRuleRepository {
 	repo := &MockRuleRepository{
-		rules: make(map[string]*fraud.Rule),
+		rules:    make(map[string]*fraud.Rule),
+		versions: make(map[string][]*fraud.RuleVersion),
+	}
+	for _, rule := range defaultRules() {
+		repo.rules[rule.ID.String()] = rule
+		repo.recordVersion(rule)
 	}
-	// Add default rules
-	repo.seedDefaultRules()
 	return repo
 }
 
-func (r *MockRuleRepository) seedDefaultRules() {
+// recordVersion appends a snapshot of the rule to its history; callers hold the lock
+func (r *MockRuleRepository) recordVersion(rule *fraud.Rule) {
+	snapshot := *rule
+	snapshot.Config = make(map[string]interface{}, len(rule.Config))
+	for k, v := range rule.Config {
+		snapshot.Config[k] = v
+	}
+	r.versions[rule.ID.String()] = append(r.versions[rule.ID.String()], &fraud.RuleVersion{
+		Rule:      &snapshot,
+		ChangedBy: rule.UpdatedBy,
+		ChangedAt: rule.UpdatedAt,
+	})
+}
+
+// defaultRules returns the rules a fresh standalone store starts with
+func defaultRules() []*fraud.Rule {
 	// Velocity rule
 	velocityRule := fraud.NewRule(
 		"high_velocity",
@@ -354,7 +1005,6 @@ func (r *MockRuleRepository) seedDefaultRules() {
 		"max_transactions": float64(5),
 		"window_minutes":   float64(5),
 	}
-	r.rules[velocityRule.ID.String()] = velocityRule
 
 	// Amount rule
 	amountRule := fraud.NewRule(
@@ -369,7 +1019,6 @@ func (r *MockRuleRepository) seedDefaultRules() {
 		"max_amount":       "5000",
 		"deviation_factor": float64(5),
 	}
-	r.rules[amountRule.ID.String()] = amountRule
 
 	// Geographic rule
 	geoRule := fraud.NewRule(
@@ -384,7 +1033,6 @@ func (r *MockRuleRepository) seedDefaultRules() {
 		"blocked_countries":  []interface{}{"KP", "IR", "SY"},
 		"require_consistent": true,
 	}
-	r.rules[geoRule.ID.String()] = geoRule
 
 	// Device rule
 	deviceRule := fraud.NewRule(
@@ -399,7 +1047,6 @@ func (r *MockRuleRepository) seedDefaultRules() {
 		"require_trusted_device": true,
 		"max_devices_per_user":   float64(5),
 	}
-	r.rules[deviceRule.ID.String()] = deviceRule
 
 	// Behavioral rule
 	behaviorRule := fraud.NewRule(
@@ -411,7 +1058,6 @@ func (r *MockRuleRepository) seedDefaultRules() {
 		uuid.Nil,
 	)
 	behaviorRule.Config = map[string]interface{}{}
-	r.rules[behaviorRule.ID.String()] = behaviorRule
 
 	// Merchant rule
 	merchantRule := fraud.NewRule(
@@ -425,15 +1071,31 @@ func (r *MockRuleRepository) seedDefaultRules() {
 	merchantRule.Config = map[string]interface{}{
 		"high_risk_mcc_codes": []interface{}{"7995", "7801", "5967", "6051"},
 	}
-	r.rules[merchantRule.ID.String()] = merchantRule
+
+	return []*fraud.Rule{velocityRule, amountRule, geoRule, deviceRule, behaviorRule, merchantRule}
 }
 
 func (r *MockRuleRepository) Create(ctx context.Context, rule *fraud.Rule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.rules[rule.ID.String()] = rule
+	r.recordVersion(rule)
+	return nil
+}
+
+func (r *MockRuleRepository) CreateBatch(ctx context.Context, rules []*fraud.Rule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rule := range rules {
+		r.rules[rule.ID.String()] = rule
+		r.recordVersion(rule)
+	}
 	return nil
 }
 
 func (r *MockRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.Rule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	if rule, ok := r.rules[id.String()]; ok {
 		return rule, nil
 	}
@@ -441,11 +1103,16 @@ func (r *MockRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.
 }
 
 func (r *MockRuleRepository) Update(ctx context.Context, rule *fraud.Rule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.rules[rule.ID.String()] = rule
+	r.recordVersion(rule)
 	return nil
 }
 
 func (r *MockRuleRepository) ListActive(ctx context.Context) ([]*fraud.Rule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var results []*fraud.Rule
 	for _, rule := range r.rules {
 		if rule.IsActive() {
@@ -456,6 +1123,8 @@ func (r *MockRuleRepository) ListActive(ctx context.Context) ([]*fraud.Rule, err
 }
 
 func (r *MockRuleRepository) ListByType(ctx context.Context, ruleType fraud.RuleType) ([]*fraud.Rule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	var results []*fraud.Rule
 	for _, rule := range r.rules {
 		if rule.Type == ruleType && rule.Enabled {
@@ -466,14 +1135,301 @@ func (r *MockRuleRepository) ListByType(ctx context.Context, ruleType fraud.Rule
 }
 
 func (r *MockRuleRepository) Disable(ctx context.Context, ruleID uuid.UUID) error {
-	if rule, ok := r.rules[ruleID.String()]; ok {
-		rule.Disable()
-		return nil
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.rules[ruleID.String()]
+	if !ok {
+		return fraud.ErrRuleNotFound
 	}
-	return fraud.ErrRuleNotFound
+
+	// Replace rather than modify, since the engine may hold the old pointer
+	disabled := *rule
+	disabled.Disable()
+	r.rules[ruleID.String()] = &disabled
+	return nil
 }
 
 func (r *MockRuleRepository) GetVersion(ctx context.Context, ruleID uuid.UUID, version int) (*fraud.Rule, error) {
-	return r.GetByID(ctx, ruleID)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, v := range r.versions[ruleID.String()] {
+		if v.Rule.Version == version {
+			return v.Rule, nil
+		}
+	}
+	return nil, fraud.ErrRuleNotFound
+}
+
+func (r *MockRuleRepository) ListVersions(ctx context.Context, ruleID uuid.UUID) ([]*fraud.RuleVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.versions[ruleID.String()], nil
 }
 
+// MockListRepository implements fraud.ListRepository for standalone mode
+type MockListRepository struct {
+	mu      sync.RWMutex
+	entries map[string]*fraud.ListEntry
+}
+
+func NewMockListRepository() *MockListRepository {
+	return &MockListRepository{
+		entries: make(map[string]*fraud.ListEntry),
+	}
+}
+
+func (r *MockListRepository) Create(ctx context.Context, entry *fraud.ListEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entries {
+		if e.ListType == entry.ListType && e.EntityType == entry.EntityType && e.Value == entry.Value {
+			return fraud.ErrDuplicateListEntry
+		}
+	}
+	r.entries[entry.ID.String()] = entry
+	return nil
+}
+
+func (r *MockListRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.ListEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if e, ok := r.entries[id.String()]; ok {
+		return e, nil
+	}
+	return nil, fraud.ErrListEntryNotFound
+}
+
+func (r *MockListRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[id.String()]; !ok {
+		return fraud.ErrListEntryNotFound
+	}
+	delete(r.entries, id.String())
+	return nil
+}
+
+func (r *MockListRepository) List(ctx context.Context, listType fraud.ListType) ([]*fraud.ListEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	results := make([]*fraud.ListEntry, 0)
+	for _, e := range r.entries {
+		if listType == "" || e.ListType == listType {
+			results = append(results, e)
+		}
+	}
+
+	// Newest first, matching the database repository
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+	return results, nil
+}
+
+func (r *MockListRepository) FindMatches(ctx context.Context, keys []fraud.ListKey) ([]*fraud.ListEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	results := make([]*fraud.ListEntry, 0)
+	for _, e := range r.entries {
+		for _, key := range keys {
+			if e.EntityType == key.EntityType && e.Value == key.Value {
+				results = append(results, e)
+				break
+			}
+		}
+	}
+	return results, nil
+}
+
+// MockChargebackRepository implements fraud.ChargebackRepository for standalone mode
+type MockChargebackRepository struct {
+	mu          sync.RWMutex
+	chargebacks []*fraud.Chargeback
+}
+
+func NewMockChargebackRepository() *MockChargebackRepository {
+	return &MockChargebackRepository{}
+}
+
+func (r *MockChargebackRepository) Create(ctx context.Context, chargeback *fraud.Chargeback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chargebacks = append(r.chargebacks, chargeback)
+	return nil
+}
+
+func (r *MockChargebackRepository) CountByUserID(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var count int64
+	for _, c := range r.chargebacks {
+		if c.UserID == userID && !c.OccurredAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MockTransactionRepository implements transaction.Repository for standalone mode
+type MockTransactionRepository struct {
+	mu           sync.RWMutex
+	transactions map[string]*transaction.Transaction
+	order        evictionQueue
+}
+
+func NewMockTransactionRepository(maxEntries int) *MockTransactionRepository {
+	return &MockTransactionRepository{
+		transactions: make(map[string]*transaction.Transaction),
+		order:        evictionQueue{max: maxEntries},
+	}
+}
+
+func (r *MockTransactionRepository) Create(ctx context.Context, tx *transaction.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transactions[tx.ID.String()] = tx
+	if oldest, ok := r.order.push(tx.ID.String()); ok {
+		delete(r.transactions, oldest)
+	}
+	return nil
+}
+
+func (r *MockTransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*transaction.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if tx, ok := r.transactions[id.String()]; ok {
+		return tx, nil
+	}
+	return nil, transaction.ErrTransactionNotFound
+}
+
+func (r *MockTransactionRepository) GetByExternalID(ctx context.Context, externalID string) (*transaction.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, tx := range r.transactions {
+		if tx.ExternalID == externalID {
+			return tx, nil
+		}
+	}
+	return nil, transaction.ErrTransactionNotFound
+}
+
+func (r *MockTransactionRepository) Update(ctx context.Context, tx *transaction.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transactions[tx.ID.String()] = tx
+	return nil
+}
+
+func (r *MockTransactionRepository) ListByUserID(ctx context.Context, userID uuid.UUID, filter transaction.ListFilter, limit, offset int) ([]*transaction.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.page(r.filter(userMatch(userID, filter)), limit, offset), nil
+}
+
+func (r *MockTransactionRepository) CountByUserID(ctx context.Context, userID uuid.UUID, filter transaction.ListFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return int64(len(r.filter(userMatch(userID, filter)))), nil
+}
+
+// userMatch matches a user's transactions passing filter
+func userMatch(userID uuid.UUID, filter transaction.ListFilter) func(*transaction.Transaction) bool {
+	return func(tx *transaction.Transaction) bool {
+		if tx.UserID != userID || (filter.Status != "" && tx.Status != filter.Status) {
+			return false
+		}
+		return filter.MinScore == nil || (tx.FraudScore != nil && tx.FraudScore.GreaterThanOrEqual(*filter.MinScore))
+	}
+}
+
+func (r *MockTransactionRepository) ListByAccountID(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*transaction.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.page(r.filter(func(tx *transaction.Transaction) bool { return tx.AccountID == accountID }), limit, offset), nil
+}
+
+func (r *MockTransactionRepository) GetRecentByUserID(ctx context.Context, userID uuid.UUID, since time.Time) ([]*transaction.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.filter(func(tx *transaction.Transaction) bool {
+		return tx.UserID == userID && !tx.CreatedAt.Before(since)
+	}), nil
+}
+
+func (r *MockTransactionRepository) GetFirstByUserID(ctx context.Context, userID uuid.UUID) (*transaction.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	txs := r.filter(func(tx *transaction.Transaction) bool { return tx.UserID == userID })
+	if len(txs) == 0 {
+		return nil, transaction.ErrTransactionNotFound
+	}
+	return txs[len(txs)-1], nil
+}
+
+func (r *MockTransactionRepository) GetByTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*transaction.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.filter(func(tx *transaction.Transaction) bool {
+		return tx.UserID == userID && !tx.CreatedAt.Before(start) && tx.CreatedAt.Before(end)
+	}), nil
+}
+
+func (r *MockTransactionRepository) ListCreatedBetween(ctx context.Context, start, end time.Time, limit int) ([]*transaction.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	txs := r.filter(func(tx *transaction.Transaction) bool {
+		return !tx.CreatedAt.Before(start) && tx.CreatedAt.Before(end)
+	})
+	slices.Reverse(txs)
+	return r.page(txs, limit, 0), nil
+}
+
+func (r *MockTransactionRepository) CountByUserIDAndTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) (int64, error) {
+	txs, _ := r.GetByTimeRange(ctx, userID, start, end)
+	return int64(len(txs)), nil
+}
+
+func (r *MockTransactionRepository) SumAmountByUserIDAndTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) (decimal.Decimal, error) {
+	txs, _ := r.GetByTimeRange(ctx, userID, start, end)
+	total := decimal.Zero
+	for _, tx := range txs {
+		total = total.Add(tx.Amount)
+	}
+	return total, nil
+}
+
+func (r *MockTransactionRepository) GetByStatus(ctx context.Context, status transaction.TransactionStatus, limit, offset int) ([]*transaction.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.page(r.filter(func(tx *transaction.Transaction) bool { return tx.Status == status }), limit, offset), nil
+}
+
+func (r *MockTransactionRepository) GetFlaggedTransactions(ctx context.Context, limit, offset int) ([]*transaction.Transaction, error) {
+	return r.GetByStatus(ctx, transaction.StatusFlagged, limit, offset)
+}
+
+// filter returns matching transactions, newest first; callers hold the lock
+func (r *MockTransactionRepository) filter(match func(*transaction.Transaction) bool) []*transaction.Transaction {
+	var results []*transaction.Transaction
+	for _, tx := range r.transactions {
+		if match(tx) {
+			results = append(results, tx)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CreatedAt.After(results[j].CreatedAt)
+	})
+	return results
+}
+
+func (r *MockTransactionRepository) page(txs []*transaction.Transaction, limit, offset int) []*transaction.Transaction {
+	if offset >= len(txs) {
+		return nil
+	}
+	txs = txs[offset:]
+	if limit > 0 && limit < len(txs) {
+		txs = txs[:limit]
+	}
+	return txs
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/domain/transaction"
+)
+
+// createTransactions stores n transactions for user on account through the
+// service, one minute apart with the last created newest
+func createTransactions(t *testing.T, service *transaction.Service, userID, accountID uuid.UUID, n int) []*transaction.Transaction {
+	t.Helper()
+	txs := make([]*transaction.Transaction, n)
+	for i := range txs {
+		tx := transaction.NewTransaction(userID, accountID, transaction.TypePurchase, decimal.NewFromInt(int64(10+i)), transaction.USD)
+		if err := service.CreateTransaction(context.Background(), tx); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		// CreateTransaction stamps the time; spread them out so the order is certain
+		tx.CreatedAt = time.Now().Add(time.Duration(i-n) * time.Minute)
+		txs[i] = tx
+	}
+	return txs
+}
+
+func TestMockTransactionRepositoryCreateAndList(t *testing.T) {
+	repo := NewMockTransactionRepository(0)
+	service := transaction.NewService(repo)
+	userID, accountID := uuid.New(), uuid.New()
+	txs := createTransactions(t, service, userID, accountID, 5)
+	createTransactions(t, service, uuid.New(), uuid.New(), 2) // Someone else's
+
+	got, err := repo.GetByID(context.Background(), txs[0].ID)
+	if err != nil || got.ID != txs[0].ID || got.Status != transaction.StatusPending {
+		t.Fatalf("get %+v, %v; want the pending transaction", got, err)
+	}
+
+	tests := []struct {
+		name   string
+		list   func() ([]*transaction.Transaction, error)
+		wantIx []int // Indexes into txs, in the order listed
+	}{
+		{"by user", func() ([]*transaction.Transaction, error) {
+			return repo.ListByUserID(context.Background(), userID, transaction.ListFilter{}, 0, 0)
+		}, []int{4, 3, 2, 1, 0}},
+		{"by user paged", func() ([]*transaction.Transaction, error) {
+			return repo.ListByUserID(context.Background(), userID, transaction.ListFilter{}, 2, 1)
+		}, []int{3, 2}},
+		{"by account", func() ([]*transaction.Transaction, error) {
+			return repo.ListByAccountID(context.Background(), accountID, 0, 0)
+		}, []int{4, 3, 2, 1, 0}},
+		{"by account past the end", func() ([]*transaction.Transaction, error) {
+			return repo.ListByAccountID(context.Background(), accountID, 10, 5)
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed, err := tt.list()
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(listed) != len(tt.wantIx) {
+				t.Fatalf("%d transactions, want %d", len(listed), len(tt.wantIx))
+			}
+			for i, ix := range tt.wantIx {
+				if listed[i].ID != txs[ix].ID {
+					t.Errorf("position %d is %s, want %s", i, listed[i].ID, txs[ix].ID)
+				}
+			}
+		})
+	}
+
+	count, err := repo.CountByUserID(context.Background(), userID, transaction.ListFilter{})
+	if err != nil || count != 5 {
+		t.Errorf("count %d, %v; want 5", count, err)
+	}
+}
+
+func TestMockTransactionRepositoryEvictsOldest(t *testing.T) {
+	repo := NewMockTransactionRepository(3)
+	txs := createTransactions(t, transaction.NewService(repo), uuid.New(), uuid.New(), 5)
+
+	for i, tx := range txs {
+		_, err := repo.GetByID(context.Background(), tx.ID)
+		if kept := i >= 2; kept != (err == nil) {
+			t.Errorf("transaction %d kept %t, want %t", i, err == nil, kept)
+		}
+	}
+}
+
+func TestMockDecisionRepositoryListByUserIDLimit(t *testing.T) {
+	repo := NewMockDecisionRepository(0)
+	userID := uuid.New()
+	for i := 0; i < fraud.DefaultDecisionListLimit+10; i++ {
+		d := fraud.NewFraudDecision(uuid.New(), userID, fraud.DecisionAllow, decimal.NewFromFloat(0.1))
+		if err := repo.Create(context.Background(), d); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"zero limit uses the default", 0, fraud.DefaultDecisionListLimit},
+		{"negative limit uses the default", -1, fraud.DefaultDecisionListLimit},
+		{"explicit limit", 10, 10},
+		{"limit past the end", 100, fraud.DefaultDecisionListLimit + 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisions, err := repo.ListByUserID(context.Background(), userID, tt.limit, 0)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(decisions) != tt.want {
+				t.Errorf("%d decisions, want %d", len(decisions), tt.want)
+			}
+		})
+	}
+}
+
+// queueCase stores an open case at risk created age ago
+func queueCase(t *testing.T, repo *MockCaseRepository, risk fraud.RiskLevel, age time.Duration) *fraud.FraudCase {
+	t.Helper()
+	c := fraud.NewFraudCase(uuid.New(), uuid.New(), uuid.New(), risk)
+	c.CreatedAt = time.Now().Add(-age)
+	if err := repo.Create(context.Background(), c); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	return c
+}
+
+func TestMockCaseRepositoryAssignNextCase(t *testing.T) {
+	repo := NewMockCaseRepository(0)
+	service := fraud.NewService(nil, repo, nil, nil, nil)
+	ctx := context.Background()
+
+	newHigh := queueCase(t, repo, fraud.RiskLevelHigh, time.Minute)
+	oldHigh := queueCase(t, repo, fraud.RiskLevelHigh, time.Hour)
+	critical := queueCase(t, repo, fraud.RiskLevelCritical, time.Second)
+	low := queueCase(t, repo, fraud.RiskLevelLow, 24*time.Hour)
+
+	for _, want := range []*fraud.FraudCase{critical, oldHigh, newHigh, low} {
+		investigatorID := uuid.New()
+		claimed, err := service.AssignNextCase(ctx, investigatorID)
+		if err != nil {
+			t.Fatalf("claim: %v", err)
+		}
+		if claimed.ID != want.ID {
+			t.Fatalf("claimed the %s case created %s, want the %s case created %s",
+				claimed.RiskLevel, claimed.CreatedAt, want.RiskLevel, want.CreatedAt)
+		}
+		if claimed.AssignedTo == nil || *claimed.AssignedTo != investigatorID || claimed.Status != fraud.CaseStatusInvestigating {
+			t.Errorf("claimed case assigned to %v with status %s, want the investigator and investigating", claimed.AssignedTo, claimed.Status)
+		}
+
+		queue, total, err := service.ListCaseQueue(ctx, 10, 0)
+		if err != nil {
+			t.Fatalf("queue: %v", err)
+		}
+		if int(total) != len(queue) || slices.ContainsFunc(queue, func(c *fraud.FraudCase) bool { return c.ID == claimed.ID }) {
+			t.Errorf("claimed case still queued, or total %d for %d queued cases", total, len(queue))
+		}
+	}
+
+	if _, err := service.AssignNextCase(ctx, uuid.New()); err != fraud.ErrCaseQueueEmpty {
+		t.Errorf("claim from an empty queue: error %v, want %v", err, fraud.ErrCaseQueueEmpty)
+	}
+}
+
+func TestMockCaseRepositoryConcurrentClaims(t *testing.T) {
+	const cases, claimers = 50, 8
+	repo := NewMockCaseRepository(0)
+	for i := 0; i < cases; i++ {
+		queueCase(t, repo, fraud.RiskLevelHigh, time.Duration(i)*time.Minute)
+	}
+
+	var mu sync.Mutex
+	claimedBy := make(map[uuid.UUID]int)
+	var wg sync.WaitGroup
+	for i := 0; i < claimers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				claimed, err := repo.ClaimNext(context.Background(), uuid.New())
+				if err == fraud.ErrCaseQueueEmpty {
+					return
+				}
+				if err != nil {
+					t.Errorf("claim: %v", err)
+					return
+				}
+				mu.Lock()
+				claimedBy[claimed.ID]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(claimedBy) != cases {
+		t.Errorf("%d cases claimed, want %d", len(claimedBy), cases)
+	}
+	for id, n := range claimedBy {
+		if n != 1 {
+			t.Errorf("case %s claimed %d times", id, n)
+		}
+	}
+}
+
+// benchmarkUsers is the pool of users the repository benchmarks spread their writes over
+func benchmarkUsers(n int) []uuid.UUID {
+	users := make([]uuid.UUID, n)
+	for i := range users {
+		users[i] = uuid.New()
+	}
+	return users
+}
+
+func BenchmarkMockDecisionRepository(b *testing.B) {
+	repo := NewMockDecisionRepository(1000)
+	users := benchmarkUsers(50)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			userID := users[i%len(users)]
+			decision := fraud.NewFraudDecision(uuid.New(), userID, fraud.DecisionAllow, decimal.NewFromFloat(0.2))
+			if err := repo.Create(ctx, decision); err != nil {
+				b.Errorf("create: %v", err)
+				return
+			}
+			if _, err := repo.GetByTransactionID(ctx, decision.TransactionID); err != nil && err != fraud.ErrDecisionNotFound {
+				b.Errorf("get: %v", err)
+				return
+			}
+			if _, err := repo.ListByUserID(ctx, userID, 20, 0); err != nil {
+				b.Errorf("list: %v", err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkMockCaseRepository(b *testing.B) {
+	repo := NewMockCaseRepository(1000)
+	users := benchmarkUsers(50)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			userID := users[i%len(users)]
+			fraudCase := fraud.NewFraudCase(uuid.New(), userID, uuid.New(), fraud.RiskLevelHigh)
+			if err := repo.Create(ctx, fraudCase); err != nil {
+				b.Errorf("create: %v", err)
+				return
+			}
+			if _, err := repo.GetOpenCasesByUser(ctx, userID); err != nil {
+				b.Errorf("open cases: %v", err)
+				return
+			}
+			if _, err := repo.ListByStatus(ctx, fraud.CaseStatusOpen, 20, 0); err != nil {
+				b.Errorf("list: %v", err)
+				return
+			}
+		}
+	})
+}
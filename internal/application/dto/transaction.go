@@ -85,8 +85,36 @@ type MerchantDTO struct {
 type PaymentDTO struct {
 	Type string `json:"type"` // car, bank_transfer, wallet
 	Last4 string `json:"last4,omitempty"`
+	BIN string `json:"bin,omitempty"` // First 6 digits
 	CardType string `json:"card_type,omitempty"`
 	BankID string `json:"bank_id,omitempty"`
 	IssuingCountry string `json:"issuing_country,omitempty"`
 }
 
+// UserTransaction is a stored transaction listed with the fraud decision made on it
+type UserTransaction struct {
+	ID          uuid.UUID        `json:"id"`
+	ExternalID  string           `json:"external_id"`
+	AccountID   uuid.UUID        `json:"account_id"`
+	Type        string           `json:"type"`
+	Status      string           `json:"status"`
+	Amount      decimal.Decimal  `json:"amount"`
+	Currency    string           `json:"currency"`
+	FraudScore  *decimal.Decimal `json:"fraud_score,omitempty"`
+	RiskLevel   string           `json:"risk_level,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	ProcessedAt *time.Time       `json:"processed_at,omitempty"`
+
+	Decision *TransactionDecision `json:"decision,omitempty"` // Nil when the transaction was never scored
+}
+
+// TransactionDecision summarizes the fraud decision on a transaction
+type TransactionDecision struct {
+	ID         uuid.UUID       `json:"id"`
+	Decision   string          `json:"decision"`
+	Score      decimal.Decimal `json:"score"`
+	RiskLevel  string          `json:"risk_level"`
+	RulesFired []string        `json:"rules_fired"`
+	Reasons    []string        `json:"reasons"`
+	DecidedAt  time.Time       `json:"decided_at"`
+}
@@ -2,16 +2,21 @@ package fraud
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	"golang.org/x/sync/errgroup"
 
 	"fraud-detecction-system/internal/domain/fraud"
 	"fraud-detecction-system/internal/infrastructure/cache/redis"
 	"fraud-detecction-system/internal/infrastructure/ml"
 	"fraud-detecction-system/internal/infrastructure/rules"
+	"fraud-detecction-system/internal/pkg/logger"
 )
 
 // DetectFraudInput contains the input for fraud detection
@@ -22,6 +27,10 @@ type DetectFraudInput struct {
 	Amount        decimal.Decimal
 	Currency      string
 	Timestamp     time.Time
+	TenantID      string // Set from the authenticated caller, never from the request body
+
+	// Currency to restate the amount in on the response; the stored amount is never converted
+	ReportCurrency string
 
 	// Optional context data
 	Location *fraud.GeoLocation
@@ -32,39 +41,105 @@ type DetectFraudInput struct {
 
 // DetectFraudOutput contains the fraud detection result
 type DetectFraudOutput struct {
-	Decision        fraud.DecisionType  `json:"decision"`
-	Score           decimal.Decimal     `json:"score"`
-	RiskLevel       fraud.RiskLevel     `json:"risk_level"`
-	Confidence      decimal.Decimal     `json:"confidence"`
-	RulesFired      []string            `json:"rules_fired"`
-	Reasons         []string            `json:"reasons"`
-	ModelVersion    string              `json:"model_version,omitempty"`
-	LatencyMs       int64               `json:"latency_ms"`
-	ShouldBlock     bool                `json:"should_block"`
-	RequiresReview  bool                `json:"requires_review"`
+	TransactionID     uuid.UUID                `json:"transaction_id"`
+	Decision          fraud.DecisionType       `json:"decision"`
+	Score             decimal.Decimal          `json:"score"`
+	RiskLevel         fraud.RiskLevel          `json:"risk_level"`
+	Confidence        decimal.Decimal          `json:"confidence"`
+	ConfidenceFactors *fraud.ConfidenceFactors `json:"confidence_factors,omitempty"`
+	DataCoverage      *decimal.Decimal         `json:"data_coverage,omitempty"`
+	RulesFired        []string                 `json:"rules_fired"`
+	ShadowRulesFired  []string                 `json:"shadow_rules_fired,omitempty"`
+	Reasons           []string                 `json:"reasons"`
+	ModelVersion      string                   `json:"model_version,omitempty"`
+	LatencyMs         int64                    `json:"latency_ms"`
+	ShouldBlock       bool                     `json:"should_block"`
+	RequiresReview    bool                     `json:"requires_review"`
+	DowngradedFrom    fraud.DecisionType       `json:"downgraded_from,omitempty"`
+	Degraded          bool                     `json:"degraded"`
+	DegradedReason    string                   `json:"degraded_reason,omitempty"`
+
+	// Set when the request asked for a report currency
+	ReportCurrency *ReportCurrencyAmount `json:"report_currency,omitempty"`
+
+	// Set on batch results when decisions are signed; single responses use a header instead
+	Signature string `json:"signature,omitempty"`
+}
+
+// ReportCurrencyAmount restates the transaction amount in the caller's reporting currency
+// The base amount is what the rule engine compared against its amount thresholds.
+// Only the amount is converted; figures in reasons name their own currency.
+type ReportCurrencyAmount struct {
+	Currency         string          `json:"currency"`
+	Amount           decimal.Decimal `json:"amount"`
+	OriginalCurrency string          `json:"original_currency"`
+	OriginalAmount   decimal.Decimal `json:"original_amount"`
+	BaseCurrency     string          `json:"base_currency"`
+	BaseAmount       decimal.Decimal `json:"base_amount"`
+}
+
+// ErrUnsupportedReportCurrency is returned when the amount can't be converted to the report currency
+var ErrUnsupportedReportCurrency = errors.New("unsupported report currency")
+
+// RuleEngine is the rule engine as the use case needs it: the domain engine plus
+// the currency conversion it uses for card testing counters and report amounts
+type RuleEngine interface {
+	fraud.RuleEngine
+	ToBaseCurrency(ctx context.Context, amount decimal.Decimal, currency string) (decimal.Decimal, error)
+	ConvertCurrency(ctx context.Context, amount decimal.Decimal, from, to string) (decimal.Decimal, error)
+	BaseCurrency() string
+}
+
+// MLPredictor scores a transaction with the ML model
+type MLPredictor interface {
+	Predict(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) (*ml.PredictionResult, error)
+	IsEnabled() bool
 }
 
+var (
+	_ RuleEngine  = (*rules.Engine)(nil)
+	_ MLPredictor = (*ml.Predictor)(nil)
+)
+
 // DetectFraudUseCase handles fraud detection for transactions
 type DetectFraudUseCase struct {
 	fraudService  *fraud.Service
-	ruleEngine    *rules.Engine
-	mlPredictor   *ml.Predictor
+	ruleEngine    RuleEngine
+	mlPredictor   MLPredictor
 	velocityCache *redis.VelocityCache
 	deviceCache   *redis.DeviceCache
 	locationCache *redis.LocationCache
+	merchantCache *redis.MerchantCache
+
+	// Optional card testing counters
+	cardTestingCache *redis.CardTestingCache
+
+	// Optional users per device, IP and card
+	linkingCache *redis.LinkingCache
 
 	// Config
-	analysisTimeout time.Duration
+	analysisTimeout     time.Duration
+	recentHistoryWindow time.Duration
+	batchConcurrency    int
+
+	logger *slog.Logger
 }
 
+// defaultRecentHistoryWindow matches how long the velocity cache keeps entries
+const defaultRecentHistoryWindow = 24 * time.Hour
+
+// defaultBatchConcurrency is how many transactions of a batch are analyzed at once
+const defaultBatchConcurrency = 8
+
 // NewDetectFraudUseCase creates a new detect fraud use case
 func NewDetectFraudUseCase(
 	fraudService *fraud.Service,
-	ruleEngine *rules.Engine,
-	mlPredictor *ml.Predictor,
+	ruleEngine RuleEngine,
+	mlPredictor MLPredictor,
 	velocityCache *redis.VelocityCache,
 	deviceCache *redis.DeviceCache,
 	locationCache *redis.LocationCache,
+	merchantCache *redis.MerchantCache,
 	analysisTimeout time.Duration,
 ) *DetectFraudUseCase {
 	return &DetectFraudUseCase{
@@ -74,10 +149,43 @@ func NewDetectFraudUseCase(
 		velocityCache:   velocityCache,
 		deviceCache:     deviceCache,
 		locationCache:   locationCache,
+		merchantCache:   merchantCache,
 		analysisTimeout: analysisTimeout,
+
+		recentHistoryWindow: defaultRecentHistoryWindow,
+		batchConcurrency:    defaultBatchConcurrency,
+		logger:              slog.Default(),
 	}
 }
 
+// SetLogger sets the logger used to report degraded analyses
+func (uc *DetectFraudUseCase) SetLogger(log *slog.Logger) {
+	uc.logger = log
+}
+
+// SetCardTestingCache sets the cache card attempts are recorded in
+func (uc *DetectFraudUseCase) SetCardTestingCache(cache *redis.CardTestingCache) {
+	uc.cardTestingCache = cache
+}
+
+// SetLinkingCache sets the cache the users of each device, IP and card are recorded in
+func (uc *DetectFraudUseCase) SetLinkingCache(cache *redis.LinkingCache) {
+	uc.linkingCache = cache
+}
+
+// SetBatchConcurrency sets how many transactions of a batch are analyzed at once
+// Values below 1 analyze one at a time
+func (uc *DetectFraudUseCase) SetBatchConcurrency(n int) {
+	uc.batchConcurrency = max(n, 1)
+}
+
+// SetRecentHistoryWindow sets how much velocity history is loaded once per analysis
+// Velocity rules with windows inside it reuse that history instead of querying Redis
+// themselves; zero skips the preload so every rule queries the cache
+func (uc *DetectFraudUseCase) SetRecentHistoryWindow(window time.Duration) {
+	uc.recentHistoryWindow = window
+}
+
 // Execute performs fraud detection on a transaction
 func (uc *DetectFraudUseCase) Execute(ctx context.Context, input DetectFraudInput) (*DetectFraudOutput, error) {
 	startTime := time.Now()
@@ -86,23 +194,21 @@ func (uc *DetectFraudUseCase) Execute(ctx context.Context, input DetectFraudInpu
 	ctx, cancel := context.WithTimeout(ctx, uc.analysisTimeout)
 	defer cancel()
 
-	// Build evaluation context
-	evalCtx := &fraud.RuleEvaluationContext{
-		TransactionID: input.TransactionID,
-		UserID:        input.UserID,
-		AccountID:     input.AccountID,
-		Amount:        input.Amount,
-		Currency:      input.Currency,
-		Timestamp:     input.Timestamp,
-		Location:      input.Location,
-		Device:        input.Device,
-		Merchant:      input.Merchant,
-		Payment:       input.Payment,
+	// Convert up front so an unsupported currency fails before anything is recorded
+	reportAmount, err := uc.reportCurrencyAmount(ctx, input)
+	if err != nil {
+		return nil, err
 	}
 
-	// Enrich context with historical data
-	if err := uc.enrichContext(ctx, evalCtx); err != nil {
-		// Log error but continue - we can still evaluate with available data
+	// Build evaluation context with historical data
+	evalCtx := uc.buildEvaluationContext(ctx, input)
+
+	// Score with the ML model alongside the rules when enabled
+	// The service runs it after the rules, so an early stop can skip it
+	if uc.mlPredictor != nil {
+		evalCtx.ScoreML = func(ctx context.Context) *fraud.MLScore {
+			return uc.predictML(ctx, evalCtx)
+		}
 	}
 
 	// Run fraud analysis through the service
@@ -115,39 +221,176 @@ func (uc *DetectFraudUseCase) Execute(ctx context.Context, input DetectFraudInpu
 	go func() {
 		bgCtx := context.Background()
 		if uc.velocityCache != nil {
-			uc.velocityCache.RecordTransaction(bgCtx, input.UserID, input.TransactionID, input.Amount, input.Timestamp)
+			// Store amounts in the base currency so velocity sums compare across currencies
+			amount, err := uc.ruleEngine.ToBaseCurrency(bgCtx, input.Amount, input.Currency)
+			if err != nil {
+				amount = input.Amount
+			}
+			uc.velocityCache.RecordTransaction(bgCtx, input.UserID, input.TransactionID, amount, input.Timestamp)
+		}
+		if uc.cardTestingCache != nil {
+			if scope := rules.CardTestingScope(input.UserID, input.Payment); scope != "" {
+				amount, err := uc.ruleEngine.ToBaseCurrency(bgCtx, input.Amount, input.Currency)
+				if err != nil {
+					amount = input.Amount
+				}
+				uc.cardTestingCache.RecordAttempt(bgCtx, scope, input.TransactionID, amount, input.Timestamp)
+			}
+		}
+		if uc.linkingCache != nil {
+			for attribute, value := range rules.LinkedAttributes(input.Device, input.Location, input.Payment) {
+				uc.linkingCache.RecordLink(bgCtx, string(attribute), value, input.UserID, input.Timestamp)
+			}
 		}
 		if uc.deviceCache != nil && input.Device != nil {
 			uc.deviceCache.RecordDeviceUsage(bgCtx, input.UserID, input.Device.DeviceID)
 		}
 		if uc.locationCache != nil && input.Location != nil {
-			uc.locationCache.RecordLocation(bgCtx, input.UserID, input.Location.Country, input.Location.City)
+			uc.locationCache.RecordLocation(bgCtx, input.UserID, input.Location.Country, input.Location.Region, input.Location.City)
+			if fraud.ValidCoordinates(input.Location.Latitude, input.Location.Longitude) {
+				uc.locationCache.RecordLocationPoint(bgCtx, input.UserID, redis.LocationPoint{
+					TransactionID: input.TransactionID,
+					Country:       input.Location.Country,
+					City:          input.Location.City,
+					Latitude:      input.Location.Latitude,
+					Longitude:     input.Location.Longitude,
+					Timestamp:     input.Timestamp,
+				})
+			}
+		}
+		if uc.merchantCache != nil && input.Merchant != nil && input.Merchant.MerchantID != "" {
+			uc.merchantCache.RecordMerchant(bgCtx, input.UserID, input.Merchant.MerchantID, input.Timestamp)
 		}
 	}()
 
-	// Build output
-	output := &DetectFraudOutput{
-		Decision:       decision.Decision,
-		Score:          decision.Score,
-		RiskLevel:      decision.RiskLevel,
-		Confidence:     decision.Confidence,
-		RulesFired:     decision.RulesFired,
-		Reasons:        decision.Reasons,
-		ModelVersion:   decision.ModelVersion,
-		LatencyMs:      time.Since(startTime).Milliseconds(),
-		ShouldBlock:    decision.ShouldBlock(),
-		RequiresReview: decision.RequiresReview(),
+	return buildOutput(input, decision, startTime, reportAmount), nil
+}
+
+// buildOutput converts a decision into the use case's output
+func buildOutput(input DetectFraudInput, decision *fraud.FraudDecision, startTime time.Time, reportAmount *ReportCurrencyAmount) *DetectFraudOutput {
+	return &DetectFraudOutput{
+		TransactionID:     input.TransactionID,
+		Decision:          decision.Decision,
+		Score:             decision.Score,
+		RiskLevel:         decision.RiskLevel,
+		Confidence:        decision.Confidence,
+		ConfidenceFactors: decision.ConfidenceFactors,
+		DataCoverage:      decision.DataCoverage,
+		RulesFired:        decision.RulesFired,
+		ShadowRulesFired:  decision.ShadowRulesFired,
+		Reasons:           decision.Reasons,
+		ModelVersion:      decision.ModelVersion,
+		LatencyMs:         time.Since(startTime).Milliseconds(),
+		ShouldBlock:       decision.ShouldBlock(),
+		RequiresReview:    decision.RequiresReview(),
+		DowngradedFrom:    decision.DowngradedFrom,
+		Degraded:          decision.Degraded,
+		DegradedReason:    decision.DegradedReason,
+		ReportCurrency:    reportAmount,
+	}
+}
+
+// reportCurrencyAmount converts the transaction amount to the requested report currency
+// It returns nil when no report currency was requested
+func (uc *DetectFraudUseCase) reportCurrencyAmount(ctx context.Context, input DetectFraudInput) (*ReportCurrencyAmount, error) {
+	if input.ReportCurrency == "" {
+		return nil, nil
 	}
 
-	return output, nil
+	amount, err := uc.ruleEngine.ConvertCurrency(ctx, input.Amount, input.Currency, input.ReportCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrUnsupportedReportCurrency, input.ReportCurrency, err)
+	}
+	baseAmount, err := uc.ruleEngine.ToBaseCurrency(ctx, input.Amount, input.Currency)
+	if err != nil {
+		baseAmount = input.Amount
+	}
+
+	return &ReportCurrencyAmount{
+		Currency:         strings.ToUpper(input.ReportCurrency),
+		Amount:           amount.Round(2),
+		OriginalCurrency: input.Currency,
+		OriginalAmount:   input.Amount,
+		BaseCurrency:     uc.ruleEngine.BaseCurrency(),
+		BaseAmount:       baseAmount.Round(2),
+	}, nil
+}
+
+// TestRule evaluates a single candidate rule against a transaction without side effects
+// No decision is persisted and no velocity, device, location or merchant history is recorded
+func (uc *DetectFraudUseCase) TestRule(ctx context.Context, rule *fraud.Rule, input DetectFraudInput) (*fraud.RuleResult, error) {
+	// Apply timeout
+	ctx, cancel := context.WithTimeout(ctx, uc.analysisTimeout)
+	defer cancel()
+
+	evalCtx := uc.buildEvaluationContext(ctx, input)
+
+	result, err := uc.ruleEngine.EvaluateRule(ctx, rule, evalCtx)
+	if err != nil {
+		return nil, fmt.Errorf("rule evaluation failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// predictML runs the ML model, returning nil when it is disabled or fails
+func (uc *DetectFraudUseCase) predictML(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) *fraud.MLScore {
+	prediction, err := uc.mlPredictor.Predict(ctx, evalCtx)
+	if err != nil {
+		// Log error but continue - rules alone still produce a decision
+		uc.logger.WarnContext(ctx, "ML prediction failed",
+			slog.String(logger.KeyTransactionID, evalCtx.TransactionID.String()),
+			logger.Err(err),
+		)
+		return nil
+	}
+	if !prediction.Enabled {
+		return nil
+	}
+	return &fraud.MLScore{
+		Score:         prediction.Score,
+		Confidence:    prediction.Confidence,
+		ModelVersion:  prediction.ModelVersion,
+		FeatureVector: prediction.FeatureVector,
+	}
+}
+
+// buildEvaluationContext creates the rule evaluation context for a transaction
+// It only reads from the caches, so it is safe to use for dry runs
+func (uc *DetectFraudUseCase) buildEvaluationContext(ctx context.Context, input DetectFraudInput) *fraud.RuleEvaluationContext {
+	evalCtx := &fraud.RuleEvaluationContext{
+		TransactionID: input.TransactionID,
+		UserID:        input.UserID,
+		AccountID:     input.AccountID,
+		Amount:        input.Amount,
+		Currency:      input.Currency,
+		Timestamp:     input.Timestamp,
+		TenantID:      input.TenantID,
+		Location:      input.Location,
+		Device:        input.Device,
+		Merchant:      input.Merchant,
+		Payment:       input.Payment,
+	}
+
+	// Enrich context with historical data
+	if err := uc.enrichContext(ctx, evalCtx); err != nil {
+		// Log error but continue - we can still evaluate with available data
+		uc.logger.WarnContext(ctx, "failed to load transaction history",
+			slog.String(logger.KeyTransactionID, input.TransactionID.String()),
+			logger.Err(err),
+		)
+	}
+
+	return evalCtx
 }
 
 // enrichContext adds historical data to the evaluation context
 func (uc *DetectFraudUseCase) enrichContext(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) error {
-	// Get recent transactions from cache
-	if uc.velocityCache != nil {
-		records, err := uc.velocityCache.GetRecentTransactions(ctx, evalCtx.UserID, 24*time.Hour)
+	// Get recent transactions from cache, once for the whole analysis
+	if uc.velocityCache != nil && uc.recentHistoryWindow > 0 {
+		records, err := uc.velocityCache.GetRecentTransactions(ctx, evalCtx.UserID, uc.recentHistoryWindow)
 		if err == nil {
+			evalCtx.RecentWindow = uc.recentHistoryWindow
 			evalCtx.RecentTransactions = make([]fraud.TransactionSummary, len(records))
 			for i, r := range records {
 				evalCtx.RecentTransactions[i] = fraud.TransactionSummary{
@@ -159,6 +402,8 @@ func (uc *DetectFraudUseCase) enrichContext(ctx context.Context, evalCtx *fraud.
 		}
 	}
 
+	evalCtx.Known = uc.lookupKnownHistory(ctx, evalCtx)
+
 	// Build basic user profile from available data
 	if evalCtx.UserProfile == nil {
 		evalCtx.UserProfile = &fraud.UserProfile{
@@ -171,19 +416,74 @@ func (uc *DetectFraudUseCase) enrichContext(ctx context.Context, evalCtx *fraud.
 	return nil
 }
 
+// lookupKnownHistory asks the caches once whether the user has used this device, location and merchant
+// Without it every device rule, geographic rule and the ML features would each ask again.
+// Confidence signals always read the device and country; the device count, city and
+// merchant are looked up only when an active rule type or the ML features read them.
+// Rules ask the caches themselves for anything left unset.
+func (uc *DetectFraudUseCase) lookupKnownHistory(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) *fraud.KnownHistory {
+	mlEnabled := uc.mlPredictor != nil && uc.mlPredictor.IsEnabled()
+	ruleTypes := uc.activeRuleTypes(ctx)
+
+	known := &fraud.KnownHistory{}
+	if uc.deviceCache != nil {
+		if mlEnabled || ruleTypes[fraud.RuleTypeDevice] {
+			if count, err := uc.deviceCache.GetDeviceCount(ctx, evalCtx.UserID); err == nil {
+				known.DeviceCount = &count
+			}
+		}
+		if evalCtx.Device != nil && evalCtx.Device.DeviceID != "" {
+			known.Device = knownFlag(uc.deviceCache.IsKnownDevice(ctx, evalCtx.UserID, evalCtx.Device.DeviceID))
+		}
+	}
+	if uc.locationCache != nil && evalCtx.Location != nil && evalCtx.Location.Country != "" {
+		if ruleTypes[fraud.RuleTypeGeographic] {
+			known.Location = knownFlag(uc.locationCache.IsKnownLocation(ctx, evalCtx.UserID, evalCtx.Location.Country, evalCtx.Location.City))
+		}
+		known.Country = knownFlag(uc.locationCache.IsKnownCountry(ctx, evalCtx.UserID, evalCtx.Location.Country))
+	}
+	if uc.merchantCache != nil && ruleTypes[fraud.RuleTypeMerchant] && evalCtx.Merchant != nil && evalCtx.Merchant.MerchantID != "" {
+		known.Merchant = knownFlag(uc.merchantCache.IsKnownMerchant(ctx, evalCtx.UserID, evalCtx.Merchant.MerchantID))
+	}
+	return known
+}
+
+// activeRuleTypes returns the types of the active rules, from the engine's rule cache
+// A failed load returns none, leaving the lookups to the rules
+func (uc *DetectFraudUseCase) activeRuleTypes(ctx context.Context) map[fraud.RuleType]bool {
+	rules, err := uc.ruleEngine.GetActiveRules(ctx)
+	if err != nil {
+		return nil
+	}
+	types := make(map[fraud.RuleType]bool, len(rules))
+	for _, rule := range rules {
+		types[rule.Type] = true
+	}
+	return types
+}
+
+// knownFlag keeps a cache answer, or nil when the lookup failed
+func knownFlag(known bool, err error) *bool {
+	if err != nil {
+		return nil
+	}
+	return &known
+}
+
 // AnalyzeTransactionRequest is the API request structure
 type AnalyzeTransactionRequest struct {
-	TransactionID string  `json:"transaction_id" validate:"required,uuid"`
-	UserID        string  `json:"user_id" validate:"required,uuid"`
-	AccountID     string  `json:"account_id" validate:"required,uuid"`
-	Amount        string  `json:"amount" validate:"required"`
-	Currency      string  `json:"currency" validate:"required,len=3"`
+	TransactionID string `json:"transaction_id" validate:"omitempty,uuid"` // Required unless the deployment generates missing IDs
+	UserID        string `json:"user_id" validate:"required,uuid"`
+	AccountID     string `json:"account_id" validate:"required,uuid"`
+	Amount        string `json:"amount" validate:"required"`
+	Currency      string `json:"currency" validate:"required,len=3"`
 
 	// Optional
-	Location *LocationRequest `json:"location,omitempty"`
-	Device   *DeviceRequest   `json:"device,omitempty"`
-	Merchant *MerchantRequest `json:"merchant,omitempty"`
-	Payment  *PaymentRequest  `json:"payment,omitempty"`
+	ReportCurrency string           `json:"report_currency,omitempty"` // Echo the amount converted to this currency
+	Location       *LocationRequest `json:"location,omitempty"`
+	Device         *DeviceRequest   `json:"device,omitempty"`
+	Merchant       *MerchantRequest `json:"merchant,omitempty"`
+	Payment        *PaymentRequest  `json:"payment,omitempty"`
 }
 
 // LocationRequest represents location data in API request
@@ -219,6 +519,7 @@ type MerchantRequest struct {
 type PaymentRequest struct {
 	Type           string `json:"type"` // card, bank_account, wallet
 	Last4          string `json:"last4"`
+	BIN            string `json:"bin,omitempty"` // First 6 digits
 	Network        string `json:"network"`
 	BankID         string `json:"bank_id,omitempty"`
 	IssuingCountry string `json:"issuing_country"`
@@ -226,6 +527,10 @@ type PaymentRequest struct {
 
 // ToInput converts the API request to use case input
 func (r *AnalyzeTransactionRequest) ToInput() (*DetectFraudInput, error) {
+	if r.TransactionID == "" {
+		return nil, errors.New("transaction_id is required")
+	}
+
 	txID, err := uuid.Parse(r.TransactionID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid transaction_id: %w", err)
@@ -247,12 +552,13 @@ func (r *AnalyzeTransactionRequest) ToInput() (*DetectFraudInput, error) {
 	}
 
 	input := &DetectFraudInput{
-		TransactionID: txID,
-		UserID:        userID,
-		AccountID:     accountID,
-		Amount:        amount,
-		Currency:      r.Currency,
-		Timestamp:     time.Now(),
+		TransactionID:  txID,
+		UserID:         userID,
+		AccountID:      accountID,
+		Amount:         amount,
+		Currency:       r.Currency,
+		Timestamp:      time.Now(),
+		ReportCurrency: r.ReportCurrency,
 	}
 
 	// Convert optional fields
@@ -293,6 +599,7 @@ func (r *AnalyzeTransactionRequest) ToInput() (*DetectFraudInput, error) {
 		input.Payment = &fraud.PaymentMethod{
 			Type:           r.Payment.Type,
 			Last4:          r.Payment.Last4,
+			BIN:            r.Payment.BIN,
 			Network:        r.Payment.Network,
 			BankID:         r.Payment.BankID,
 			IssuingCountry: r.Payment.IssuingCountry,
@@ -315,36 +622,44 @@ type BatchAnalyzeOutput struct {
 
 // BatchSummary summarizes batch analysis results
 type BatchSummary struct {
-	Total     int   `json:"total"`
-	Allowed   int   `json:"allowed"`
-	Blocked   int   `json:"blocked"`
-	Review    int   `json:"review"`
-	Challenge int   `json:"challenge"`
+	Total        int   `json:"total"`
+	Allowed      int   `json:"allowed"`
+	Blocked      int   `json:"blocked"`
+	Review       int   `json:"review"`
+	Challenge    int   `json:"challenge"`
 	AvgLatencyMs int64 `json:"avg_latency_ms"`
 }
 
 // ExecuteBatch performs fraud detection on multiple transactions
+// Up to batchConcurrency transactions are analyzed at once, each under its own
+// analysis timeout. Results keep the order of the input. Transactions not yet
+// started when ctx ends are reported as analysis errors.
 func (uc *DetectFraudUseCase) ExecuteBatch(ctx context.Context, input BatchAnalyzeInput) (*BatchAnalyzeOutput, error) {
-	results := make([]DetectFraudOutput, len(input.Transactions))
-	summary := BatchSummary{Total: len(input.Transactions)}
-	var totalLatency int64
+	// Reject the whole batch before analyzing anything if a report currency can't be served
+	for _, tx := range input.Transactions {
+		if _, err := uc.reportCurrencyAmount(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	existing := uc.existingDecisions(ctx, input.Transactions)
 
+	// Each goroutine writes only its own index, so results need no locking
+	results := make([]DetectFraudOutput, len(input.Transactions))
+	var g errgroup.Group
+	g.SetLimit(max(uc.batchConcurrency, 1))
 	for i, tx := range input.Transactions {
-		result, err := uc.Execute(ctx, tx)
-		if err != nil {
-			// Record error but continue with other transactions
-			results[i] = DetectFraudOutput{
-				Decision:  fraud.DecisionReview,
-				RiskLevel: fraud.RiskLevelHigh,
-				Reasons:   []string{"Analysis error: " + err.Error()},
-			}
-			summary.Review++
-			continue
-		}
+		g.Go(func() error {
+			results[i] = uc.executeBatchItem(ctx, tx, existing[tx.TransactionID])
+			return nil
+		})
+	}
+	g.Wait()
 
-		results[i] = *result
+	summary := BatchSummary{Total: len(input.Transactions)}
+	var totalLatency int64
+	for _, result := range results {
 		totalLatency += result.LatencyMs
-
 		switch result.Decision {
 		case fraud.DecisionAllow:
 			summary.Allowed++
@@ -366,3 +681,48 @@ func (uc *DetectFraudUseCase) ExecuteBatch(ctx context.Context, input BatchAnaly
 		Summary: summary,
 	}, nil
 }
+
+// executeBatchItem analyzes one transaction of a batch
+// A failed analysis is reported as a review result so the rest of the batch still completes
+func (uc *DetectFraudUseCase) executeBatchItem(ctx context.Context, tx DetectFraudInput, existing *fraud.FraudDecision) DetectFraudOutput {
+	if existing != nil {
+		// A retried transaction gets its first decision without being analyzed or recorded again
+		reportAmount, _ := uc.reportCurrencyAmount(ctx, tx)
+		return *buildOutput(tx, existing, time.Now(), reportAmount)
+	}
+
+	err := ctx.Err()
+	var result *DetectFraudOutput
+	if err == nil {
+		result, err = uc.Execute(ctx, tx)
+	}
+	if err != nil {
+		return DetectFraudOutput{
+			TransactionID: tx.TransactionID,
+			Decision:      fraud.DecisionReview,
+			RiskLevel:     fraud.RiskLevelHigh,
+			Reasons:       []string{"Analysis error: " + err.Error()},
+		}
+	}
+	return *result
+}
+
+// existingDecisions looks up the decisions already stored for a batch's transactions in one call
+// A failed lookup is logged and the batch is analyzed as usual; each analysis
+// still checks for its own earlier decision
+func (uc *DetectFraudUseCase) existingDecisions(ctx context.Context, txs []DetectFraudInput) map[uuid.UUID]*fraud.FraudDecision {
+	ids := make([]uuid.UUID, len(txs))
+	for i, tx := range txs {
+		ids[i] = tx.TransactionID
+	}
+
+	decisions, err := uc.fraudService.GetDecisionsByTransactionIDs(ctx, ids)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "failed to look up existing decisions for batch",
+			slog.Int("transactions", len(ids)),
+			logger.Err(err),
+		)
+		return nil
+	}
+	return decisions
+}
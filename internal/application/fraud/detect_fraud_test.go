@@ -0,0 +1,116 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/infrastructure/rules"
+)
+
+// memoryDecisionRepo keeps decisions by transaction ID; only the methods analysis uses are implemented
+type memoryDecisionRepo struct {
+	fraud.DecisionRepository
+	decisions map[uuid.UUID]*fraud.FraudDecision
+}
+
+func (r *memoryDecisionRepo) Create(ctx context.Context, decision *fraud.FraudDecision) error {
+	r.decisions[decision.TransactionID] = decision
+	return nil
+}
+
+func (r *memoryDecisionRepo) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*fraud.FraudDecision, error) {
+	if d, ok := r.decisions[transactionID]; ok {
+		return d, nil
+	}
+	return nil, fraud.ErrDecisionNotFound
+}
+
+// noRules is a rule repository with no active rules
+type noRules struct {
+	fraud.RuleRepository
+}
+
+func (noRules) ListActive(ctx context.Context) ([]*fraud.Rule, error) {
+	return nil, nil
+}
+
+// newReportCurrencyUseCase returns a use case converting between USD, EUR and GBP
+func newReportCurrencyUseCase() (*DetectFraudUseCase, *memoryDecisionRepo) {
+	converter := rules.NewStaticCurrencyConverter("USD", map[string]decimal.Decimal{
+		"EUR": decimal.RequireFromString("1.10"),
+		"GBP": decimal.RequireFromString("1.25"),
+	})
+	engine := rules.NewEngine(noRules{}, nil, nil, nil, nil)
+	engine.SetCurrencyConverter(converter, "USD")
+
+	decisions := &memoryDecisionRepo{decisions: make(map[uuid.UUID]*fraud.FraudDecision)}
+	service := fraud.NewService(decisions, nil, nil, engine, nil)
+	service.SetCurrencyConverter(converter, "USD")
+	return NewDetectFraudUseCase(service, engine, nil, nil, nil, nil, nil, time.Second), decisions
+}
+
+func TestExecuteReportCurrency(t *testing.T) {
+	tests := []struct {
+		name           string
+		reportCurrency string
+		want           *ReportCurrencyAmount
+		wantErr        error
+	}{
+		{"no report currency", "", nil, nil},
+		{"other currency", "gbp", &ReportCurrencyAmount{Currency: "GBP", Amount: decimal.NewFromInt(88), OriginalCurrency: "EUR", OriginalAmount: decimal.NewFromInt(100), BaseCurrency: "USD", BaseAmount: decimal.NewFromInt(110)}, nil},
+		{"same currency", "EUR", &ReportCurrencyAmount{Currency: "EUR", Amount: decimal.NewFromInt(100), OriginalCurrency: "EUR", OriginalAmount: decimal.NewFromInt(100), BaseCurrency: "USD", BaseAmount: decimal.NewFromInt(110)}, nil},
+		{"unsupported currency", "JPY", nil, ErrUnsupportedReportCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, decisions := newReportCurrencyUseCase()
+			input := DetectFraudInput{
+				TransactionID:  uuid.New(),
+				UserID:         uuid.New(),
+				Amount:         decimal.NewFromInt(100),
+				Currency:       "EUR",
+				Timestamp:      time.Now(),
+				ReportCurrency: tt.reportCurrency,
+			}
+
+			output, err := uc.Execute(context.Background(), input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err %v, want %v", err, tt.wantErr)
+			}
+			if !input.Amount.Equal(decimal.NewFromInt(100)) || input.Currency != "EUR" {
+				t.Errorf("input amount changed to %s %s", input.Amount, input.Currency)
+			}
+			if tt.wantErr != nil {
+				if len(decisions.decisions) != 0 {
+					t.Error("decision stored for a rejected report currency")
+				}
+				return
+			}
+
+			got := output.ReportCurrency
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("report currency %+v, want none", got)
+			case tt.want != nil && (got == nil || got.Currency != tt.want.Currency || !got.Amount.Equal(tt.want.Amount) ||
+				got.OriginalCurrency != tt.want.OriginalCurrency || !got.OriginalAmount.Equal(tt.want.OriginalAmount) ||
+				got.BaseCurrency != tt.want.BaseCurrency || !got.BaseAmount.Equal(tt.want.BaseAmount)):
+				t.Errorf("report currency %+v, want %+v", got, tt.want)
+			}
+
+			// The stored decision keeps the base amount, never the report currency amount
+			stored, ok := decisions.decisions[input.TransactionID]
+			if !ok {
+				t.Fatal("decision not stored")
+			}
+			if stored.BaseAmount == nil || !stored.BaseAmount.Equal(decimal.NewFromInt(110)) || stored.BaseCurrency != "USD" {
+				t.Errorf("stored base amount %v %s, want 110 USD", stored.BaseAmount, stored.BaseCurrency)
+			}
+		})
+	}
+}
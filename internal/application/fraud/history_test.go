@@ -0,0 +1,125 @@
+package fraud
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/infrastructure/cache/redis"
+	"fraud-detecction-system/internal/infrastructure/cache/redis/redistest"
+	"fraud-detecction-system/internal/infrastructure/rules"
+)
+
+// staticRules is a rule repository whose active rules never change
+type staticRules struct {
+	fraud.RuleRepository
+	rules []*fraud.Rule
+}
+
+func (r staticRules) ListActive(ctx context.Context) ([]*fraud.Rule, error) {
+	return r.rules, nil
+}
+
+// velocityReads counts the velocity history reads sent to Redis
+// A read with scores is the per-analysis history load; the rest are rules asking themselves
+type velocityReads struct {
+	mu      sync.Mutex
+	loads   int
+	queries int
+}
+
+func (h *velocityReads) DialHook(next goredis.DialHook) goredis.DialHook {
+	return next
+}
+
+func (h *velocityReads) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		args := cmd.Args()
+		if name := cmd.Name(); (name == "zcount" || name == "zrangebyscore") && len(args) > 1 {
+			if key, _ := args[1].(string); strings.HasPrefix(key, "velocity:user:") {
+				h.mu.Lock()
+				if slices.Contains(args, any("withscores")) {
+					h.loads++
+				} else {
+					h.queries++
+				}
+				h.mu.Unlock()
+			}
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h *velocityReads) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return next
+}
+
+// velocityWindowRule limits a user to ten transactions and 5000 within windowMinutes
+func velocityWindowRule(windowMinutes int) *fraud.Rule {
+	rule := fraud.NewRule("velocity", "", fraud.RuleTypeVelocity, fraud.SeverityHigh, fraud.ActionReview, uuid.Nil)
+	rule.Config = map[string]interface{}{
+		"max_transactions": float64(10),
+		"window_minutes":   float64(windowMinutes),
+		"amount_threshold": "5000",
+	}
+	return rule
+}
+
+func TestExecuteLoadsVelocityHistoryOnce(t *testing.T) {
+	const day = 24 * 60
+
+	tests := []struct {
+		name        string
+		windows     []int // Rule windows in minutes
+		preload     time.Duration
+		wantLoads   int
+		wantQueries int
+	}{
+		{"windows inside the history", []int{60, 600}, 24 * time.Hour, 1, 0},
+		{"window past the history", []int{60, 2 * day}, 24 * time.Hour, 1, 2}, // Count and sum for the long window
+		{"no preload", []int{60, 600}, 0, 0, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := redistest.NewClient(t)
+			reads := &velocityReads{}
+			client.Redis().AddHook(reads)
+			velocityCache := redis.NewVelocityCache(client)
+
+			var active []*fraud.Rule
+			for _, window := range tt.windows {
+				active = append(active, velocityWindowRule(window))
+			}
+			engine := rules.NewEngine(staticRules{rules: active}, velocityCache, nil, nil, nil)
+			decisions := &memoryDecisionRepo{decisions: make(map[uuid.UUID]*fraud.FraudDecision)}
+			service := fraud.NewService(decisions, nil, nil, engine, nil)
+			uc := NewDetectFraudUseCase(service, engine, nil, velocityCache, nil, nil, nil, time.Second)
+			uc.SetRecentHistoryWindow(tt.preload)
+
+			_, err := uc.Execute(context.Background(), DetectFraudInput{
+				TransactionID: uuid.New(),
+				UserID:        uuid.New(),
+				Amount:        decimal.NewFromInt(100),
+				Currency:      "USD",
+				Timestamp:     time.Now(),
+			})
+			if err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+
+			reads.mu.Lock()
+			defer reads.mu.Unlock()
+			if reads.loads != tt.wantLoads || reads.queries != tt.wantQueries {
+				t.Errorf("%d history loads and %d rule queries, want %d and %d", reads.loads, reads.queries, tt.wantLoads, tt.wantQueries)
+			}
+		})
+	}
+}
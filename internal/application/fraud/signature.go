@@ -0,0 +1,18 @@
+package fraud
+
+import (
+	"fraud-detecction-system/pkg/signature"
+)
+
+// Signed returns the signed fields of an analysis result
+func (o *DetectFraudOutput) Signed() signature.Decision {
+	return signature.Decision{
+		TransactionID:  o.TransactionID,
+		Decision:       string(o.Decision),
+		Score:          o.Score,
+		RiskLevel:      string(o.RiskLevel),
+		Confidence:     o.Confidence,
+		ShouldBlock:    o.ShouldBlock,
+		RequiresReview: o.RequiresReview,
+	}
+}
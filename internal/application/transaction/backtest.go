@@ -0,0 +1,137 @@
+package transaction
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/domain/transaction"
+)
+
+// BacktestUseCase replays stored transactions under a candidate rule or thresholds
+// It only reads; no decision, case, alert or history is written
+type BacktestUseCase struct {
+	txService    *transaction.Service
+	fraudService *fraud.Service
+}
+
+// NewBacktestUseCase creates a new use case instance
+func NewBacktestUseCase(txService *transaction.Service, fraudService *fraud.Service) *BacktestUseCase {
+	return &BacktestUseCase{
+		txService:    txService,
+		fraudService: fraudService,
+	}
+}
+
+// Execute backtests a candidate against transactions created in [from, to)
+// At most fraud.MaxBacktestTransactions are replayed, oldest first; the report
+// says when the window held more. Transactions without a decision are skipped.
+func (uc *BacktestUseCase) Execute(ctx context.Context, candidate fraud.BacktestCandidate, from, to time.Time) (*fraud.BacktestReport, error) {
+	txs, err := uc.txService.ListTransactionsCreatedBetween(ctx, from, to, fraud.MaxBacktestTransactions+1)
+	if err != nil {
+		return nil, err
+	}
+	truncated := len(txs) > fraud.MaxBacktestTransactions
+	if truncated {
+		txs = txs[:fraud.MaxBacktestTransactions]
+	}
+
+	decisions, err := uc.decisions(ctx, txs)
+	if err != nil {
+		return nil, err
+	}
+
+	replay := make([]fraud.BacktestTransaction, 0, len(txs))
+	for _, tx := range txs {
+		if decision, ok := decisions[tx.ID]; ok {
+			replay = append(replay, fraud.BacktestTransaction{
+				Context:  backtestContext(tx),
+				Decision: decision,
+			})
+		}
+	}
+
+	report, err := uc.fraudService.Backtest(ctx, candidate, replay)
+	if err != nil {
+		return nil, err
+	}
+	report.From = from
+	report.To = to
+	report.Truncated = truncated
+	return report, nil
+}
+
+// decisions loads the decisions for txs, in lookups of at most fraud.MaxDecisionLookupBatch
+func (uc *BacktestUseCase) decisions(ctx context.Context, txs []*transaction.Transaction) (map[uuid.UUID]*fraud.FraudDecision, error) {
+	decisions := make(map[uuid.UUID]*fraud.FraudDecision, len(txs))
+	for start := 0; start < len(txs); start += fraud.MaxDecisionLookupBatch {
+		batch := txs[start:min(start+fraud.MaxDecisionLookupBatch, len(txs))]
+		ids := make([]uuid.UUID, len(batch))
+		for i, tx := range batch {
+			ids[i] = tx.ID
+		}
+		found, err := uc.fraudService.GetDecisionsByTransactionIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for id, decision := range found {
+			decisions[id] = decision
+		}
+	}
+	return decisions, nil
+}
+
+// backtestContext rebuilds a rule evaluation context from a stored transaction
+// Only what was stored with the transaction is known, so history-based inputs are left empty
+func backtestContext(tx *transaction.Transaction) *fraud.RuleEvaluationContext {
+	evalCtx := &fraud.RuleEvaluationContext{
+		TransactionID: tx.ID,
+		UserID:        tx.UserID,
+		AccountID:     tx.AccountID,
+		Amount:        tx.Amount,
+		Currency:      string(tx.Currency),
+		Timestamp:     tx.CreatedAt,
+	}
+	if loc := tx.Location; loc != nil {
+		evalCtx.Location = &fraud.GeoLocation{
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+			Country:   loc.Country,
+			City:      loc.City,
+			Region:    loc.Region,
+			IPAddress: loc.IPAddress,
+		}
+	}
+	if device := tx.Device; device != nil {
+		evalCtx.Device = &fraud.DeviceInfo{
+			DeviceID:        device.DeviceID,
+			DeviceType:      device.DeviceType,
+			OS:              device.OS,
+			Browser:         device.Browser,
+			UserAgent:       device.UserAgent,
+			IsTrustedDevice: device.IsTrustedDevice,
+			LastSeenAt:      device.LastSeenAt,
+		}
+	}
+	if merchant := tx.Merchant; merchant != nil {
+		evalCtx.Merchant = &fraud.MerchantInfo{
+			MerchantID:       merchant.MerchantID,
+			MerchantName:     merchant.MerchantName,
+			MerchantCategory: merchant.MerchantCategory,
+			Country:          merchant.Country,
+			IsHighRisk:       merchant.IsHighRisk,
+		}
+	}
+	if payment := tx.Payment; payment != nil {
+		evalCtx.Payment = &fraud.PaymentMethod{
+			Type:           payment.Type,
+			Last4:          payment.Last4,
+			Network:        payment.Network,
+			BankID:         payment.BankID,
+			IssuingCountry: payment.IssuingCountry,
+		}
+	}
+	return evalCtx
+}
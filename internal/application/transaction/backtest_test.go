@@ -0,0 +1,123 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/domain/transaction"
+)
+
+// orderedTransactionRepo lists transactions in the order they were stored
+type orderedTransactionRepo struct {
+	transaction.Repository
+	txs []*transaction.Transaction
+}
+
+func (r *orderedTransactionRepo) ListCreatedBetween(ctx context.Context, start, end time.Time, limit int) ([]*transaction.Transaction, error) {
+	var results []*transaction.Transaction
+	for _, tx := range r.txs {
+		if !tx.CreatedAt.Before(start) && tx.CreatedAt.Before(end) && len(results) < limit {
+			results = append(results, tx)
+		}
+	}
+	return results, nil
+}
+
+// batchDecisionRepo looks decisions up by transaction ID, failing lookups larger than a batch
+type batchDecisionRepo struct {
+	fraud.DecisionRepository
+	decisions map[uuid.UUID]*fraud.FraudDecision
+}
+
+func (r *batchDecisionRepo) GetByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) (map[uuid.UUID]*fraud.FraudDecision, error) {
+	if len(transactionIDs) > fraud.MaxDecisionLookupBatch {
+		return nil, fmt.Errorf("lookup of %d decisions", len(transactionIDs))
+	}
+	found := make(map[uuid.UUID]*fraud.FraudDecision)
+	for _, id := range transactionIDs {
+		if decision, ok := r.decisions[id]; ok {
+			found[id] = decision
+		}
+	}
+	return found, nil
+}
+
+// newBacktest returns a use case over txs, each created a minute after from and decided at its score
+// A transaction with no score has no decision.
+func newBacktest(from time.Time, scores []string) (*BacktestUseCase, []*transaction.Transaction) {
+	txRepo := &orderedTransactionRepo{}
+	decisionRepo := &batchDecisionRepo{decisions: make(map[uuid.UUID]*fraud.FraudDecision)}
+	scorer := fraud.NewDefaultScorer()
+	for i, score := range scores {
+		tx := transaction.NewTransaction(uuid.New(), uuid.New(), transaction.TypePurchase, decimal.NewFromInt(100), transaction.USD)
+		tx.CreatedAt = from.Add(time.Duration(i+1) * time.Minute)
+		txRepo.txs = append(txRepo.txs, tx)
+		if score == "" {
+			continue
+		}
+		value := decimal.RequireFromString(score)
+		decision, _ := scorer.DetermineDecision(context.Background(), value, fraud.DefaultDecisionThresholds())
+		decisionRepo.decisions[tx.ID] = fraud.NewFraudDecision(tx.ID, tx.UserID, decision, value)
+	}
+	txService := transaction.NewService(txRepo)
+	fraudService := fraud.NewService(decisionRepo, nil, nil, nil, nil)
+	return NewBacktestUseCase(txService, fraudService), txRepo.txs
+}
+
+// loweredThresholds blocks at 0.5, reviews at 0.4 and challenges at 0.3
+func loweredThresholds() *fraud.DecisionThresholds {
+	return &fraud.DecisionThresholds{
+		BlockThreshold:     decimal.RequireFromString("0.5"),
+		ReviewThreshold:    decimal.RequireFromString("0.4"),
+		ChallengeThreshold: decimal.RequireFromString("0.3"),
+	}
+}
+
+func TestBacktestReplaysStoredDecisions(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	uc, txs := newBacktest(from, []string{"0.2", "0.45", "", "0.65", "0.9"})
+
+	report, err := uc.Execute(context.Background(), fraud.BacktestCandidate{Thresholds: loweredThresholds()}, from, from.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("backtest: %v", err)
+	}
+
+	// The undecided transaction is skipped; the challenge becomes a review and the review a block
+	counts := []int{report.Replayed, report.Changed, report.NewBlocks, report.NewReviews}
+	if want := []int{4, 2, 1, 1}; !slices.Equal(counts, want) {
+		t.Errorf("replayed, changed, new blocks, new reviews = %v, want %v", counts, want)
+	}
+	if want := []uuid.UUID{txs[1].ID, txs[3].ID}; !slices.Equal(report.SampleTransactionIDs, want) {
+		t.Errorf("sample %v, want %v", report.SampleTransactionIDs, want)
+	}
+	if !report.From.Equal(from) || !report.To.Equal(from.Add(time.Hour)) || report.Truncated {
+		t.Errorf("window %s to %s truncated %t, want %s to %s untruncated", report.From, report.To, report.Truncated, from, from.Add(time.Hour))
+	}
+}
+
+func TestBacktestTruncatesLargeWindows(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	scores := make([]string, fraud.MaxBacktestTransactions+5)
+	for i := range scores {
+		scores[i] = "0.45"
+	}
+	uc, _ := newBacktest(from, scores)
+
+	report, err := uc.Execute(context.Background(), fraud.BacktestCandidate{Thresholds: loweredThresholds()}, from, from.Add(30*24*time.Hour))
+	if err != nil {
+		t.Fatalf("backtest: %v", err)
+	}
+	if !report.Truncated || report.Replayed != fraud.MaxBacktestTransactions || report.Changed != fraud.MaxBacktestTransactions {
+		t.Errorf("truncated %t, replayed %d, changed %d; want truncated with %d replayed and changed", report.Truncated, report.Replayed, report.Changed, fraud.MaxBacktestTransactions)
+	}
+	if len(report.SampleTransactionIDs) != fraud.BacktestSampleSize {
+		t.Errorf("%d sampled, want %d", len(report.SampleTransactionIDs), fraud.BacktestSampleSize)
+	}
+}
@@ -0,0 +1,80 @@
+package transaction
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/application/dto"
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/domain/transaction"
+)
+
+// ListUserTransactionsUseCase lists a user's transactions with their fraud decisions
+// joined in, so investigators see both in one call
+type ListUserTransactionsUseCase struct {
+	txService    *transaction.Service
+	fraudService *fraud.Service
+}
+
+// NewListUserTransactionsUseCase creates a new use case instance
+func NewListUserTransactionsUseCase(txService *transaction.Service, fraudService *fraud.Service) *ListUserTransactionsUseCase {
+	return &ListUserTransactionsUseCase{
+		txService:    txService,
+		fraudService: fraudService,
+	}
+}
+
+// Execute returns a page of the user's transactions matching filter, newest first,
+// along with the total number of matching transactions
+// The page's decisions are fetched in one lookup rather than one per transaction
+func (uc *ListUserTransactionsUseCase) Execute(ctx context.Context, userID uuid.UUID, filter transaction.ListFilter, limit, offset int) ([]*dto.UserTransaction, int64, error) {
+	txs, total, err := uc.txService.ListUserTransactions(ctx, userID, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uuid.UUID, len(txs))
+	for i, tx := range txs {
+		ids[i] = tx.ID
+	}
+	decisions, err := uc.fraudService.GetDecisionsByTransactionIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]*dto.UserTransaction, len(txs))
+	for i, tx := range txs {
+		results[i] = toUserTransaction(tx, decisions[tx.ID])
+	}
+	return results, total, nil
+}
+
+// toUserTransaction builds the listing entry for a transaction and its decision, if any
+func toUserTransaction(tx *transaction.Transaction, decision *fraud.FraudDecision) *dto.UserTransaction {
+	entry := &dto.UserTransaction{
+		ID:          tx.ID,
+		ExternalID:  tx.ExternalID,
+		AccountID:   tx.AccountID,
+		Type:        string(tx.Type),
+		Status:      string(tx.Status),
+		Amount:      tx.Amount,
+		Currency:    string(tx.Currency),
+		FraudScore:  tx.FraudScore,
+		RiskLevel:   tx.RiskLevel,
+		CreatedAt:   tx.CreatedAt,
+		ProcessedAt: tx.ProcessedAt,
+	}
+	if decision != nil {
+		entry.Decision = &dto.TransactionDecision{
+			ID:         decision.ID,
+			Decision:   string(decision.Decision),
+			Score:      decision.Score,
+			RiskLevel:  string(decision.RiskLevel),
+			RulesFired: decision.RulesFired,
+			Reasons:    decision.Reasons,
+			DecidedAt:  decision.ProcessedAt,
+		}
+	}
+	return entry
+}
@@ -4,6 +4,9 @@ import (
 	"time"
 	"fmt"
 	"context"
+	"errors"
+	"log/slog"
+	"sort"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -12,6 +15,7 @@ import (
 	"fraud-detecction-system/internal/application/dto"
 	"fraud-detecction-system/internal/domain/fraud"
 	"fraud-detecction-system/internal/domain/transaction"
+	"fraud-detecction-system/internal/pkg/logger"
 	)
 
 // ProcessTransactionUseCase orchestrates transaction processing with fraud detection
@@ -20,25 +24,68 @@ type ProcessTransactionUseCase struct {
 	// Domain services 
 	txService *transaction.Service
 	fraudService *fraud.Service
+	notifier fraud.UserNotifier
 
 	// Configs
 	fraudCheckTimeout time.Duration
 	enableAsync bool
+	profileConfig UserProfileConfig
+
+	logger *slog.Logger
 }
 
-// NewProcessTransctionUseCase creates a new use case instance
-func NewProcessTransctionUseCase(
+// NewProcessTransactionUseCase creates a new use case instance
+func NewProcessTransactionUseCase(
 	txService *transaction.Service,
-	fraudeService *fraud.Service,
+	fraudService *fraud.Service,
 ) *ProcessTransactionUseCase{
 	return &ProcessTransactionUseCase{
 		txService: txService,
-		fraudService: fraudeService,
+		fraudService: fraudService,
+		notifier: fraud.NoopUserNotifier{},
 		fraudCheckTimeout: 200 * time.Millisecond, //p99 target
 		enableAsync: false,  //Synchronous by default for correctness 
+		profileConfig: DefaultUserProfileConfig(),
+		logger: slog.Default(),
 	}
 }
 
+// ErrFraudCheckFailed is returned alongside a response when the transaction was stored
+// but fraud analysis failed, leaving it flagged for manual review
+var ErrFraudCheckFailed = errors.New("fraud check failed")
+
+// userNotifyTimeout bounds how long a background user notification may take
+const userNotifyTimeout = 10 * time.Second
+
+// SetUserNotifier sets the notifier used to tell users about blocked and challenged transactions
+func (uc *ProcessTransactionUseCase) SetUserNotifier(notifier fraud.UserNotifier) {
+	uc.notifier = notifier
+}
+
+// SetLogger sets the logger used to report failed fraud checks and notifications
+func (uc *ProcessTransactionUseCase) SetLogger(log *slog.Logger) {
+	uc.logger = log
+}
+
+// UserProfileConfig controls how the user profile is derived from transaction history
+type UserProfileConfig struct {
+	TypicalMerchantLimit int // Most frequent merchants kept as typical (0 disables)
+	TrustedDeviceMinUses int // Earlier transactions on a device before it is trusted (0 disables)
+}
+
+// DefaultUserProfileConfig keeps the five most used merchants and trusts a device after two uses
+func DefaultUserProfileConfig() UserProfileConfig {
+	return UserProfileConfig{
+		TypicalMerchantLimit: 5,
+		TrustedDeviceMinUses: 2,
+	}
+}
+
+// SetUserProfileConfig sets how typical merchants and trusted devices are derived
+func (uc *ProcessTransactionUseCase) SetUserProfileConfig(config UserProfileConfig) {
+	uc.profileConfig = config
+}
+
 // Execute processes a transaction with real-time fraud detection
 // This is the critical path - optimized for sub-100ms p99 latency
 func (uc *ProcessTransactionUseCase) Execute(
@@ -62,8 +109,17 @@ func (uc *ProcessTransactionUseCase) Execute(
 	fraudResult, err := uc.runFraudDetection(fraudCtx, tx, req)
 	if err != nil {
 		// Fraud check failed - flag for manual review as safety measure
-		_ = uc.txService.FlagForReview(ctx, tx.ID, []string{"Fraud check timeout or error"}, decimal.Zero)
-		return uc.buildResponse(tx, nil, time.Since(startTime)), fmt.Errorf("fraud check failed: %w", err)
+		uc.logger.ErrorContext(ctx, "fraud check failed, flagging for review",
+			slog.String(logger.KeyTransactionID, tx.ID.String()),
+			logger.Err(err),
+		)
+		if flagErr := uc.txService.FlagForReview(ctx, tx.ID, []string{"Fraud check timeout or error"}, decimal.Zero); flagErr != nil {
+			uc.logger.ErrorContext(ctx, "failed to flag transaction for review",
+				slog.String(logger.KeyTransactionID, tx.ID.String()),
+				logger.Err(flagErr),
+			)
+		}
+		return uc.buildResponse(uc.reload(ctx, tx), nil, time.Since(startTime)), fmt.Errorf("%w: %v", ErrFraudCheckFailed, err)
 	}
 
 	// Apply fraud decision to transaction
@@ -71,8 +127,8 @@ func (uc *ProcessTransactionUseCase) Execute(
 		return nil, fmt.Errorf("failed to apply fraud decision: %w", err)
 	}
 
-	// Build response
-	response := uc.buildResponse(tx, fraudResult, time.Since(startTime))
+	// Build response from the stored transaction so the status reflects the decision
+	response := uc.buildResponse(uc.reload(ctx, tx), fraudResult, time.Since(startTime))
 	return response, nil
 }
 
@@ -108,6 +164,7 @@ func (uc *ProcessTransactionUseCase) buildEvaluationContext(
 	var (
 		recentTxs     []*transaction.Transaction
 		velocityCheck *transaction.VelocityCheckResult
+		firstTx       *transaction.Transaction
 	)
 
 	// Use errgroup for concurrent data fetching
@@ -124,6 +181,23 @@ func (uc *ProcessTransactionUseCase) buildEvaluationContext(
 		return nil
 	})
 
+	// Earliest transaction, for account age
+	// A failed lookup only leaves the age unknown, so it doesn't fail the fraud check
+	g.Go(func() error {
+		first, err := uc.txService.GetFirstTransaction(gctx, tx.UserID)
+		if err != nil {
+			if !errors.Is(err, transaction.ErrTransactionNotFound) {
+				uc.logger.WarnContext(ctx, "failed to load first transaction for account age",
+					slog.String(logger.KeyUserID, tx.UserID.String()),
+					logger.Err(err),
+				)
+			}
+			return nil
+		}
+		firstTx = first
+		return nil
+	})
+
 	// Velocity check (Redis)
 	g.Go(func() error {
 		result, err := uc.txService.CheckVelocity(gctx, tx.UserID, tx.Amount)
@@ -156,7 +230,7 @@ func (uc *ProcessTransactionUseCase) buildEvaluationContext(
 
 		// Historical data
 		RecentTransactions: uc.mapToTransactionSummaries(recentTxs),
-		UserProfile:        uc.buildUserProfile(recentTxs, velocityCheck),
+		UserProfile:        uc.buildUserProfile(ctx, tx, recentTxs, firstTx, velocityCheck),
 	}
 
 	return evalCtx, nil
@@ -175,7 +249,11 @@ func (uc *ProcessTransactionUseCase) applyFraudDecision(
 
 	case fraud.DecisionBlock:
 		// Decline transaction with reasons
-		return uc.txService.DeclineTransaction(ctx, txID, decision.Reasons)
+		if err := uc.txService.DeclineTransaction(ctx, txID, decision.Reasons); err != nil {
+			return err
+		}
+		uc.notifyUser(ctx, decision)
+		return nil
 
 	case fraud.DecisionReview:
 		// Flag for manual review
@@ -183,13 +261,48 @@ func (uc *ProcessTransactionUseCase) applyFraudDecision(
 
 	case fraud.DecisionChallenge:
 		// Flag for additional verification (3DS, OTP, etc.)
-		return uc.txService.FlagForReview(ctx, txID, append(decision.Reasons, "Requires additional verification"), decision.Score)
+		if err := uc.txService.FlagForReview(ctx, txID, append(decision.Reasons, "Requires additional verification"), decision.Score); err != nil {
+			return err
+		}
+		uc.notifyUser(ctx, decision)
+		return nil
 
 	default:
 		return fmt.Errorf("unknown fraud decision: %s", decision.Decision)
 	}
 }
 
+// notifyUser tells the transaction owner about the decision in the background
+// so notification delivery never adds to transaction latency
+func (uc *ProcessTransactionUseCase) notifyUser(ctx context.Context, decision *fraud.FraudDecision) {
+	if uc.notifier == nil {
+		return
+	}
+
+	notification := fraud.NewUserNotification(decision)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), userNotifyTimeout)
+		defer cancel()
+		if err := uc.notifier.NotifyUser(ctx, notification); err != nil {
+			// Log but don't fail - notifications are best effort
+			uc.logger.WarnContext(ctx, "failed to notify user",
+				slog.String(logger.KeyTransactionID, notification.TransactionID.String()),
+				logger.Err(err),
+			)
+		}
+	}()
+}
+
+// reload fetches the stored transaction after a status change
+// The in-memory copy is returned if the fetch fails
+func (uc *ProcessTransactionUseCase) reload(ctx context.Context, tx *transaction.Transaction) *transaction.Transaction {
+	latest, err := uc.txService.GetTransaction(ctx, tx.ID)
+	if err != nil {
+		return tx
+	}
+	return latest
+}
+
 // buildResponse constructs the API response
 func (uc *ProcessTransactionUseCase) buildResponse(
 	tx *transaction.Transaction,
@@ -209,6 +322,7 @@ func (uc *ProcessTransactionUseCase) buildResponse(
 		CreatedAt:        tx.CreatedAt,
 		ProcessedAt:      tx.ProcessedAt,
 		ProcessingTimeMs: processingTime.Milliseconds(),
+		RequiresReview:   tx.Status == transaction.StatusFlagged,
 	}
 
 	// Add fraud analysis results if available
@@ -328,6 +442,7 @@ func (uc *ProcessTransactionUseCase) mapPaymentMethod(dto *dto.PaymentDTO) *frau
 	return &fraud.PaymentMethod{
 		Type:           dto.Type,
 		Last4:          dto.Last4,
+		BIN:            dto.BIN,
 		Network:        dto.CardType,
 		BankID:         dto.BankID,
 		IssuingCountry: dto.IssuingCountry,
@@ -362,24 +477,50 @@ func (uc *ProcessTransactionUseCase) mapGeoLocationFromTx(loc *transaction.GeoLo
 	}
 }
 
+// buildUserProfile derives the user's behavioral profile from their recent transactions
+// The transaction being evaluated is left out of the typical merchants and trusted
+// devices, so a first-time merchant or device never vouches for itself. Account age
+// is measured from the user's earliest transaction when it is known, even when the
+// user has no recent transactions to profile
+// The average amount is in the fraud service's base currency, the same currency the
+// amount rule compares against it; transactions without an exchange rate are left out
 func (uc *ProcessTransactionUseCase) buildUserProfile(
+	ctx context.Context,
+	current *transaction.Transaction,
 	recentTxs []*transaction.Transaction,
+	firstTx *transaction.Transaction,
 	velocityCheck *transaction.VelocityCheckResult,
 ) *fraud.UserProfile {
 	if len(recentTxs) == 0 {
-		return nil
+		if firstTx == nil {
+			return nil
+		}
+		return &fraud.UserProfile{
+			UserID:         current.UserID,
+			AccountAge:     time.Since(firstTx.CreatedAt),
+			LastActivityAt: time.Now(),
+		}
 	}
 
 	// Calculate average transaction amount
 	total := decimal.Zero
+	converted := 0
 	locations := make(map[string]bool)
+	hours := make(map[int]bool)
 	for _, tx := range recentTxs {
-		total = total.Add(tx.Amount)
+		if amount, err := uc.fraudService.BaseAmount(ctx, tx.Amount, string(tx.Currency)); err == nil {
+			total = total.Add(amount)
+			converted++
+		}
 		if tx.Location != nil {
 			locations[tx.Location.Country] = true
 		}
+		hours[tx.CreatedAt.Hour()] = true
+	}
+	avgAmount := decimal.Zero
+	if converted > 0 {
+		avgAmount = total.Div(decimal.NewFromInt(int64(converted)))
 	}
-	avgAmount := total.Div(decimal.NewFromInt(int64(len(recentTxs))))
 
 	// Extract typical locations
 	typicalLocations := make([]string, 0, len(locations))
@@ -387,11 +528,82 @@ func (uc *ProcessTransactionUseCase) buildUserProfile(
 		typicalLocations = append(typicalLocations, loc)
 	}
 
-	return &fraud.UserProfile{
+	// Extract the hours of day the user has been active in
+	typicalHours := make([]int, 0, len(hours))
+	for hour := range hours {
+		typicalHours = append(typicalHours, hour)
+	}
+	sort.Ints(typicalHours)
+
+	profile := &fraud.UserProfile{
 		UserID:             recentTxs[0].UserID,
 		AverageTransaction: avgAmount,
 		TypicalLocations:   typicalLocations,
+		TypicalMerchants:   typicalMerchants(current, recentTxs, uc.profileConfig.TypicalMerchantLimit),
+		TrustedDevices:     trustedDevices(current, recentTxs, uc.profileConfig.TrustedDeviceMinUses),
+		TypicalHours:       typicalHours,
 		LastActivityAt:     time.Now(),
 	}
+	if firstTx != nil {
+		profile.AccountAge = time.Since(firstTx.CreatedAt)
+	}
+	return profile
+}
+
+// typicalMerchants returns the user's most frequent merchant IDs, most used first
+// Ties are broken by merchant ID so the profile is stable between requests
+func typicalMerchants(current *transaction.Transaction, txs []*transaction.Transaction, limit int) []string {
+	if limit <= 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, tx := range txs {
+		if tx.ID == current.ID || tx.Merchant == nil || tx.Merchant.MerchantID == "" {
+			continue
+		}
+		counts[tx.Merchant.MerchantID]++
+	}
+
+	merchants := make([]string, 0, len(counts))
+	for id := range counts {
+		merchants = append(merchants, id)
+	}
+	sort.Slice(merchants, func(i, j int) bool {
+		if counts[merchants[i]] != counts[merchants[j]] {
+			return counts[merchants[i]] > counts[merchants[j]]
+		}
+		return merchants[i] < merchants[j]
+	})
+
+	if len(merchants) > limit {
+		merchants = merchants[:limit]
+	}
+	return merchants
+}
+
+// trustedDevices returns the devices the user has transacted from at least minUses times
+// Trust comes only from the stored history; the client's trusted_device flag is ignored
+func trustedDevices(current *transaction.Transaction, txs []*transaction.Transaction, minUses int) []string {
+	if minUses <= 0 {
+		return nil
+	}
+
+	uses := make(map[string]int)
+	for _, tx := range txs {
+		if tx.ID == current.ID || tx.Device == nil || tx.Device.DeviceID == "" {
+			continue
+		}
+		uses[tx.Device.DeviceID]++
+	}
+
+	devices := make([]string, 0, len(uses))
+	for id, n := range uses {
+		if n >= minUses {
+			devices = append(devices, id)
+		}
+	}
+	sort.Strings(devices)
+	return devices
 }
 
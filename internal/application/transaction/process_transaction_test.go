@@ -0,0 +1,90 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/domain/transaction"
+)
+
+// memoryTransactionRepo keeps transactions in a map; only the methods the use case needs are implemented
+type memoryTransactionRepo struct {
+	transaction.Repository
+	txs map[uuid.UUID]*transaction.Transaction
+}
+
+func (r *memoryTransactionRepo) GetByID(ctx context.Context, id uuid.UUID) (*transaction.Transaction, error) {
+	tx, ok := r.txs[id]
+	if !ok {
+		return nil, transaction.ErrTransactionNotFound
+	}
+	return tx, nil
+}
+
+func (r *memoryTransactionRepo) Update(ctx context.Context, tx *transaction.Transaction) error {
+	r.txs[tx.ID] = tx
+	return nil
+}
+
+// recordingNotifier sends every notification it receives on a channel
+type recordingNotifier struct {
+	sent chan *fraud.UserNotification
+}
+
+func (n *recordingNotifier) NotifyUser(ctx context.Context, notification *fraud.UserNotification) error {
+	n.sent <- notification
+	return nil
+}
+
+func TestApplyFraudDecisionNotifiesUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		decision   fraud.DecisionType
+		wantNotify bool
+	}{
+		{"block", fraud.DecisionBlock, true},
+		{"challenge", fraud.DecisionChallenge, true},
+		{"review", fraud.DecisionReview, false},
+		{"allow", fraud.DecisionAllow, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := transaction.NewTransaction(uuid.New(), uuid.New(), transaction.TypePurchase, decimal.NewFromInt(100), transaction.USD)
+			repo := &memoryTransactionRepo{txs: map[uuid.UUID]*transaction.Transaction{tx.ID: tx}}
+			notifier := &recordingNotifier{sent: make(chan *fraud.UserNotification, 1)}
+			uc := NewProcessTransactionUseCase(transaction.NewService(repo), nil)
+			uc.SetUserNotifier(notifier)
+
+			decision := &fraud.FraudDecision{
+				ID:            uuid.New(),
+				TransactionID: tx.ID,
+				UserID:        tx.UserID,
+				Decision:      tt.decision,
+				Score:         decimal.NewFromFloat(0.9),
+				Reasons:       []string{"velocity"},
+			}
+			if err := uc.applyFraudDecision(context.Background(), tx.ID, decision); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+
+			select {
+			case notification := <-notifier.sent:
+				if !tt.wantNotify {
+					t.Fatalf("got notification %+v, want none", notification)
+				}
+				if notification.TransactionID != tx.ID || notification.UserID != tx.UserID || notification.Decision != tt.decision {
+					t.Errorf("notification %+v, want transaction %s for user %s", notification, tx.ID, tx.UserID)
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantNotify {
+					t.Fatal("no notification sent")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package transaction
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/domain/transaction"
+)
+
+// historyTx is a past transaction of userID at merchantID from deviceID, either of which may be empty
+func historyTx(userID uuid.UUID, amount int64, merchantID, deviceID string) *transaction.Transaction {
+	tx := transaction.NewTransaction(userID, uuid.New(), transaction.TypePurchase, decimal.NewFromInt(amount), transaction.USD)
+	if merchantID != "" {
+		tx.Merchant = &transaction.MerchantInfo{MerchantID: merchantID}
+	}
+	if deviceID != "" {
+		// The client's trust flag is set everywhere to show it counts for nothing
+		tx.Device = &transaction.DeviceInfo{DeviceID: deviceID, IsTrustedDevice: true}
+	}
+	return tx
+}
+
+func TestBuildUserProfile(t *testing.T) {
+	userID := uuid.New()
+	current := historyTx(userID, 500, "merchant-new", "device-new")
+	history := []*transaction.Transaction{
+		current, // The stored copy of the transaction being evaluated
+		historyTx(userID, 100, "merchant-a", "device-1"),
+		historyTx(userID, 100, "merchant-a", "device-1"),
+		historyTx(userID, 100, "merchant-a", ""),
+		historyTx(userID, 100, "merchant-b", "device-2"),
+		historyTx(userID, 100, "merchant-b", ""),
+		historyTx(userID, 100, "merchant-c", ""),
+	}
+	first := historyTx(userID, 50, "", "")
+	first.CreatedAt = time.Now().Add(-90 * 24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		config        UserProfileConfig
+		recent        []*transaction.Transaction
+		first         *transaction.Transaction
+		wantNil       bool
+		wantMerchants []string
+		wantDevices   []string
+		wantAverage   string
+		wantAge       bool
+	}{
+		{"full history", UserProfileConfig{TypicalMerchantLimit: 2, TrustedDeviceMinUses: 2}, history, first, false, []string{"merchant-a", "merchant-b"}, []string{"device-1"}, "157.1428571428571429", true},
+		{"every merchant and device", UserProfileConfig{TypicalMerchantLimit: 10, TrustedDeviceMinUses: 1}, history, first, false, []string{"merchant-a", "merchant-b", "merchant-c"}, []string{"device-1", "device-2"}, "157.1428571428571429", true},
+		{"derivation disabled", UserProfileConfig{}, history, first, false, nil, nil, "157.1428571428571429", true},
+		{"no first transaction", DefaultUserProfileConfig(), history, nil, false, []string{"merchant-a", "merchant-b", "merchant-c"}, []string{"device-1"}, "157.1428571428571429", false},
+		{"no recent transactions", DefaultUserProfileConfig(), nil, first, false, nil, nil, "0", true},
+		{"no history at all", DefaultUserProfileConfig(), nil, nil, true, nil, nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewProcessTransactionUseCase(nil, fraud.NewService(nil, nil, nil, nil, nil))
+			uc.SetUserProfileConfig(tt.config)
+
+			profile := uc.buildUserProfile(context.Background(), current, tt.recent, tt.first, nil)
+			if tt.wantNil {
+				if profile != nil {
+					t.Fatalf("profile %+v, want none", profile)
+				}
+				return
+			}
+			if profile == nil {
+				t.Fatal("no profile")
+			}
+
+			if profile.UserID != userID {
+				t.Errorf("profile for %s, want %s", profile.UserID, userID)
+			}
+			if !slices.Equal(profile.TypicalMerchants, tt.wantMerchants) {
+				t.Errorf("typical merchants %v, want %v", profile.TypicalMerchants, tt.wantMerchants)
+			}
+			if !slices.Equal(profile.TrustedDevices, tt.wantDevices) {
+				t.Errorf("trusted devices %v, want %v", profile.TrustedDevices, tt.wantDevices)
+			}
+			if got := profile.AverageTransaction.String(); got != tt.wantAverage {
+				t.Errorf("average %s, want %s", got, tt.wantAverage)
+			}
+			if hasAge := profile.AccountAge >= 89*24*time.Hour; hasAge != tt.wantAge {
+				t.Errorf("account age %s, want about 90 days %t", profile.AccountAge, tt.wantAge)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package fraud
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// FraudAlertSchemaVersion is the current version of the FraudAlert event schema
+// Bump it when fields are removed or change meaning; adding fields is compatible
+const FraudAlertSchemaVersion = 1
+
+// FraudAlert is the event emitted when a transaction is blocked or flagged for review
+type FraudAlert struct {
+	SchemaVersion int             `json:"schema_version"`
+	DecisionID    uuid.UUID       `json:"decision_id"`
+	TransactionID uuid.UUID       `json:"transaction_id"`
+	UserID        uuid.UUID       `json:"user_id"`
+	Decision      DecisionType    `json:"decision"`
+	Score         decimal.Decimal `json:"score"`
+	RulesFired    []string        `json:"rules_fired"`
+	Reasons       []string        `json:"reasons"`
+	Timestamp     time.Time       `json:"timestamp"`
+}
+
+// NewFraudAlert builds an alert event from a fraud decision
+func NewFraudAlert(decision *FraudDecision) *FraudAlert {
+	return &FraudAlert{
+		SchemaVersion: FraudAlertSchemaVersion,
+		DecisionID:    decision.ID,
+		TransactionID: decision.TransactionID,
+		UserID:        decision.UserID,
+		Decision:      decision.Decision,
+		Score:         decision.Score,
+		RulesFired:    decision.RulesFired,
+		Reasons:       decision.Reasons,
+		Timestamp:     decision.ProcessedAt,
+	}
+}
+
+// FraudAlertPublisher publishes fraud alerts to downstream consumers
+type FraudAlertPublisher interface {
+	// PublishAlert emits a fraud alert event
+	PublishAlert(ctx context.Context, alert *FraudAlert) error
+}
@@ -0,0 +1,152 @@
+package fraud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// memoryDecisionRepo keeps decisions by transaction ID; only the methods analysis uses are implemented
+type memoryDecisionRepo struct {
+	DecisionRepository
+	decisions map[uuid.UUID]*FraudDecision
+}
+
+func (r *memoryDecisionRepo) Create(ctx context.Context, decision *FraudDecision) error {
+	if _, ok := r.decisions[decision.TransactionID]; ok {
+		return ErrDuplicateDecision
+	}
+	r.decisions[decision.TransactionID] = decision
+	return nil
+}
+
+func (r *memoryDecisionRepo) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*FraudDecision, error) {
+	if d, ok := r.decisions[transactionID]; ok {
+		return d, nil
+	}
+	return nil, ErrDecisionNotFound
+}
+
+// stubEngine returns fixed rule results, failing first with each error in errs
+type stubEngine struct {
+	RuleEngine
+	results []RuleResult
+	errs    []error
+	calls   int
+}
+
+func (e *stubEngine) Evaluate(ctx context.Context, evalCtx *RuleEvaluationContext) ([]RuleResult, error) {
+	e.calls++
+	if e.calls <= len(e.errs) {
+		return nil, e.errs[e.calls-1]
+	}
+	return e.results, nil
+}
+
+// newAnalyzeService returns a service whose rules produce results
+func newAnalyzeService(engine *stubEngine) (*Service, *memoryDecisionRepo) {
+	decisions := &memoryDecisionRepo{decisions: make(map[uuid.UUID]*FraudDecision)}
+	cases := &memoryCaseRepo{cases: make(map[uuid.UUID]*FraudCase)}
+	return NewService(decisions, cases, nil, engine, nil), decisions
+}
+
+// firedResult is a fired rule result of ruleType scoring score
+func firedResult(ruleType RuleType, score float64) RuleResult {
+	result := NewRuleResult(uuid.New(), string(ruleType), true, decimal.NewFromFloat(score), "fired", ActionReview)
+	result.RuleType = ruleType
+	return *result
+}
+
+// bareContext is a transaction with none of the optional context
+func bareContext() *RuleEvaluationContext {
+	return &RuleEvaluationContext{
+		TransactionID: uuid.New(),
+		UserID:        uuid.New(),
+		Amount:        decimal.NewFromInt(100),
+		Currency:      "USD",
+		Timestamp:     time.Now(),
+	}
+}
+
+// fullContext is a transaction with every optional context field
+func fullContext() *RuleEvaluationContext {
+	evalCtx := bareContext()
+	evalCtx.Location = &GeoLocation{Country: "US", City: "Austin"}
+	evalCtx.Device = &DeviceInfo{DeviceID: "device-1"}
+	evalCtx.Merchant = &MerchantInfo{MerchantID: "merchant-1"}
+	evalCtx.Payment = &PaymentMethod{Type: "card"}
+	evalCtx.UserProfile = &UserProfile{AccountAge: 365 * 24 * time.Hour}
+	return evalCtx
+}
+
+func TestAnalyzeTransactionMissingContext(t *testing.T) {
+	challengeAtFour := DefaultContextRiskConfig()
+	challengeAtFour.ChallengeMinMissing = 4
+
+	tests := []struct {
+		name         string
+		evalCtx      *RuleEvaluationContext
+		config       ContextRiskConfig
+		wantMissing  int
+		wantScore    string
+		wantDecision DecisionType
+	}{
+		{"no context scores the capped baseline", bareContext(), DefaultContextRiskConfig(), 5, "0.3", DecisionAllow},
+		{"full context scores zero", fullContext(), DefaultContextRiskConfig(), 0, "0", DecisionAllow},
+		{"disabled baseline", bareContext(), ContextRiskConfig{}, 5, "0", DecisionAllow},
+		{"challenge when too much is missing", bareContext(), challengeAtFour, 5, "0.3", DecisionChallenge},
+		{"full context is never challenged", fullContext(), challengeAtFour, 0, "0", DecisionAllow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newAnalyzeService(&stubEngine{})
+			service.SetContextRiskConfig(tt.config)
+
+			decision, err := service.AnalyzeTransaction(context.Background(), tt.evalCtx)
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			if decision.MissingContextCount != tt.wantMissing {
+				t.Errorf("missing context %d, want %d", decision.MissingContextCount, tt.wantMissing)
+			}
+			if !decision.Score.Equal(decimal.RequireFromString(tt.wantScore)) {
+				t.Errorf("score %s, want %s", decision.Score, tt.wantScore)
+			}
+			if decision.Decision != tt.wantDecision {
+				t.Errorf("decision %s, want %s", decision.Decision, tt.wantDecision)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTransactionTwiceReturnsStoredDecision(t *testing.T) {
+	engine := &stubEngine{results: []RuleResult{firedResult(RuleTypeAmount, 0.7)}}
+	service, decisions := newAnalyzeService(engine)
+	evalCtx := fullContext()
+
+	first, err := service.AnalyzeTransaction(context.Background(), evalCtx)
+	if err != nil {
+		t.Fatalf("first analysis: %v", err)
+	}
+
+	// The retry would score differently if the rules ran again
+	engine.results = []RuleResult{firedResult(RuleTypeAmount, 0.95)}
+	second, err := service.AnalyzeTransaction(context.Background(), evalCtx)
+	if err != nil {
+		t.Fatalf("second analysis: %v", err)
+	}
+
+	if second.ID != first.ID || second.Decision != first.Decision || !second.Score.Equal(first.Score) {
+		t.Errorf("retry got decision %s %s at %s, want the stored %s %s at %s",
+			second.ID, second.Decision, second.Score, first.ID, first.Decision, first.Score)
+	}
+	if len(decisions.decisions) != 1 {
+		t.Errorf("%d decisions stored, want 1", len(decisions.decisions))
+	}
+	if engine.calls != 1 {
+		t.Errorf("rules evaluated %d times, want 1", engine.calls)
+	}
+}
@@ -0,0 +1,158 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MaxBacktestTransactions caps how many past transactions one backtest replays
+const MaxBacktestTransactions = 10000
+
+// BacktestSampleSize is how many affected transaction IDs a backtest report lists
+const BacktestSampleSize = 20
+
+// BacktestCandidate is the change a backtest measures
+// At least one of Rule and Thresholds must be set.
+type BacktestCandidate struct {
+	Rule       *Rule               // Evaluated on top of the rules that ran at the time
+	Thresholds *DecisionThresholds // Replaces the current thresholds
+}
+
+// BacktestTransaction is a past transaction and the decision it was given
+// Context is rebuilt from the stored transaction, so it has no velocity or profile
+// history; candidate rules whose type reads history are rejected for that reason
+type BacktestTransaction struct {
+	Context  *RuleEvaluationContext
+	Decision *FraudDecision
+}
+
+// BacktestReport counts how past decisions would change under a candidate
+// A decision counts as changed only when the new one differs from the recorded one
+type BacktestReport struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Truncated bool      `json:"truncated"` // More than MaxBacktestTransactions were in the window
+
+	Replayed  int `json:"replayed"`
+	Kept      int `json:"kept"`       // Not decided by score alone (lists, early stops, downgrades, coverage reviews), so left as recorded
+	RuleFired int `json:"rule_fired"` // Transactions the candidate rule fired on
+	Failed    int `json:"failed"`     // Transactions the candidate rule or the scorer errored on, left as recorded
+
+	Changed       int            `json:"changed"`
+	NewBlocks     int            `json:"new_blocks"`     // Not blocked at the time, blocked now
+	NewAllows     int            `json:"new_allows"`     // Not allowed at the time, allowed now
+	NewReviews    int            `json:"new_reviews"`    // Not sent to review at the time, sent now
+	NewChallenges int            `json:"new_challenges"` // Not challenged at the time, challenged now
+	Transitions   map[string]int `json:"transitions"`    // Keyed "<from>_to_<to>", e.g. "allow_to_block"
+
+	SampleTransactionIDs []uuid.UUID `json:"sample_transaction_ids"` // Up to BacktestSampleSize changed transactions, oldest first
+}
+
+// Validate checks that thresholds are within 0-1 and ordered challenge < review < block
+func (t DecisionThresholds) Validate() error {
+	one := decimal.NewFromInt(1)
+	for _, threshold := range []decimal.Decimal{t.BlockThreshold, t.ReviewThreshold, t.ChallengeThreshold} {
+		if threshold.IsNegative() || threshold.GreaterThan(one) {
+			return ErrInvalidThresholds
+		}
+	}
+	if !t.ChallengeThreshold.LessThan(t.ReviewThreshold) || !t.ReviewThreshold.LessThan(t.BlockThreshold) {
+		return ErrInvalidThresholds
+	}
+	return nil
+}
+
+// Backtest replays past transactions under a candidate rule or thresholds without changing anything
+// A fired candidate rule raises the recorded score to its own score, the way the
+// max score strategy combines rules. Decisions that don't follow from their score
+// under the current thresholds were made some other way and are kept as recorded.
+func (s *Service) Backtest(ctx context.Context, candidate BacktestCandidate, txs []BacktestTransaction) (*BacktestReport, error) {
+	if candidate.Rule == nil && candidate.Thresholds == nil {
+		return nil, ErrEmptyBacktest
+	}
+	if candidate.Rule != nil {
+		if err := s.validateRule(candidate.Rule); err != nil {
+			return nil, err
+		}
+		// The engine would read today's history for these, not the history at the time
+		if candidate.Rule.Type.ReadsHistory() {
+			return nil, ErrBacktestHistory
+		}
+	}
+	if candidate.Thresholds != nil {
+		if err := candidate.Thresholds.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &BacktestReport{
+		Transitions:          make(map[string]int),
+		SampleTransactionIDs: make([]uuid.UUID, 0),
+	}
+	for _, tx := range txs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report.Replayed++
+
+		recorded := tx.Decision
+		current := s.scoringFor(tx.Context.TenantID).Thresholds
+		decision, err := s.scorer.DetermineDecision(ctx, recorded.Score, current)
+		if err != nil {
+			report.Failed++
+			continue
+		}
+		if decision != recorded.Decision {
+			report.Kept++
+			continue
+		}
+
+		score := recorded.Score
+		if candidate.Rule != nil {
+			result, err := s.ruleEngine.EvaluateRule(ctx, candidate.Rule, tx.Context)
+			if err != nil {
+				report.Failed++
+				continue
+			}
+			if result.Fired {
+				report.RuleFired++
+				score = decimal.Max(score, result.Score)
+			}
+		}
+
+		thresholds := current
+		if candidate.Thresholds != nil {
+			thresholds = *candidate.Thresholds
+		}
+		replayed, err := s.scorer.DetermineDecision(ctx, score, thresholds)
+		if err != nil {
+			report.Failed++
+			continue
+		}
+		if replayed == recorded.Decision {
+			continue
+		}
+
+		report.Changed++
+		report.Transitions[fmt.Sprintf("%s_to_%s", recorded.Decision, replayed)]++
+		switch replayed {
+		case DecisionBlock:
+			report.NewBlocks++
+		case DecisionAllow:
+			report.NewAllows++
+		case DecisionReview:
+			report.NewReviews++
+		case DecisionChallenge:
+			report.NewChallenges++
+		}
+		if len(report.SampleTransactionIDs) < BacktestSampleSize {
+			report.SampleTransactionIDs = append(report.SampleTransactionIDs, recorded.TransactionID)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,124 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// backtestEngine fires the candidate rule at 0.9 on transactions in fires and errors on those in fails
+type backtestEngine struct {
+	RuleEngine
+	fires map[uuid.UUID]bool
+	fails map[uuid.UUID]bool
+}
+
+func (e *backtestEngine) EvaluateRule(ctx context.Context, rule *Rule, evalCtx *RuleEvaluationContext) (*RuleResult, error) {
+	if e.fails[evalCtx.TransactionID] {
+		return nil, errors.New("rule data unavailable")
+	}
+	return NewRuleResult(rule.ID, rule.Name, e.fires[evalCtx.TransactionID], decimal.RequireFromString("0.9"), "replayed", rule.Action), nil
+}
+
+// recorded is a past transaction given decision at score
+func recorded(decision DecisionType, score string) BacktestTransaction {
+	evalCtx := bareContext()
+	return BacktestTransaction{
+		Context:  evalCtx,
+		Decision: NewFraudDecision(evalCtx.TransactionID, evalCtx.UserID, decision, decimal.RequireFromString(score)),
+	}
+}
+
+// amountRule is a candidate rule that reads only the transaction itself
+func amountRule() *Rule {
+	rule := NewRule("large amount", "", RuleTypeAmount, SeverityHigh, ActionBlock, uuid.Nil)
+	rule.Config = map[string]interface{}{"max_amount": "50"}
+	return rule
+}
+
+func TestBacktestCandidateRule(t *testing.T) {
+	fired := recorded(DecisionAllow, "0.2")
+	quiet := recorded(DecisionAllow, "0.1")
+	review := recorded(DecisionReview, "0.65")
+	listed := recorded(DecisionBlock, "0.3") // Blocked by a list, not by its score
+	failing := recorded(DecisionAllow, "0.2")
+	engine := &backtestEngine{
+		fires: map[uuid.UUID]bool{fired.Context.TransactionID: true, review.Context.TransactionID: true, listed.Context.TransactionID: true},
+		fails: map[uuid.UUID]bool{failing.Context.TransactionID: true},
+	}
+	service := NewService(nil, nil, nil, engine, nil)
+
+	report, err := service.Backtest(context.Background(), BacktestCandidate{Rule: amountRule()}, []BacktestTransaction{fired, quiet, review, listed, failing})
+	if err != nil {
+		t.Fatalf("backtest: %v", err)
+	}
+
+	counts := []int{report.Replayed, report.Kept, report.RuleFired, report.Failed, report.Changed, report.NewBlocks, report.NewAllows}
+	if want := []int{5, 1, 2, 1, 2, 2, 0}; !slices.Equal(counts, want) {
+		t.Errorf("replayed, kept, fired, failed, changed, new blocks, new allows = %v, want %v", counts, want)
+	}
+	if report.Transitions["allow_to_block"] != 1 || report.Transitions["review_to_block"] != 1 || len(report.Transitions) != 2 {
+		t.Errorf("transitions %v, want one allow_to_block and one review_to_block", report.Transitions)
+	}
+	if want := []uuid.UUID{fired.Decision.TransactionID, review.Decision.TransactionID}; !slices.Equal(report.SampleTransactionIDs, want) {
+		t.Errorf("sample %v, want %v", report.SampleTransactionIDs, want)
+	}
+}
+
+func TestBacktestCandidateThresholds(t *testing.T) {
+	service := NewService(nil, nil, nil, nil, nil)
+	lower := DecisionThresholds{
+		BlockThreshold:     decimal.RequireFromString("0.5"),
+		ReviewThreshold:    decimal.RequireFromString("0.4"),
+		ChallengeThreshold: decimal.RequireFromString("0.3"),
+	}
+
+	txs := []BacktestTransaction{
+		recorded(DecisionAllow, "0.2"),
+		recorded(DecisionChallenge, "0.45"),
+		recorded(DecisionReview, "0.65"),
+		recorded(DecisionBlock, "0.9"),
+	}
+	report, err := service.Backtest(context.Background(), BacktestCandidate{Thresholds: &lower}, txs)
+	if err != nil {
+		t.Fatalf("backtest: %v", err)
+	}
+
+	counts := []int{report.Replayed, report.Changed, report.NewBlocks, report.NewReviews, report.NewChallenges}
+	if want := []int{4, 2, 1, 1, 0}; !slices.Equal(counts, want) {
+		t.Errorf("replayed, changed, new blocks, new reviews, new challenges = %v, want %v", counts, want)
+	}
+	if report.Transitions["challenge_to_review"] != 1 || report.Transitions["review_to_block"] != 1 {
+		t.Errorf("transitions %v, want one challenge_to_review and one review_to_block", report.Transitions)
+	}
+}
+
+func TestBacktestRejects(t *testing.T) {
+	velocity := NewRule("velocity", "", RuleTypeVelocity, SeverityHigh, ActionReview, uuid.Nil)
+	velocity.Config = map[string]interface{}{"max_transactions": float64(5), "window_minutes": float64(10)}
+	unordered := DefaultDecisionThresholds()
+	unordered.ReviewThreshold = unordered.BlockThreshold
+
+	tests := []struct {
+		name      string
+		candidate BacktestCandidate
+		wantErr   error
+	}{
+		{"no candidate", BacktestCandidate{}, ErrEmptyBacktest},
+		{"rule reading live history", BacktestCandidate{Rule: velocity}, ErrBacktestHistory},
+		{"unordered thresholds", BacktestCandidate{Thresholds: &unordered}, ErrInvalidThresholds},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &backtestEngine{}
+			service := NewService(nil, nil, nil, engine, nil)
+			if _, err := service.Backtest(context.Background(), tt.candidate, []BacktestTransaction{recorded(DecisionAllow, "0.2")}); err != tt.wantErr {
+				t.Errorf("error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package fraud
+
+import "math/rand/v2"
+
+// BreakdownSampling controls which decisions are stored with their full score breakdown
+// Every decision keeps its summary (decision, score, rules fired, reasons).
+// Decisions in AlwaysFor always keep their contributions and feature vector.
+// Other decisions keep them with probability SampleRate.
+type BreakdownSampling struct {
+	SampleRate float64        // Fraction of other decisions stored in full, 0-1
+	AlwaysFor  []DecisionType // Decisions always stored in full
+}
+
+// DefaultBreakdownSampling stores every decision in full
+func DefaultBreakdownSampling() BreakdownSampling {
+	return BreakdownSampling{
+		SampleRate: 1,
+		AlwaysFor:  []DecisionType{DecisionBlock, DecisionReview},
+	}
+}
+
+// keep reports whether a decision should be stored with its full breakdown
+func (s BreakdownSampling) keep(decision DecisionType) bool {
+	for _, always := range s.AlwaysFor {
+		if decision == always {
+			return true
+		}
+	}
+	if s.SampleRate >= 1 {
+		return true
+	}
+	return rand.Float64() < s.SampleRate
+}
+
+// withoutBreakdown returns a copy of the decision with only its summary
+// The copy is what gets stored; the original keeps its breakdown
+func (fd *FraudDecision) withoutBreakdown() *FraudDecision {
+	summary := *fd
+	summary.Contributions = make([]RuleContribution, 0)
+	summary.FeatureVector = nil
+	summary.BreakdownOmitted = true
+	return &summary
+}
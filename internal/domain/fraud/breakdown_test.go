@@ -0,0 +1,57 @@
+package fraud
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAnalyzeTransactionBreakdownSampling(t *testing.T) {
+	tests := []struct {
+		name         string
+		score        float64
+		sampleRate   float64
+		runs         int
+		wantDecision DecisionType
+		minKept      float64 // Bounds on the share of decisions stored with their breakdown
+		maxKept      float64
+	}{
+		{"block always kept", 0.9, 0, 50, DecisionBlock, 1, 1},
+		{"allow dropped", 0.3, 0, 50, DecisionAllow, 0, 0},
+		{"allow kept in full", 0.3, 1, 50, DecisionAllow, 1, 1},
+		{"allow sampled", 0.3, 0.5, 1000, DecisionAllow, 0.4, 0.6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, decisions := newAnalyzeService(&stubEngine{results: []RuleResult{firedResult(RuleTypeAmount, tt.score)}})
+			service.SetBreakdownSampling(BreakdownSampling{SampleRate: tt.sampleRate, AlwaysFor: []DecisionType{DecisionBlock}})
+
+			kept := 0
+			for i := 0; i < tt.runs; i++ {
+				decision, err := service.AnalyzeTransaction(context.Background(), fullContext())
+				if err != nil {
+					t.Fatalf("analyze: %v", err)
+				}
+				if decision.Decision != tt.wantDecision {
+					t.Fatalf("decision %s, want %s", decision.Decision, tt.wantDecision)
+				}
+				// The caller always gets the full breakdown, whatever is stored
+				if len(decision.Contributions) == 0 || decision.BreakdownOmitted {
+					t.Fatalf("returned decision has no breakdown")
+				}
+
+				stored := decisions.decisions[decision.TransactionID]
+				if stored.BreakdownOmitted != (len(stored.Contributions) == 0) {
+					t.Fatalf("stored decision omitted %t with %d contributions", stored.BreakdownOmitted, len(stored.Contributions))
+				}
+				if !stored.BreakdownOmitted {
+					kept++
+				}
+			}
+
+			share := float64(kept) / float64(tt.runs)
+			if share < tt.minKept || share > tt.maxKept {
+				t.Errorf("%.2f of decisions kept their breakdown, want %.2f-%.2f", share, tt.minKept, tt.maxKept)
+			}
+		})
+	}
+}
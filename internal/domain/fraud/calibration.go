@@ -0,0 +1,128 @@
+package fraud
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ThresholdCalibration suggests decision thresholds from historical scores
+// Suggestions are never applied; operators copy them into config if they agree
+type ThresholdCalibration struct {
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	SampleSize int       `json:"sample_size"`
+
+	TargetBlockRate  decimal.Decimal `json:"target_block_rate"`
+	TargetReviewRate decimal.Decimal `json:"target_review_rate"`
+
+	SuggestedBlockThreshold  decimal.Decimal `json:"suggested_block_threshold"`
+	SuggestedReviewThreshold decimal.Decimal `json:"suggested_review_threshold"`
+
+	// Rates the suggestions would have produced over the window; ties in the
+	// score distribution can push these away from the targets
+	ExpectedBlockRate  decimal.Decimal `json:"expected_block_rate"`
+	ExpectedReviewRate decimal.Decimal `json:"expected_review_rate"`
+
+	CurrentBlockThreshold  decimal.Decimal `json:"current_block_threshold"`
+	CurrentReviewThreshold decimal.Decimal `json:"current_review_threshold"`
+}
+
+// ScoreCount is how many decisions were made at one score
+type ScoreCount struct {
+	Score decimal.Decimal
+	Count int
+}
+
+// NewScoreHistogram counts scores into a histogram, lowest score first
+func NewScoreHistogram(scores []decimal.Decimal) []ScoreCount {
+	sorted := slices.Clone(scores)
+	slices.SortFunc(sorted, func(a, b decimal.Decimal) int {
+		return a.Cmp(b)
+	})
+
+	var histogram []ScoreCount
+	for _, score := range sorted {
+		if n := len(histogram); n > 0 && histogram[n-1].Score.Equal(score) {
+			histogram[n-1].Count++
+			continue
+		}
+		histogram = append(histogram, ScoreCount{Score: score, Count: 1})
+	}
+	return histogram
+}
+
+// CalibrateThresholds suggests block and review thresholds that would have
+// blocked targetBlockRate and reviewed targetReviewRate of decisions made in [from, to)
+func (s *Service) CalibrateThresholds(ctx context.Context, from, to time.Time, targetBlockRate, targetReviewRate decimal.Decimal) (*ThresholdCalibration, error) {
+	one := decimal.NewFromInt(1)
+	if targetBlockRate.IsNegative() || targetReviewRate.IsNegative() || targetBlockRate.Add(targetReviewRate).GreaterThan(one) {
+		return nil, ErrInvalidCalibrationTarget
+	}
+
+	histogram, err := s.decisionRepo.ScoreHistogram(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	n := countAtOrAbove(histogram, decimal.Zero)
+	if n == 0 {
+		return nil, ErrNoCalibrationData
+	}
+
+	blockThreshold := scoreQuantile(histogram, n, targetBlockRate)
+	reviewThreshold := scoreQuantile(histogram, n, targetBlockRate.Add(targetReviewRate))
+	if reviewThreshold.GreaterThan(blockThreshold) {
+		reviewThreshold = blockThreshold
+	}
+
+	blocked := countAtOrAbove(histogram, blockThreshold)
+	reviewed := countAtOrAbove(histogram, reviewThreshold) - blocked
+	total := decimal.NewFromInt(int64(n))
+
+	return &ThresholdCalibration{
+		From:                     from,
+		To:                       to,
+		SampleSize:               n,
+		TargetBlockRate:          targetBlockRate,
+		TargetReviewRate:         targetReviewRate,
+		SuggestedBlockThreshold:  blockThreshold,
+		SuggestedReviewThreshold: reviewThreshold,
+		ExpectedBlockRate:        decimal.NewFromInt(int64(blocked)).Div(total).Round(4),
+		ExpectedReviewRate:       decimal.NewFromInt(int64(reviewed)).Div(total).Round(4),
+		CurrentBlockThreshold:    s.decisionThresholds.BlockThreshold,
+		CurrentReviewThreshold:   s.decisionThresholds.ReviewThreshold,
+	}, nil
+}
+
+// scoreQuantile returns the threshold that flags the top rate of the n scores in histogram
+// Decisions fire at score >= threshold, so the threshold is the smallest flagged score;
+// when nothing should be flagged it sits just above the highest score
+func scoreQuantile(histogram []ScoreCount, n int, rate decimal.Decimal) decimal.Decimal {
+	flagged := int(rate.Mul(decimal.NewFromInt(int64(n))).Round(0).IntPart())
+	if flagged <= 0 {
+		highest := histogram[len(histogram)-1].Score
+		return decimal.Min(highest.Add(decimal.New(1, -4)), decimal.NewFromInt(1))
+	}
+
+	// The smallest flagged score is the one at sorted position n-flagged
+	below := 0
+	for _, bucket := range histogram {
+		below += bucket.Count
+		if below > n-flagged {
+			return bucket.Score
+		}
+	}
+	return histogram[0].Score
+}
+
+func countAtOrAbove(histogram []ScoreCount, threshold decimal.Decimal) int {
+	count := 0
+	for _, bucket := range histogram {
+		if bucket.Score.GreaterThanOrEqual(threshold) {
+			count += bucket.Count
+		}
+	}
+	return count
+}
@@ -0,0 +1,108 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// histogramRepo serves a fixed score distribution
+type histogramRepo struct {
+	DecisionRepository
+	scores []decimal.Decimal
+}
+
+func (r *histogramRepo) ScoreHistogram(ctx context.Context, from, to time.Time) ([]ScoreCount, error) {
+	return NewScoreHistogram(r.scores), nil
+}
+
+// skewedScores returns n scores bunched towards zero, as real traffic is, with ties
+func skewedScores(n int) []decimal.Decimal {
+	scores := make([]decimal.Decimal, n)
+	for i := range scores {
+		x := float64(i) / float64(n)
+		scores[i] = decimal.NewFromFloat(x * x).Round(3)
+	}
+	return scores
+}
+
+func TestCalibrateThresholds(t *testing.T) {
+	scores := skewedScores(2000)
+	tolerance := decimal.NewFromFloat(0.005)
+
+	tests := []struct {
+		name       string
+		blockRate  float64
+		reviewRate float64
+	}{
+		{"one percent blocked", 0.01, 0.04},
+		{"five percent blocked", 0.05, 0.10},
+		{"a quarter blocked", 0.25, 0.25},
+		{"nothing blocked", 0, 0.02},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewService(&histogramRepo{scores: scores}, nil, nil, nil, nil)
+			targetBlock, targetReview := decimal.NewFromFloat(tt.blockRate), decimal.NewFromFloat(tt.reviewRate)
+
+			calibration, err := service.CalibrateThresholds(context.Background(), time.Now().Add(-time.Hour), time.Now(), targetBlock, targetReview)
+			if err != nil {
+				t.Fatalf("calibrate: %v", err)
+			}
+
+			// Apply the suggestions to the fixture the way decisions would
+			blocked, reviewed := 0, 0
+			for _, score := range scores {
+				switch {
+				case score.GreaterThanOrEqual(calibration.SuggestedBlockThreshold):
+					blocked++
+				case score.GreaterThanOrEqual(calibration.SuggestedReviewThreshold):
+					reviewed++
+				}
+			}
+			total := decimal.NewFromInt(int64(len(scores)))
+			blockRate := decimal.NewFromInt(int64(blocked)).Div(total)
+			reviewRate := decimal.NewFromInt(int64(reviewed)).Div(total)
+
+			if blockRate.Sub(targetBlock).Abs().GreaterThan(tolerance) {
+				t.Errorf("threshold %s blocks %s, want about %s", calibration.SuggestedBlockThreshold, blockRate, targetBlock)
+			}
+			if reviewRate.Sub(targetReview).Abs().GreaterThan(tolerance) {
+				t.Errorf("threshold %s reviews %s, want about %s", calibration.SuggestedReviewThreshold, reviewRate, targetReview)
+			}
+			if !calibration.ExpectedBlockRate.Equal(blockRate.Round(4)) {
+				t.Errorf("expected block rate %s, want %s", calibration.ExpectedBlockRate, blockRate)
+			}
+			if calibration.SampleSize != len(scores) {
+				t.Errorf("sample size %d, want %d", calibration.SampleSize, len(scores))
+			}
+		})
+	}
+}
+
+func TestCalibrateThresholdsRejects(t *testing.T) {
+	tests := []struct {
+		name       string
+		scores     []decimal.Decimal
+		blockRate  float64
+		reviewRate float64
+		wantErr    error
+	}{
+		{"negative rate", skewedScores(100), -0.1, 0.1, ErrInvalidCalibrationTarget},
+		{"rates over one", skewedScores(100), 0.6, 0.5, ErrInvalidCalibrationTarget},
+		{"no decisions", nil, 0.05, 0.1, ErrNoCalibrationData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewService(&histogramRepo{scores: tt.scores}, nil, nil, nil, nil)
+			_, err := service.CalibrateThresholds(context.Background(), time.Now().Add(-time.Hour), time.Now(),
+				decimal.NewFromFloat(tt.blockRate), decimal.NewFromFloat(tt.reviewRate))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
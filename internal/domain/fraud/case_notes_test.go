@@ -0,0 +1,97 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryCaseRepo keeps cases in a map; only the methods the tests use are implemented
+type memoryCaseRepo struct {
+	CaseRepository
+	cases map[uuid.UUID]*FraudCase
+}
+
+func (r *memoryCaseRepo) GetByID(ctx context.Context, id uuid.UUID) (*FraudCase, error) {
+	fraudCase, ok := r.cases[id]
+	if !ok {
+		return nil, ErrCaseNotFound
+	}
+	return fraudCase, nil
+}
+
+func (r *memoryCaseRepo) Create(ctx context.Context, fraudCase *FraudCase) error {
+	r.cases[fraudCase.ID] = fraudCase
+	return nil
+}
+
+func (r *memoryCaseRepo) Update(ctx context.Context, fraudCase *FraudCase) error {
+	r.cases[fraudCase.ID] = fraudCase
+	return nil
+}
+
+func (r *memoryCaseRepo) GetOpenCasesByUser(ctx context.Context, userID uuid.UUID) ([]*FraudCase, error) {
+	var results []*FraudCase
+	for _, c := range r.cases {
+		if c.UserID == userID && c.IsOpen() {
+			results = append(results, c)
+		}
+	}
+	return results, nil
+}
+
+func TestListCaseNotes(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fraudCase := &FraudCase{ID: uuid.New()}
+	// Stored newest first, so the service has to sort
+	for i, content := range []string{"third", "second", "first"} {
+		fraudCase.Notes = append(fraudCase.Notes, CaseNote{
+			ID:        uuid.New(),
+			Content:   content,
+			CreatedAt: start.Add(time.Duration(2-i) * time.Hour),
+		})
+	}
+	service := NewService(nil, &memoryCaseRepo{cases: map[uuid.UUID]*FraudCase{fraudCase.ID: fraudCase}}, nil, nil, nil)
+
+	tests := []struct {
+		name   string
+		limit  int
+		offset int
+		want   []string
+	}{
+		{"all notes oldest first", 0, 0, []string{"first", "second", "third"}},
+		{"first page", 2, 0, []string{"first", "second"}},
+		{"second page", 2, 2, []string{"third"}},
+		{"offset past the end", 2, 5, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, total, err := service.ListCaseNotes(context.Background(), fraudCase.ID, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("ListCaseNotes: %v", err)
+			}
+			if total != 3 {
+				t.Errorf("total = %d, want 3", total)
+			}
+			got := make([]string, len(notes))
+			for i, note := range notes {
+				got[i] = note.Content
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("notes = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("notes = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, _, err := service.ListCaseNotes(context.Background(), uuid.New(), 10, 0); !errors.Is(err, ErrCaseNotFound) {
+		t.Errorf("unknown case: err = %v, want %v", err, ErrCaseNotFound)
+	}
+}
@@ -0,0 +1,60 @@
+package fraud
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// caseQueueRank orders risk levels in the unassigned case queue, most urgent first
+var caseQueueRank = map[RiskLevel]int{
+	RiskLevelCritical: 0,
+	RiskLevelHigh:     1,
+	RiskLevelMedium:   2,
+	RiskLevelLow:      3,
+}
+
+// IsQueued reports whether a case is open and waiting for an investigator
+func (fc *FraudCase) IsQueued() bool {
+	return fc.Status == CaseStatusOpen && fc.AssignedTo == nil
+}
+
+// CompareCaseQueue orders the unassigned case queue: highest risk first, then oldest first
+// Repositories that can't sort in their query sort with it
+func CompareCaseQueue(a, b *FraudCase) int {
+	rankA, ok := caseQueueRank[a.RiskLevel]
+	if !ok {
+		rankA = len(caseQueueRank)
+	}
+	rankB, ok := caseQueueRank[b.RiskLevel]
+	if !ok {
+		rankB = len(caseQueueRank)
+	}
+	if rankA != rankB {
+		return rankA - rankB
+	}
+	return a.CreatedAt.Compare(b.CreatedAt)
+}
+
+// ListCaseQueue returns a page of open, unassigned cases in the order they should be
+// worked, along with the total number of cases waiting
+func (s *Service) ListCaseQueue(ctx context.Context, limit, offset int) ([]*FraudCase, int64, error) {
+	cases, err := s.caseRepo.ListUnassigned(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.caseRepo.CountUnassigned(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return cases, total, nil
+}
+
+// AssignNextCase assigns the first case in the unassigned queue to an investigator
+// The claim is atomic, so concurrent callers never get the same case. It returns
+// ErrCaseQueueEmpty when no case is waiting.
+func (s *Service) AssignNextCase(ctx context.Context, investigatorID uuid.UUID) (*FraudCase, error) {
+	return s.caseRepo.ClaimNext(ctx, investigatorID)
+}
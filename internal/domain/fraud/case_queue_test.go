@@ -0,0 +1,35 @@
+package fraud
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCompareCaseQueue(t *testing.T) {
+	now := time.Now()
+	queued := func(risk RiskLevel, age time.Duration) *FraudCase {
+		c := NewFraudCase(uuid.New(), uuid.New(), uuid.New(), risk)
+		c.CreatedAt = now.Add(-age)
+		return c
+	}
+	newHigh := queued(RiskLevelHigh, time.Minute)
+	oldHigh := queued(RiskLevelHigh, time.Hour)
+	newCritical := queued(RiskLevelCritical, time.Second)
+	oldLow := queued(RiskLevelLow, 24*time.Hour)
+	medium := queued(RiskLevelMedium, time.Minute)
+	unknown := queued(RiskLevel("unknown"), 48*time.Hour)
+
+	cases := []*FraudCase{oldLow, newHigh, unknown, medium, newCritical, oldHigh}
+	slices.SortFunc(cases, CompareCaseQueue)
+
+	want := []*FraudCase{newCritical, oldHigh, newHigh, medium, oldLow, unknown}
+	for i := range want {
+		if cases[i] != want[i] {
+			t.Errorf("position %d is %s created %s ago, want %s created %s ago", i,
+				cases[i].RiskLevel, now.Sub(cases[i].CreatedAt), want[i].RiskLevel, now.Sub(want[i].CreatedAt))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package fraud
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"fraud-detecction-system/internal/pkg/logger"
+	"fraud-detecction-system/internal/pkg/metrics"
+)
+
+// MaxAgingCases caps how many cases of one risk level an aging check loads
+const MaxAgingCases = 500
+
+// CaseSLAConfig sets how long open cases may wait, by risk level
+type CaseSLAConfig struct {
+	// Targets is the most time a case of each risk level may stay open.
+	// Risk levels without a target are not tracked.
+	Targets map[RiskLevel]time.Duration
+
+	// WarningFraction is the share of a target after which a case is reported as
+	// nearing its SLA, so 0.8 reports a 2h case after 96 minutes. 1 reports breaches only.
+	WarningFraction float64
+}
+
+// DefaultCaseSLAConfig gives critical cases 2h, high 8h, medium 24h and low 72h
+func DefaultCaseSLAConfig() CaseSLAConfig {
+	return CaseSLAConfig{
+		Targets: map[RiskLevel]time.Duration{
+			RiskLevelCritical: 2 * time.Hour,
+			RiskLevelHigh:     8 * time.Hour,
+			RiskLevelMedium:   24 * time.Hour,
+			RiskLevelLow:      72 * time.Hour,
+		},
+		WarningFraction: 0.8,
+	}
+}
+
+// CaseAging is an open case measured against its SLA
+// Age is counted from when the case was created
+type CaseAging struct {
+	Case       *FraudCase `json:"case"`
+	AgeMinutes int64      `json:"age_minutes"`
+	SLAMinutes int64      `json:"sla_minutes"`
+	DueAt      time.Time  `json:"due_at"`
+	Breached   bool       `json:"breached"`
+}
+
+// newCaseAging measures a case against an SLA target at now
+func newCaseAging(fraudCase *FraudCase, target time.Duration, now time.Time) *CaseAging {
+	dueAt := fraudCase.CreatedAt.Add(target)
+	return &CaseAging{
+		Case:       fraudCase,
+		AgeMinutes: int64(now.Sub(fraudCase.CreatedAt) / time.Minute),
+		SLAMinutes: int64(target / time.Minute),
+		DueAt:      dueAt,
+		Breached:   !now.Before(dueAt),
+	}
+}
+
+// SetCaseSLAConfig sets the per risk level SLAs open cases are measured against
+func (s *Service) SetCaseSLAConfig(config CaseSLAConfig) {
+	s.caseSLA = config
+}
+
+// ListAgingCases returns open cases that breached or are nearing their SLA, most overdue first
+// At most MaxAgingCases of each risk level are loaded, oldest first.
+func (s *Service) ListAgingCases(ctx context.Context) ([]*CaseAging, error) {
+	return s.listAgingCases(ctx, time.Now())
+}
+
+func (s *Service) listAgingCases(ctx context.Context, now time.Time) ([]*CaseAging, error) {
+	aging := make([]*CaseAging, 0)
+	for level, target := range s.caseSLA.Targets {
+		if target <= 0 {
+			continue
+		}
+		warnAfter := time.Duration(float64(target) * s.caseSLA.WarningFraction)
+		cases, err := s.caseRepo.ListOpenCreatedBefore(ctx, level, now.Add(-warnAfter), MaxAgingCases)
+		if err != nil {
+			return nil, err
+		}
+		for _, fraudCase := range cases {
+			aging = append(aging, newCaseAging(fraudCase, target, now))
+		}
+	}
+	slices.SortFunc(aging, func(a, b *CaseAging) int {
+		return a.DueAt.Compare(b.DueAt)
+	})
+	return aging, nil
+}
+
+// checkCaseSLAs reports open cases whose SLA passed in (since, now]
+// Each newly breached case is logged, counted and sent as a case.sla_breached
+// webhook. The breached cases gauge is set to every case in breach at now.
+func (s *Service) checkCaseSLAs(ctx context.Context, since, now time.Time) error {
+	aging, err := s.listAgingCases(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	breached := 0
+	for _, a := range aging {
+		if !a.Breached {
+			continue
+		}
+		breached++
+		if !a.DueAt.After(since) {
+			continue
+		}
+
+		metrics.RecordCaseSLABreach(string(a.Case.RiskLevel))
+		s.logger.WarnContext(ctx, "fraud case breached its SLA",
+			slog.String("case_id", a.Case.ID.String()),
+			slog.String("risk_level", string(a.Case.RiskLevel)),
+			slog.String("status", string(a.Case.Status)),
+			slog.Int64("age_minutes", a.AgeMinutes),
+			slog.Int64("sla_minutes", a.SLAMinutes),
+		)
+		s.notifyWebhook(ctx, newCaseSLAEvent(a))
+	}
+	metrics.SetCasesBreachingSLA(breached)
+	return nil
+}
+
+// MonitorCaseSLAs checks case SLAs every interval until ctx is canceled
+// The first check reports every case already in breach, so breaches found
+// before a restart are reported again.
+func (s *Service) MonitorCaseSLAs(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var since time.Time
+	for {
+		now := time.Now()
+		if err := s.checkCaseSLAs(ctx, since, now); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// Keep since, so breaches missed by a failed check are reported by the next one
+			s.logger.ErrorContext(ctx, "case SLA check failed", logger.Err(err))
+		} else {
+			since = now
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
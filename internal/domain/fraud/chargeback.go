@@ -0,0 +1,70 @@
+package fraud
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Chargeback is a payment the cardholder disputed and the issuer reversed
+type Chargeback struct {
+	ID            uuid.UUID       `json:"id"`
+	UserID        uuid.UUID       `json:"user_id"`
+	TransactionID uuid.UUID       `json:"transaction_id"` // Nil when the disputed payment isn't known here
+	Amount        decimal.Decimal `json:"amount"`
+	Currency      string          `json:"currency"`
+	ReasonCode    string          `json:"reason_code,omitempty"` // Network reason code, e.g. 10.4
+	OccurredAt    time.Time       `json:"occurred_at"`           // When the chargeback was filed; windows count from this
+	RecordedBy    uuid.UUID       `json:"recorded_by"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// NewChargeback creates a chargeback event
+// A zero occurredAt means the chargeback is being reported as it happens
+func NewChargeback(userID, transactionID uuid.UUID, amount decimal.Decimal, currency, reasonCode string, occurredAt time.Time, recordedBy uuid.UUID) *Chargeback {
+	now := time.Now()
+	if occurredAt.IsZero() {
+		occurredAt = now
+	}
+	return &Chargeback{
+		ID:            uuid.New(),
+		UserID:        userID,
+		TransactionID: transactionID,
+		Amount:        amount,
+		Currency:      currency,
+		ReasonCode:    reasonCode,
+		OccurredAt:    occurredAt,
+		RecordedBy:    recordedBy,
+		CreatedAt:     now,
+	}
+}
+
+// Validate checks that a chargeback can be stored
+func (c *Chargeback) Validate() error {
+	if c.UserID == uuid.Nil || c.Amount.IsNegative() || c.OccurredAt.After(time.Now()) {
+		return ErrInvalidChargeback
+	}
+	return nil
+}
+
+// RecordChargeback validates and stores a chargeback against a user
+func (s *Service) RecordChargeback(ctx context.Context, chargeback *Chargeback) error {
+	if s.chargebackRepo == nil {
+		return ErrChargebacksUnavailable
+	}
+	if err := chargeback.Validate(); err != nil {
+		return err
+	}
+	return s.chargebackRepo.Create(ctx, chargeback)
+}
+
+// chargebackCount counts a user's chargebacks since a time, or returns 0 when
+// no chargeback history is configured
+func (s *Service) chargebackCount(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	if s.chargebackRepo == nil {
+		return 0, nil
+	}
+	return s.chargebackRepo.CountByUserID(ctx, userID, since)
+}
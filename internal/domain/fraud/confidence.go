@@ -0,0 +1,148 @@
+package fraud
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxConfidence caps decision confidence to account for uncertainty
+var maxConfidence = decimal.NewFromFloat(0.95)
+
+// ConfidenceConfig weighs the factors decision confidence is built from
+// Each factor scores 0-1 and confidence is their weighted average, capped at 0.95
+type ConfidenceConfig struct {
+	CoverageWeight  decimal.Decimal // Share of rules that had the data they need
+	AgreementWeight decimal.Decimal // How closely the fired rules' scores agree
+	SignalWeight    decimal.Decimal // Known device, known location and an established account
+
+	EstablishedAccountAge time.Duration // Account age from which an account counts as established
+}
+
+// DefaultConfidenceConfig weighs data coverage most, then agreement, then context signals
+func DefaultConfidenceConfig() ConfidenceConfig {
+	return ConfidenceConfig{
+		CoverageWeight:        decimal.NewFromFloat(0.5),
+		AgreementWeight:       decimal.NewFromFloat(0.3),
+		SignalWeight:          decimal.NewFromFloat(0.2),
+		EstablishedAccountAge: 30 * 24 * time.Hour,
+	}
+}
+
+// ConfidenceFactors are the inputs a decision's confidence was calculated from
+type ConfidenceFactors struct {
+	RulesEvaluated   int             `json:"rules_evaluated"`    // Rules that ran; inactive and unscheduled ones are left out
+	RulesWithoutData int             `json:"rules_without_data"` // Insufficient data, or errored
+	Coverage         decimal.Decimal `json:"coverage"`
+
+	RulesFired int             `json:"rules_fired"`
+	Agreement  decimal.Decimal `json:"agreement"` // 1 when no rule fired, 0.5 for a lone fired rule
+
+	KnownDevice        bool            `json:"known_device"`
+	KnownLocation      bool            `json:"known_location"`
+	EstablishedAccount bool            `json:"established_account"`
+	Signals            decimal.Decimal `json:"signals"`
+}
+
+// Calculate returns the confidence for a decision made from results, and the factors behind it
+// A decision made with no rule that ran has zero confidence
+func (c ConfidenceConfig) Calculate(results []RuleResult, evalCtx *RuleEvaluationContext) (decimal.Decimal, *ConfidenceFactors) {
+	factors := &ConfidenceFactors{
+		Coverage:  decimal.Zero,
+		Agreement: decimal.Zero,
+		Signals:   decimal.Zero,
+	}
+	for _, result := range results {
+		if result.Ran() {
+			factors.RulesEvaluated++
+		}
+	}
+	if factors.RulesEvaluated == 0 {
+		return decimal.Zero, factors
+	}
+
+	var firedScores []float64
+	for _, result := range results {
+		if !result.Ran() {
+			continue
+		}
+		if !result.HasData() {
+			factors.RulesWithoutData++
+		}
+		if result.Fired {
+			firedScores = append(firedScores, result.Score.InexactFloat64())
+		}
+	}
+	factors.RulesFired = len(firedScores)
+	factors.Coverage = decimal.NewFromInt(int64(factors.RulesEvaluated - factors.RulesWithoutData)).
+		Div(decimal.NewFromInt(int64(factors.RulesEvaluated)))
+	factors.Agreement = decimal.NewFromFloat(scoreAgreement(firedScores))
+
+	factors.KnownDevice, factors.KnownLocation, factors.EstablishedAccount = c.contextSignals(evalCtx)
+	present := 0
+	for _, signal := range []bool{factors.KnownDevice, factors.KnownLocation, factors.EstablishedAccount} {
+		if signal {
+			present++
+		}
+	}
+	factors.Signals = decimal.NewFromInt(int64(present)).Div(decimal.NewFromInt(3))
+
+	totalWeight := c.CoverageWeight.Add(c.AgreementWeight).Add(c.SignalWeight)
+	if !totalWeight.IsPositive() {
+		return decimal.Zero, factors
+	}
+	confidence := c.CoverageWeight.Mul(factors.Coverage).
+		Add(c.AgreementWeight.Mul(factors.Agreement)).
+		Add(c.SignalWeight.Mul(factors.Signals)).
+		Div(totalWeight)
+
+	return decimal.Min(confidence, maxConfidence), factors
+}
+
+// contextSignals reports which high-quality signals the transaction carries
+// Device and country history come from enrichment, the same answers the rules saw
+func (c ConfidenceConfig) contextSignals(evalCtx *RuleEvaluationContext) (knownDevice, knownLocation, establishedAccount bool) {
+	profile := evalCtx.UserProfile
+	known := evalCtx.Known
+	if evalCtx.Device != nil {
+		knownDevice = evalCtx.Device.IsTrustedDevice ||
+			(known != nil && known.Device != nil && *known.Device) ||
+			(profile != nil && slices.Contains(profile.TrustedDevices, evalCtx.Device.DeviceID))
+	}
+	if evalCtx.Location != nil {
+		knownLocation = (known != nil && known.Country != nil && *known.Country) ||
+			(profile != nil && slices.Contains(profile.TypicalLocations, evalCtx.Location.Country))
+	}
+	if profile != nil {
+		establishedAccount = profile.AccountAge >= c.EstablishedAccountAge
+	}
+	return knownDevice, knownLocation, establishedAccount
+}
+
+// scoreAgreement scores how closely fired rule scores agree, from 0 to 1
+// Two or more rules score 1 minus twice the standard deviation of their scores, so
+// rules at 0.8 and 0.9 agree at 0.9. A lone fired rule has nothing corroborating it.
+func scoreAgreement(scores []float64) float64 {
+	switch len(scores) {
+	case 0:
+		return 1
+	case 1:
+		return 0.5
+	}
+
+	mean := 0.0
+	for _, s := range scores {
+		mean += s
+	}
+	mean /= float64(len(scores))
+
+	variance := 0.0
+	for _, s := range scores {
+		variance += (s - mean) * (s - mean)
+	}
+	variance /= float64(len(scores))
+
+	return math.Max(0, 1-2*math.Sqrt(variance))
+}
@@ -0,0 +1,139 @@
+package fraud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func ruleResult(fired bool, score float64, status RuleDataStatus) RuleResult {
+	result := NewRuleResult(uuid.New(), "rule", fired, decimal.NewFromFloat(score), "test", ActionAllow)
+	result.DataStatus = status
+	return *result
+}
+
+func TestConfidenceCalculate(t *testing.T) {
+	known := true
+	corroborated := &RuleEvaluationContext{
+		Device:      &DeviceInfo{DeviceID: "device-1"},
+		Location:    &GeoLocation{Country: "US"},
+		Known:       &KnownHistory{Device: &known, Country: &known},
+		UserProfile: &UserProfile{AccountAge: 90 * 24 * time.Hour},
+	}
+
+	tests := []struct {
+		name         string
+		results      []RuleResult
+		evalCtx      *RuleEvaluationContext
+		wantMin      float64
+		wantMax      float64
+		wantCoverage string
+	}{
+		{
+			name: "mostly missing data",
+			results: []RuleResult{
+				ruleResult(false, 0, RuleDataEvaluated),
+				ruleResult(false, 0, RuleDataInsufficient),
+				ruleResult(false, 0, RuleDataInsufficient),
+				ruleResult(false, 0, RuleDataErrored),
+			},
+			evalCtx:      &RuleEvaluationContext{UserProfile: &UserProfile{AccountAge: time.Hour}},
+			wantMin:      0,
+			wantMax:      0.5,
+			wantCoverage: "0.25",
+		},
+		{
+			name: "strong corroborating signals",
+			results: []RuleResult{
+				ruleResult(true, 0.8, RuleDataEvaluated),
+				ruleResult(true, 0.85, RuleDataEvaluated),
+				ruleResult(false, 0, RuleDataEvaluated),
+			},
+			evalCtx:      corroborated,
+			wantMin:      0.9,
+			wantMax:      0.95,
+			wantCoverage: "1",
+		},
+		{
+			name: "rules that didn't run are left out",
+			results: []RuleResult{
+				ruleResult(true, 0.8, RuleDataEvaluated),
+				ruleResult(false, 0, RuleDataNotRun),
+				ruleResult(false, 0, RuleDataNotRun),
+			},
+			evalCtx:      corroborated,
+			wantMin:      0.8,
+			wantMax:      0.95,
+			wantCoverage: "1",
+		},
+		{
+			name:         "no rules",
+			evalCtx:      corroborated,
+			wantMin:      0,
+			wantMax:      0,
+			wantCoverage: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			confidence, factors := DefaultConfidenceConfig().Calculate(tt.results, tt.evalCtx)
+			got := confidence.InexactFloat64()
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("confidence = %s, want between %v and %v", confidence, tt.wantMin, tt.wantMax)
+			}
+			if !factors.Coverage.Equal(decimal.RequireFromString(tt.wantCoverage)) {
+				t.Errorf("coverage = %s, want %s", factors.Coverage, tt.wantCoverage)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTransactionLowConfidenceDowngrade(t *testing.T) {
+	// One fired rule among rules that mostly had no data to check
+	weak := []RuleResult{
+		firedResult(RuleTypeAmount, 0.9),
+		ruleResult(false, 0, RuleDataInsufficient),
+		ruleResult(false, 0, RuleDataInsufficient),
+		ruleResult(false, 0, RuleDataInsufficient),
+	}
+	// Agreeing rules that all ran, on a known device and location
+	strong := []RuleResult{
+		firedResult(RuleTypeAmount, 0.9),
+		firedResult(RuleTypeVelocity, 0.95),
+		ruleResult(false, 0, RuleDataEvaluated),
+	}
+
+	tests := []struct {
+		name           string
+		results        []RuleResult
+		minConfidence  string
+		wantDecision   DecisionType
+		wantDowngraded DecisionType
+	}{
+		{"low confidence block downgraded", weak, "0.6", DecisionReview, DecisionBlock},
+		{"high confidence block stands", strong, "0.6", DecisionBlock, ""},
+		{"downgrade disabled", weak, "0", DecisionBlock, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newAnalyzeService(&stubEngine{results: tt.results})
+			service.SetMinConfidence(decimal.RequireFromString(tt.minConfidence))
+			known := true
+			evalCtx := fullContext()
+			evalCtx.Known = &KnownHistory{Device: &known, Location: &known, Country: &known}
+
+			decision, err := service.AnalyzeTransaction(context.Background(), evalCtx)
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			if decision.Decision != tt.wantDecision || decision.DowngradedFrom != tt.wantDowngraded {
+				t.Errorf("decision %s downgraded from %q at confidence %s, want %s from %q",
+					decision.Decision, decision.DowngradedFrom, decision.Confidence, tt.wantDecision, tt.wantDowngraded)
+			}
+		})
+	}
+}
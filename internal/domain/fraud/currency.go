@@ -0,0 +1,53 @@
+package fraud
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/pkg/logger"
+)
+
+// CurrencyConverter converts amounts between currencies
+// Implementations return an error for a currency they have no rate for
+type CurrencyConverter interface {
+	Convert(ctx context.Context, amount decimal.Decimal, from, to string) (decimal.Decimal, error)
+}
+
+// unconvertedConfidenceFactor scales the confidence of a decision whose amount
+// couldn't be converted, since amount rules then compared it against thresholds
+// in another currency
+var unconvertedConfidenceFactor = decimal.NewFromFloat(0.8)
+
+// BaseAmount converts an amount to the base currency
+// It returns the amount unchanged when no converter is set, and an error when
+// the currency has no exchange rate
+func (s *Service) BaseAmount(ctx context.Context, amount decimal.Decimal, currency string) (decimal.Decimal, error) {
+	if s.currencyConverter == nil || currency == "" {
+		return amount, nil
+	}
+	return s.currencyConverter.Convert(ctx, amount, currency, s.baseCurrency)
+}
+
+// normalizeAmount sets the transaction's amount in the base currency
+// It reports false when the currency has no exchange rate, leaving BaseCurrency empty
+func (s *Service) normalizeAmount(ctx context.Context, evalCtx *RuleEvaluationContext) bool {
+	if s.currencyConverter == nil || evalCtx.Currency == "" {
+		return true
+	}
+
+	amount, err := s.BaseAmount(ctx, evalCtx.Amount, evalCtx.Currency)
+	if err != nil {
+		s.logger.WarnContext(ctx, "transaction amount not converted to base currency",
+			slog.String(logger.KeyTransactionID, evalCtx.TransactionID.String()),
+			slog.String("currency", evalCtx.Currency),
+			slog.String("base_currency", s.baseCurrency),
+			logger.Err(err),
+		)
+		return false
+	}
+	evalCtx.BaseAmount = amount
+	evalCtx.BaseCurrency = s.baseCurrency
+	return true
+}
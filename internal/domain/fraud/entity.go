@@ -51,10 +51,40 @@ type FraudDecision struct {
 	RiskLevel     RiskLevel        `json:"risk_level"`
 	Confidence    decimal.Decimal  `json:"confidence"`     // Model confidence 0.0 to 1.0
 
+	// What confidence was calculated from; unset for decisions made before rules ran
+	ConfidenceFactors *ConfidenceFactors `json:"confidence_factors,omitempty"`
+
 	// Explanation
-	RulesFired    []string         `json:"rules_fired"`    // Which rules triggered
-	Reasons       []string         `json:"reasons"`        // Human-readable explanations
-	ModelVersion  string           `json:"model_version"`  // Which ML model version was used
+	RulesFired    []string           `json:"rules_fired"`   // Which rules triggered
+	Reasons       []string           `json:"reasons"`       // Human-readable explanations
+	ModelVersion  string             `json:"model_version"` // Which ML model version was used
+	Contributions []RuleContribution `json:"contributions"` // How much each rule added to the score, largest first
+
+	// Context completeness
+	MissingContextCount int      `json:"missing_context_count"` // Optional context fields absent from the request
+	SkippedRules        []string `json:"skipped_rules"`         // Rules shed under load or over the rule limit, left out of the score
+	ShadowRulesFired    []string `json:"shadow_rules_fired"`    // Shadow mode rules that would have fired, left out of the score
+
+	// Share of evaluated rules that had the data they need, 0-1; unset when no rules ran, e.g. list matches
+	DataCoverage *decimal.Decimal `json:"data_coverage,omitempty"`
+
+	// Set when confidence was below the minimum and the decision was softened to review
+	DowngradedFrom DecisionType `json:"downgraded_from,omitempty"`
+
+	// Set when an analyst overrode the decision; the latest override's outcome
+	OverriddenDecision DecisionType `json:"overridden_decision,omitempty"`
+
+	// Set when rules could not run because a dependency was down, so the score may be too low
+	Degraded       bool   `json:"degraded"`
+	DegradedReason string `json:"degraded_reason,omitempty"`
+
+	// Transaction amount in the base currency; unset when the currency had no exchange rate
+	BaseAmount   *decimal.Decimal `json:"base_amount,omitempty"`
+	BaseCurrency string           `json:"base_currency,omitempty"`
+
+	// Full breakdown; stored only for decisions picked by breakdown sampling
+	FeatureVector    []float64 `json:"feature_vector,omitempty"`    // ML model input, when ML scoring ran
+	BreakdownOmitted bool      `json:"breakdown_omitted,omitempty"` // Contributions and feature vector were not stored
 
 	// Metadata
 	ProcessedAt   time.Time        `json:"processed_at"`
@@ -64,20 +94,31 @@ type FraudDecision struct {
 	UpdatedAt     time.Time        `json:"updated_at"`
 }
 
+// EffectiveDecision returns the latest override's outcome, or Decision when there is none
+func (d *FraudDecision) EffectiveDecision() DecisionType {
+	if d.OverriddenDecision != "" {
+		return d.OverriddenDecision
+	}
+	return d.Decision
+}
+
 // NewFraudDecision creates a new fraud decision
 func NewFraudDecision(transactionID, userID uuid.UUID, decision DecisionType, score decimal.Decimal) *FraudDecision {
 	now := time.Now()
 	return &FraudDecision{
-		ID:            uuid.New(),
-		TransactionID: transactionID,
-		UserID:        userID,
-		Decision:      decision,
-		Score:         score,
-		RulesFired:    make([]string, 0),
-		Reasons:       make([]string, 0),
-		ProcessedAt:   now,
-		CreatedAt:     now,
-		UpdatedAt:     now,
+		ID:               uuid.New(),
+		TransactionID:    transactionID,
+		UserID:           userID,
+		Decision:         decision,
+		Score:            score,
+		RulesFired:       make([]string, 0),
+		Reasons:          make([]string, 0),
+		Contributions:    make([]RuleContribution, 0),
+		SkippedRules:     make([]string, 0),
+		ShadowRulesFired: make([]string, 0),
+		ProcessedAt:      now,
+		CreatedAt:        now,
+		UpdatedAt:        now,
 	}
 }
 
@@ -119,6 +160,47 @@ func (fd *FraudDecision) RequiresReview() bool {
 	return fd.Decision == DecisionReview
 }
 
+// FeedbackLabel is an analyst's ground-truth verdict on a decision
+type FeedbackLabel string
+
+const (
+	LabelFraud FeedbackLabel = "fraud"
+	LabelLegit FeedbackLabel = "legit"
+)
+
+// DecisionFeedback records an analyst's verdict on a fraud decision
+// The decision and when it was made are copied so accuracy can be computed from feedback alone
+type DecisionFeedback struct {
+	ID         uuid.UUID     `json:"id"`
+	DecisionID uuid.UUID     `json:"decision_id"`
+	Label      FeedbackLabel `json:"label"`
+	Note       string        `json:"note,omitempty"`
+	Decision   DecisionType  `json:"decision"`
+	DecidedAt  time.Time     `json:"decided_at"`
+	CreatedBy  uuid.UUID     `json:"created_by"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// NewDecisionFeedback creates feedback on a decision
+func NewDecisionFeedback(decision *FraudDecision, label FeedbackLabel, note string, createdBy uuid.UUID) *DecisionFeedback {
+	return &DecisionFeedback{
+		ID:         uuid.New(),
+		DecisionID: decision.ID,
+		Label:      label,
+		Note:       note,
+		Decision:   decision.Decision,
+		DecidedAt:  decision.ProcessedAt,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// PredictedFraud reports whether the decision treated the transaction as fraud
+// Blocked and review decisions count as fraud predictions
+func (f *DecisionFeedback) PredictedFraud() bool {
+	return f.Decision == DecisionBlock || f.Decision == DecisionReview
+}
+
 // FraudCase represents an investigated fraud incident
 // This is created when transactions are flagged for manual review
 type FraudCase struct {
@@ -141,6 +223,7 @@ type FraudCase struct {
 
 	// Resolution
 	Resolution      string            `json:"resolution,omitempty"`
+	Outcome         ResolutionOutcome `json:"outcome,omitempty"` // What the resolution did to the case's transactions
 	ResolvedBy      *uuid.UUID        `json:"resolved_by,omitempty"`
 	ResolvedAt      *time.Time        `json:"resolved_at,omitempty"`
 
@@ -167,6 +250,22 @@ type Evidence struct {
 	CreatedAt   time.Time         `json:"created_at"`
 }
 
+// Evidence types an investigator can attach to a case
+const (
+	EvidenceScreenshot = "screenshot"
+	EvidenceLog        = "log"
+	EvidenceDocument   = "document"
+)
+
+// IsValidEvidenceType checks if an evidence type is one cases accept
+func IsValidEvidenceType(evidenceType string) bool {
+	switch evidenceType {
+	case EvidenceScreenshot, EvidenceLog, EvidenceDocument:
+		return true
+	}
+	return false
+}
+
 // NewFraudCase creates a new fraud case
 func NewFraudCase(transactionID, userID, accountID uuid.UUID, riskLevel RiskLevel) *FraudCase {
 	now := time.Now()
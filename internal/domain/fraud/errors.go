@@ -1,13 +1,31 @@
 package fraud
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// Decision errors
-	ErrDecisionNotFound    = errors.New("fraud decision not found")
-	ErrInvalidScore        = errors.New("invalid fraud score: must be between 0 and 1")
-	ErrInvalidRiskLevel    = errors.New("invalid risk level")
-	ErrInvalidDecisionType = errors.New("invalid decision type")
+	ErrDecisionNotFound      = errors.New("fraud decision not found")
+	ErrDuplicateDecision     = errors.New("a decision already exists for this transaction")
+	ErrInvalidScore          = errors.New("invalid fraud score: must be between 0 and 1")
+	ErrInvalidRiskLevel      = errors.New("invalid risk level")
+	ErrInvalidDecisionType   = errors.New("invalid decision type")
+	ErrInvalidFeedbackLabel  = errors.New("invalid feedback label: must be fraud or legit")
+	ErrDecisionBatchTooLarge = errors.New("too many transactions in one decision lookup")
+
+	// Calibration errors
+	ErrInvalidCalibrationTarget = errors.New("invalid calibration target: rates must be between 0 and 1 and sum to at most 1")
+	ErrNoCalibrationData        = errors.New("no decisions in the calibration window")
+
+	// Backtest errors
+	ErrEmptyBacktest     = errors.New("backtest needs a candidate rule or thresholds")
+	ErrBacktestHistory   = errors.New("backtest can't replay rules that read live history: only amount, ip_reputation and issuer_country_mismatch rules can be backtested")
+	ErrInvalidThresholds = errors.New("invalid decision thresholds: must be between 0 and 1 with challenge < review < block")
+
+	// Scoring errors
+	ErrInvalidScoreWeights = errors.New("invalid score weights: must not be negative and must not all be zero")
 
 	// Case errors
 	ErrCaseNotFound      = errors.New("fraud case not found")
@@ -15,6 +33,21 @@ var (
 	ErrCaseNotResolved   = errors.New("case must be resolved before closing")
 	ErrInvalidCaseStatus = errors.New("invalid case status")
 	ErrCaseNotAssigned   = errors.New("case is not assigned to an investigator")
+	ErrCaseQueueEmpty    = errors.New("no open cases are waiting for an investigator")
+
+	// Evidence errors
+	ErrInvalidEvidenceType = errors.New("invalid evidence type: must be screenshot, log or document")
+
+	// Resolution outcome errors
+	ErrInvalidResolutionOutcome     = errors.New("invalid resolution outcome: must be approve or decline")
+	ErrTransactionReviewUnavailable = errors.New("transaction review is not configured")
+
+	// Override errors
+	ErrInvalidOverrideDecision = errors.New("invalid override decision: must be allow, block or review")
+	ErrOverrideReasonRequired  = errors.New("an override needs a reason")
+	ErrOverrideUnchanged       = errors.New("decision already has this outcome")
+	ErrBlockOverrideForbidden  = errors.New("not permitted to override a block decision")
+	ErrOverrideConflict        = errors.New("decision was overridden by someone else first")
 
 	// Rule errors
 	ErrRuleNotFound         = errors.New("fraud rule not found")
@@ -22,9 +55,25 @@ var (
 	ErrInvalidRuleType      = errors.New("invalid rule type")
 	ErrInvalidRuleSeverity  = errors.New("invalid rule severity")
 	ErrInvalidRuleAction    = errors.New("invalid rule action")
+	ErrInvalidRuleFailMode  = errors.New("invalid rule fail mode")
+	ErrInvalidRuleMode      = errors.New("invalid rule mode: must be active or shadow")
 	ErrRuleConfigInvalid    = errors.New("rule configuration is invalid")
+	ErrInvalidRuleSchedule  = errors.New("invalid rule schedule: days must be weekday names, hours 0-23 and timezone an IANA name")
 	ErrRuleNotActive        = errors.New("rule is not active")
 	ErrRuleVersionMismatch  = errors.New("rule version mismatch")
+	ErrNoRulesToImport      = errors.New("no rules to import")
+
+	// List errors
+	ErrListEntryNotFound  = errors.New("list entry not found")
+	ErrDuplicateListEntry = errors.New("entity is already on this list")
+	ErrInvalidListType    = errors.New("invalid list type: must be allow or deny")
+	ErrInvalidListEntity  = errors.New("invalid list entity type: must be user, device_id, card_bin or ip")
+	ErrInvalidListValue   = errors.New("invalid list value")
+	ErrListsUnavailable   = errors.New("allowlist and denylist are not configured")
+
+	// Chargeback errors
+	ErrInvalidChargeback      = errors.New("invalid chargeback: amount must not be negative and occurred_at must not be in the future")
+	ErrChargebacksUnavailable = errors.New("chargeback history is not configured")
 
 	// Evaluation errors
 	ErrEvaluationFailed       = errors.New("rule evaluation failed")
@@ -37,3 +86,19 @@ var (
 	ErrAnalysisTimeout = errors.New("fraud analysis timed out")
 	ErrModelUnavailable = errors.New("ML model is unavailable")
 )
+
+// RuleValidationError describes why one rule in a batch failed validation
+type RuleValidationError struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// RuleImportError reports every invalid rule in a rejected import batch
+type RuleImportError struct {
+	Errors []RuleValidationError `json:"errors"`
+}
+
+func (e *RuleImportError) Error() string {
+	return fmt.Sprintf("rule import rejected: %d invalid rule(s)", len(e.Errors))
+}
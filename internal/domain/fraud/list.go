@@ -0,0 +1,204 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/pkg/logger"
+)
+
+// ListType says whether a list entry hard-allows or hard-blocks what it matches
+type ListType string
+
+const (
+	ListTypeAllow ListType = "allow" // Always allow, regardless of score
+	ListTypeDeny  ListType = "deny"  // Always block, regardless of score
+)
+
+// IsValid checks if the list type is known
+func (t ListType) IsValid() bool {
+	return t == ListTypeAllow || t == ListTypeDeny
+}
+
+// ListEntityType is the transaction attribute a list entry is matched against
+type ListEntityType string
+
+const (
+	ListEntityUser     ListEntityType = "user"      // User ID
+	ListEntityDeviceID ListEntityType = "device_id" // Device fingerprint
+	ListEntityCardBIN  ListEntityType = "card_bin"  // First 6 digits of the card
+	ListEntityIP       ListEntityType = "ip"        // Client IP address
+)
+
+// IsValid checks if the entity type is known
+func (t ListEntityType) IsValid() bool {
+	switch t {
+	case ListEntityUser, ListEntityDeviceID, ListEntityCardBIN, ListEntityIP:
+		return true
+	}
+	return false
+}
+
+// ListEntry is one allowlist or denylist entry
+type ListEntry struct {
+	ID         uuid.UUID      `json:"id"`
+	ListType   ListType       `json:"list_type"`
+	EntityType ListEntityType `json:"entity_type"`
+	Value      string         `json:"value"`
+	Reason     string         `json:"reason"` // Why the entry was added, shown in the decision's reasons
+	CreatedBy  uuid.UUID      `json:"created_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// NewListEntry creates a list entry, normalizing its value for matching
+func NewListEntry(listType ListType, entityType ListEntityType, value, reason string, createdBy uuid.UUID) *ListEntry {
+	return &ListEntry{
+		ID:         uuid.New(),
+		ListType:   listType,
+		EntityType: entityType,
+		Value:      NormalizeListValue(entityType, value),
+		Reason:     reason,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Validate checks that an entry can be stored
+func (e *ListEntry) Validate() error {
+	if !e.ListType.IsValid() {
+		return ErrInvalidListType
+	}
+	if !e.EntityType.IsValid() {
+		return ErrInvalidListEntity
+	}
+	if e.Value == "" {
+		return ErrInvalidListValue
+	}
+	if e.EntityType == ListEntityUser {
+		if _, err := uuid.Parse(e.Value); err != nil {
+			return ErrInvalidListValue
+		}
+	}
+	return nil
+}
+
+// NormalizeListValue puts a value in the form entries are stored and looked up in
+// User IDs are lowercased so any UUID spelling matches; other values are only trimmed
+func NormalizeListValue(entityType ListEntityType, value string) string {
+	value = strings.TrimSpace(value)
+	if entityType == ListEntityUser {
+		value = strings.ToLower(value)
+	}
+	return value
+}
+
+// ListKey is an entity type and value to look up on the lists
+type ListKey struct {
+	EntityType ListEntityType
+	Value      string
+}
+
+// ListKeys returns the list lookups for a transaction, skipping attributes it doesn't have
+func ListKeys(evalCtx *RuleEvaluationContext) []ListKey {
+	keys := []ListKey{{EntityType: ListEntityUser, Value: NormalizeListValue(ListEntityUser, evalCtx.UserID.String())}}
+	if evalCtx.Device != nil && evalCtx.Device.DeviceID != "" {
+		keys = append(keys, ListKey{EntityType: ListEntityDeviceID, Value: NormalizeListValue(ListEntityDeviceID, evalCtx.Device.DeviceID)})
+	}
+	if evalCtx.Payment != nil && evalCtx.Payment.BIN != "" {
+		keys = append(keys, ListKey{EntityType: ListEntityCardBIN, Value: NormalizeListValue(ListEntityCardBIN, evalCtx.Payment.BIN)})
+	}
+	if evalCtx.Location != nil && evalCtx.Location.IPAddress != "" {
+		keys = append(keys, ListKey{EntityType: ListEntityIP, Value: NormalizeListValue(ListEntityIP, evalCtx.Location.IPAddress)})
+	}
+	return keys
+}
+
+// listMatch returns the list entry that decides a transaction, if any
+// A denylist entry wins over an allowlist entry. A failed lookup is logged and
+// the transaction goes through the rules as usual.
+func (s *Service) listMatch(ctx context.Context, evalCtx *RuleEvaluationContext) *ListEntry {
+	if s.listRepo == nil {
+		return nil
+	}
+
+	entries, err := s.listRepo.FindMatches(ctx, ListKeys(evalCtx))
+	if err != nil {
+		s.logger.WarnContext(ctx, "allowlist and denylist lookup failed",
+			slog.String(logger.KeyTransactionID, evalCtx.TransactionID.String()),
+			logger.Err(err),
+		)
+		return nil
+	}
+
+	var allowed *ListEntry
+	for _, entry := range entries {
+		if entry.ListType == ListTypeDeny {
+			return entry
+		}
+		if allowed == nil {
+			allowed = entry
+		}
+	}
+	return allowed
+}
+
+// listDecision builds the decision forced by a list entry
+func listDecision(evalCtx *RuleEvaluationContext, entry *ListEntry, startTime time.Time) *FraudDecision {
+	var decision *FraudDecision
+	var reason string
+	if entry.ListType == ListTypeDeny {
+		decision = NewFraudDecision(evalCtx.TransactionID, evalCtx.UserID, DecisionBlock, decimal.NewFromInt(1))
+		decision.RiskLevel = RiskLevelCritical
+		reason = fmt.Sprintf("Denylisted %s %s", entry.EntityType, entry.Value)
+	} else {
+		decision = NewFraudDecision(evalCtx.TransactionID, evalCtx.UserID, DecisionAllow, decimal.Zero)
+		decision.RiskLevel = RiskLevelLow
+		reason = fmt.Sprintf("Allowlisted %s %s", entry.EntityType, entry.Value)
+	}
+	if entry.Reason != "" {
+		reason += ": " + entry.Reason
+	}
+
+	decision.Confidence = decimal.NewFromInt(1)
+	decision.AddReason(reason)
+	decision.MissingContextCount = CountMissingContext(evalCtx)
+	decision.ProcessedAt = time.Now()
+	decision.LatencyMs = time.Since(startTime).Milliseconds()
+	return decision
+}
+
+// AddListEntry validates and stores an allowlist or denylist entry
+func (s *Service) AddListEntry(ctx context.Context, entry *ListEntry) error {
+	if s.listRepo == nil {
+		return ErrListsUnavailable
+	}
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	return s.listRepo.Create(ctx, entry)
+}
+
+// RemoveListEntry deletes an allowlist or denylist entry
+func (s *Service) RemoveListEntry(ctx context.Context, id uuid.UUID) error {
+	if s.listRepo == nil {
+		return ErrListsUnavailable
+	}
+	return s.listRepo.Delete(ctx, id)
+}
+
+// ListEntries retrieves the entries of a list, or of both lists when listType is empty
+func (s *Service) ListEntries(ctx context.Context, listType ListType) ([]*ListEntry, error) {
+	if s.listRepo == nil {
+		return nil, ErrListsUnavailable
+	}
+	if listType != "" && !listType.IsValid() {
+		return nil, ErrInvalidListType
+	}
+	return s.listRepo.List(ctx, listType)
+}
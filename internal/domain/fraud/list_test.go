@@ -0,0 +1,107 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// memoryLists matches stored entries against lookup keys, or fails every lookup with err
+type memoryLists struct {
+	ListRepository
+	entries []*ListEntry
+	err     error
+}
+
+func (r *memoryLists) FindMatches(ctx context.Context, keys []ListKey) ([]*ListEntry, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	var matches []*ListEntry
+	for _, entry := range r.entries {
+		for _, key := range keys {
+			if entry.EntityType == key.EntityType && entry.Value == key.Value {
+				matches = append(matches, entry)
+			}
+		}
+	}
+	return matches, nil
+}
+
+func TestAnalyzeTransactionLists(t *testing.T) {
+	evalCtx := fullContext()
+	evalCtx.Device.DeviceID = "device-1"
+	evalCtx.Payment.BIN = "411111"
+	evalCtx.Location.IPAddress = "203.0.113.7"
+	user := strings.ToUpper(evalCtx.UserID.String()) // Any spelling of the ID matches
+
+	tests := []struct {
+		name         string
+		entries      []*ListEntry
+		lookupErr    error
+		wantDecision DecisionType
+		wantReason   string // Start of the first reason; empty when the rules decide
+	}{
+		{"on no list", []*ListEntry{NewListEntry(ListTypeDeny, ListEntityDeviceID, "device-2", "", uuid.Nil)}, nil, DecisionReview, ""},
+		{"allowlisted user", []*ListEntry{NewListEntry(ListTypeAllow, ListEntityUser, user, "VIP", uuid.Nil)}, nil, DecisionAllow, "Allowlisted user"},
+		{"denylisted device", []*ListEntry{NewListEntry(ListTypeDeny, ListEntityDeviceID, " device-1 ", "", uuid.Nil)}, nil, DecisionBlock, "Denylisted device_id device-1"},
+		{"denylisted card BIN", []*ListEntry{NewListEntry(ListTypeDeny, ListEntityCardBIN, "411111", "", uuid.Nil)}, nil, DecisionBlock, "Denylisted card_bin"},
+		{"deny wins over allow", []*ListEntry{
+			NewListEntry(ListTypeAllow, ListEntityUser, user, "", uuid.Nil),
+			NewListEntry(ListTypeDeny, ListEntityIP, "203.0.113.7", "botnet", uuid.Nil),
+		}, nil, DecisionBlock, "Denylisted ip 203.0.113.7: botnet"},
+		{"failed lookup falls back to the rules", []*ListEntry{NewListEntry(ListTypeDeny, ListEntityDeviceID, "device-1", "", uuid.Nil)}, errors.New("lists unavailable"), DecisionReview, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &stubEngine{results: []RuleResult{firedResult(RuleTypeAmount, 0.7)}}
+			service, _ := newAnalyzeService(engine)
+			service.SetListRepository(&memoryLists{entries: tt.entries, err: tt.lookupErr})
+
+			decision, err := service.AnalyzeTransaction(context.Background(), evalCtx)
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			if decision.Decision != tt.wantDecision {
+				t.Errorf("decision %s, want %s", decision.Decision, tt.wantDecision)
+			}
+			if tt.wantReason == "" {
+				if engine.calls != 1 {
+					t.Errorf("rules evaluated %d times, want 1", engine.calls)
+				}
+				return
+			}
+			// A listed transaction is decided without running the rules
+			if engine.calls != 0 {
+				t.Errorf("rules evaluated %d times, want 0", engine.calls)
+			}
+			if len(decision.Reasons) == 0 || !strings.HasPrefix(decision.Reasons[0], tt.wantReason) {
+				t.Errorf("reasons %v, want one starting %q", decision.Reasons, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestListEntryValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   *ListEntry
+		wantErr error
+	}{
+		{"valid", NewListEntry(ListTypeDeny, ListEntityIP, "203.0.113.7", "", uuid.Nil), nil},
+		{"unknown list", NewListEntry("watch", ListEntityIP, "203.0.113.7", "", uuid.Nil), ErrInvalidListType},
+		{"unknown entity", NewListEntry(ListTypeDeny, "email", "a@example.com", "", uuid.Nil), ErrInvalidListEntity},
+		{"blank value", NewListEntry(ListTypeDeny, ListEntityDeviceID, "   ", "", uuid.Nil), ErrInvalidListValue},
+		{"user that is not a UUID", NewListEntry(ListTypeAllow, ListEntityUser, "alice", "", uuid.Nil), ErrInvalidListValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.entry.Validate(); err != tt.wantErr {
+				t.Errorf("error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
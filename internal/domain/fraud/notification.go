@@ -0,0 +1,45 @@
+package fraud
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserNotification tells a transaction's owner that it was blocked or challenged
+// so they can complete step-up verification or dispute the decision
+type UserNotification struct {
+	UserID        uuid.UUID    `json:"user_id"`
+	TransactionID uuid.UUID    `json:"transaction_id"`
+	DecisionID    uuid.UUID    `json:"decision_id"`
+	Decision      DecisionType `json:"decision"`
+	Reasons       []string     `json:"reasons"`
+	Timestamp     time.Time    `json:"timestamp"`
+}
+
+// NewUserNotification builds a user notification from a fraud decision
+func NewUserNotification(decision *FraudDecision) *UserNotification {
+	return &UserNotification{
+		UserID:        decision.UserID,
+		TransactionID: decision.TransactionID,
+		DecisionID:    decision.ID,
+		Decision:      decision.Decision,
+		Reasons:       decision.Reasons,
+		Timestamp:     decision.ProcessedAt,
+	}
+}
+
+// UserNotifier delivers decision notifications to end users (email, SMS, push)
+type UserNotifier interface {
+	// NotifyUser sends a notification to the transaction's owner
+	NotifyUser(ctx context.Context, notification *UserNotification) error
+}
+
+// NoopUserNotifier discards notifications; it is the default until a channel is configured
+type NoopUserNotifier struct{}
+
+// NotifyUser implements UserNotifier
+func (NoopUserNotifier) NotifyUser(ctx context.Context, notification *UserNotification) error {
+	return nil
+}
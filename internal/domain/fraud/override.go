@@ -0,0 +1,137 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/pkg/logger"
+)
+
+// DecisionOverride records an analyst replacing a decision's outcome with their own
+// The decision itself is never changed; its latest override is its effective decision
+type DecisionOverride struct {
+	ID                 uuid.UUID    `json:"id"`
+	DecisionID         uuid.UUID    `json:"decision_id"`
+	TransactionID      uuid.UUID    `json:"transaction_id"`
+	PreviousDecision   DecisionType `json:"previous_decision"` // Effective decision before the override
+	Decision           DecisionType `json:"decision"`
+	Reason             string       `json:"reason"`
+	TransactionUpdated bool         `json:"transaction_updated"` // Whether the transaction's status was changed to match
+	OverriddenBy       uuid.UUID    `json:"overridden_by"`
+	CreatedAt          time.Time    `json:"created_at"`
+}
+
+// NewDecisionOverride creates an override of a decision whose effective outcome is previous
+func NewDecisionOverride(decision *FraudDecision, previous, outcome DecisionType, reason string, overriddenBy uuid.UUID) *DecisionOverride {
+	return &DecisionOverride{
+		ID:               uuid.New(),
+		DecisionID:       decision.ID,
+		TransactionID:    decision.TransactionID,
+		PreviousDecision: previous,
+		Decision:         outcome,
+		Reason:           reason,
+		OverriddenBy:     overriddenBy,
+		CreatedAt:        time.Now(),
+	}
+}
+
+// TransactionOverrider moves a transaction to the status an overriding decision calls for
+// Implemented by transaction.Service. Unlike TransactionReviewer it also reverses a
+// transaction that was already approved or declined. applied is false when the
+// transaction isn't stored or already has that status.
+type TransactionOverrider interface {
+	ApproveOverride(ctx context.Context, txID, actorID uuid.UUID) (applied bool, err error)
+	DeclineOverride(ctx context.Context, txID, actorID uuid.UUID, reason string) (applied bool, err error)
+	FlagOverride(ctx context.Context, txID, actorID uuid.UUID, reason string) (applied bool, err error)
+}
+
+// OverrideDecision replaces a decision's effective outcome with allow, block or review
+// canOverrideBlock is whether the actor may override a decision that is currently
+// block. The override is stored first and the transaction updated after, so a
+// transaction never changes for an override that was not recorded. If the update
+// fails the override stands with TransactionUpdated false.
+func (s *Service) OverrideDecision(ctx context.Context, decisionID uuid.UUID, outcome DecisionType, reason string, actor uuid.UUID, canOverrideBlock bool) (*DecisionOverride, error) {
+	switch outcome {
+	case DecisionAllow, DecisionBlock, DecisionReview:
+	default:
+		return nil, ErrInvalidOverrideDecision
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrOverrideReasonRequired
+	}
+
+	decision, err := s.decisionRepo.GetByID(ctx, decisionID)
+	if err != nil {
+		return nil, err
+	}
+	previous := decision.EffectiveDecision()
+
+	if previous == outcome {
+		return nil, ErrOverrideUnchanged
+	}
+	if previous == DecisionBlock && !canOverrideBlock {
+		return nil, ErrBlockOverrideForbidden
+	}
+
+	// The repository only records the override if no other one landed since the read
+	override := NewDecisionOverride(decision, previous, outcome, reason, actor)
+	if err := s.decisionRepo.RecordOverride(ctx, override); err != nil {
+		return nil, err
+	}
+	if s.riskProfileCache != nil {
+		s.riskProfileCache.Delete(ctx, decision.UserID)
+	}
+
+	if s.transactionOverrider != nil {
+		if err := s.syncOverriddenTransaction(ctx, override); err != nil {
+			s.logger.ErrorContext(ctx, "overridden decision's transaction not updated",
+				"decision_id", decision.ID,
+				"transaction_id", decision.TransactionID,
+				logger.Err(err),
+			)
+		}
+	}
+
+	s.logger.InfoContext(ctx, "fraud decision overridden",
+		"decision_id", decision.ID,
+		"previous_decision", previous,
+		"decision", outcome,
+		"overridden_by", actor,
+		"transaction_updated", override.TransactionUpdated,
+	)
+	return override, nil
+}
+
+// syncOverriddenTransaction updates the override's transaction and records that it did
+func (s *Service) syncOverriddenTransaction(ctx context.Context, override *DecisionOverride) error {
+	applied, err := s.applyOverride(ctx, override)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction %s: %w", override.TransactionID, err)
+	}
+	if !applied {
+		return nil
+	}
+	if err := s.decisionRepo.MarkOverrideApplied(ctx, override.ID); err != nil {
+		return err
+	}
+	override.TransactionUpdated = true
+	return nil
+}
+
+// applyOverride approves, declines or flags the override's transaction to match it
+func (s *Service) applyOverride(ctx context.Context, override *DecisionOverride) (bool, error) {
+	reason := "Decision overridden: " + override.Reason
+	switch override.Decision {
+	case DecisionAllow:
+		return s.transactionOverrider.ApproveOverride(ctx, override.TransactionID, override.OverriddenBy)
+	case DecisionBlock:
+		return s.transactionOverrider.DeclineOverride(ctx, override.TransactionID, override.OverriddenBy, reason)
+	default:
+		return s.transactionOverrider.FlagOverride(ctx, override.TransactionID, override.OverriddenBy, reason)
+	}
+}
@@ -0,0 +1,125 @@
+package fraud
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// overrideRepo holds one decision and records overrides against it
+// raced, when set, lands as another analyst's override between the read and the write
+type overrideRepo struct {
+	DecisionRepository
+	decision *FraudDecision
+	raced    DecisionType
+	applied  []uuid.UUID
+}
+
+func (r *overrideRepo) GetByID(ctx context.Context, id uuid.UUID) (*FraudDecision, error) {
+	if id != r.decision.ID {
+		return nil, ErrDecisionNotFound
+	}
+	decision := *r.decision
+	return &decision, nil
+}
+
+func (r *overrideRepo) RecordOverride(ctx context.Context, override *DecisionOverride) error {
+	if r.raced != "" {
+		r.decision.OverriddenDecision = r.raced
+	}
+	if r.decision.EffectiveDecision() != override.PreviousDecision {
+		return ErrOverrideConflict
+	}
+	r.decision.OverriddenDecision = override.Decision
+	return nil
+}
+
+func (r *overrideRepo) MarkOverrideApplied(ctx context.Context, overrideID uuid.UUID) error {
+	r.applied = append(r.applied, overrideID)
+	return nil
+}
+
+// recordingOverrider records which transaction update each override asked for
+type recordingOverrider struct {
+	calls []string
+	err   error
+}
+
+func (o *recordingOverrider) ApproveOverride(ctx context.Context, txID, actorID uuid.UUID) (bool, error) {
+	o.calls = append(o.calls, "approve")
+	return o.err == nil, o.err
+}
+
+func (o *recordingOverrider) DeclineOverride(ctx context.Context, txID, actorID uuid.UUID, reason string) (bool, error) {
+	o.calls = append(o.calls, "decline")
+	return o.err == nil, o.err
+}
+
+func (o *recordingOverrider) FlagOverride(ctx context.Context, txID, actorID uuid.UUID, reason string) (bool, error) {
+	o.calls = append(o.calls, "flag")
+	return o.err == nil, o.err
+}
+
+func TestOverrideDecision(t *testing.T) {
+	errStore := errors.New("transaction store unavailable")
+
+	tests := []struct {
+		name             string
+		current          DecisionType
+		outcome          DecisionType
+		reason           string
+		canOverrideBlock bool
+		raced            DecisionType
+		overriderErr     error
+		wantErr          error
+		wantEffective    DecisionType
+		wantCall         string // Transaction update asked for, if any
+		wantUpdated      bool
+	}{
+		{"invalid outcome", DecisionReview, "escalate", "checked", true, "", nil, ErrInvalidOverrideDecision, DecisionReview, "", false},
+		{"blank reason", DecisionReview, DecisionAllow, "   ", true, "", nil, ErrOverrideReasonRequired, DecisionReview, "", false},
+		{"no change", DecisionReview, DecisionReview, "checked", true, "", nil, ErrOverrideUnchanged, DecisionReview, "", false},
+		{"block without permission", DecisionBlock, DecisionAllow, "checked", false, "", nil, ErrBlockOverrideForbidden, DecisionBlock, "", false},
+		{"block with permission", DecisionBlock, DecisionAllow, "checked", true, "", nil, nil, DecisionAllow, "approve", true},
+		{"review to block", DecisionReview, DecisionBlock, "confirmed fraud", false, "", nil, nil, DecisionBlock, "decline", true},
+		{"allow to review", DecisionAllow, DecisionReview, "needs a look", false, "", nil, nil, DecisionReview, "flag", true},
+		{"concurrent override wins", DecisionReview, DecisionAllow, "checked", true, DecisionBlock, nil, ErrOverrideConflict, DecisionBlock, "", false},
+		{"transaction update fails", DecisionReview, DecisionAllow, "checked", true, "", errStore, nil, DecisionAllow, "approve", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := NewFraudDecision(uuid.New(), uuid.New(), tt.current, decimal.RequireFromString("0.5"))
+			repo := &overrideRepo{decision: decision, raced: tt.raced}
+			overrider := &recordingOverrider{err: tt.overriderErr}
+			service := NewService(repo, nil, nil, nil, nil)
+			service.SetTransactionOverrider(overrider)
+
+			override, err := service.OverrideDecision(context.Background(), decision.ID, tt.outcome, tt.reason, uuid.New(), tt.canOverrideBlock)
+			if err != tt.wantErr {
+				t.Fatalf("error %v, want %v", err, tt.wantErr)
+			}
+			if got := repo.decision.EffectiveDecision(); got != tt.wantEffective {
+				t.Errorf("effective decision %s, want %s", got, tt.wantEffective)
+			}
+			if tt.wantCall == "" {
+				if len(overrider.calls) != 0 {
+					t.Errorf("transaction updates %v, want none", overrider.calls)
+				}
+				return
+			}
+
+			if len(overrider.calls) != 1 || overrider.calls[0] != tt.wantCall {
+				t.Errorf("transaction updates %v, want [%s]", overrider.calls, tt.wantCall)
+			}
+			if override.PreviousDecision != tt.current || override.Decision != tt.outcome {
+				t.Errorf("override from %s to %s, want %s to %s", override.PreviousDecision, override.Decision, tt.current, tt.outcome)
+			}
+			if override.TransactionUpdated != tt.wantUpdated || (len(repo.applied) == 1) != tt.wantUpdated {
+				t.Errorf("transaction updated %t with %d overrides marked applied, want %t", override.TransactionUpdated, len(repo.applied), tt.wantUpdated)
+			}
+		})
+	}
+}
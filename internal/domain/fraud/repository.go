@@ -7,6 +7,21 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultDecisionListLimit is the page size used when ListByUserID is given no limit
+const DefaultDecisionListLimit = 50
+
+// MaxDecisionLookupBatch is the most transaction IDs one GetDecisionsByTransactionIDs call accepts
+const MaxDecisionLookupBatch = 1000
+
+// DecisionListLimit returns the page size ListByUserID implementations should apply
+// A zero limit would otherwise return nothing, so it falls back to the default
+func DecisionListLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultDecisionListLimit
+	}
+	return limit
+}
+
 // DecisionRepository manages fraud decisions
 type DecisionRepository interface {
 	// Create stores a fraud decision
@@ -18,11 +33,44 @@ type DecisionRepository interface {
 	// GetByTransactionID retrieves decision for a transaction
 	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*FraudDecision, error)
 
-	// ListByUserID gets fraud decisions for a user
+	// GetByTransactionIDs retrieves the decisions for several transactions, keyed by transaction ID
+	// Transactions without a decision are left out of the map. Implementations should
+	// split long inputs so no single query carries an unbounded number of parameters.
+	GetByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) (map[uuid.UUID]*FraudDecision, error)
+
+	// ListByUserID gets fraud decisions for a user, newest first
+	// A zero or negative limit returns DefaultDecisionListLimit decisions
 	ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*FraudDecision, error)
 
-	// GetBlockedCount counts how many times a user has been blocked
+	// CountByUserID counts all fraud decisions for a user
+	CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	// GetBlockedCount counts how many times a user has been blocked, by effective decision
 	GetBlockedCount(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+
+	// ListBlockTimes gets when each of a user's blocks since a time was made, newest first
+	// Blocks are counted by effective decision, like GetBlockedCount
+	ListBlockTimes(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error)
+
+	// RecordFeedback stores an analyst's verdict on a decision
+	RecordFeedback(ctx context.Context, feedback *DecisionFeedback) error
+
+	// ListFeedback gets feedback on decisions made within [from, to), oldest feedback first
+	ListFeedback(ctx context.Context, from, to time.Time) ([]*DecisionFeedback, error)
+
+	// RecordOverride stores an analyst's override and makes it the decision's effective outcome
+	// It fails with ErrOverrideConflict unless the effective outcome is still
+	// override.PreviousDecision, so concurrent overrides are applied one at a time.
+	RecordOverride(ctx context.Context, override *DecisionOverride) error
+
+	// MarkOverrideApplied records that an override's transaction was updated to match it
+	MarkOverrideApplied(ctx context.Context, overrideID uuid.UUID) error
+
+	// ListOverrides gets a decision's overrides, oldest first
+	ListOverrides(ctx context.Context, decisionID uuid.UUID) ([]*DecisionOverride, error)
+
+	// ScoreHistogram counts the decisions made within [from, to) at each score, lowest score first
+	ScoreHistogram(ctx context.Context, from, to time.Time) ([]ScoreCount, error)
 }
 
 // CaseRepository manages fraud investigation cases
@@ -39,11 +87,27 @@ type CaseRepository interface {
 	// ListByStatus retrieves cases by status
 	ListByStatus(ctx context.Context, status CaseStatus, limit, offset int) ([]*FraudCase, error)
 
+	// CountByStatus counts cases in a status
+	CountByStatus(ctx context.Context, status CaseStatus) (int64, error)
+
 	// ListByAssignee retrieves cases assigned to an investigator
 	ListByAssignee(ctx context.Context, assigneeID uuid.UUID, limit, offset int) ([]*FraudCase, error)
 
 	// GetOpenCasesByUser checks if user has any open fraud cases
 	GetOpenCasesByUser(ctx context.Context, userID uuid.UUID) ([]*FraudCase, error)
+
+	// ListUnassigned retrieves open, unassigned cases in CompareCaseQueue order
+	ListUnassigned(ctx context.Context, limit, offset int) ([]*FraudCase, error)
+
+	// CountUnassigned counts open, unassigned cases
+	CountUnassigned(ctx context.Context) (int64, error)
+
+	// ClaimNext atomically assigns the first unassigned case to an investigator
+	// Returns ErrCaseQueueEmpty when there is none
+	ClaimNext(ctx context.Context, investigatorID uuid.UUID) (*FraudCase, error)
+
+	// ListOpenCreatedBefore retrieves open or investigating cases of a risk level created before a time, oldest first
+	ListOpenCreatedBefore(ctx context.Context, riskLevel RiskLevel, before time.Time, limit int) ([]*FraudCase, error)
 }
 
 // RuleRepository manages fraud detection rules
@@ -51,6 +115,9 @@ type RuleRepository interface {
 	// Create adds a new rule
 	Create(ctx context.Context, rule *Rule) error
 
+	// CreateBatch adds multiple rules atomically (all or none)
+	CreateBatch(ctx context.Context, rules []*Rule) error
+
 	// GetByID retrieves a rule by ID
 	GetByID(ctx context.Context, id uuid.UUID) (*Rule, error)
 
@@ -68,4 +135,35 @@ type RuleRepository interface {
 
 	// GetVersion retrieves a specific version of a rule
 	GetVersion(ctx context.Context, ruleID uuid.UUID, version int) (*Rule, error)
+
+	// ListVersions retrieves every recorded version of a rule, oldest first
+	ListVersions(ctx context.Context, ruleID uuid.UUID) ([]*RuleVersion, error)
+}
+
+// ListRepository manages allowlist and denylist entries
+type ListRepository interface {
+	// Create stores a new entry
+	// Returns ErrDuplicateListEntry if the list already has the entity
+	Create(ctx context.Context, entry *ListEntry) error
+
+	// GetByID retrieves an entry by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*ListEntry, error)
+
+	// Delete removes an entry
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// List retrieves the entries of a list, or of both lists when listType is empty
+	List(ctx context.Context, listType ListType) ([]*ListEntry, error)
+
+	// FindMatches retrieves the entries on either list matching any of the keys
+	FindMatches(ctx context.Context, keys []ListKey) ([]*ListEntry, error)
+}
+
+// ChargebackRepository manages chargebacks reported against users
+type ChargebackRepository interface {
+	// Create stores a chargeback
+	Create(ctx context.Context, chargeback *Chargeback) error
+
+	// CountByUserID counts a user's chargebacks that occurred at or after since
+	CountByUserID(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
 }
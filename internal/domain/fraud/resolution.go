@@ -0,0 +1,64 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ResolutionOutcome is what resolving a case does to its transactions
+type ResolutionOutcome string
+
+const (
+	OutcomeNone    ResolutionOutcome = ""        // Transactions are left as they are
+	OutcomeApprove ResolutionOutcome = "approve" // The flagged transactions were legitimate
+	OutcomeDecline ResolutionOutcome = "decline" // The flagged transactions were fraud
+)
+
+// IsValid reports whether the outcome is known
+func (o ResolutionOutcome) IsValid() bool {
+	switch o {
+	case OutcomeNone, OutcomeApprove, OutcomeDecline:
+		return true
+	}
+	return false
+}
+
+// TransactionReviewer approves and declines transactions on a reviewer's behalf
+// Implemented by transaction.Service. applied is false when the transaction is no
+// longer awaiting review, e.g. because it was already declined automatically.
+type TransactionReviewer interface {
+	ApproveAfterReview(ctx context.Context, txID, reviewerID uuid.UUID) (applied bool, err error)
+	DeclineAfterReview(ctx context.Context, txID, reviewerID uuid.UUID, reason string) (applied bool, err error)
+}
+
+// applyCaseOutcome approves or declines the case's transactions still awaiting review
+// Who overrode the automated decision is recorded on each transaction as its
+// reviewer, and in a case note listing what was changed. It runs before the case
+// is resolved, so a failure leaves the case open and resolving again is safe.
+func (s *Service) applyCaseOutcome(ctx context.Context, fraudCase *FraudCase, resolverID uuid.UUID, outcome ResolutionOutcome) error {
+	applied := 0
+	for _, txID := range fraudCase.TransactionIDs {
+		var ok bool
+		var err error
+		if outcome == OutcomeApprove {
+			ok, err = s.transactionReviewer.ApproveAfterReview(ctx, txID, resolverID)
+		} else {
+			ok, err = s.transactionReviewer.DeclineAfterReview(ctx, txID, resolverID, "Declined on resolution of case "+fraudCase.ID.String())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s transaction %s: %w", outcome, txID, err)
+		}
+		if ok {
+			applied++
+		}
+	}
+
+	note := fmt.Sprintf("Resolution outcome %s overrode the automated decision on %d of %d transactions", outcome, applied, len(fraudCase.TransactionIDs))
+	if applied < len(fraudCase.TransactionIDs) {
+		note += "; the rest were no longer awaiting review"
+	}
+	fraudCase.AddNote(resolverID, note)
+	return nil
+}
@@ -0,0 +1,113 @@
+package fraud
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// reviewCall is one transaction a reviewer was asked to approve or decline
+type reviewCall struct {
+	outcome    ResolutionOutcome
+	txID       uuid.UUID
+	reviewerID uuid.UUID
+}
+
+// recordingReviewer records review calls; transactions in settled are no longer awaiting review
+type recordingReviewer struct {
+	calls   []reviewCall
+	settled map[uuid.UUID]bool
+}
+
+func (r *recordingReviewer) ApproveAfterReview(ctx context.Context, txID, reviewerID uuid.UUID) (bool, error) {
+	r.calls = append(r.calls, reviewCall{OutcomeApprove, txID, reviewerID})
+	return !r.settled[txID], nil
+}
+
+func (r *recordingReviewer) DeclineAfterReview(ctx context.Context, txID, reviewerID uuid.UUID, reason string) (bool, error) {
+	r.calls = append(r.calls, reviewCall{OutcomeDecline, txID, reviewerID})
+	return !r.settled[txID], nil
+}
+
+func TestResolveCase(t *testing.T) {
+	tests := []struct {
+		name      string
+		outcome   ResolutionOutcome
+		wantErr   error
+		wantCalls int
+		wantNote  string // Start of the note left on the case, if any
+	}{
+		{"approve", OutcomeApprove, nil, 2, "Resolution outcome approve overrode the automated decision on 1 of 2 transactions"},
+		{"decline", OutcomeDecline, nil, 2, "Resolution outcome decline overrode the automated decision on 1 of 2 transactions"},
+		{"no outcome", OutcomeNone, nil, 0, ""},
+		{"invalid outcome", "refund", ErrInvalidResolutionOutcome, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fraudCase := NewFraudCase(uuid.New(), uuid.New(), uuid.New(), RiskLevelHigh)
+			settled := uuid.New()
+			fraudCase.TransactionIDs = append(fraudCase.TransactionIDs, settled)
+			reviewer := &recordingReviewer{settled: map[uuid.UUID]bool{settled: true}}
+			service := NewService(nil, &memoryCaseRepo{cases: map[uuid.UUID]*FraudCase{fraudCase.ID: fraudCase}}, nil, nil, nil)
+			service.SetTransactionReviewer(reviewer)
+			resolverID := uuid.New()
+
+			err := service.ResolveCase(context.Background(), fraudCase.ID, resolverID, "checked with the customer", tt.outcome)
+			if err != tt.wantErr {
+				t.Fatalf("error %v, want %v", err, tt.wantErr)
+			}
+			if len(reviewer.calls) != tt.wantCalls {
+				t.Fatalf("%d transactions reviewed, want %d", len(reviewer.calls), tt.wantCalls)
+			}
+			if tt.wantErr != nil {
+				if fraudCase.Status != CaseStatusOpen {
+					t.Errorf("case %s, want it left open", fraudCase.Status)
+				}
+				return
+			}
+
+			if fraudCase.Status != CaseStatusResolved || fraudCase.Outcome != tt.outcome {
+				t.Errorf("case %s with outcome %q, want resolved with %q", fraudCase.Status, fraudCase.Outcome, tt.outcome)
+			}
+			// Every change is made on the resolver's behalf, so the audit trail names them
+			if fraudCase.ResolvedBy == nil || *fraudCase.ResolvedBy != resolverID {
+				t.Errorf("resolved by %v, want %s", fraudCase.ResolvedBy, resolverID)
+			}
+			for i, call := range reviewer.calls {
+				if call.outcome != tt.outcome || call.txID != fraudCase.TransactionIDs[i] || call.reviewerID != resolverID {
+					t.Errorf("review %d was %+v, want %s of %s by %s", i, call, tt.outcome, fraudCase.TransactionIDs[i], resolverID)
+				}
+			}
+
+			if tt.wantNote == "" {
+				if len(fraudCase.Notes) != 0 {
+					t.Errorf("notes %+v, want none", fraudCase.Notes)
+				}
+				return
+			}
+			if len(fraudCase.Notes) != 1 {
+				t.Fatalf("%d notes, want 1", len(fraudCase.Notes))
+			}
+			if note := fraudCase.Notes[0]; !strings.HasPrefix(note.Content, tt.wantNote) || note.Author != resolverID {
+				t.Errorf("note %q by %s, want %q by %s", note.Content, note.Author, tt.wantNote, resolverID)
+			}
+		})
+	}
+}
+
+func TestResolveCaseWithoutReviewer(t *testing.T) {
+	fraudCase := NewFraudCase(uuid.New(), uuid.New(), uuid.New(), RiskLevelHigh)
+	service := NewService(nil, &memoryCaseRepo{cases: map[uuid.UUID]*FraudCase{fraudCase.ID: fraudCase}}, nil, nil, nil)
+
+	if err := service.ResolveCase(context.Background(), fraudCase.ID, uuid.New(), "fraud", OutcomeDecline); err != ErrTransactionReviewUnavailable {
+		t.Fatalf("error %v, want %v", err, ErrTransactionReviewUnavailable)
+	}
+	if err := service.ResolveCase(context.Background(), fraudCase.ID, uuid.New(), "fraud", OutcomeNone); err != nil {
+		t.Fatalf("resolve without outcome: %v", err)
+	}
+	if fraudCase.Status != CaseStatusResolved {
+		t.Errorf("case %s, want resolved", fraudCase.Status)
+	}
+}
@@ -0,0 +1,51 @@
+package fraud
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAnalyzeTransactionRetriesTransientRuleLoad(t *testing.T) {
+	transient := fmt.Errorf("failed to get active rules: %w", driver.ErrBadConn)
+	permanent := errors.New("failed to get active rules: invalid rule config")
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantErr   error
+		wantCalls int
+	}{
+		{"transient error succeeds on retry", []error{transient}, nil, 2},
+		{"retried only once", []error{transient, transient}, ErrEvaluationFailed, 2},
+		{"permanent error is not retried", []error{permanent}, ErrEvaluationFailed, 1},
+		{"no error", nil, nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := &stubEngine{results: []RuleResult{firedResult(RuleTypeAmount, 0.7)}, errs: tt.errs}
+			service, decisions := newAnalyzeService(engine)
+			service.SetEvaluationRetryBackoff(0)
+
+			evalCtx := fullContext()
+			decision, err := service.AnalyzeTransaction(context.Background(), evalCtx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err %v, want %v", err, tt.wantErr)
+			}
+			if engine.calls != tt.wantCalls {
+				t.Errorf("engine called %d times, want %d", engine.calls, tt.wantCalls)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if decision.Decision != DecisionReview || len(decision.RulesFired) != 1 {
+				t.Errorf("decision %s with rules %v, want review from the retried rule", decision.Decision, decision.RulesFired)
+			}
+			if _, ok := decisions.decisions[evalCtx.TransactionID]; !ok {
+				t.Error("decision not stored")
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package fraud
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/pkg/logger"
+)
+
+// RiskProfileConfig controls how a user's history is turned into a risk level
+type RiskProfileConfig struct {
+	// BlockedHalfLife weighs each past block by its age, halving its weight every
+	// half-life, so users recover after a stretch of clean behavior.
+	// Zero counts every block in the last 30 days equally.
+	BlockedHalfLife time.Duration
+
+	// DecisionWeight is the most a user's history can add to a live decision's score.
+	// The profile's history score is scaled by it, so a 0.5 history score with a weight
+	// of 0.2 adds 0.1. Zero keeps history out of live decisions.
+	DecisionWeight decimal.Decimal
+}
+
+// DefaultRiskProfileConfig counts blocks without decay and keeps history out of live decisions
+func DefaultRiskProfileConfig() RiskProfileConfig {
+	return RiskProfileConfig{}
+}
+
+// blockedWindow is how far back a user's risk profile counts blocks
+const blockedWindow = 30 * 24 * time.Hour
+
+// weightedBlockedCount sums the blocks, each weighted by 0.5^(age/halfLife)
+// A block made now counts as 1, one a half-life ago as 0.5
+func weightedBlockedCount(blockTimes []time.Time, halfLife time.Duration, now time.Time) float64 {
+	if halfLife <= 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, blockedAt := range blockTimes {
+		age := now.Sub(blockedAt)
+		if age < 0 {
+			age = 0
+		}
+		total += math.Pow(0.5, float64(age)/float64(halfLife))
+	}
+	return total
+}
+
+// RiskProfileCache keeps recently built user risk profiles for live decisions
+type RiskProfileCache interface {
+	// Get returns the cached profile for a user; false on a miss or cache error
+	Get(ctx context.Context, userID uuid.UUID) (*UserRiskProfile, bool)
+
+	// Set caches a profile until it expires
+	Set(ctx context.Context, profile *UserRiskProfile)
+
+	// Delete drops a user's cached profile, e.g. after one of their decisions is overridden
+	Delete(ctx context.Context, userID uuid.UUID)
+}
+
+// historyBoost returns how much a user's history raises the score of a new transaction,
+// with the profile it came from. A failed profile lookup is logged and adds nothing.
+func (s *Service) historyBoost(ctx context.Context, userID uuid.UUID) (decimal.Decimal, *UserRiskProfile) {
+	if !s.riskProfile.DecisionWeight.IsPositive() {
+		return decimal.Zero, nil
+	}
+
+	profile := s.liveRiskProfile(ctx, userID)
+	if profile == nil {
+		return decimal.Zero, nil
+	}
+	return profile.HistoryScore.Mul(s.riskProfile.DecisionWeight).Round(4), profile
+}
+
+// liveRiskProfile returns the user's risk profile, from the cache when possible
+func (s *Service) liveRiskProfile(ctx context.Context, userID uuid.UUID) *UserRiskProfile {
+	if s.riskProfileCache != nil {
+		if profile, ok := s.riskProfileCache.Get(ctx, userID); ok {
+			return profile
+		}
+	}
+
+	profile, err := s.GetUserRiskProfile(ctx, userID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "user risk profile lookup failed",
+			slog.String(logger.KeyUserID, userID.String()),
+			logger.Err(err),
+		)
+		return nil
+	}
+
+	if s.riskProfileCache != nil {
+		s.riskProfileCache.Set(ctx, profile)
+	}
+	return profile
+}
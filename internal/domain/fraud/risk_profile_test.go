@@ -0,0 +1,88 @@
+package fraud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// blockHistoryRepo reports a fixed set of block times and no other decisions
+type blockHistoryRepo struct {
+	DecisionRepository
+	blockTimes []time.Time
+}
+
+func (r *blockHistoryRepo) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*FraudDecision, error) {
+	return nil, nil
+}
+
+func (r *blockHistoryRepo) ListBlockTimes(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	for _, blockedAt := range r.blockTimes {
+		if !blockedAt.Before(since) {
+			times = append(times, blockedAt)
+		}
+	}
+	return times, nil
+}
+
+// blockedProfile returns the risk profile of a user blocked count times, each age ago
+func blockedProfile(t *testing.T, halfLife time.Duration, count int, age time.Duration) *UserRiskProfile {
+	t.Helper()
+	repo := &blockHistoryRepo{}
+	for i := 0; i < count; i++ {
+		repo.blockTimes = append(repo.blockTimes, time.Now().Add(-age))
+	}
+	service := NewService(repo, &memoryCaseRepo{cases: make(map[uuid.UUID]*FraudCase)}, nil, nil, nil)
+	service.SetRiskProfileConfig(RiskProfileConfig{BlockedHalfLife: halfLife})
+
+	profile, err := service.GetUserRiskProfile(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("risk profile: %v", err)
+	}
+	return profile
+}
+
+func TestGetUserRiskProfileBlockedDecay(t *testing.T) {
+	const day = 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		halfLife  time.Duration
+		count     int
+		wantDecay bool // Recent blocks score above old ones
+	}{
+		{"two blocks decay", 7 * day, 2, true},
+		{"several blocks decay", 7 * day, 4, true},
+		{"no half-life counts blocks equally", 0, 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recent := blockedProfile(t, tt.halfLife, tt.count, day)
+			old := blockedProfile(t, tt.halfLife, tt.count, 25*day)
+
+			if recent.BlockedCount != int64(tt.count) || old.BlockedCount != int64(tt.count) {
+				t.Fatalf("blocked counts %d and %d, want %d", recent.BlockedCount, old.BlockedCount, tt.count)
+			}
+			if !tt.wantDecay {
+				if recent.WeightedBlockedCount != nil || !recent.HistoryScore.Equal(old.HistoryScore) || recent.RiskLevel != old.RiskLevel {
+					t.Errorf("recent blocks score %s (%s), old %s (%s); want equal and unweighted",
+						recent.HistoryScore, recent.RiskLevel, old.HistoryScore, old.RiskLevel)
+				}
+				return
+			}
+
+			if recent.WeightedBlockedCount == nil || old.WeightedBlockedCount == nil {
+				t.Fatal("no weighted blocked count with a half-life set")
+			}
+			if *recent.WeightedBlockedCount <= *old.WeightedBlockedCount {
+				t.Errorf("weighted count %.2f for recent blocks, want above %.2f for old ones", *recent.WeightedBlockedCount, *old.WeightedBlockedCount)
+			}
+			if !recent.HistoryScore.GreaterThan(old.HistoryScore) {
+				t.Errorf("history score %s for recent blocks, want above %s for old ones", recent.HistoryScore, old.HistoryScore)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package fraud
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,14 +13,30 @@ import (
 type RuleType string
 
 const (
-	RuleTypeVelocity   RuleType = "velocity"   // Transaction frequency
-	RuleTypeAmount     RuleType = "amount"     // Transaction amount threshold
-	RuleTypeGeographic RuleType = "geographic" // Location-based
-	RuleTypeDevice     RuleType = "device"     // Device fingerprinting
-	RuleTypeMerchant   RuleType = "merchant"   // Merchant risk
-	RuleTypeBehavioral RuleType = "behavioral" // User behavior patterns
+	RuleTypeVelocity       RuleType = "velocity"                // Transaction frequency
+	RuleTypeAmount         RuleType = "amount"                  // Transaction amount threshold
+	RuleTypeGeographic     RuleType = "geographic"              // Location-based
+	RuleTypeDevice         RuleType = "device"                  // Device fingerprinting
+	RuleTypeMerchant       RuleType = "merchant"                // Merchant risk
+	RuleTypeBehavioral     RuleType = "behavioral"              // User behavior patterns
+	RuleTypeIPReputation   RuleType = "ip_reputation"           // Known-bad IP ranges
+	RuleTypeCardTesting    RuleType = "card_testing"            // Small-amount probing across cards
+	RuleTypeChargeback     RuleType = "chargeback"              // Prior chargebacks against the user
+	RuleTypeLinking        RuleType = "account_linking"         // Many users sharing a device, IP or card
+	RuleTypeIssuerMismatch RuleType = "issuer_country_mismatch" // Card used outside its issuing country
 )
 
+// ReadsHistory reports whether rules of the type read per-user or per-entity history
+// Velocity windows, last locations, device and merchant sets, profiles, chargebacks
+// and account links are kept live, so these rules can't be replayed as of a past transaction
+func (t RuleType) ReadsHistory() bool {
+	switch t {
+	case RuleTypeAmount, RuleTypeIPReputation, RuleTypeIssuerMismatch:
+		return false
+	}
+	return true
+}
+
 // RuleSeverity indicates how serious a rule violation is
 type RuleSeverity string
 
@@ -40,6 +57,32 @@ const (
 	ActionChallenge RuleAction = "challenge"
 )
 
+// RuleFailMode decides how a rule is scored when the data it needs can't be read
+type RuleFailMode string
+
+const (
+	FailModeOpen   RuleFailMode = "open"   // Treat the rule as not fired
+	FailModeClosed RuleFailMode = "closed" // Fire the rule with its configured action
+)
+
+// IsValid checks if the fail mode is known; empty means open
+func (m RuleFailMode) IsValid() bool {
+	return m == "" || m == FailModeOpen || m == FailModeClosed
+}
+
+// RuleMode decides whether a rule's results count toward decisions
+type RuleMode string
+
+const (
+	RuleModeActive RuleMode = "active" // Scored and decided on
+	RuleModeShadow RuleMode = "shadow" // Evaluated and recorded, but left out of the score and decision
+)
+
+// IsValid checks if the mode is known; empty means active
+func (m RuleMode) IsValid() bool {
+	return m == "" || m == RuleModeActive || m == RuleModeShadow
+}
+
 // Rule represents a fraud detection rule
 // Rules are configurable and versioned - not hardcoded
 type Rule struct {
@@ -49,6 +92,9 @@ type Rule struct {
 	Type        RuleType                   `json:"type"`
 	Severity    RuleSeverity               `json:"severity"`
 	Action      RuleAction                 `json:"action"`
+	FailMode    RuleFailMode               `json:"fail_mode"` // How the rule scores when its data source errors
+	Mode        RuleMode                   `json:"mode"`      // Shadow rules are observed without affecting decisions
+	Priority    int                        `json:"priority"`  // Higher runs first under the per-transaction rule limit
 
 	// Configuration - JSON blob for flexibility
 	// Example for velocity: {"max_transactions": 5, "window_minutes": 5, "amount_threshold": "1000"}
@@ -58,6 +104,7 @@ type Rule struct {
 	Enabled     bool                       `json:"enabled"`
 	Version     int                        `json:"version"`
 	CreatedBy   uuid.UUID                  `json:"created_by"`
+	UpdatedBy   uuid.UUID                  `json:"updated_by"`
 
 	// Timestamps
 	CreatedAt   time.Time                  `json:"created_at"`
@@ -66,14 +113,39 @@ type Rule struct {
 	ExpiresAt   *time.Time                 `json:"expires_at,omitempty"`
 }
 
+// RuleVersion is a snapshot of a rule as of one version
+// Every create and update records one, so the history shows when a rule changed and who changed it
+type RuleVersion struct {
+	Rule      *Rule     `json:"rule"`
+	ChangedBy uuid.UUID `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// RuleDataStatus says whether a rule had what it needed to judge the transaction
+// A rule that couldn't look returns the same non-fired allow as one that looked and found nothing
+type RuleDataStatus string
+
+const (
+	RuleDataEvaluated    RuleDataStatus = "evaluated"         // Checked the transaction with the data it needs
+	RuleDataInsufficient RuleDataStatus = "insufficient_data" // The request lacked the context the rule reads, e.g. no device or location
+	RuleDataErrored      RuleDataStatus = "errored"           // A dependency failed or the rule timed out
+	RuleDataNotRun       RuleDataStatus = "not_run"           // Inactive, outside its schedule or of an unknown type; left out of coverage
+)
+
 // RuleResult represents the outcome of evaluating a rule
 type RuleResult struct {
 	RuleID      uuid.UUID                  `json:"rule_id"`
 	RuleName    string                     `json:"rule_name"`
+	RuleType    RuleType                   `json:"rule_type"`
 	Fired       bool                       `json:"fired"`
 	Score       decimal.Decimal            `json:"score"` // 0.0 to 1.0
 	Reason      string                     `json:"reason"`
 	Action      RuleAction                 `json:"action"`
+	Skipped     bool                       `json:"skipped,omitempty"` // Not evaluated because of load shedding, the rule limit or an early stop
+	StoppedEvaluation bool                 `json:"stopped_evaluation,omitempty"` // A critical block rule that ended evaluation early
+	Degraded    bool                       `json:"degraded,omitempty"` // Could not run because a dependency it needs (Redis, the database) was unavailable
+	Shadow      bool                       `json:"shadow,omitempty"`   // From a shadow mode rule, so left out of the score and decision
+	DataStatus  RuleDataStatus             `json:"data_status"`
 	Metadata    map[string]interface{}     `json:"metadata,omitempty"`
 	EvaluatedAt time.Time                  `json:"evaluated_at"`
 }
@@ -97,6 +169,9 @@ type RuleEngine interface {
 
 	// DisableRule disables a rule
 	DisableRule(ctx context.Context, ruleID uuid.UUID) error
+
+	// InvalidateCache forces active rules to be reloaded on next evaluation
+	InvalidateCache()
 }
 
 // RuleEvaluationContext contains all data needed to evaluate rules
@@ -107,6 +182,11 @@ type RuleEvaluationContext struct {
 	Amount        decimal.Decimal
 	Currency      string
 	Timestamp     time.Time
+	TenantID      string // Selects tenant scoring config; empty uses the global config
+
+	// Amount in the base currency; BaseCurrency is empty when it wasn't converted
+	BaseAmount   decimal.Decimal
+	BaseCurrency string
 
 	// Context data for different rule types
 	Location  *GeoLocation
@@ -118,6 +198,55 @@ type RuleEvaluationContext struct {
 	RecentTransactions []TransactionSummary
 	UserProfile        *UserProfile
 	DeviceHistory      []DeviceRecord
+
+	// How far back RecentTransactions holds every velocity-cache entry; zero when it
+	// wasn't loaded from the velocity cache. Rules with windows inside it read the
+	// history here instead of querying the cache again.
+	RecentWindow time.Duration
+
+	// What the user's history says about this transaction, looked up once during
+	// enrichment; nil when it wasn't enriched, e.g. backtests
+	Known *KnownHistory
+
+	// ML model prediction, set by the caller when ML scoring is enabled
+	MLScore *MLScore
+
+	// Optional deferred ML scoring, run after the rules unless a critical block
+	// rule stopped evaluation early; ignored when MLScore is already set
+	ScoreML func(ctx context.Context) *MLScore
+}
+
+// RecentActivity counts and sums the recent transactions within window
+// ok is false when the loaded history doesn't reach back that far
+func (c *RuleEvaluationContext) RecentActivity(window time.Duration) (count int64, total decimal.Decimal, ok bool) {
+	if c.RecentWindow <= 0 || window > c.RecentWindow {
+		return 0, decimal.Zero, false
+	}
+
+	// Match the velocity cache's whole-second window bounds
+	now := time.Now()
+	minTime := now.Add(-window).Unix()
+	maxTime := now.Unix()
+	total = decimal.Zero
+	for _, tx := range c.RecentTransactions {
+		if ts := tx.Timestamp.Unix(); ts >= minTime && ts <= maxTime {
+			count++
+			total = total.Add(tx.Amount)
+		}
+	}
+	return count, total, true
+}
+
+// KnownHistory records whether the user has used the transaction's device, location and merchant before
+// Rules and ML features read these instead of each asking the caches, so they see the
+// same answers. A nil field wasn't looked up or its lookup failed; readers then fall
+// back to asking the cache themselves.
+type KnownHistory struct {
+	Device      *bool
+	DeviceCount *int64 // Distinct devices the user has transacted from
+	Location    *bool  // Country and city
+	Country     *bool
+	Merchant    *bool
 }
 
 // TransactionSummary is a lightweight transaction record for rule evaluation
@@ -135,8 +264,9 @@ type UserProfile struct {
 	AccountAge         time.Duration
 	TypicalLocations   []string
 	TypicalMerchants   []string
-	AverageTransaction decimal.Decimal
+	AverageTransaction decimal.Decimal // In the base currency
 	TrustedDevices     []string
+	TypicalHours       []int // Hours of the day (0-23) the user has transacted in
 	LastActivityAt     time.Time
 }
 
@@ -159,6 +289,22 @@ type GeoLocation struct {
 	IPAddress string  `json:"ip_address"`
 }
 
+// HasCoordinates reports whether the location carries a usable latitude and longitude
+// Out-of-range values are treated as missing, so a bogus reading never reaches a distance calculation
+func (l *GeoLocation) HasCoordinates() bool {
+	return l != nil && ValidCoordinates(l.Latitude, l.Longitude)
+}
+
+// ValidCoordinates reports whether lat and lon are a real position
+// Latitude must be within [-90, 90] and longitude within [-180, 180]. (0, 0) means
+// no coordinates were sent. NaN fails every comparison and is rejected too.
+func ValidCoordinates(lat, lon float64) bool {
+	if lat == 0 && lon == 0 {
+		return false
+	}
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
 // DeviceInfo captures device fingerprint data
 type DeviceInfo struct {
 	DeviceID        string    `json:"device_id"`
@@ -183,41 +329,233 @@ type MerchantInfo struct {
 type PaymentMethod struct {
 	Type           string `json:"type"`            // card, bank_account, wallet
 	Last4          string `json:"last4"`           // Last 4 digits
+	BIN            string `json:"bin,omitempty"`   // First 6 digits (issuer identification)
 	Network        string `json:"network"`         // Visa, Mastercard, ACH, etc
 	BankID         string `json:"bank_id"`         // Which bank
 	IssuingCountry string `json:"issuing_country"`
 }
 
+// IsCrossBorder reports whether a card is used outside the country that issued it
+// A location or issuing country that isn't known is never cross-border, so
+// rules and ML features only see a mismatch when both countries were sent
+func IsCrossBorder(location *GeoLocation, payment *PaymentMethod) bool {
+	if location == nil || payment == nil || location.Country == "" || payment.IssuingCountry == "" {
+		return false
+	}
+	return !strings.EqualFold(location.Country, payment.IssuingCountry)
+}
+
 // VelocityRuleConfig defines configuration for velocity checks
 type VelocityRuleConfig struct {
 	MaxTransactions int             `json:"max_transactions"`
 	WindowMinutes   int             `json:"window_minutes"`
-	AmountThreshold decimal.Decimal `json:"amount_threshold,omitempty"`
+	AmountThreshold decimal.Decimal `json:"amount_threshold,omitempty"` // In the engine's base currency
 	CountOnly       bool            `json:"count_only"` // Count transactions or sum amounts
+
+	// How count and amount violations combine when both limits are exceeded
+	CombineMode  VelocityCombineMode `json:"combine_mode,omitempty"`
+	CountWeight  float64             `json:"count_weight,omitempty"`  // Scales the count score, 0-1
+	AmountWeight float64             `json:"amount_weight,omitempty"` // Scales the amount score, 0-1
+
+	// Tiers replace the single window above with several limits checked together,
+	// e.g. 3 per minute and 10 per hour and 30 per day
+	Tiers []VelocityTier `json:"tiers,omitempty"`
+}
+
+// VelocityTier is one window of a multi-tier velocity policy
+// A zero MaxCount or MaxAmount leaves that dimension unchecked
+type VelocityTier struct {
+	WindowMinutes int             `json:"window_minutes"`
+	MaxCount      int             `json:"max_count,omitempty"`
+	MaxAmount     decimal.Decimal `json:"max_amount,omitempty"` // In the engine's base currency
 }
 
+// VelocityCombineMode defines how count and amount velocity scores are combined
+type VelocityCombineMode string
+
+const (
+	VelocityCombineMax           VelocityCombineMode = "max"           // Highest weighted score wins
+	VelocityCombineProbabilistic VelocityCombineMode = "probabilistic" // 1 - (1-count)(1-amount), so both together score higher
+)
+
 // AmountRuleConfig defines configuration for amount-based rules
 type AmountRuleConfig struct {
-	MinAmount       decimal.Decimal `json:"min_amount,omitempty"`
-	MaxAmount       decimal.Decimal `json:"max_amount,omitempty"`
+	MinAmount       decimal.Decimal `json:"min_amount,omitempty"` // In the engine's base currency
+	MaxAmount       decimal.Decimal `json:"max_amount,omitempty"` // In the engine's base currency
 	DeviationFactor float64         `json:"deviation_factor,omitempty"` // X times user's average
+
+	// Per-currency thresholds keyed by ISO code, used instead of MinAmount/MaxAmount
+	// for transactions in that currency and compared with the unconverted amount
+	MinAmountByCurrency map[string]decimal.Decimal `json:"min_amount_by_currency,omitempty"`
+	MaxAmountByCurrency map[string]decimal.Decimal `json:"max_amount_by_currency,omitempty"`
+}
+
+// Thresholds returns the min and max amounts that apply to a currency
+func (c AmountRuleConfig) Thresholds(currency string) (minAmount, maxAmount decimal.Decimal) {
+	minAmount, maxAmount = c.MinAmount, c.MaxAmount
+	currency = strings.ToUpper(currency)
+	if v, ok := c.MinAmountByCurrency[currency]; ok {
+		minAmount = v
+	}
+	if v, ok := c.MaxAmountByCurrency[currency]; ok {
+		maxAmount = v
+	}
+	return minAmount, maxAmount
+}
+
+// CurrencyThresholds reports whether the min and max amounts have an entry for a currency
+func (c AmountRuleConfig) CurrencyThresholds(currency string) (hasMin, hasMax bool) {
+	currency = strings.ToUpper(currency)
+	_, hasMin = c.MinAmountByCurrency[currency]
+	_, hasMax = c.MaxAmountByCurrency[currency]
+	return hasMin, hasMax
 }
 
 // GeographicRuleConfig defines configuration for location-based rules
 type GeographicRuleConfig struct {
 	AllowedCountries  []string `json:"allowed_countries,omitempty"`
 	BlockedCountries  []string `json:"blocked_countries,omitempty"`
-	MaxDistanceKm     float64  `json:"max_distance_km,omitempty"` // Max distance from last known location
-	RequireConsistent bool     `json:"require_consistent"`        // Location must match previous pattern
+	MaxDistanceKm     float64  `json:"max_distance_km,omitempty"`      // Max distance from last known location
+	MaxTravelSpeedKmH float64  `json:"max_travel_speed_kmh,omitempty"` // Beyond MaxDistanceKm, faster travel than this is impossible
+	RequireConsistent bool     `json:"require_consistent"`             // Location must match previous pattern
+
+	// New-location signal tuning (used when RequireConsistent is set)
+	NewLocationScore   decimal.Decimal `json:"new_location_score,omitempty"`   // Score for a never-seen country
+	NewLocationAction  RuleAction      `json:"new_location_action,omitempty"`  // Action for a never-seen country
+	KnownCountryScore  decimal.Decimal `json:"known_country_score,omitempty"`  // Score for a new city in a previously seen country
+	KnownCountryAction RuleAction      `json:"known_country_action,omitempty"` // Action for a new city in a previously seen country
+
+	// Max distance between the IP-derived and reported GPS location (requires a GeoIP resolver)
+	MaxIPDistanceKm float64 `json:"max_ip_distance_km,omitempty"`
+
+	// Region (state/province) checks, keyed as "<country>-<region>", e.g. "US-CA"
+	// Allowed regions only restrict countries that have at least one entry
+	AllowedRegions          []string        `json:"allowed_regions,omitempty"`
+	BlockedRegions          []string        `json:"blocked_regions,omitempty"`
+	BlockedRegionScore      decimal.Decimal `json:"blocked_region_score,omitempty"`     // Score for a blocked region
+	NonAllowedRegionScore   decimal.Decimal `json:"non_allowed_region_score,omitempty"` // Score for a region outside allowed_regions
+	RequireConsistentRegion bool            `json:"require_consistent_region"`          // Flag a never-seen region in a known country
+	NewRegionScore          decimal.Decimal `json:"new_region_score,omitempty"`         // Score for a new region in a known country
+	NewRegionAction         RuleAction      `json:"new_region_action,omitempty"`        // Action for a new region in a known country
+}
+
+// RegionKey returns the "<country>-<region>" key region lists are matched against
+// Regions already given as ISO 3166-2 codes (e.g. "US-CA") are used as is
+func RegionKey(country, region string) string {
+	if region == "" {
+		return ""
+	}
+	key := strings.ToUpper(region)
+	prefix := strings.ToUpper(country) + "-"
+	if strings.HasPrefix(key, prefix) {
+		return key
+	}
+	return prefix + key
 }
 
 // DeviceRuleConfig defines configuration for device-based rules
 type DeviceRuleConfig struct {
 	RequireTrustedDevice bool `json:"require_trusted_device"`
 	MaxDevicesPerUser    int  `json:"max_devices_per_user,omitempty"`
+	MaxUsersPerDevice    int  `json:"max_users_per_device,omitempty"` // Distinct accounts one device may transact for
 	BlockNewDevices      bool `json:"block_new_devices"`
 }
 
+// MerchantRuleConfig defines configuration for merchant-based rules
+type MerchantRuleConfig struct {
+	MaxNewMerchants          int `json:"max_new_merchants,omitempty"`           // Novel merchants allowed per window before firing
+	NewMerchantWindowMinutes int `json:"new_merchant_window_minutes,omitempty"` // Window for counting novel merchants
+
+	// Merchant category codes that raise the score (gambling, crypto, etc.)
+	HighRiskMCCs   []string        `json:"high_risk_mccs,omitempty"`
+	HighRiskScore  decimal.Decimal `json:"high_risk_score,omitempty"`
+	HighRiskAction RuleAction      `json:"high_risk_action,omitempty"`
+
+	// Merchant category codes that are refused outright
+	BlockedMCCs     []string        `json:"blocked_mccs,omitempty"`
+	BlockedMCCScore decimal.Decimal `json:"blocked_mcc_score,omitempty"`
+	BlockedAction   RuleAction      `json:"blocked_action,omitempty"`
+}
+
+// BehavioralRuleConfig defines configuration for behavioral rules
+type BehavioralRuleConfig struct {
+	// Account age risk is NewAccountScore inside the new-account window, then halves
+	// every AgeHalfLifeHours as the account ages past it
+	NewAccountWindowHours float64         `json:"new_account_window_hours,omitempty"`
+	AgeHalfLifeHours      float64         `json:"age_half_life_hours,omitempty"`
+	NewAccountScore       decimal.Decimal `json:"new_account_score,omitempty"`
+	MinAgeRisk            decimal.Decimal `json:"min_age_risk,omitempty"` // Age risk below this doesn't fire the rule
+
+	// Static unusual-hour window, inclusive; a start after the end wraps past midnight
+	// Used unless the user's profile has at least MinTypicalHours typical hours (0 always uses it)
+	UnusualHourStart int `json:"unusual_hour_start"`
+	UnusualHourEnd   int `json:"unusual_hour_end"`
+	MinTypicalHours  int `json:"min_typical_hours"`
+
+	DormantDays int `json:"dormant_days"` // Inactivity after which an account counts as dormant
+}
+
+// CardTestingRuleConfig defines configuration for card-testing rules
+// Attempts are counted per BIN, or per user and card network when no BIN is sent
+type CardTestingRuleConfig struct {
+	MaxSmallTransactions int             `json:"max_small_transactions"`
+	WindowMinutes        int             `json:"window_minutes"`
+	SmallAmountCeiling   decimal.Decimal `json:"small_amount_ceiling"` // In the engine's base currency
+}
+
+// IPReputationRuleConfig defines configuration for IP reputation rules
+type IPReputationRuleConfig struct {
+	BlockTorExits      bool            `json:"block_tor_exits"`
+	BlockDatacenterIPs bool            `json:"block_datacenter_ips"`
+	BlockedCIDRs       []string        `json:"blocked_cidrs,omitempty"` // Extra ranges or single IPs specific to this rule
+	TorExitScore       decimal.Decimal `json:"tor_exit_score,omitempty"`
+	DatacenterScore    decimal.Decimal `json:"datacenter_score,omitempty"`
+	BlockedCIDRScore   decimal.Decimal `json:"blocked_cidr_score,omitempty"`
+}
+
+// ChargebackRuleConfig defines configuration for chargeback history rules
+// The score comes from the highest threshold the user's chargeback count in the window
+// reaches. RecentBoost is added on top when any of them fall within RecentDays.
+type ChargebackRuleConfig struct {
+	WindowDays  int                   `json:"window_days"`
+	Thresholds  []ChargebackThreshold `json:"thresholds"`
+	RecentDays  int                   `json:"recent_days,omitempty"`
+	RecentBoost decimal.Decimal       `json:"recent_boost,omitempty"`
+}
+
+// ChargebackThreshold is the score for a user with at least MinCount chargebacks
+type ChargebackThreshold struct {
+	MinCount int             `json:"min_count"`
+	Score    decimal.Decimal `json:"score"`
+}
+
+// LinkAttribute is a transaction attribute that links the users sharing it
+type LinkAttribute string
+
+const (
+	LinkDevice LinkAttribute = "device" // Device ID
+	LinkIP     LinkAttribute = "ip"     // Location IP address
+	LinkCard   LinkAttribute = "card"   // Card BIN and last four digits
+)
+
+// LinkingRuleConfig defines configuration for account linking rules
+// The distinct users seen on each of the transaction's attributes in the window are
+// counted, and the rule fires on the largest cluster above MaxLinkedUsers
+type LinkingRuleConfig struct {
+	Attributes     []LinkAttribute `json:"attributes,omitempty"` // Defaults to device, IP and card
+	MaxLinkedUsers int             `json:"max_linked_users"`
+	WindowMinutes  int             `json:"window_minutes"`
+}
+
+// IssuerMismatchRuleConfig defines configuration for issuer country mismatch rules
+// The rule fires when a card is used outside its issuing country, and scores
+// higher when the merchant is in a third country too
+type IssuerMismatchRuleConfig struct {
+	Score             decimal.Decimal `json:"score"`                      // Location differs from the issuing country
+	ThreeCountryScore decimal.Decimal `json:"three_country_score"`        // Merchant country differs from both as well
+	TravelCorridors   []string        `json:"travel_corridors,omitempty"` // Country pairs like "US-CA", exempt in either direction
+}
+
 // NewRule creates a new fraud detection rule
 func NewRule(name, description string, ruleType RuleType, severity RuleSeverity, action RuleAction, createdBy uuid.UUID) *Rule {
 	now := time.Now()
@@ -228,16 +566,29 @@ func NewRule(name, description string, ruleType RuleType, severity RuleSeverity,
 		Type:        ruleType,
 		Severity:    severity,
 		Action:      action,
+		FailMode:    FailModeOpen,
+		Mode:        RuleModeActive,
 		Config:      make(map[string]interface{}),
 		Enabled:     true,
 		Version:     1,
 		CreatedBy:   createdBy,
+		UpdatedBy:   createdBy,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 		EffectiveAt: now,
 	}
 }
 
+// FailsClosed reports whether the rule fires when it can't be evaluated
+func (r *Rule) FailsClosed() bool {
+	return r.FailMode == FailModeClosed
+}
+
+// IsShadow reports whether the rule runs in shadow mode
+func (r *Rule) IsShadow() bool {
+	return r.Mode == RuleModeShadow
+}
+
 // IsActive checks if the rule is currently active
 func (r *Rule) IsActive() bool {
 	now := time.Now()
@@ -292,11 +643,23 @@ func NewRuleResult(ruleID uuid.UUID, ruleName string, fired bool, score decimal.
 		Score:       score,
 		Reason:      reason,
 		Action:      action,
+		DataStatus:  RuleDataEvaluated,
 		Metadata:    make(map[string]interface{}),
 		EvaluatedAt: time.Now(),
 	}
 }
 
+// Ran reports whether the rule looked at the transaction at all
+func (rr *RuleResult) Ran() bool {
+	return rr.DataStatus != RuleDataNotRun
+}
+
+// HasData reports whether the rule had the data it needs
+// Results without a status, from engines that don't set one, count as evaluated
+func (rr *RuleResult) HasData() bool {
+	return rr.DataStatus == "" || rr.DataStatus == RuleDataEvaluated
+}
+
 // AddMetadata adds metadata to the rule result
 func (rr *RuleResult) AddMetadata(key string, value interface{}) {
 	if rr.Metadata == nil {
@@ -0,0 +1,148 @@
+package fraud
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RuleSchedule limits a rule to recurring hours and days, e.g. overnight or at weekends
+// A rule without one is active all the time, within its EffectiveAt and ExpiresAt.
+// Kept in the rule's config under "schedule":
+//
+//	{"days": ["sat", "sun"], "start_hour": 22, "end_hour": 5, "timezone": "America/New_York"}
+type RuleSchedule struct {
+	Days []time.Weekday // Empty means every day
+
+	// Inclusive hour window; a start after the end wraps past midnight
+	// HasHours is false when neither hour is set, which means all day
+	StartHour int
+	EndHour   int
+	HasHours  bool
+
+	Location *time.Location // Zone days and hours are read in; UTC unless set
+}
+
+// scheduleLocations caches loaded time zones by name, since schedules are read on every evaluation
+var scheduleLocations sync.Map
+
+// weekdays maps the day names a schedule accepts
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Schedule returns the rule's recurring schedule, or nil when it has none
+func (r *Rule) Schedule() (*RuleSchedule, error) {
+	raw, ok := r.Config["schedule"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	config, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, ErrInvalidRuleSchedule
+	}
+
+	schedule := &RuleSchedule{Location: time.UTC}
+
+	if v, ok := config["days"]; ok {
+		days, ok := v.([]interface{})
+		if !ok {
+			return nil, ErrInvalidRuleSchedule
+		}
+		for _, d := range days {
+			name, _ := d.(string)
+			day, ok := weekdays[strings.ToLower(name)]
+			if !ok {
+				return nil, ErrInvalidRuleSchedule
+			}
+			schedule.Days = append(schedule.Days, day)
+		}
+	}
+
+	start, hasStart := config["start_hour"]
+	end, hasEnd := config["end_hour"]
+	if hasStart || hasEnd {
+		startHour, ok := scheduleHour(start)
+		if !ok {
+			return nil, ErrInvalidRuleSchedule
+		}
+		endHour, ok := scheduleHour(end)
+		if !ok {
+			return nil, ErrInvalidRuleSchedule
+		}
+		schedule.StartHour, schedule.EndHour, schedule.HasHours = startHour, endHour, true
+	}
+
+	if v, ok := config["timezone"]; ok {
+		name, _ := v.(string)
+		location, err := loadScheduleLocation(name)
+		if err != nil {
+			return nil, ErrInvalidRuleSchedule
+		}
+		schedule.Location = location
+	}
+
+	return schedule, nil
+}
+
+// ScheduledAt reports whether the rule's schedule covers t
+// A rule without a schedule, or with one that can't be read, is always scheduled
+func (r *Rule) ScheduledAt(t time.Time) bool {
+	schedule, err := r.Schedule()
+	if err != nil || schedule == nil {
+		return true
+	}
+	return schedule.Covers(t)
+}
+
+// Covers reports whether t falls on one of the schedule's days and within its hours
+// Days and hours are checked separately, so a 22 to 5 window on Friday covers
+// Friday before 6am and from 10pm, but not early Saturday
+func (s *RuleSchedule) Covers(t time.Time) bool {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	local := t.In(s.Location)
+
+	if len(s.Days) > 0 && !slices.Contains(s.Days, local.Weekday()) {
+		return false
+	}
+	if !s.HasHours {
+		return true
+	}
+
+	hour := local.Hour()
+	if s.StartHour <= s.EndHour {
+		return hour >= s.StartHour && hour <= s.EndHour
+	}
+	return hour >= s.StartHour || hour <= s.EndHour
+}
+
+// scheduleHour reads an hour of the day from a decoded JSON number
+func scheduleHour(v interface{}) (int, bool) {
+	f, ok := v.(float64)
+	if !ok || f != float64(int(f)) || f < 0 || f > 23 {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// loadScheduleLocation loads a time zone by IANA name, caching it
+func loadScheduleLocation(name string) (*time.Location, error) {
+	if location, ok := scheduleLocations.Load(name); ok {
+		return location.(*time.Location), nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	scheduleLocations.Store(name, location)
+	return location, nil
+}
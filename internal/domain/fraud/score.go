@@ -2,8 +2,10 @@ package fraud
 
 import (
 	"context"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
@@ -31,6 +33,84 @@ func DefaultScoreWeights() ScoreWeights {
 	}
 }
 
+// ForRuleType returns the configured weight for a rule type
+// Unknown rule types carry no weight
+func (w ScoreWeights) ForRuleType(ruleType RuleType) decimal.Decimal {
+	switch ruleType {
+	case RuleTypeVelocity:
+		return w.Velocity
+	case RuleTypeAmount:
+		return w.Amount
+	case RuleTypeGeographic:
+		return w.Geographic
+	case RuleTypeDevice:
+		return w.Device
+	case RuleTypeMerchant:
+		return w.Merchant
+	case RuleTypeBehavioral:
+		return w.Behavioral
+	case RuleTypeIPReputation:
+		// IP reputation is a network-location signal, so it shares the geographic weight
+		return w.Geographic
+	case RuleTypeCardTesting:
+		// Card testing is a frequency signal, so it shares the velocity weight
+		return w.Velocity
+	case RuleTypeChargeback:
+		// Chargebacks are part of the user's history, so they share the behavioral weight
+		return w.Behavioral
+	case RuleTypeLinking:
+		// Shared devices, IPs and cards are identity signals, so they share the device weight
+		return w.Device
+	case RuleTypeIssuerMismatch:
+		// Where a card is used against where it was issued is a location signal
+		return w.Geographic
+	default:
+		return decimal.Zero
+	}
+}
+
+// total returns the sum of all weights, including the ML model's
+func (w ScoreWeights) total() decimal.Decimal {
+	return w.ruleTypeTotal().Add(w.MLModel)
+}
+
+// Validate checks that no weight is negative and that at least one is positive
+func (w ScoreWeights) Validate() error {
+	for _, weight := range []decimal.Decimal{w.Velocity, w.Amount, w.Geographic, w.Device, w.Merchant, w.Behavioral, w.MLModel} {
+		if weight.IsNegative() {
+			return ErrInvalidScoreWeights
+		}
+	}
+	if !w.total().IsPositive() {
+		return ErrInvalidScoreWeights
+	}
+	return nil
+}
+
+// Normalized returns the weights scaled so they sum to 1
+// Scoring only uses weights relative to each other, so this doesn't change any
+// score; it keeps the configured weights readable as shares of the total.
+func (w ScoreWeights) Normalized() ScoreWeights {
+	total := w.total()
+	if !total.IsPositive() || total.Equal(decimal.NewFromInt(1)) {
+		return w
+	}
+	return ScoreWeights{
+		Velocity:   w.Velocity.Div(total),
+		Amount:     w.Amount.Div(total),
+		Geographic: w.Geographic.Div(total),
+		Device:     w.Device.Div(total),
+		Merchant:   w.Merchant.Div(total),
+		Behavioral: w.Behavioral.Div(total),
+		MLModel:    w.MLModel.Div(total),
+	}
+}
+
+// ruleTypeTotal returns the sum of all rule type weights
+func (w ScoreWeights) ruleTypeTotal() decimal.Decimal {
+	return w.Velocity.Add(w.Amount).Add(w.Geographic).Add(w.Device).Add(w.Merchant).Add(w.Behavioral)
+}
+
 // ScoringStrategy defines how multiple rule results are combined
 type ScoringStrategy string
 
@@ -40,13 +120,23 @@ const (
 	StrategyBayesian        ScoringStrategy = "bayesian"
 )
 
+// TypeAggregation defines how the fired rules of one rule type are combined
+// before the type's weight is applied under the weighted average strategy
+type TypeAggregation string
+
+const (
+	TypeAggregationMax  TypeAggregation = "max"  // Highest score among the type's fired rules
+	TypeAggregationMean TypeAggregation = "mean" // Average score of the type's fired rules
+)
+
 // FraudScorer calculates final fraud scores from rule results
 type FraudScorer interface {
-	// CalculateScore computes the final fraud score
-	CalculateScore(ctx context.Context, results []RuleResult, weights ScoreWeights) (decimal.Decimal, error)
+	// CalculateScore computes the final fraud score and its breakdown
+	// ml is the model's prediction, or nil to score on rules alone
+	CalculateScore(ctx context.Context, results []RuleResult, weights ScoreWeights, ml *MLScore) (*ScoreCalculationResult, error)
 
 	// DetermineDecision decides the action based on score
-	DetermineDecision(ctx context.Context, score decimal.Decimal, riskLevel RiskLevel) (DecisionType, error)
+	DetermineDecision(ctx context.Context, score decimal.Decimal, thresholds DecisionThresholds) (DecisionType, error)
 
 	// GetRiskLevel converts a score to a risk level
 	GetRiskLevel(score decimal.Decimal) RiskLevel
@@ -54,12 +144,45 @@ type FraudScorer interface {
 
 // ScoreCalculationResult contains the detailed scoring breakdown
 type ScoreCalculationResult struct {
-	FinalScore        decimal.Decimal            `json:"final_score"`
-	RiskLevel         RiskLevel                  `json:"risk_level"`
-	Decision          DecisionType               `json:"decision"`
-	RuleContributions map[string]decimal.Decimal `json:"rule_contributions"`
-	Strategy          ScoringStrategy            `json:"strategy"`
-	CalculatedAt      time.Time                  `json:"calculated_at"`
+	FinalScore        decimal.Decimal                `json:"final_score"`
+	RiskLevel         RiskLevel                      `json:"risk_level"`
+	Decision          DecisionType                   `json:"decision"`
+	RuleContributions map[uuid.UUID]RuleContribution `json:"rule_contributions"` // Keyed by rule ID so same-named rules never collide
+	MLContribution    decimal.Decimal                `json:"ml_contribution"`
+	Strategy          ScoringStrategy                `json:"strategy"`
+	CalculatedAt      time.Time                      `json:"calculated_at"`
+}
+
+// RuleContribution records how much a single fired rule added to the final score
+// The rule name is carried for display only - the rule ID is the identity
+type RuleContribution struct {
+	RuleID       uuid.UUID       `json:"rule_id"`
+	RuleName     string          `json:"rule_name"`
+	Contribution decimal.Decimal `json:"contribution"`
+}
+
+// SortedContributions returns the rule contributions, largest first
+func (r *ScoreCalculationResult) SortedContributions() []RuleContribution {
+	contributions := make([]RuleContribution, 0, len(r.RuleContributions))
+	for _, c := range r.RuleContributions {
+		contributions = append(contributions, c)
+	}
+	sort.Slice(contributions, func(i, j int) bool {
+		if !contributions[i].Contribution.Equal(contributions[j].Contribution) {
+			return contributions[i].Contribution.GreaterThan(contributions[j].Contribution)
+		}
+		return contributions[i].RuleName < contributions[j].RuleName
+	})
+	return contributions
+}
+
+// addContribution records a rule's contribution keyed by its ID
+func addContribution(contributions map[uuid.UUID]RuleContribution, result RuleResult, contribution decimal.Decimal) {
+	contributions[result.RuleID] = RuleContribution{
+		RuleID:       result.RuleID,
+		RuleName:     result.RuleName,
+		Contribution: contribution,
+	}
 }
 
 // DecisionThresholds defines score thresholds for decisions
@@ -79,38 +202,181 @@ func DefaultDecisionThresholds() DecisionThresholds {
 	}
 }
 
+// TenantScoringConfig holds the score weights and decision thresholds for one tenant
+type TenantScoringConfig struct {
+	Weights    ScoreWeights
+	Thresholds DecisionThresholds
+}
+
+// ContextRiskConfig scores transactions that arrive with missing context
+// A transaction with no device, location or profile is itself suspicious, so
+// it should not receive a confident allow just because no rule fired
+type ContextRiskConfig struct {
+	Enabled             bool
+	ScorePerMissing     decimal.Decimal // Baseline score per missing context field
+	MaxScore            decimal.Decimal // Cap on the baseline score
+	ChallengeMinMissing int             // Challenge instead of allow at this many missing fields (0 disables)
+}
+
+// DefaultContextRiskConfig provides a low baseline that stays below the challenge threshold
+func DefaultContextRiskConfig() ContextRiskConfig {
+	return ContextRiskConfig{
+		Enabled:         true,
+		ScorePerMissing: decimal.NewFromFloat(0.1),
+		MaxScore:        decimal.NewFromFloat(0.3),
+	}
+}
+
+// BaselineScore returns the baseline risk for the given number of missing context fields
+func (c ContextRiskConfig) BaselineScore(missing int) decimal.Decimal {
+	if !c.Enabled || missing <= 0 {
+		return decimal.Zero
+	}
+	score := c.ScorePerMissing.Mul(decimal.NewFromInt(int64(missing)))
+	if score.GreaterThan(c.MaxScore) {
+		return c.MaxScore
+	}
+	return score
+}
+
+// contextFieldCount is the number of optional context fields checked by CountMissingContext
+const contextFieldCount = 5
+
+// CountMissingContext counts the optional context fields absent from an evaluation context
+func CountMissingContext(evalCtx *RuleEvaluationContext) int {
+	missing := 0
+	if evalCtx.Location == nil {
+		missing++
+	}
+	if evalCtx.Device == nil {
+		missing++
+	}
+	if evalCtx.Merchant == nil {
+		missing++
+	}
+	if evalCtx.Payment == nil {
+		missing++
+	}
+	if evalCtx.UserProfile == nil {
+		missing++
+	}
+	return missing
+}
+
+// MLScore is an ML model prediction that can be combined with rule results
+type MLScore struct {
+	Score         decimal.Decimal // 0.0 to 1.0 fraud probability
+	Confidence    decimal.Decimal // 0.0 to 1.0, scales the model's weight
+	ModelVersion  string
+	FeatureVector []float64 // Model input, kept with the decision's breakdown
+}
+
 // AggregateRuleResults combines multiple rule results
-func AggregateRuleResults(results []RuleResult, weights ScoreWeights, strategy ScoringStrategy) (*ScoreCalculationResult, error) {
+// The aggregation controls how same-type rules are combined under the weighted average strategy.
+// An optional ML score is blended in using the MLModel weight; pass nil to score on rules alone
+func AggregateRuleResults(results []RuleResult, weights ScoreWeights, strategy ScoringStrategy, aggregation TypeAggregation, ml *MLScore) (*ScoreCalculationResult, error) {
+	var result *ScoreCalculationResult
+	var err error
 	switch strategy {
 	case StrategyWeightedAverage:
-		return aggregateWeightedAverage(results, weights)
+		result, err = aggregateWeightedAverage(results, weights, aggregation)
 	case StrategyMaxScore:
-		return aggregateMaxScore(results)
+		result, err = aggregateMaxScore(results)
 	case StrategyBayesian:
-		return aggregateBayesian(results, weights)
+		result, err = aggregateBayesian(results, weights)
 	default:
-		return aggregateWeightedAverage(results, weights)
+		result, err = aggregateWeightedAverage(results, weights, aggregation)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	applyMLScore(result, ml, weights)
+	return result, nil
+}
+
+// applyMLScore blends an ML prediction into an aggregated rule score
+// The model's share is its weight scaled by confidence, relative to the rule type
+// weights, so a low-confidence prediction barely moves the score. Under the max score
+// strategy the model can raise the score but never lower one set by a rule
+func applyMLScore(result *ScoreCalculationResult, ml *MLScore, weights ScoreWeights) {
+	if ml == nil {
+		return
+	}
+
+	mlWeight := weights.MLModel.Mul(ml.Confidence)
+	if !mlWeight.IsPositive() {
+		return
+	}
+
+	one := decimal.NewFromInt(1)
+	share := mlWeight.Div(weights.ruleTypeTotal().Add(mlWeight))
+	contribution := ml.Score.Mul(share)
+	blended := result.FinalScore.Mul(one.Sub(share)).Add(contribution)
+
+	if result.Strategy == StrategyMaxScore && blended.LessThan(result.FinalScore) {
+		return
+	}
+
+	// Clamp to 0-1 range
+	if blended.GreaterThan(one) {
+		blended = one
 	}
+	if blended.IsNegative() {
+		blended = decimal.Zero
+	}
+
+	result.FinalScore = blended
+	result.RiskLevel = getRiskLevel(blended)
+	result.MLContribution = contribution
 }
 
-func aggregateWeightedAverage(results []RuleResult, weights ScoreWeights) (*ScoreCalculationResult, error) {
+// aggregateWeightedAverage computes a normalized weighted average across rule types
+// The fired rules of each type are first combined into one score (see TypeAggregation),
+// which is then multiplied by the type's configured weight, so three velocity rules
+// firing count no more than one. Types with no fired rules contribute zero, and the
+// sum is divided by the total rule type weight so the result stays in the 0-1 range
+func aggregateWeightedAverage(results []RuleResult, weights ScoreWeights, aggregation TypeAggregation) (*ScoreCalculationResult, error) {
 	totalScore := decimal.Zero
-	contributions := make(map[string]decimal.Decimal)
+	contributions := make(map[uuid.UUID]RuleContribution)
 
+	firedByType := make(map[RuleType][]RuleResult)
 	for _, result := range results {
-		if !result.Fired {
-			continue
+		if result.Fired {
+			firedByType[result.RuleType] = append(firedByType[result.RuleType], result)
 		}
+	}
+
+	totalWeight := weights.ruleTypeTotal()
+	if totalWeight.IsPositive() {
+		for ruleType, fired := range firedByType {
+			weight := weights.ForRuleType(ruleType).Div(totalWeight)
 
-		// Get weight for this rule type (simplified - in production map RuleID to type)
-		weight := getWeightForRule(result.RuleName, weights)
-		contribution := result.Score.Mul(weight)
+			if aggregation == TypeAggregationMean {
+				// Every fired rule shares the type's contribution equally
+				share := weight.Div(decimal.NewFromInt(int64(len(fired))))
+				for _, result := range fired {
+					contribution := result.Score.Mul(share)
+					totalScore = totalScore.Add(contribution)
+					addContribution(contributions, result, contribution)
+				}
+				continue
+			}
 
-		totalScore = totalScore.Add(contribution)
-		contributions[result.RuleName] = contribution
+			// Only the highest scoring fired rule of the type counts
+			top := fired[0]
+			for _, result := range fired[1:] {
+				if result.Score.GreaterThan(top.Score) {
+					top = result
+				}
+			}
+			contribution := top.Score.Mul(weight)
+			totalScore = totalScore.Add(contribution)
+			addContribution(contributions, top, contribution)
+		}
 	}
 
-	// Normalize to 0-1 range
+	// Clamp to 0-1 range
 	if totalScore.GreaterThan(decimal.NewFromInt(1)) {
 		totalScore = decimal.NewFromInt(1)
 	}
@@ -126,14 +392,14 @@ func aggregateWeightedAverage(results []RuleResult, weights ScoreWeights) (*Scor
 
 func aggregateMaxScore(results []RuleResult) (*ScoreCalculationResult, error) {
 	maxScore := decimal.Zero
-	contributions := make(map[string]decimal.Decimal)
+	contributions := make(map[uuid.UUID]RuleContribution)
 
 	for _, result := range results {
 		if result.Fired && result.Score.GreaterThan(maxScore) {
 			maxScore = result.Score
 		}
 		if result.Fired {
-			contributions[result.RuleName] = result.Score
+			addContribution(contributions, result, result.Score)
 		}
 	}
 
@@ -146,6 +412,9 @@ func aggregateMaxScore(results []RuleResult) (*ScoreCalculationResult, error) {
 	}, nil
 }
 
+// maxBayesianScore is the highest rule score the bayesian strategy takes at face value
+var maxBayesianScore = decimal.NewFromFloat(0.999)
+
 func aggregateBayesian(results []RuleResult, weights ScoreWeights) (*ScoreCalculationResult, error) {
 	// Bayesian combination: P(fraud | evidence)
 	// Using naive Bayes assumption for simplicity
@@ -158,7 +427,9 @@ func aggregateBayesian(results []RuleResult, weights ScoreWeights) (*ScoreCalcul
 		if result.Fired {
 			// Likelihood ratio = P(evidence | fraud) / P(evidence | not fraud)
 			// Simplified: use rule score as proxy for likelihood
-			likelihoodRatio := result.Score.Div(decimal.NewFromInt(1).Sub(result.Score))
+			// A score of 1 would divide by zero, so cap it just below certainty
+			score := decimal.Min(result.Score, maxBayesianScore)
+			likelihoodRatio := score.Div(decimal.NewFromInt(1).Sub(score))
 			posteriorOdds = posteriorOdds.Mul(likelihoodRatio)
 		}
 	}
@@ -166,10 +437,10 @@ func aggregateBayesian(results []RuleResult, weights ScoreWeights) (*ScoreCalcul
 	// Convert odds back to probability
 	finalProb := posteriorOdds.Div(decimal.NewFromInt(1).Add(posteriorOdds))
 
-	contributions := make(map[string]decimal.Decimal)
+	contributions := make(map[uuid.UUID]RuleContribution)
 	for _, result := range results {
 		if result.Fired {
-			contributions[result.RuleName] = result.Score
+			addContribution(contributions, result, result.Score)
 		}
 	}
 
@@ -195,9 +466,3 @@ func getRiskLevel(score decimal.Decimal) RiskLevel {
 		return RiskLevelLow
 	}
 }
-
-func getWeightForRule(ruleName string, weights ScoreWeights) decimal.Decimal {
-	// In production, maintain a mapping of rule names to types
-	// This is simplified for demonstration
-	return decimal.NewFromFloat(0.15)
-}
@@ -0,0 +1,185 @@
+package fraud
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestAggregateRuleResultsKeysContributionsByRuleID(t *testing.T) {
+	velocity := RuleResult{RuleID: uuid.New(), RuleName: "High risk", RuleType: RuleTypeVelocity, Fired: true, Score: decimal.NewFromFloat(0.6)}
+	amount := RuleResult{RuleID: uuid.New(), RuleName: "High risk", RuleType: RuleTypeAmount, Fired: true, Score: decimal.NewFromFloat(0.8)}
+	quiet := RuleResult{RuleID: uuid.New(), RuleName: "High risk", RuleType: RuleTypeDevice, Score: decimal.NewFromFloat(0.9)}
+	results := []RuleResult{velocity, amount, quiet}
+
+	for _, strategy := range []ScoringStrategy{StrategyWeightedAverage, StrategyMaxScore, StrategyBayesian} {
+		t.Run(string(strategy), func(t *testing.T) {
+			got, err := AggregateRuleResults(results, DefaultScoreWeights(), strategy, TypeAggregationMax, nil)
+			if err != nil {
+				t.Fatalf("aggregate: %v", err)
+			}
+			if len(got.RuleContributions) != 2 {
+				t.Fatalf("%d contributions, want 2: %v", len(got.RuleContributions), got.RuleContributions)
+			}
+			for _, fired := range []RuleResult{velocity, amount} {
+				c, ok := got.RuleContributions[fired.RuleID]
+				if !ok {
+					t.Errorf("no contribution for rule %s", fired.RuleID)
+					continue
+				}
+				if c.RuleID != fired.RuleID || c.RuleName != fired.RuleName {
+					t.Errorf("contribution %+v, want rule %s %q", c, fired.RuleID, fired.RuleName)
+				}
+				if !c.Contribution.IsPositive() {
+					t.Errorf("rule %s contribution %s, want positive", fired.RuleID, c.Contribution)
+				}
+			}
+			if _, ok := got.RuleContributions[quiet.RuleID]; ok {
+				t.Errorf("rule that did not fire has a contribution")
+			}
+		})
+	}
+}
+
+func TestAggregateBayesianCertainRule(t *testing.T) {
+	results := []RuleResult{
+		{RuleID: uuid.New(), RuleName: "New location", RuleType: RuleTypeGeographic, Fired: true, Score: decimal.NewFromInt(1)},
+		{RuleID: uuid.New(), RuleName: "High amount", RuleType: RuleTypeAmount, Fired: true, Score: decimal.NewFromFloat(0.4)},
+	}
+
+	got, err := AggregateRuleResults(results, DefaultScoreWeights(), StrategyBayesian, TypeAggregationMax, nil)
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if !got.FinalScore.GreaterThan(decimal.NewFromFloat(0.8)) || got.FinalScore.GreaterThan(decimal.NewFromInt(1)) {
+		t.Errorf("final score %s, want in (0.8, 1]", got.FinalScore)
+	}
+}
+
+func TestAggregateWeightedAverageSameType(t *testing.T) {
+	velocity := func(scores ...float64) []RuleResult {
+		results := []RuleResult{{RuleID: uuid.New(), RuleName: "High amount", RuleType: RuleTypeAmount, Fired: true, Score: decimal.NewFromFloat(0.5)}}
+		for _, score := range scores {
+			results = append(results, RuleResult{RuleID: uuid.New(), RuleName: "Velocity", RuleType: RuleTypeVelocity, Fired: true, Score: decimal.NewFromFloat(score)})
+		}
+		return results
+	}
+
+	tests := []struct {
+		name        string
+		aggregation TypeAggregation
+		one         []RuleResult
+		three       []RuleResult
+	}{
+		{"max of equal scores", TypeAggregationMax, velocity(0.6), velocity(0.6, 0.6, 0.6)},
+		{"max of mixed scores", TypeAggregationMax, velocity(0.6), velocity(0.4, 0.6, 0.5)},
+		{"mean of equal scores", TypeAggregationMean, velocity(0.6), velocity(0.6, 0.6, 0.6)},
+		{"mean of mixed scores", TypeAggregationMean, velocity(0.5), velocity(0.4, 0.6, 0.5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			one, err := AggregateRuleResults(tt.one, DefaultScoreWeights(), StrategyWeightedAverage, tt.aggregation, nil)
+			if err != nil {
+				t.Fatalf("aggregate one: %v", err)
+			}
+			three, err := AggregateRuleResults(tt.three, DefaultScoreWeights(), StrategyWeightedAverage, tt.aggregation, nil)
+			if err != nil {
+				t.Fatalf("aggregate three: %v", err)
+			}
+			// Mean shares are divided per rule, so allow for decimal division rounding
+			if !three.FinalScore.Round(10).Equal(one.FinalScore.Round(10)) {
+				t.Errorf("three velocity rules score %s, want %s like one", three.FinalScore, one.FinalScore)
+			}
+		})
+	}
+}
+
+// scaleWeights multiplies every weight by factor, and the velocity weight by velocityFactor on top
+func scaleWeights(w ScoreWeights, factor, velocityFactor float64) ScoreWeights {
+	f := decimal.NewFromFloat(factor)
+	return ScoreWeights{
+		Velocity:   w.Velocity.Mul(f).Mul(decimal.NewFromFloat(velocityFactor)),
+		Amount:     w.Amount.Mul(f),
+		Geographic: w.Geographic.Mul(f),
+		Device:     w.Device.Mul(f),
+		Merchant:   w.Merchant.Mul(f),
+		Behavioral: w.Behavioral.Mul(f),
+		MLModel:    w.MLModel.Mul(f),
+	}
+}
+
+func TestSetScoreWeightsScalesContributions(t *testing.T) {
+	velocity := RuleResult{RuleID: uuid.New(), RuleName: "Velocity", RuleType: RuleTypeVelocity, Fired: true, Score: decimal.NewFromFloat(0.6)}
+	amount := RuleResult{RuleID: uuid.New(), RuleName: "High amount", RuleType: RuleTypeAmount, Fired: true, Score: decimal.NewFromFloat(0.6)}
+
+	// contributions returns the velocity and amount contributions under the weights the service keeps
+	contributions := func(t *testing.T, weights ScoreWeights) (decimal.Decimal, decimal.Decimal) {
+		t.Helper()
+		service := NewService(nil, nil, nil, nil, nil)
+		if err := service.SetScoreWeights(weights); err != nil {
+			t.Fatalf("set weights: %v", err)
+		}
+		if total := service.scoreWeights.total(); !total.Round(10).Equal(decimal.NewFromInt(1)) {
+			t.Errorf("stored weights sum to %s, want 1", total)
+		}
+		got, err := AggregateRuleResults([]RuleResult{velocity, amount}, service.scoreWeights, StrategyWeightedAverage, TypeAggregationMax, nil)
+		if err != nil {
+			t.Fatalf("aggregate: %v", err)
+		}
+		return got.RuleContributions[velocity.RuleID].Contribution, got.RuleContributions[amount.RuleID].Contribution
+	}
+	baseVelocity, baseAmount := contributions(t, DefaultScoreWeights())
+
+	tests := []struct {
+		name           string
+		factor         float64 // Applied to every weight
+		velocityFactor float64 // Applied to the velocity weight on top
+	}{
+		{"all weights tripled", 3, 1},
+		{"all weights scaled down", 0.1, 1},
+		{"velocity weight doubled", 1, 2},
+		{"velocity weight halved", 5, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotVelocity, gotAmount := contributions(t, scaleWeights(DefaultScoreWeights(), tt.factor, tt.velocityFactor))
+
+			// Only the velocity weight's share relative to the others changes
+			wantRatio := baseVelocity.Div(baseAmount).Mul(decimal.NewFromFloat(tt.velocityFactor))
+			if gotRatio := gotVelocity.Div(gotAmount); !gotRatio.Round(6).Equal(wantRatio.Round(6)) {
+				t.Errorf("velocity to amount contribution ratio %s, want %s", gotRatio, wantRatio)
+			}
+			if tt.velocityFactor == 1 && (!gotVelocity.Round(10).Equal(baseVelocity.Round(10)) || !gotAmount.Round(10).Equal(baseAmount.Round(10))) {
+				t.Errorf("contributions %s and %s, want %s and %s as with the unscaled weights", gotVelocity, gotAmount, baseVelocity, baseAmount)
+			}
+		})
+	}
+}
+
+func TestSetScoreWeightsRejects(t *testing.T) {
+	negative := DefaultScoreWeights()
+	negative.Device = decimal.NewFromFloat(-0.1)
+
+	tests := []struct {
+		name    string
+		weights ScoreWeights
+	}{
+		{"negative weight", negative},
+		{"all zero", ScoreWeights{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewService(nil, nil, nil, nil, nil)
+			before := service.scoreWeights
+
+			if err := service.SetScoreWeights(tt.weights); !errors.Is(err, ErrInvalidScoreWeights) {
+				t.Fatalf("error %v, want %v", err, ErrInvalidScoreWeights)
+			}
+			if service.scoreWeights != before {
+				t.Errorf("weights changed to %+v after a rejected update", service.scoreWeights)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package fraud
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultScorer is the FraudScorer the service uses when none is supplied
+// It combines rule results with AggregateRuleResults and decides by threshold
+type DefaultScorer struct {
+	Strategy    ScoringStrategy
+	Aggregation TypeAggregation // Only used by the weighted average strategy
+}
+
+var _ FraudScorer = (*DefaultScorer)(nil)
+
+// NewDefaultScorer creates a scorer using the max score strategy
+func NewDefaultScorer() *DefaultScorer {
+	return &DefaultScorer{
+		Strategy:    StrategyMaxScore, // Use max score - more appropriate for fraud detection
+		Aggregation: TypeAggregationMax,
+	}
+}
+
+// CalculateScore aggregates the rule results and blends in the ML score, if any
+func (d *DefaultScorer) CalculateScore(ctx context.Context, results []RuleResult, weights ScoreWeights, ml *MLScore) (*ScoreCalculationResult, error) {
+	return AggregateRuleResults(results, weights, d.Strategy, d.Aggregation, ml)
+}
+
+// DetermineDecision returns the most severe decision whose threshold the score reaches
+func (d *DefaultScorer) DetermineDecision(ctx context.Context, score decimal.Decimal, thresholds DecisionThresholds) (DecisionType, error) {
+	// Check thresholds in order of severity
+	if score.GreaterThanOrEqual(thresholds.BlockThreshold) {
+		return DecisionBlock, nil
+	}
+	if score.GreaterThanOrEqual(thresholds.ReviewThreshold) {
+		return DecisionReview, nil
+	}
+	if score.GreaterThanOrEqual(thresholds.ChallengeThreshold) {
+		return DecisionChallenge, nil
+	}
+	return DecisionAllow, nil
+}
+
+// GetRiskLevel converts a score to a risk level
+func (d *DefaultScorer) GetRiskLevel(score decimal.Decimal) RiskLevel {
+	return getRiskLevel(score)
+}
@@ -2,28 +2,70 @@ package fraud
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/pkg/logger"
+	"fraud-detecction-system/internal/pkg/metrics"
+	"fraud-detecction-system/internal/pkg/retry"
 )
 
 // Service handles fraud detection business logic
 // This is the core domain service that orchestrates fraud analysis
 type Service struct {
-	decisionRepo DecisionRepository
-	caseRepo     CaseRepository
-	ruleRepo     RuleRepository
-	ruleEngine   RuleEngine
-	scorer       FraudScorer
+	decisionRepo   DecisionRepository
+	caseRepo       CaseRepository
+	ruleRepo       RuleRepository
+	ruleEngine     RuleEngine
+	scorer         FraudScorer
+	alertPublisher FraudAlertPublisher
+
+	// Optional allowlist and denylist checked before rules run
+	listRepo ListRepository
+
+	// Optional chargeback history for recording chargebacks and user risk profiles
+	chargebackRepo ChargebackRepository
+
+	// Optional client webhook told about decisions and case resolutions
+	webhookNotifier WebhookNotifier
+	webhooks        *webhookQueue
+
+	// Optional link to the transactions a case resolution approves or declines
+	transactionReviewer TransactionReviewer
+
+	// Optional link to the transactions a decision override approves, declines or flags
+	transactionOverrider TransactionOverrider
+
+	// Optional conversion of transaction amounts to the base currency
+	currencyConverter CurrencyConverter
+	baseCurrency      string
 
 	// Configuration
 	decisionThresholds DecisionThresholds
 	scoreWeights       ScoreWeights
-	scoringStrategy    ScoringStrategy
+	contextRisk        ContextRiskConfig
+	tenantScoring      map[string]TenantScoringConfig
+	evalRetryBackoff   time.Duration
+	minConfidence      decimal.Decimal
+	confidence         ConfidenceConfig
+	minDataCoverage    decimal.Decimal
+	breakdownSampling  BreakdownSampling
+	riskProfile        RiskProfileConfig
+	riskProfileCache   RiskProfileCache
+	caseSLA            CaseSLAConfig
+
+	logger *slog.Logger
 }
 
 // NewService creates a new fraud detection service
+// A nil scorer means the DefaultScorer with the max score strategy
 func NewService(
 	decisionRepo DecisionRepository,
 	caseRepo CaseRepository,
@@ -31,6 +73,9 @@ func NewService(
 	ruleEngine RuleEngine,
 	scorer FraudScorer,
 ) *Service {
+	if scorer == nil {
+		scorer = NewDefaultScorer()
+	}
 	return &Service{
 		decisionRepo:       decisionRepo,
 		caseRepo:           caseRepo,
@@ -39,10 +84,105 @@ func NewService(
 		scorer:             scorer,
 		decisionThresholds: DefaultDecisionThresholds(),
 		scoreWeights:       DefaultScoreWeights(),
-		scoringStrategy:    StrategyMaxScore, // Use max score - more appropriate for fraud detection
+		contextRisk:        DefaultContextRiskConfig(),
+		evalRetryBackoff:   defaultEvalRetryBackoff,
+		confidence:         DefaultConfidenceConfig(),
+		breakdownSampling:  DefaultBreakdownSampling(),
+		riskProfile:        DefaultRiskProfileConfig(),
+		caseSLA:            DefaultCaseSLAConfig(),
+		logger:             slog.Default(),
 	}
 }
 
+// defaultEvalRetryBackoff is the pause before retrying a failed rule evaluation
+const defaultEvalRetryBackoff = 50 * time.Millisecond
+
+// alertPublishTimeout bounds how long a background alert publish may take
+const alertPublishTimeout = 10 * time.Second
+
+// SetContextRiskConfig sets how transactions with missing context are scored
+func (s *Service) SetContextRiskConfig(config ContextRiskConfig) {
+	s.contextRisk = config
+}
+
+// SetAlertPublisher sets the publisher used to emit alerts for blocked and flagged transactions
+func (s *Service) SetAlertPublisher(publisher FraudAlertPublisher) {
+	s.alertPublisher = publisher
+}
+
+// SetWebhookNotifier sets the webhook told about decisions and case resolutions
+// and starts the workers that deliver to it; stop them with DrainWebhooks
+func (s *Service) SetWebhookNotifier(notifier WebhookNotifier, delivery WebhookDeliveryConfig) {
+	s.webhookNotifier = notifier
+	s.webhooks = s.startWebhookQueue(delivery)
+}
+
+// SetTransactionReviewer sets what applies case resolution outcomes to transactions
+// Without one, resolving a case with an outcome fails with ErrTransactionReviewUnavailable
+func (s *Service) SetTransactionReviewer(reviewer TransactionReviewer) {
+	s.transactionReviewer = reviewer
+}
+
+// SetTransactionOverrider sets what moves transactions to match overridden decisions
+// Without one, overrides are still recorded but transactions are left as they are
+func (s *Service) SetTransactionOverrider(overrider TransactionOverrider) {
+	s.transactionOverrider = overrider
+}
+
+// SetCurrencyConverter sets how transaction amounts are converted to the base currency
+// Decisions then record the converted amount, and lose confidence when it can't be converted
+func (s *Service) SetCurrencyConverter(converter CurrencyConverter, baseCurrency string) {
+	s.currencyConverter = converter
+	s.baseCurrency = strings.ToUpper(baseCurrency)
+}
+
+// SetListRepository sets the allowlist and denylist checked before rule evaluation
+func (s *Service) SetListRepository(repo ListRepository) {
+	s.listRepo = repo
+}
+
+// SetChargebackRepository sets where chargebacks are recorded and counted
+func (s *Service) SetChargebackRepository(repo ChargebackRepository) {
+	s.chargebackRepo = repo
+}
+
+// SetMinConfidence sets the confidence below which block and challenge decisions
+// are downgraded to review; zero disables the downgrade
+func (s *Service) SetMinConfidence(minConfidence decimal.Decimal) {
+	s.minConfidence = minConfidence
+}
+
+// SetMinDataCoverage sets the data coverage below which allow decisions are sent to review
+// instead; zero disables it
+func (s *Service) SetMinDataCoverage(minCoverage decimal.Decimal) {
+	s.minDataCoverage = minCoverage
+}
+
+// SetConfidenceConfig sets how decision confidence weighs data coverage, rule agreement and context signals
+func (s *Service) SetConfidenceConfig(config ConfidenceConfig) {
+	s.confidence = config
+}
+
+// SetBreakdownSampling sets which decisions are stored with their full score breakdown
+func (s *Service) SetBreakdownSampling(sampling BreakdownSampling) {
+	s.breakdownSampling = sampling
+}
+
+// SetRiskProfileConfig sets how user risk profiles weigh past blocks
+func (s *Service) SetRiskProfileConfig(config RiskProfileConfig) {
+	s.riskProfile = config
+}
+
+// SetRiskProfileCache sets the cache live decisions read user risk profiles from
+func (s *Service) SetRiskProfileCache(cache RiskProfileCache) {
+	s.riskProfileCache = cache
+}
+
+// SetLogger sets the logger used to report failures off the decision path
+func (s *Service) SetLogger(log *slog.Logger) {
+	s.logger = log
+}
+
 // AnalyzeTransaction performs fraud analysis on a transaction
 // This is the main entry point for fraud detection
 func (s *Service) AnalyzeTransaction(ctx context.Context, evalCtx *RuleEvaluationContext) (*FraudDecision, error) {
@@ -56,20 +196,82 @@ func (s *Service) AnalyzeTransaction(ctx context.Context, evalCtx *RuleEvaluatio
 		return nil, ErrMissingTransactionData
 	}
 
+	// A retried transaction gets the decision it was given the first time
+	if existing := s.existingDecision(ctx, evalCtx.TransactionID); existing != nil {
+		return existing, nil
+	}
+
+	// Amount thresholds are in the base currency
+	converted := s.normalizeAmount(ctx, evalCtx)
+
+	// Listed users, devices, cards and IPs are decided without running the rules
+	if entry := s.listMatch(ctx, evalCtx); entry != nil {
+		fraudDecision := listDecision(evalCtx, entry, startTime)
+		metrics.RecordDecision(string(fraudDecision.Decision), time.Since(startTime))
+		return s.recordDecision(ctx, evalCtx, fraudDecision)
+	}
+
 	// Evaluate all active rules
-	ruleResults, err := s.ruleEngine.Evaluate(ctx, evalCtx)
+	allResults, err := s.evaluateRules(ctx, evalCtx)
 	if err != nil {
+		s.logger.ErrorContext(ctx, "fraud rule evaluation failed",
+			slog.String(logger.KeyTransactionID, evalCtx.TransactionID.String()),
+			logger.Err(err),
+		)
 		return nil, ErrEvaluationFailed
 	}
 
-	// Calculate aggregate fraud score
-	scoreResult, err := AggregateRuleResults(ruleResults, s.scoreWeights, s.scoringStrategy)
+	// Rules shed under load say nothing about the transaction, so keep them out of the score
+	ruleResults, skippedRules := splitSkippedResults(allResults)
+
+	// Shadow rules are only observed, so keep them out of the score too
+	ruleResults, shadowRulesFired := splitShadowResults(ruleResults)
+
+	// A critical block rule that stopped evaluation settles the decision, so skip the ML call too
+	stoppedBy := stoppingRule(ruleResults)
+	if stoppedBy == nil && evalCtx.MLScore == nil && evalCtx.ScoreML != nil {
+		evalCtx.MLScore = evalCtx.ScoreML(ctx)
+	}
+
+	// Calculate aggregate fraud score with the tenant's weights
+	scoring := s.scoringFor(evalCtx.TenantID)
+	scoreResult, err := s.scorer.CalculateScore(ctx, ruleResults, scoring.Weights, evalCtx.MLScore)
 	if err != nil {
 		return nil, ErrScoringFailed
 	}
 
+	// Missing context is a risk signal on its own, so raise the score to the baseline
+	missingContext := CountMissingContext(evalCtx)
+	baseline := s.contextRisk.BaselineScore(missingContext)
+	contextApplied := baseline.GreaterThan(scoreResult.FinalScore)
+	if contextApplied {
+		scoreResult.FinalScore = baseline
+		scoreResult.RiskLevel = s.scorer.GetRiskLevel(baseline)
+	}
+
+	// A user with past blocks, open cases or chargebacks starts from a higher score
+	var historyBoost decimal.Decimal
+	var profile *UserRiskProfile
+	if stoppedBy == nil {
+		historyBoost, profile = s.historyBoost(ctx, evalCtx.UserID)
+		if historyBoost.IsPositive() {
+			scoreResult.FinalScore = decimal.Min(scoreResult.FinalScore.Add(historyBoost), decimal.NewFromInt(1))
+			scoreResult.RiskLevel = s.scorer.GetRiskLevel(scoreResult.FinalScore)
+		}
+	}
+
 	// Determine decision based on score
-	decision := s.determineDecision(scoreResult.FinalScore)
+	decision, err := s.scorer.DetermineDecision(ctx, scoreResult.FinalScore, scoring.Thresholds)
+	if err != nil {
+		return nil, ErrScoringFailed
+	}
+	if decision == DecisionAllow && s.contextRisk.Enabled && s.contextRisk.ChallengeMinMissing > 0 && missingContext >= s.contextRisk.ChallengeMinMissing {
+		decision = DecisionChallenge
+		contextApplied = true
+	}
+	if stoppedBy != nil {
+		decision = DecisionBlock
+	}
 
 	// Create fraud decision
 	fraudDecision := NewFraudDecision(
@@ -81,9 +283,45 @@ func (s *Service) AnalyzeTransaction(ctx context.Context, evalCtx *RuleEvaluatio
 
 	// Populate decision details
 	fraudDecision.RiskLevel = scoreResult.RiskLevel
-	fraudDecision.Confidence = s.calculateConfidence(ruleResults)
+	fraudDecision.Confidence, fraudDecision.ConfidenceFactors = s.confidence.Calculate(ruleResults, evalCtx)
+	if !converted {
+		fraudDecision.Confidence = fraudDecision.Confidence.Mul(unconvertedConfidenceFactor)
+	}
+	if fraudDecision.ConfidenceFactors.RulesEvaluated > 0 {
+		coverage := fraudDecision.ConfidenceFactors.Coverage
+		fraudDecision.DataCoverage = &coverage
+	}
+
+	// An allow means little when most rules had nothing to check, so let an analyst decide
+	lowCoverage := s.needsCoverageReview(decision, fraudDecision.DataCoverage)
+	if lowCoverage {
+		decision = DecisionReview
+		fraudDecision.Decision = decision
+	}
+
+	// Too little evidence to act on automatically, so let an analyst decide
+	if stoppedBy == nil && s.shouldDowngrade(decision, fraudDecision.Confidence) {
+		fraudDecision.DowngradedFrom = decision
+		decision = DecisionReview
+		fraudDecision.Decision = decision
+	}
+
 	fraudDecision.ProcessedAt = time.Now()
 	fraudDecision.LatencyMs = time.Since(startTime).Milliseconds()
+	metrics.RecordDecision(string(decision), time.Since(startTime))
+	fraudDecision.MissingContextCount = missingContext
+	fraudDecision.Contributions = scoreResult.SortedContributions()
+	fraudDecision.SkippedRules = skippedRules
+	fraudDecision.ShadowRulesFired = shadowRulesFired
+	if degraded := degradedRules(ruleResults); len(degraded) > 0 {
+		fraudDecision.Degraded = true
+		fraudDecision.DegradedReason = fmt.Sprintf("Dependencies unavailable: %s not checked", strings.Join(degraded, ", "))
+		metrics.RecordDegradedDecision()
+	}
+	if evalCtx.MLScore != nil {
+		fraudDecision.ModelVersion = evalCtx.MLScore.ModelVersion
+		fraudDecision.FeatureVector = evalCtx.MLScore.FeatureVector
+	}
 
 	// Add fired rules and reasons
 	for _, result := range ruleResults {
@@ -92,23 +330,197 @@ func (s *Service) AnalyzeTransaction(ctx context.Context, evalCtx *RuleEvaluatio
 			fraudDecision.AddReason(result.Reason)
 		}
 	}
+	if stoppedBy != nil {
+		fraudDecision.AddReason(fmt.Sprintf("Evaluation stopped early by critical block rule %s: %d rules skipped", stoppedBy.RuleName, len(skippedRules)))
+	}
+	if historyBoost.IsPositive() {
+		fraudDecision.AddReason(fmt.Sprintf("User history raised score by %s (%s risk: %d blocks, %d open cases, %d chargebacks)",
+			historyBoost.StringFixed(2), profile.RiskLevel, profile.BlockedCount, profile.OpenCasesCount, profile.ChargebackCount))
+	}
+	if !converted {
+		fraudDecision.AddReason(fmt.Sprintf("Amount could not be converted from %s to %s; amount rules compared it unconverted", evalCtx.Currency, s.baseCurrency))
+	}
+	if contextApplied {
+		fraudDecision.AddReason(fmt.Sprintf("Insufficient transaction context: %d of %d fields missing", missingContext, contextFieldCount))
+	}
+	if lowCoverage {
+		fraudDecision.AddReason(fmt.Sprintf("Low data coverage (%s, minimum %s): %d of %d rules lacked data, allow sent to review",
+			fraudDecision.DataCoverage.StringFixed(2), s.minDataCoverage.StringFixed(2),
+			fraudDecision.ConfidenceFactors.RulesWithoutData, fraudDecision.ConfidenceFactors.RulesEvaluated))
+	}
+	if fraudDecision.DowngradedFrom != "" {
+		fraudDecision.AddReason(fmt.Sprintf("Low confidence (%s, minimum %s): %s downgraded to review",
+			fraudDecision.Confidence.StringFixed(2), s.minConfidence.StringFixed(2), fraudDecision.DowngradedFrom))
+	}
+
+	return s.recordDecision(ctx, evalCtx, fraudDecision)
+}
 
-	// Persist decision
-	if err := s.decisionRepo.Create(ctx, fraudDecision); err != nil {
+// recordDecision stores a decision, opening a case and publishing an alert when it needs attention
+func (s *Service) recordDecision(ctx context.Context, evalCtx *RuleEvaluationContext, fraudDecision *FraudDecision) (*FraudDecision, error) {
+	decision := fraudDecision.Decision
+	if evalCtx.BaseCurrency != "" {
+		baseAmount := evalCtx.BaseAmount
+		fraudDecision.BaseAmount = &baseAmount
+		fraudDecision.BaseCurrency = evalCtx.BaseCurrency
+	}
+
+	// Persist decision, leaving out the breakdown unless this decision is sampled
+	stored := fraudDecision
+	if !s.breakdownSampling.keep(decision) {
+		stored = fraudDecision.withoutBreakdown()
+	}
+	if err := s.decisionRepo.Create(ctx, stored); err != nil {
+		// A concurrent retry stored its decision first, so return that one
+		if err == ErrDuplicateDecision {
+			if existing := s.existingDecision(ctx, evalCtx.TransactionID); existing != nil {
+				return existing, nil
+			}
+		}
 		return nil, err
 	}
+	s.logger.InfoContext(ctx, "fraud decision",
+		slog.String(logger.KeyDecisionID, fraudDecision.ID.String()),
+		slog.String(logger.KeyTransactionID, fraudDecision.TransactionID.String()),
+		slog.String(logger.KeyUserID, fraudDecision.UserID.String()),
+		slog.String("decision", string(decision)),
+		slog.String("score", fraudDecision.Score.String()),
+		slog.Any("rules_fired", fraudDecision.RulesFired),
+		slog.Int64("latency_ms", fraudDecision.LatencyMs),
+	)
 
 	// If flagged for review, create a fraud case
 	if decision == DecisionReview || decision == DecisionBlock {
 		if err := s.createFraudCaseIfNeeded(ctx, evalCtx, fraudDecision); err != nil {
 			// Log error but don't fail the analysis
 			// Creating a case is secondary to making the fraud decision
+			s.logger.ErrorContext(ctx, "failed to create fraud case",
+				slog.String(logger.KeyDecisionID, fraudDecision.ID.String()),
+				slog.String(logger.KeyTransactionID, fraudDecision.TransactionID.String()),
+				logger.Err(err),
+			)
 		}
+		s.publishAlert(ctx, fraudDecision)
 	}
+	s.notifyWebhook(ctx, newDecisionEvent(fraudDecision))
 
 	return fraudDecision, nil
 }
 
+// existingDecision returns the decision already stored for a transaction, if any
+// A failed lookup is logged and treated as no decision; the unique transaction ID
+// constraint still stops a duplicate from being stored
+func (s *Service) existingDecision(ctx context.Context, transactionID uuid.UUID) *FraudDecision {
+	decision, err := s.decisionRepo.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		if err != ErrDecisionNotFound {
+			s.logger.WarnContext(ctx, "failed to check for an existing decision",
+				slog.String(logger.KeyTransactionID, transactionID.String()),
+				logger.Err(err),
+			)
+		}
+		return nil
+	}
+
+	s.logger.InfoContext(ctx, "returning existing decision for retried transaction",
+		slog.String(logger.KeyDecisionID, decision.ID.String()),
+		slog.String(logger.KeyTransactionID, transactionID.String()),
+	)
+	return decision
+}
+
+// evaluateRules runs the rule engine, retrying a transient failure once after a short pause
+// The engine only fails when active rules can't be loaded, which is usually a
+// momentary database blip rather than a reason to flag the transaction. Other
+// failures, such as a rule config that can't be read, fail the same way again.
+func (s *Service) evaluateRules(ctx context.Context, evalCtx *RuleEvaluationContext) ([]RuleResult, error) {
+	results, err := s.ruleEngine.Evaluate(ctx, evalCtx)
+	if err == nil || ctx.Err() != nil || !retry.IsTransient(err) {
+		return results, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, err
+	case <-time.After(s.evalRetryBackoff):
+	}
+
+	return s.ruleEngine.Evaluate(ctx, evalCtx)
+}
+
+// degradedRules returns the names of rules that could not run because a dependency was unavailable
+func degradedRules(results []RuleResult) []string {
+	var names []string
+	for _, result := range results {
+		if result.Degraded {
+			names = append(names, result.RuleName)
+		}
+	}
+	return names
+}
+
+// splitSkippedResults separates evaluated results from rules that were skipped
+func splitSkippedResults(results []RuleResult) ([]RuleResult, []string) {
+	evaluated := make([]RuleResult, 0, len(results))
+	skipped := make([]string, 0)
+	for _, result := range results {
+		if result.Skipped {
+			skipped = append(skipped, result.RuleName)
+			continue
+		}
+		evaluated = append(evaluated, result)
+	}
+	return evaluated, skipped
+}
+
+// splitShadowResults separates shadow rule results from those that count
+// It returns the names of the shadow rules that would have fired
+func splitShadowResults(results []RuleResult) ([]RuleResult, []string) {
+	counted := make([]RuleResult, 0, len(results))
+	fired := make([]string, 0)
+	for _, result := range results {
+		if !result.Shadow {
+			counted = append(counted, result)
+			continue
+		}
+		if result.Fired {
+			fired = append(fired, result.RuleName)
+		}
+	}
+	return counted, fired
+}
+
+// stoppingRule returns the critical block rule that ended evaluation early, if any
+func stoppingRule(results []RuleResult) *RuleResult {
+	for i := range results {
+		if results[i].StoppedEvaluation {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+// publishAlert emits a fraud alert in the background so it never blocks the decision path
+// The publish outlives the request but keeps its context values for log correlation
+func (s *Service) publishAlert(ctx context.Context, decision *FraudDecision) {
+	if s.alertPublisher == nil {
+		return
+	}
+
+	alert := NewFraudAlert(decision)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), alertPublishTimeout)
+		defer cancel()
+		if err := s.alertPublisher.PublishAlert(ctx, alert); err != nil {
+			// Log but don't fail - alerts are best effort
+			s.logger.WarnContext(ctx, "failed to publish fraud alert",
+				slog.String(logger.KeyDecisionID, alert.DecisionID.String()),
+				logger.Err(err),
+			)
+		}
+	}()
+}
+
 // GetDecision retrieves a fraud decision by ID
 func (s *Service) GetDecision(ctx context.Context, decisionID uuid.UUID) (*FraudDecision, error) {
 	return s.decisionRepo.GetByID(ctx, decisionID)
@@ -119,6 +531,125 @@ func (s *Service) GetDecisionByTransaction(ctx context.Context, transactionID uu
 	return s.decisionRepo.GetByTransactionID(ctx, transactionID)
 }
 
+// GetDecisionsByTransactionIDs retrieves the decisions for several transactions, keyed by transaction ID
+// At most MaxDecisionLookupBatch IDs are accepted; duplicates are looked up once
+func (s *Service) GetDecisionsByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) (map[uuid.UUID]*FraudDecision, error) {
+	ids := uniqueIDs(transactionIDs)
+	if len(ids) > MaxDecisionLookupBatch {
+		return nil, ErrDecisionBatchTooLarge
+	}
+	return s.decisionRepo.GetByTransactionIDs(ctx, ids)
+}
+
+// uniqueIDs returns ids without duplicates, keeping the first occurrence of each
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// ListUserDecisions returns a page of a user's decisions, newest first,
+// along with the total number of decisions for the user
+func (s *Service) ListUserDecisions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*FraudDecision, int64, error) {
+	decisions, err := s.decisionRepo.ListByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.decisionRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return decisions, total, nil
+}
+
+// RecordFeedback labels a decision with an analyst's verdict
+func (s *Service) RecordFeedback(ctx context.Context, decisionID uuid.UUID, label FeedbackLabel, note string, actor uuid.UUID) (*DecisionFeedback, error) {
+	if label != LabelFraud && label != LabelLegit {
+		return nil, ErrInvalidFeedbackLabel
+	}
+
+	decision, err := s.decisionRepo.GetByID(ctx, decisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	feedback := NewDecisionFeedback(decision, label, note, actor)
+	if err := s.decisionRepo.RecordFeedback(ctx, feedback); err != nil {
+		return nil, err
+	}
+
+	return feedback, nil
+}
+
+// AccuracyReport summarizes how labeled decisions compare to analyst verdicts
+// Block and review decisions are fraud predictions; allow and challenge are not
+type AccuracyReport struct {
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	Labeled        int             `json:"labeled"`
+	TruePositives  int             `json:"true_positives"`
+	FalsePositives int             `json:"false_positives"`
+	TrueNegatives  int             `json:"true_negatives"`
+	FalseNegatives int             `json:"false_negatives"`
+	Precision      decimal.Decimal `json:"precision"`
+	Recall         decimal.Decimal `json:"recall"`
+}
+
+// GetAccuracy computes precision and recall for decisions made within [from, to)
+// When a decision was labeled more than once, the latest label wins
+func (s *Service) GetAccuracy(ctx context.Context, from, to time.Time) (*AccuracyReport, error) {
+	feedback, err := s.decisionRepo.ListFeedback(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[uuid.UUID]*DecisionFeedback, len(feedback))
+	for _, f := range feedback {
+		if prev, ok := latest[f.DecisionID]; !ok || f.CreatedAt.After(prev.CreatedAt) {
+			latest[f.DecisionID] = f
+		}
+	}
+
+	report := &AccuracyReport{
+		From:      from,
+		To:        to,
+		Labeled:   len(latest),
+		Precision: decimal.Zero,
+		Recall:    decimal.Zero,
+	}
+	for _, f := range latest {
+		isFraud := f.Label == LabelFraud
+		switch {
+		case f.PredictedFraud() && isFraud:
+			report.TruePositives++
+		case f.PredictedFraud():
+			report.FalsePositives++
+		case isFraud:
+			report.FalseNegatives++
+		default:
+			report.TrueNegatives++
+		}
+	}
+
+	tp := decimal.NewFromInt(int64(report.TruePositives))
+	if predicted := report.TruePositives + report.FalsePositives; predicted > 0 {
+		report.Precision = tp.Div(decimal.NewFromInt(int64(predicted))).Round(4)
+	}
+	if actual := report.TruePositives + report.FalseNegatives; actual > 0 {
+		report.Recall = tp.Div(decimal.NewFromInt(int64(actual))).Round(4)
+	}
+
+	return report, nil
+}
+
 // GetUserBlockedCount returns how many times a user has been blocked
 func (s *Service) GetUserBlockedCount(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
 	return s.decisionRepo.GetBlockedCount(ctx, userID, since)
@@ -183,18 +714,120 @@ func (s *Service) AddCaseNote(ctx context.Context, caseID, authorID uuid.UUID, c
 	return s.caseRepo.Update(ctx, fraudCase)
 }
 
+// AddCaseEvidence attaches a supporting artifact to a case
+func (s *Service) AddCaseEvidence(ctx context.Context, caseID uuid.UUID, evidenceType, description, url string, metadata map[string]string) error {
+	if !IsValidEvidenceType(evidenceType) {
+		return ErrInvalidEvidenceType
+	}
+
+	fraudCase, err := s.caseRepo.GetByID(ctx, caseID)
+	if err != nil {
+		return err
+	}
+
+	fraudCase.AddEvidence(evidenceType, description, url, metadata)
+	return s.caseRepo.Update(ctx, fraudCase)
+}
+
+// ListCaseNotes returns a page of a case's notes ordered oldest to newest,
+// along with the total number of notes on the case
+func (s *Service) ListCaseNotes(ctx context.Context, caseID uuid.UUID, limit, offset int) ([]CaseNote, int, error) {
+	fraudCase, err := s.caseRepo.GetByID(ctx, caseID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	notes := make([]CaseNote, len(fraudCase.Notes))
+	copy(notes, fraudCase.Notes)
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].CreatedAt.Before(notes[j].CreatedAt)
+	})
+
+	total := len(notes)
+	if offset >= total {
+		return []CaseNote{}, total, nil
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+
+	return notes[offset:end], total, nil
+}
+
+// CaseReport compiles a case with the decisions for its transactions and its notes
+type CaseReport struct {
+	Case        *FraudCase       `json:"case"`
+	Decisions   []*FraudDecision `json:"decisions"` // One per case transaction that has a decision
+	Notes       []CaseNote       `json:"notes"`     // Oldest to newest
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+// GetCaseReport compiles everything recorded about a case for export
+func (s *Service) GetCaseReport(ctx context.Context, caseID uuid.UUID) (*CaseReport, error) {
+	fraudCase, err := s.caseRepo.GetByID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	byTransaction, err := s.GetDecisionsByTransactionIDs(ctx, fraudCase.TransactionIDs)
+	if err != nil {
+		return nil, err
+	}
+	decisions := make([]*FraudDecision, 0, len(byTransaction))
+	for _, txID := range uniqueIDs(fraudCase.TransactionIDs) {
+		if decision, ok := byTransaction[txID]; ok {
+			decisions = append(decisions, decision)
+		}
+	}
+
+	notes, _, err := s.ListCaseNotes(ctx, caseID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CaseReport{
+		Case:        fraudCase,
+		Decisions:   decisions,
+		Notes:       notes,
+		GeneratedAt: time.Now(),
+	}, nil
+}
+
 // ResolveCase marks a case as resolved
-func (s *Service) ResolveCase(ctx context.Context, caseID, resolverID uuid.UUID, resolution string) error {
+// A non-empty outcome also approves or declines the case's transactions that are
+// still awaiting review, on the resolver's behalf; see applyCaseOutcome
+func (s *Service) ResolveCase(ctx context.Context, caseID, resolverID uuid.UUID, resolution string, outcome ResolutionOutcome) error {
+	if !outcome.IsValid() {
+		return ErrInvalidResolutionOutcome
+	}
+	if outcome != OutcomeNone && s.transactionReviewer == nil {
+		return ErrTransactionReviewUnavailable
+	}
+
 	fraudCase, err := s.caseRepo.GetByID(ctx, caseID)
 	if err != nil {
 		return err
 	}
+	if fraudCase.IsClosed() {
+		return ErrCaseAlreadyClosed
+	}
 
+	if outcome != OutcomeNone {
+		if err := s.applyCaseOutcome(ctx, fraudCase, resolverID, outcome); err != nil {
+			return err
+		}
+	}
 	if err := fraudCase.Resolve(resolverID, resolution); err != nil {
 		return err
 	}
+	fraudCase.Outcome = outcome
 
-	return s.caseRepo.Update(ctx, fraudCase)
+	if err := s.caseRepo.Update(ctx, fraudCase); err != nil {
+		return err
+	}
+	s.notifyWebhook(ctx, newCaseEvent(EventCaseResolved, fraudCase))
+	return nil
 }
 
 // CloseCase closes a resolved case
@@ -208,7 +841,11 @@ func (s *Service) CloseCase(ctx context.Context, caseID uuid.UUID) error {
 		return err
 	}
 
-	return s.caseRepo.Update(ctx, fraudCase)
+	if err := s.caseRepo.Update(ctx, fraudCase); err != nil {
+		return err
+	}
+	s.notifyWebhook(ctx, newCaseEvent(EventCaseClosed, fraudCase))
+	return nil
 }
 
 // EscalateCase escalates a case to higher authority
@@ -222,9 +859,20 @@ func (s *Service) EscalateCase(ctx context.Context, caseID uuid.UUID, reason str
 	return s.caseRepo.Update(ctx, fraudCase)
 }
 
-// ListCasesByStatus retrieves cases by status
-func (s *Service) ListCasesByStatus(ctx context.Context, status CaseStatus, limit, offset int) ([]*FraudCase, error) {
-	return s.caseRepo.ListByStatus(ctx, status, limit, offset)
+// ListCasesByStatus returns a page of cases in a status, newest first,
+// along with the total number of cases in that status
+func (s *Service) ListCasesByStatus(ctx context.Context, status CaseStatus, limit, offset int) ([]*FraudCase, int64, error) {
+	cases, err := s.caseRepo.ListByStatus(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.caseRepo.CountByStatus(ctx, status)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return cases, total, nil
 }
 
 // ListCasesByAssignee retrieves cases assigned to an investigator
@@ -232,6 +880,11 @@ func (s *Service) ListCasesByAssignee(ctx context.Context, assigneeID uuid.UUID,
 	return s.caseRepo.ListByAssignee(ctx, assigneeID, limit, offset)
 }
 
+// ValidateRule checks a rule's type, severity, action and config without persisting it
+func (s *Service) ValidateRule(rule *Rule) error {
+	return s.validateRule(rule)
+}
+
 // CreateRule creates a new fraud detection rule
 func (s *Service) CreateRule(ctx context.Context, rule *Rule) error {
 	// Validate rule
@@ -242,6 +895,43 @@ func (s *Service) CreateRule(ctx context.Context, rule *Rule) error {
 	return s.ruleRepo.Create(ctx, rule)
 }
 
+// ImportRules validates and creates a batch of rules all-or-nothing
+// If any rule is invalid, none are created and a *RuleImportError lists every failure
+func (s *Service) ImportRules(ctx context.Context, rules []*Rule) error {
+	if len(rules) == 0 {
+		return ErrNoRulesToImport
+	}
+
+	var validationErrors []RuleValidationError
+	seen := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		if err := s.validateRule(rule); err != nil {
+			name := ""
+			if rule != nil {
+				name = rule.Name
+			}
+			validationErrors = append(validationErrors, RuleValidationError{Index: i, Name: name, Error: err.Error()})
+			continue
+		}
+		if seen[rule.Name] {
+			validationErrors = append(validationErrors, RuleValidationError{Index: i, Name: rule.Name, Error: ErrRuleAlreadyExists.Error()})
+			continue
+		}
+		seen[rule.Name] = true
+	}
+	if len(validationErrors) > 0 {
+		return &RuleImportError{Errors: validationErrors}
+	}
+
+	if err := s.ruleRepo.CreateBatch(ctx, rules); err != nil {
+		return err
+	}
+
+	// Reload once for the whole batch
+	s.ruleEngine.InvalidateCache()
+	return nil
+}
+
 // UpdateRule updates an existing rule
 func (s *Service) UpdateRule(ctx context.Context, rule *Rule) error {
 	// Validate rule
@@ -252,7 +942,12 @@ func (s *Service) UpdateRule(ctx context.Context, rule *Rule) error {
 	// Increment version for audit trail
 	rule.IncrementVersion()
 
-	return s.ruleRepo.Update(ctx, rule)
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return err
+	}
+
+	s.ruleEngine.InvalidateCache()
+	return nil
 }
 
 // GetRule retrieves a rule by ID
@@ -265,20 +960,74 @@ func (s *Service) ListActiveRules(ctx context.Context) ([]*Rule, error) {
 	return s.ruleRepo.ListActive(ctx)
 }
 
+// ListActiveRulesPage returns a page of active rules ordered by name,
+// along with the total number of active rules
+func (s *Service) ListActiveRulesPage(ctx context.Context, limit, offset int) ([]*Rule, int, error) {
+	rules, err := s.ruleRepo.ListActive(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name < rules[j].Name
+	})
+
+	total := len(rules)
+	if offset >= total {
+		return []*Rule{}, total, nil
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return rules[offset:end], total, nil
+}
+
 // DisableRule disables a rule
-func (s *Service) DisableRule(ctx context.Context, ruleID uuid.UUID) error {
-	return s.ruleRepo.Disable(ctx, ruleID)
+// Disabling is recorded as a new version so it shows in the rule's history
+func (s *Service) DisableRule(ctx context.Context, ruleID, changedBy uuid.UUID) error {
+	return s.setRuleEnabled(ctx, ruleID, changedBy, false)
 }
 
 // EnableRule enables a disabled rule
-func (s *Service) EnableRule(ctx context.Context, ruleID uuid.UUID) error {
+func (s *Service) EnableRule(ctx context.Context, ruleID, changedBy uuid.UUID) error {
+	return s.setRuleEnabled(ctx, ruleID, changedBy, true)
+}
+
+// setRuleEnabled toggles a rule and records the change as a new version
+func (s *Service) setRuleEnabled(ctx context.Context, ruleID, changedBy uuid.UUID, enabled bool) error {
 	rule, err := s.ruleRepo.GetByID(ctx, ruleID)
 	if err != nil {
 		return err
 	}
 
-	rule.Enable()
-	return s.ruleRepo.Update(ctx, rule)
+	if enabled {
+		rule.Enable()
+	} else {
+		rule.Disable()
+	}
+	rule.UpdatedBy = changedBy
+	rule.IncrementVersion()
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return err
+	}
+
+	s.ruleEngine.InvalidateCache()
+	return nil
+}
+
+// ListRuleVersions returns a rule's version history, oldest first
+func (s *Service) ListRuleVersions(ctx context.Context, ruleID uuid.UUID) ([]*RuleVersion, error) {
+	// Check the rule exists so a missing ID surfaces as ErrRuleNotFound
+	if _, err := s.ruleRepo.GetByID(ctx, ruleID); err != nil {
+		return nil, err
+	}
+	return s.ruleRepo.ListVersions(ctx, ruleID)
+}
+
+// GetRuleVersion retrieves a rule as it was at a specific version
+func (s *Service) GetRuleVersion(ctx context.Context, ruleID uuid.UUID, version int) (*Rule, error) {
+	return s.ruleRepo.GetVersion(ctx, ruleID, version)
 }
 
 // GetUserRiskProfile analyzes a user's risk profile
@@ -289,9 +1038,9 @@ func (s *Service) GetUserRiskProfile(ctx context.Context, userID uuid.UUID) (*Us
 		return nil, err
 	}
 
-	// Get blocked count in last 30 days
-	since := time.Now().AddDate(0, 0, -30)
-	blockedCount, err := s.decisionRepo.GetBlockedCount(ctx, userID, since)
+	// Get blocks in the window, which both the count and the weighted count cover
+	now := time.Now()
+	blockTimes, err := s.decisionRepo.ListBlockTimes(ctx, userID, now.Add(-blockedWindow))
 	if err != nil {
 		return nil, err
 	}
@@ -302,13 +1051,20 @@ func (s *Service) GetUserRiskProfile(ctx context.Context, userID uuid.UUID) (*Us
 		return nil, err
 	}
 
+	// Get chargebacks in last 180 days, long enough to cover the usual dispute window
+	chargebackCount, err := s.chargebackCount(ctx, userID, time.Now().AddDate(0, 0, -180))
+	if err != nil {
+		return nil, err
+	}
+
 	// Calculate risk metrics
 	riskProfile := &UserRiskProfile{
 		UserID:            userID,
-		BlockedCount:      blockedCount,
+		BlockedCount:      int64(len(blockTimes)),
 		OpenCasesCount:    int64(len(openCases)),
+		ChargebackCount:   chargebackCount,
 		RecentDecisions:   len(recentDecisions),
-		AnalyzedAt:        time.Now(),
+		AnalyzedAt:        now,
 	}
 
 	// Calculate average risk score from recent decisions
@@ -320,8 +1076,15 @@ func (s *Service) GetUserRiskProfile(ctx context.Context, userID uuid.UUID) (*Us
 		riskProfile.AverageRiskScore = totalScore.Div(decimal.NewFromInt(int64(len(recentDecisions))))
 	}
 
+	// Weigh recent blocks above old ones when decay is configured
+	if s.riskProfile.BlockedHalfLife > 0 {
+		weighted := weightedBlockedCount(blockTimes, s.riskProfile.BlockedHalfLife, now)
+		riskProfile.WeightedBlockedCount = &weighted
+	}
+
 	// Determine overall risk level
 	riskProfile.RiskLevel = s.determineUserRiskLevel(riskProfile)
+	riskProfile.HistoryScore = decimal.NewFromFloat(math.Min(userRiskPoints(riskProfile)/100, 1)).Round(4)
 
 	return riskProfile, nil
 }
@@ -331,56 +1094,48 @@ type UserRiskProfile struct {
 	UserID           uuid.UUID       `json:"user_id"`
 	RiskLevel        RiskLevel       `json:"risk_level"`
 	AverageRiskScore decimal.Decimal `json:"average_risk_score"`
-	BlockedCount     int64           `json:"blocked_count"`
+	BlockedCount     int64           `json:"blocked_count"` // Last 30 days
 	OpenCasesCount   int64           `json:"open_cases_count"`
+	ChargebackCount  int64           `json:"chargeback_count"` // Last 180 days
 	RecentDecisions  int             `json:"recent_decisions"`
 	AnalyzedAt       time.Time       `json:"analyzed_at"`
+
+	// The profile's risk points scaled to 0-1; live decisions add a weighted share of it
+	HistoryScore decimal.Decimal `json:"history_score"`
+
+	// Blocks in the last 30 days weighted by age; set when a blocked half-life
+	// is configured, and used for the risk level instead of BlockedCount
+	WeightedBlockedCount *float64 `json:"weighted_blocked_count,omitempty"`
 }
 
 // Private helper methods
 
-func (s *Service) determineDecision(score decimal.Decimal) DecisionType {
-	// Check thresholds in order of severity
-	if score.GreaterThanOrEqual(s.decisionThresholds.BlockThreshold) {
-		return DecisionBlock
-	}
-	if score.GreaterThanOrEqual(s.decisionThresholds.ReviewThreshold) {
-		return DecisionReview
-	}
-	if score.GreaterThanOrEqual(s.decisionThresholds.ChallengeThreshold) {
-		return DecisionChallenge
+func (s *Service) scoringFor(tenantID string) TenantScoringConfig {
+	if tenantID != "" {
+		if scoring, ok := s.tenantScoring[tenantID]; ok {
+			return scoring
+		}
 	}
-	return DecisionAllow
+	return TenantScoringConfig{Weights: s.scoreWeights, Thresholds: s.decisionThresholds}
 }
 
-func (s *Service) calculateConfidence(results []RuleResult) decimal.Decimal {
-	// Confidence is based on:
-	// Number of rules that fired
-	// Consistency of scores across rules
-	// Quality of data (how many rules could be evaluated)
-
-	if len(results) == 0 {
-		return decimal.Zero
+// shouldDowngrade reports whether a block or challenge has too little confidence to stand
+func (s *Service) shouldDowngrade(decision DecisionType, confidence decimal.Decimal) bool {
+	if !s.minConfidence.IsPositive() {
+		return false
 	}
-
-	firedCount := 0
-	for _, result := range results {
-		if result.Fired {
-			firedCount++
-		}
+	if decision != DecisionBlock && decision != DecisionChallenge {
+		return false
 	}
+	return confidence.LessThan(s.minConfidence)
+}
 
-	// Simple confidence: ratio of fired rules to total rules
-	// I believe this should be a sophisticated methods
-	confidence := decimal.NewFromInt(int64(firedCount)).Div(decimal.NewFromInt(int64(len(results))))
-
-	// Cap at 0.95 to account for uncertainty
-	maxConfidence := decimal.NewFromFloat(0.95)
-	if confidence.GreaterThan(maxConfidence) {
-		return maxConfidence
+// needsCoverageReview reports whether an allow was decided with too little data to stand
+func (s *Service) needsCoverageReview(decision DecisionType, coverage *decimal.Decimal) bool {
+	if !s.minDataCoverage.IsPositive() || coverage == nil {
+		return false
 	}
-
-	return confidence
+	return decision == DecisionAllow && coverage.LessThan(s.minDataCoverage)
 }
 
 func (s *Service) createFraudCaseIfNeeded(ctx context.Context, evalCtx *RuleEvaluationContext, decision *FraudDecision) error {
@@ -401,12 +1156,17 @@ func (s *Service) validateRule(rule *Rule) error {
 
 	// Validate rule type
 	validTypes := map[RuleType]bool{
-		RuleTypeVelocity:   true,
-		RuleTypeAmount:     true,
-		RuleTypeGeographic: true,
-		RuleTypeDevice:     true,
-		RuleTypeMerchant:   true,
-		RuleTypeBehavioral: true,
+		RuleTypeVelocity:       true,
+		RuleTypeAmount:         true,
+		RuleTypeGeographic:     true,
+		RuleTypeDevice:         true,
+		RuleTypeMerchant:       true,
+		RuleTypeBehavioral:     true,
+		RuleTypeIPReputation:   true,
+		RuleTypeCardTesting:    true,
+		RuleTypeChargeback:     true,
+		RuleTypeLinking:        true,
+		RuleTypeIssuerMismatch: true,
 	}
 	if !validTypes[rule.Type] {
 		return ErrInvalidRuleType
@@ -434,24 +1194,57 @@ func (s *Service) validateRule(rule *Rule) error {
 		return ErrInvalidRuleAction
 	}
 
+	if !rule.FailMode.IsValid() {
+		return ErrInvalidRuleFailMode
+	}
+
+	if !rule.Mode.IsValid() {
+		return ErrInvalidRuleMode
+	}
+
 	// Validate config is not empty
 	if len(rule.Config) == 0 {
 		return ErrRuleConfigInvalid
 	}
 
+	if _, err := rule.Schedule(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (s *Service) determineUserRiskLevel(profile *UserRiskProfile) RiskLevel {
+	score := userRiskPoints(profile)
+
+	// Determine risk level from total score
+	switch {
+	case score >= 80.0:
+		return RiskLevelCritical
+	case score >= 60.0:
+		return RiskLevelHigh
+	case score >= 30.0:
+		return RiskLevelMedium
+	default:
+		return RiskLevelLow
+	}
+}
+
+// userRiskPoints scores a user's history, 100 being a clearly risky user
+func userRiskPoints(profile *UserRiskProfile) float64 {
 	// Multiple factors determine user risk level
 	score := 0.0
 
 	// Blocked count (30%)
-	if profile.BlockedCount >= 5 {
+	blocked := float64(profile.BlockedCount)
+	if profile.WeightedBlockedCount != nil {
+		blocked = *profile.WeightedBlockedCount
+	}
+	if blocked >= 5 {
 		score += 30.0
-	} else if profile.BlockedCount >= 3 {
+	} else if blocked >= 3 {
 		score += 20.0
-	} else if profile.BlockedCount >= 1 {
+	} else if blocked >= 1 {
 		score += 10.0
 	}
 
@@ -466,17 +1259,14 @@ func (s *Service) determineUserRiskLevel(profile *UserRiskProfile) RiskLevel {
 	avgScore := profile.AverageRiskScore.InexactFloat64()
 	score += avgScore * 40.0
 
-	// Determine risk level from total score
-	switch {
-	case score >= 80.0:
-		return RiskLevelCritical
-	case score >= 60.0:
-		return RiskLevelHigh
-	case score >= 30.0:
-		return RiskLevelMedium
-	default:
-		return RiskLevelLow
+	// Chargebacks come on top - a confirmed dispute is stronger evidence than any score
+	if profile.ChargebackCount >= 2 {
+		score += 40.0
+	} else if profile.ChargebackCount >= 1 {
+		score += 20.0
 	}
+
+	return score
 }
 
 // SetDecisionThresholds allows customizing decision thresholds
@@ -485,11 +1275,67 @@ func (s *Service) SetDecisionThresholds(thresholds DecisionThresholds) {
 }
 
 // SetScoreWeights allows customizing score weights
-func (s *Service) SetScoreWeights(weights ScoreWeights) {
-	s.scoreWeights = weights
+// Weights are scaled to sum to 1; negative weights, or all zero, are rejected
+func (s *Service) SetScoreWeights(weights ScoreWeights) error {
+	normalized, err := s.normalizeScoreWeights(weights, "")
+	if err != nil {
+		return err
+	}
+	s.scoreWeights = normalized
+	return nil
+}
+
+// SetTenantScoringConfig overrides score weights and decision thresholds for one tenant
+// Tenants without an override use the global weights and thresholds. The weights
+// are checked and normalized the same way as in SetScoreWeights.
+func (s *Service) SetTenantScoringConfig(tenantID string, scoring TenantScoringConfig) error {
+	normalized, err := s.normalizeScoreWeights(scoring.Weights, tenantID)
+	if err != nil {
+		return err
+	}
+	scoring.Weights = normalized
+
+	if s.tenantScoring == nil {
+		s.tenantScoring = make(map[string]TenantScoringConfig)
+	}
+	s.tenantScoring[tenantID] = scoring
+	return nil
+}
+
+// normalizeScoreWeights validates weights and scales them to sum to 1, logging
+// when the configured weights had to be adjusted
+func (s *Service) normalizeScoreWeights(weights ScoreWeights, tenantID string) (ScoreWeights, error) {
+	if err := weights.Validate(); err != nil {
+		return weights, err
+	}
+
+	if total := weights.total(); !total.Equal(decimal.NewFromInt(1)) {
+		log := s.logger
+		if tenantID != "" {
+			log = log.With(slog.String("tenant_id", tenantID))
+		}
+		log.Info("score weights normalized to sum to 1", slog.String("configured_total", total.String()))
+	}
+	return weights.Normalized(), nil
+}
+
+// SetEvaluationRetryBackoff sets the pause before a failed rule evaluation is retried
+func (s *Service) SetEvaluationRetryBackoff(backoff time.Duration) {
+	s.evalRetryBackoff = backoff
 }
 
 // SetScoringStrategy allows customizing scoring strategy
+// It applies to the default scorer; a scorer passed to NewService picks its own
 func (s *Service) SetScoringStrategy(strategy ScoringStrategy) {
-	s.scoringStrategy = strategy
+	if scorer, ok := s.scorer.(*DefaultScorer); ok {
+		scorer.Strategy = strategy
+	}
+}
+
+// SetTypeAggregation sets how same-type rule scores are combined under the weighted average strategy
+// Like SetScoringStrategy it only applies to the default scorer
+func (s *Service) SetTypeAggregation(aggregation TypeAggregation) {
+	if scorer, ok := s.scorer.(*DefaultScorer); ok {
+		scorer.Aggregation = aggregation
+	}
 }
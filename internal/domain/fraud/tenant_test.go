@@ -0,0 +1,62 @@
+package fraud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestAnalyzeTransactionTenantThresholds(t *testing.T) {
+	strict := TenantScoringConfig{
+		Weights: DefaultScoreWeights(),
+		Thresholds: DecisionThresholds{
+			BlockThreshold:     decimal.NewFromFloat(0.5),
+			ReviewThreshold:    decimal.NewFromFloat(0.4),
+			ChallengeThreshold: decimal.NewFromFloat(0.3),
+		},
+	}
+	lenient := TenantScoringConfig{
+		Weights: DefaultScoreWeights(),
+		Thresholds: DecisionThresholds{
+			BlockThreshold:     decimal.NewFromFloat(0.95),
+			ReviewThreshold:    decimal.NewFromFloat(0.9),
+			ChallengeThreshold: decimal.NewFromFloat(0.85),
+		},
+	}
+
+	tests := []struct {
+		name         string
+		tenantID     string
+		wantDecision DecisionType
+	}{
+		{"strict tenant blocks", "strict", DecisionBlock},
+		{"lenient tenant allows", "lenient", DecisionAllow},
+		{"unknown tenant uses the global thresholds", "other", DecisionReview},
+		{"no tenant uses the global thresholds", "", DecisionReview},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newAnalyzeService(&stubEngine{results: []RuleResult{firedResult(RuleTypeAmount, 0.7)}})
+			if err := service.SetTenantScoringConfig("strict", strict); err != nil {
+				t.Fatalf("set strict: %v", err)
+			}
+			if err := service.SetTenantScoringConfig("lenient", lenient); err != nil {
+				t.Fatalf("set lenient: %v", err)
+			}
+
+			evalCtx := fullContext()
+			evalCtx.TenantID = tt.tenantID
+			decision, err := service.AnalyzeTransaction(context.Background(), evalCtx)
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			if !decision.Score.Equal(decimal.NewFromFloat(0.7)) {
+				t.Errorf("score %s, want 0.7 for every tenant", decision.Score)
+			}
+			if decision.Decision != tt.wantDecision {
+				t.Errorf("decision %s, want %s", decision.Decision, tt.wantDecision)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package fraud
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/pkg/logger"
+)
+
+// WebhookEventType names an event clients can subscribe to
+type WebhookEventType string
+
+const (
+	EventDecisionAllowed    WebhookEventType = "decision.allowed"
+	EventDecisionBlocked    WebhookEventType = "decision.blocked"
+	EventDecisionReview     WebhookEventType = "decision.review"
+	EventDecisionChallenged WebhookEventType = "decision.challenged"
+	EventCaseResolved       WebhookEventType = "case.resolved"
+	EventCaseClosed         WebhookEventType = "case.closed"
+	EventCaseSLABreached    WebhookEventType = "case.sla_breached"
+)
+
+// decisionEvents maps each decision to the event sent when it is made
+var decisionEvents = map[DecisionType]WebhookEventType{
+	DecisionAllow:     EventDecisionAllowed,
+	DecisionBlock:     EventDecisionBlocked,
+	DecisionReview:    EventDecisionReview,
+	DecisionChallenge: EventDecisionChallenged,
+}
+
+// WebhookEvent is the JSON body POSTed to a client's webhook
+// Data is a FraudAlert for decision events and a CaseEvent for case events
+type WebhookEvent struct {
+	ID         uuid.UUID        `json:"id"` // Unique per event, so receivers can drop redelivered ones
+	Type       WebhookEventType `json:"type"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Data       any              `json:"data"`
+}
+
+// CaseEvent describes a case that was resolved, closed or breached its SLA
+type CaseEvent struct {
+	CaseID         uuid.UUID         `json:"case_id"`
+	UserID         uuid.UUID         `json:"user_id"`
+	TransactionIDs []uuid.UUID       `json:"transaction_ids"`
+	Status         CaseStatus        `json:"status"`
+	Resolution     string            `json:"resolution,omitempty"`
+	Outcome        ResolutionOutcome `json:"outcome,omitempty"`
+	ResolvedBy     *uuid.UUID        `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time        `json:"resolved_at,omitempty"`
+	SLADueAt       *time.Time        `json:"sla_due_at,omitempty"` // Set on case.sla_breached
+}
+
+// newDecisionEvent builds the webhook event for a fraud decision
+func newDecisionEvent(decision *FraudDecision) *WebhookEvent {
+	return &WebhookEvent{
+		ID:         uuid.New(),
+		Type:       decisionEvents[decision.Decision],
+		OccurredAt: decision.ProcessedAt,
+		Data:       NewFraudAlert(decision),
+	}
+}
+
+// newCaseEvent builds the webhook event for a case status change
+func newCaseEvent(eventType WebhookEventType, fraudCase *FraudCase) *WebhookEvent {
+	return &WebhookEvent{
+		ID:         uuid.New(),
+		Type:       eventType,
+		OccurredAt: fraudCase.UpdatedAt,
+		Data:       newCaseEventData(fraudCase),
+	}
+}
+
+// newCaseSLAEvent builds the webhook event for a case that breached its SLA
+func newCaseSLAEvent(aging *CaseAging) *WebhookEvent {
+	data := newCaseEventData(aging.Case)
+	data.SLADueAt = &aging.DueAt
+	return &WebhookEvent{
+		ID:         uuid.New(),
+		Type:       EventCaseSLABreached,
+		OccurredAt: aging.DueAt,
+		Data:       data,
+	}
+}
+
+func newCaseEventData(fraudCase *FraudCase) *CaseEvent {
+	return &CaseEvent{
+		CaseID:         fraudCase.ID,
+		UserID:         fraudCase.UserID,
+		TransactionIDs: fraudCase.TransactionIDs,
+		Status:         fraudCase.Status,
+		Resolution:     fraudCase.Resolution,
+		Outcome:        fraudCase.Outcome,
+		ResolvedBy:     fraudCase.ResolvedBy,
+		ResolvedAt:     fraudCase.ResolvedAt,
+	}
+}
+
+// WebhookNotifier delivers events to a client's webhook
+type WebhookNotifier interface {
+	// Subscribed reports whether the webhook receives an event type
+	Subscribed(eventType WebhookEventType) bool
+
+	// Notify delivers an event, retrying until it succeeds or gives up
+	Notify(ctx context.Context, event *WebhookEvent) error
+}
+
+// WebhookDeliveryConfig bounds background webhook delivery
+type WebhookDeliveryConfig struct {
+	Workers   int // Events delivered at once
+	QueueSize int // Events waiting for a worker; events past it are dead-lettered
+}
+
+// webhookQueue hands events to a fixed pool of delivery workers
+type webhookQueue struct {
+	events chan queuedWebhook
+	wg     sync.WaitGroup
+
+	// stop cancels in-flight deliveries when a drain runs out of time
+	stop     context.Context
+	stopNow  context.CancelFunc
+	mu       sync.RWMutex
+	draining bool
+}
+
+type queuedWebhook struct {
+	ctx   context.Context
+	event *WebhookEvent
+}
+
+// startWebhookQueue starts the delivery workers
+func (s *Service) startWebhookQueue(cfg WebhookDeliveryConfig) *webhookQueue {
+	stop, stopNow := context.WithCancel(context.Background())
+	q := &webhookQueue{
+		events:  make(chan queuedWebhook, max(cfg.QueueSize, 0)),
+		stop:    stop,
+		stopNow: stopNow,
+	}
+	for range max(cfg.Workers, 1) {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for item := range q.events {
+				s.deliverWebhook(item.ctx, q.stop, item.event)
+			}
+		}()
+	}
+	return q
+}
+
+// enqueue queues an event without blocking; false when the queue is full or draining
+func (q *webhookQueue) enqueue(ctx context.Context, event *WebhookEvent) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.draining {
+		return false
+	}
+	select {
+	case q.events <- queuedWebhook{ctx: ctx, event: event}:
+		return true
+	default:
+		return false
+	}
+}
+
+// notifyWebhook queues an event for background delivery so it never blocks the caller
+// Delivery outlives the request but keeps its context values for log correlation.
+// The notifier handles retries. An event it gives up on, or one that finds the
+// queue full, is logged in full as a dead letter, so it can be found and replayed by hand.
+func (s *Service) notifyWebhook(ctx context.Context, event *WebhookEvent) {
+	if s.webhookNotifier == nil || !s.webhookNotifier.Subscribed(event.Type) {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	if !s.webhooks.enqueue(ctx, event) {
+		s.logWebhookDeadLetter(ctx, "webhook queue full, event dead-lettered", event, nil)
+	}
+}
+
+// deliverWebhook delivers one queued event, giving up once stop is cancelled
+func (s *Service) deliverWebhook(ctx, stop context.Context, event *WebhookEvent) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(stop, cancel)()
+
+	if err := s.webhookNotifier.Notify(ctx, event); err != nil {
+		s.logWebhookDeadLetter(ctx, "webhook delivery failed, event dead-lettered", event, err)
+	}
+}
+
+func (s *Service) logWebhookDeadLetter(ctx context.Context, msg string, event *WebhookEvent, err error) {
+	attrs := []any{
+		slog.String("event_id", event.ID.String()),
+		slog.String("event_type", string(event.Type)),
+		slog.Any("event", event),
+	}
+	if err != nil {
+		attrs = append(attrs, logger.Err(err))
+	}
+	s.logger.ErrorContext(ctx, msg, attrs...)
+}
+
+// DrainWebhooks stops taking webhook events and waits for the queued ones to be delivered
+// When ctx ends first, the remaining deliveries are cancelled and dead-lettered.
+// Events sent after the drain starts are dead-lettered.
+func (s *Service) DrainWebhooks(ctx context.Context) error {
+	q := s.webhooks
+	if q == nil {
+		return nil
+	}
+
+	q.mu.Lock()
+	if !q.draining {
+		q.draining = true
+		close(q.events)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.stopNow()
+		<-done
+		return ctx.Err()
+	}
+}
@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +19,15 @@ const (
 	StatusReviewing TransactionStatus = "reviewing"
 )
 
+// IsValid checks if the status is known
+func (s TransactionStatus) IsValid() bool {
+	switch s {
+	case StatusPending, StatusApproved, StatusDeclined, StatusFlagged, StatusReviewing:
+		return true
+	}
+	return false
+}
+
 // TransactionType categorizes the type of transaction
 type TransactionType string
 
@@ -204,6 +214,56 @@ func (t *Transaction) MarkUnderReview(reviewerID uuid.UUID) error {
 	return nil
 }
 
+// Override moves the transaction to the status a manual re-decision calls for
+// Unlike the review transitions it starts from any status, since reversing an
+// automated approve or decline is what an override is for. It can only set
+// approved, declined or flagged.
+func (t *Transaction) Override(status TransactionStatus, reviewerID uuid.UUID) error {
+	now := time.Now()
+	switch status {
+	case StatusApproved, StatusDeclined:
+		t.ProcessedAt = &now
+	case StatusFlagged:
+		t.ProcessedAt = nil
+	default:
+		return ErrInvalidStatusTransition
+	}
+	t.Status = status
+	t.ReviewedBy = &reviewerID
+	t.ReviewedAt = &now
+	t.UpdatedAt = now
+	return nil
+}
+
+// ApproveClaimed approves a transaction the reviewer has claimed
+// Unlike Approve it only starts from reviewing, and only for the reviewer who claimed it
+func (t *Transaction) ApproveClaimed(reviewerID uuid.UUID) error {
+	if err := t.checkClaimedBy(reviewerID); err != nil {
+		return err
+	}
+	return t.Approve()
+}
+
+// DeclineClaimed declines a transaction the reviewer has claimed
+// Like ApproveClaimed, it only starts from reviewing, for the reviewer who claimed it
+func (t *Transaction) DeclineClaimed(reviewerID uuid.UUID, reasons []string) error {
+	if err := t.checkClaimedBy(reviewerID); err != nil {
+		return err
+	}
+	return t.Decline(reasons)
+}
+
+// checkClaimedBy checks the transaction is under review by reviewerID
+func (t *Transaction) checkClaimedBy(reviewerID uuid.UUID) error {
+	if t.Status != StatusReviewing {
+		return ErrInvalidStatusTransition
+	}
+	if t.ReviewedBy == nil || *t.ReviewedBy != reviewerID {
+		return ErrClaimedByAnotherReviewer
+	}
+	return nil
+}
+
 // SetFraudScore updates the fraud score for this transaction
 func (t *Transaction) SetFraudScore(score decimal.Decimal, riskLevel string) {
 	t.FraudScore = &score
@@ -226,10 +286,11 @@ func (t *Transaction) IsHighValue(threshold decimal.Decimal) bool {
 // IsCrossBorder checks if transaction crosses international borders
 // Cross-border transactions have higher fraud risk
 func (t *Transaction) IsCrossBorder() bool {
-	if t.Location == nil || t.Payment == nil {
+	// Matches fraud.IsCrossBorder: an unknown country on either side is not a mismatch
+	if t.Location == nil || t.Payment == nil || t.Location.Country == "" || t.Payment.IssuingCountry == "" {
 		return false
 	}
-	return t.Location.Country != t.Payment.IssuingCountry
+	return !strings.EqualFold(t.Location.Country, t.Payment.IssuingCountry)
 }
 
 // GetAge returns how long ago the transaction was created
@@ -27,6 +27,9 @@ var (
 	// ErrInvalidStatusTransition is returned when an invalid status change is attempted
 	ErrInvalidStatusTransition = errors.New("invalid transaction status transition")
 
+	// ErrClaimedByAnotherReviewer is returned when a reviewer finishes a review someone else claimed
+	ErrClaimedByAnotherReviewer = errors.New("transaction is claimed by another reviewer")
+
 	// ErrTransactionAlreadyProcessed is returned when trying to modify a processed transaction
 	ErrTransactionAlreadyProcessed = errors.New("transaction has already been processed")
 
@@ -51,3 +54,20 @@ var (
 	// ErrInvalidTransactionType is returned when transaction type is invalid
 	ErrInvalidTransactionType = errors.New("invalid transaction type")
 )
+
+// IsValidationError reports whether err means the transaction itself was rejected
+func IsValidationError(err error) bool {
+	for _, target := range []error{
+		ErrInvalidTransaction,
+		ErrInvalidAmount,
+		ErrAmountTooSmall,
+		ErrAmountTooLarge,
+		ErrInvalidCurrency,
+		ErrInvalidTransactionType,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -8,6 +8,12 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// ListFilter narrows a transaction listing; zero fields match everything
+type ListFilter struct {
+	Status   TransactionStatus // Only transactions in this status
+	MinScore *decimal.Decimal  // Only transactions with a fraud score of at least this
+}
+
 // Repository defines the contract for transaction persistence
 type Repository interface {
 	// Create stores a new transaction
@@ -22,8 +28,11 @@ type Repository interface {
 	// Update updates an existing transaction
 	Update(ctx context.Context, tx *Transaction) error
 
-	// ListByUserID retrieves transactions for a user
-	ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Transaction, error)
+	// ListByUserID retrieves transactions for a user matching filter, newest first
+	ListByUserID(ctx context.Context, userID uuid.UUID, filter ListFilter, limit, offset int) ([]*Transaction, error)
+
+	// CountByUserID counts transactions for a user matching filter
+	CountByUserID(ctx context.Context, userID uuid.UUID, filter ListFilter) (int64, error)
 
 	// ListByAccountID retrieves transactions for an account
 	ListByAccountID(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*Transaction, error)
@@ -32,9 +41,16 @@ type Repository interface {
 	// This is critical for fraud detection - needs to be fast (Redis)
 	GetRecentByUserID(ctx context.Context, userID uuid.UUID, since time.Time) ([]*Transaction, error)
 
+	// GetFirstByUserID retrieves a user's earliest transaction
+	// Returns ErrTransactionNotFound when the user has none
+	GetFirstByUserID(ctx context.Context, userID uuid.UUID) (*Transaction, error)
+
 	// GetByTimeRange retrieves transactions in a time window
 	GetByTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*Transaction, error)
 
+	// ListCreatedBetween retrieves up to limit transactions of any user created in [start, end), oldest first
+	ListCreatedBetween(ctx context.Context, start, end time.Time, limit int) ([]*Transaction, error)
+
 	// CountByUserIDAndTimeRange counts transactions in a time window
 	// Used for velocity rule evaluation
 	CountByUserIDAndTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) (int64, error)
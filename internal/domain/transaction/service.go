@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -82,6 +83,34 @@ func (s *Service) DeclineTransaction(ctx context.Context, txID uuid.UUID, reason
 	return s.repo.Update(ctx, tx)
 }
 
+// ApproveClaimed approves a transaction on behalf of the reviewer who claimed it
+func (s *Service) ApproveClaimed(ctx context.Context, txID, reviewerID uuid.UUID) error {
+	tx, err := s.repo.GetByID(ctx, txID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.ApproveClaimed(reviewerID); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, tx)
+}
+
+// DeclineClaimed declines a transaction on behalf of the reviewer who claimed it
+func (s *Service) DeclineClaimed(ctx context.Context, txID, reviewerID uuid.UUID, reasons []string) error {
+	tx, err := s.repo.GetByID(ctx, txID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.DeclineClaimed(reviewerID, reasons); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, tx)
+}
+
 // FlagForReview flags a transaction for manual review
 func (s *Service) FlagForReview(ctx context.Context, txID uuid.UUID, reasons []string, score decimal.Decimal) error {
 	tx, err := s.repo.GetByID(ctx, txID)
@@ -96,6 +125,120 @@ func (s *Service) FlagForReview(ctx context.Context, txID uuid.UUID, reasons []s
 	return s.repo.Update(ctx, tx)
 }
 
+// MarkUnderReview claims a flagged transaction for a reviewer
+func (s *Service) MarkUnderReview(ctx context.Context, txID, reviewerID uuid.UUID) error {
+	tx, err := s.repo.GetByID(ctx, txID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.MarkUnderReview(reviewerID); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, tx)
+}
+
+// ApproveAfterReview approves a transaction awaiting review on a reviewer's behalf
+// A flagged transaction is claimed for the reviewer first, so ReviewedBy records
+// who overrode the automated decision. applied is false, and nothing changes,
+// when the transaction is already approved or declined.
+func (s *Service) ApproveAfterReview(ctx context.Context, txID, reviewerID uuid.UUID) (applied bool, err error) {
+	tx, err := s.claimForReview(ctx, txID, reviewerID)
+	if err != nil || tx == nil {
+		return false, err
+	}
+
+	if err := tx.Approve(); err != nil {
+		return false, err
+	}
+	return true, s.repo.Update(ctx, tx)
+}
+
+// DeclineAfterReview declines a transaction awaiting review on a reviewer's behalf
+// The reason is added to its fraud reasons. Like ApproveAfterReview, a transaction
+// that is already approved or declined is left alone.
+func (s *Service) DeclineAfterReview(ctx context.Context, txID, reviewerID uuid.UUID, reason string) (applied bool, err error) {
+	tx, err := s.claimForReview(ctx, txID, reviewerID)
+	if err != nil || tx == nil {
+		return false, err
+	}
+
+	reasons := append([]string{}, tx.FraudReasons...)
+	if reason != "" {
+		reasons = append(reasons, reason)
+	}
+	if err := tx.Decline(reasons); err != nil {
+		return false, err
+	}
+	return true, s.repo.Update(ctx, tx)
+}
+
+// claimForReview loads a transaction and claims it for the reviewer if it is flagged
+// It returns nil when the transaction is no longer awaiting review
+func (s *Service) claimForReview(ctx context.Context, txID, reviewerID uuid.UUID) (*Transaction, error) {
+	tx, err := s.repo.GetByID(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch tx.Status {
+	case StatusFlagged:
+		if err := tx.MarkUnderReview(reviewerID); err != nil {
+			return nil, err
+		}
+	case StatusPending, StatusReviewing:
+		now := time.Now()
+		tx.ReviewedBy = &reviewerID
+		tx.ReviewedAt = &now
+	default:
+		return nil, nil
+	}
+	return tx, nil
+}
+
+// ApproveOverride approves a transaction whose fraud decision was overridden to allow
+// applied is false, and nothing changes, when the transaction isn't stored or is
+// already approved. Unlike ApproveAfterReview it reverses a decline.
+func (s *Service) ApproveOverride(ctx context.Context, txID, actorID uuid.UUID) (applied bool, err error) {
+	return s.applyOverride(ctx, txID, actorID, StatusApproved, "")
+}
+
+// DeclineOverride declines a transaction whose fraud decision was overridden to block
+// The reason is added to its fraud reasons
+func (s *Service) DeclineOverride(ctx context.Context, txID, actorID uuid.UUID, reason string) (applied bool, err error) {
+	return s.applyOverride(ctx, txID, actorID, StatusDeclined, reason)
+}
+
+// FlagOverride flags a transaction for review after its fraud decision was overridden to review
+// The reason is added to its fraud reasons
+func (s *Service) FlagOverride(ctx context.Context, txID, actorID uuid.UUID, reason string) (applied bool, err error) {
+	return s.applyOverride(ctx, txID, actorID, StatusFlagged, reason)
+}
+
+// applyOverride moves a transaction to status on the actor's behalf
+// Decisions made by analysis alone have no stored transaction, which isn't an error
+func (s *Service) applyOverride(ctx context.Context, txID, actorID uuid.UUID, status TransactionStatus, reason string) (bool, error) {
+	tx, err := s.repo.GetByID(ctx, txID)
+	if errors.Is(err, ErrTransactionNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if tx.Status == status {
+		return false, nil
+	}
+
+	if reason != "" {
+		tx.FraudReasons = append(append([]string{}, tx.FraudReasons...), reason)
+	}
+	if err := tx.Override(status, actorID); err != nil {
+		return false, err
+	}
+	return true, s.repo.Update(ctx, tx)
+}
+
 // UpdateFraudScore updates the fraud score for a transaction
 func (s *Service) UpdateFraudScore(ctx context.Context, txID uuid.UUID, score decimal.Decimal, riskLevel string) error {
 	tx, err := s.repo.GetByID(ctx, txID)
@@ -107,9 +250,20 @@ func (s *Service) UpdateFraudScore(ctx context.Context, txID uuid.UUID, score de
 	return s.repo.Update(ctx, tx)
 }
 
-// ListUserTransactions retrieves transactions for a user
-func (s *Service) ListUserTransactions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Transaction, error) {
-	return s.repo.ListByUserID(ctx, userID, limit, offset)
+// ListUserTransactions returns a page of a user's transactions matching filter, newest first,
+// along with the total number of matching transactions
+func (s *Service) ListUserTransactions(ctx context.Context, userID uuid.UUID, filter ListFilter, limit, offset int) ([]*Transaction, int64, error) {
+	txs, err := s.repo.ListByUserID(ctx, userID, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.repo.CountByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return txs, total, nil
 }
 
 // ListAccountTransactions retrieves transactions for an account
@@ -122,11 +276,21 @@ func (s *Service) GetRecentTransactions(ctx context.Context, userID uuid.UUID, s
 	return s.repo.GetRecentByUserID(ctx, userID, since)
 }
 
+// GetFirstTransaction retrieves a user's earliest transaction
+func (s *Service) GetFirstTransaction(ctx context.Context, userID uuid.UUID) (*Transaction, error) {
+	return s.repo.GetFirstByUserID(ctx, userID)
+}
+
 // GetTransactionsByTimeRange retrieves transactions in a time window
 func (s *Service) GetTransactionsByTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*Transaction, error) {
 	return s.repo.GetByTimeRange(ctx, userID, start, end)
 }
 
+// ListTransactionsCreatedBetween retrieves up to limit transactions created in [start, end), oldest first
+func (s *Service) ListTransactionsCreatedBetween(ctx context.Context, start, end time.Time, limit int) ([]*Transaction, error) {
+	return s.repo.ListCreatedBetween(ctx, start, end, limit)
+}
+
 // CountTransactionsInWindow counts transactions in a time window (velocity check)
 func (s *Service) CountTransactionsInWindow(ctx context.Context, userID uuid.UUID, windowDuration time.Duration) (int64, error) {
 	start := time.Now().Add(-windowDuration)
@@ -0,0 +1,149 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// memoryRepo keeps transactions in the order created; only the methods the service tests use are implemented
+type memoryRepo struct {
+	Repository
+	txs []*Transaction
+}
+
+func (r *memoryRepo) Create(ctx context.Context, tx *Transaction) error {
+	r.txs = append(r.txs, tx)
+	return nil
+}
+
+func (r *memoryRepo) GetByID(ctx context.Context, id uuid.UUID) (*Transaction, error) {
+	for _, tx := range r.txs {
+		if tx.ID == id {
+			return tx, nil
+		}
+	}
+	return nil, ErrTransactionNotFound
+}
+
+func (r *memoryRepo) ListByUserID(ctx context.Context, userID uuid.UUID, filter ListFilter, limit, offset int) ([]*Transaction, error) {
+	return r.list(userMatch(userID, filter), limit, offset), nil
+}
+
+func (r *memoryRepo) CountByUserID(ctx context.Context, userID uuid.UUID, filter ListFilter) (int64, error) {
+	return int64(len(r.list(userMatch(userID, filter), len(r.txs), 0))), nil
+}
+
+// userMatch matches a user's transactions with filter's status
+func userMatch(userID uuid.UUID, filter ListFilter) func(*Transaction) bool {
+	return func(tx *Transaction) bool {
+		return tx.UserID == userID && (filter.Status == "" || tx.Status == filter.Status)
+	}
+}
+
+func (r *memoryRepo) ListByAccountID(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*Transaction, error) {
+	return r.list(func(tx *Transaction) bool { return tx.AccountID == accountID }, limit, offset), nil
+}
+
+// list returns a page of matching transactions, newest first
+func (r *memoryRepo) list(match func(*Transaction) bool, limit, offset int) []*Transaction {
+	var matched []*Transaction
+	for i := len(r.txs) - 1; i >= 0; i-- {
+		if match(r.txs[i]) {
+			matched = append(matched, r.txs[i])
+		}
+	}
+	if offset >= len(matched) {
+		return nil
+	}
+	return matched[offset:min(offset+limit, len(matched))]
+}
+
+func TestServiceCreateAndList(t *testing.T) {
+	repo := &memoryRepo{}
+	service := NewService(repo)
+	userID, accountID := uuid.New(), uuid.New()
+
+	txs := make([]*Transaction, 3)
+	for i := range txs {
+		txs[i] = NewTransaction(userID, accountID, TypePurchase, decimal.NewFromInt(int64(10+i)), USD)
+		if err := service.CreateTransaction(context.Background(), txs[i]); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	other := NewTransaction(uuid.New(), uuid.New(), TypePurchase, decimal.NewFromInt(5), USD)
+	if err := service.CreateTransaction(context.Background(), other); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	got, err := service.GetTransaction(context.Background(), txs[0].ID)
+	if err != nil || got.ID != txs[0].ID || got.Status != StatusPending {
+		t.Fatalf("get %+v, %v; want the pending transaction", got, err)
+	}
+	if _, total, err := service.ListUserTransactions(context.Background(), userID, ListFilter{}, 1, 0); err != nil || total != 3 {
+		t.Errorf("user total %d, %v; want 3", total, err)
+	}
+
+	tests := []struct {
+		name   string
+		list   func() ([]*Transaction, error)
+		wantIx []int // Indexes into txs, in the order listed
+	}{
+		{"by user", func() ([]*Transaction, error) {
+			listed, _, err := service.ListUserTransactions(context.Background(), userID, ListFilter{}, 10, 0)
+			return listed, err
+		}, []int{2, 1, 0}},
+		{"by user paged", func() ([]*Transaction, error) {
+			listed, _, err := service.ListUserTransactions(context.Background(), userID, ListFilter{}, 1, 1)
+			return listed, err
+		}, []int{1}},
+		{"by account", func() ([]*Transaction, error) {
+			return service.ListAccountTransactions(context.Background(), accountID, 10, 0)
+		}, []int{2, 1, 0}},
+		{"by account past the end", func() ([]*Transaction, error) {
+			return service.ListAccountTransactions(context.Background(), accountID, 10, 5)
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listed, err := tt.list()
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(listed) != len(tt.wantIx) {
+				t.Fatalf("%d transactions, want %d", len(listed), len(tt.wantIx))
+			}
+			for i, ix := range tt.wantIx {
+				if listed[i].ID != txs[ix].ID {
+					t.Errorf("position %d is %s, want %s", i, listed[i].ID, txs[ix].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceCreateRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		tx      *Transaction
+		wantErr error
+	}{
+		{"no user", NewTransaction(uuid.Nil, uuid.New(), TypePurchase, decimal.NewFromInt(10), USD), ErrInvalidTransaction},
+		{"zero amount", NewTransaction(uuid.New(), uuid.New(), TypePurchase, decimal.Zero, USD), ErrInvalidAmount},
+		{"unknown type", NewTransaction(uuid.New(), uuid.New(), "gift", decimal.NewFromInt(10), USD), ErrInvalidTransactionType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryRepo{}
+			if err := NewService(repo).CreateTransaction(context.Background(), tt.tx); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err %v, want %v", err, tt.wantErr)
+			}
+			if len(repo.txs) != 0 {
+				t.Error("invalid transaction stored")
+			}
+		})
+	}
+}
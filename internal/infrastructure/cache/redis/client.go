@@ -3,9 +3,13 @@ package redis
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+
+	"fraud-detecction-system/internal/pkg/logger"
+	"fraud-detecction-system/internal/pkg/retry"
 )
 
 // Client wraps the Redis client
@@ -22,10 +26,17 @@ type Config struct {
 	PoolSize     int
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+
+	// Connect retries the first ping, so a Redis that starts after the API is still used
+	Connect retry.Backoff
+	Logger  *slog.Logger // Logs each failed attempt; defaults to slog.Default()
 }
 
-// NewClient creates a new Redis client
-func NewClient(cfg Config) (*Client, error) {
+// pingTimeout bounds each connection attempt
+const pingTimeout = 5 * time.Second
+
+// NewClient creates a new Redis client, retrying until Redis answers a ping
+func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 	rdb := redis.NewClient(&redis.Options{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
 		Password:     cfg.Password,
@@ -35,11 +46,25 @@ func NewClient(cfg Config) (*Client, error) {
 		WriteTimeout: cfg.WriteTimeout,
 	})
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := rdb.Ping(ctx).Err(); err != nil {
+	log := cfg.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+	ping := func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+		defer cancel()
+		return rdb.Ping(ctx).Err()
+	}
+	err := cfg.Connect.Do(ctx, ping, func(attempt int, wait time.Duration, err error) {
+		log.Warn("redis not ready, retrying",
+			slog.Int("attempt", attempt),
+			slog.Int("max_attempts", cfg.Connect.Attempts),
+			slog.Duration("retry_in", wait),
+			logger.Err(err),
+		)
+	})
+	if err != nil {
+		rdb.Close()
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
@@ -71,6 +96,11 @@ func (c *Client) Get(ctx context.Context, key string) (string, error) {
 	return c.rdb.Get(ctx, key).Result()
 }
 
+// MGet gets the values of several keys; missing keys come back as nil
+func (c *Client) MGet(ctx context.Context, keys ...string) ([]interface{}, error) {
+	return c.rdb.MGet(ctx, keys...).Result()
+}
+
 // Del deletes keys
 func (c *Client) Del(ctx context.Context, keys ...string) error {
 	return c.rdb.Del(ctx, keys...).Err()
@@ -111,16 +141,31 @@ func (c *Client) ZAdd(ctx context.Context, key string, members ...redis.Z) error
 	return c.rdb.ZAdd(ctx, key, members...).Err()
 }
 
+// ZAddNX adds to sorted set, leaving members that already exist untouched
+func (c *Client) ZAddNX(ctx context.Context, key string, members ...redis.Z) error {
+	return c.rdb.ZAddNX(ctx, key, members...).Err()
+}
+
 // ZRangeByScore gets sorted set members by score
 func (c *Client) ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) ([]string, error) {
 	return c.rdb.ZRangeByScore(ctx, key, opt).Result()
 }
 
+// ZRangeByScoreWithScores gets sorted set members and their scores by score
+func (c *Client) ZRangeByScoreWithScores(ctx context.Context, key string, opt *redis.ZRangeBy) ([]redis.Z, error) {
+	return c.rdb.ZRangeByScoreWithScores(ctx, key, opt).Result()
+}
+
 // ZRemRangeByScore removes sorted set members by score
 func (c *Client) ZRemRangeByScore(ctx context.Context, key, min, max string) error {
 	return c.rdb.ZRemRangeByScore(ctx, key, min, max).Err()
 }
 
+// ZRemRangeByRank removes sorted set members by rank, lowest score first
+func (c *Client) ZRemRangeByRank(ctx context.Context, key string, start, stop int64) error {
+	return c.rdb.ZRemRangeByRank(ctx, key, start, stop).Err()
+}
+
 // ZCard returns the cardinality of a sorted set
 func (c *Client) ZCard(ctx context.Context, key string) (int64, error) {
 	return c.rdb.ZCard(ctx, key).Result()
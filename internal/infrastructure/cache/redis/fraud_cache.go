@@ -1,5 +1,108 @@
 package redis
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/pkg/metrics"
+)
+
 // FraudCache provides caching for fraud-related data
 // This file is a placeholder for additional fraud-specific caching needs
 
+// CachedDecisionRepository is a read-through cache of decisions by transaction ID
+// Retries and webhooks repeatedly ask whether a transaction was already decided,
+// so those lookups are served from Redis for a short TTL. Overrides drop the cached
+// copy. Every other method goes straight to the wrapped repository. Redis errors fall back to the repository,
+// and a nil client disables caching entirely
+type CachedDecisionRepository struct {
+	fraud.DecisionRepository
+	client *Client
+	ttl    time.Duration
+}
+
+// NewCachedDecisionRepository wraps a decision repository with a Redis cache
+func NewCachedDecisionRepository(repo fraud.DecisionRepository, client *Client, ttl time.Duration) *CachedDecisionRepository {
+	return &CachedDecisionRepository{
+		DecisionRepository: repo,
+		client:             client,
+		ttl:                ttl,
+	}
+}
+
+func decisionCacheKey(transactionID uuid.UUID) string {
+	return fmt.Sprintf("decision:tx:%s", transactionID.String())
+}
+
+// enabled reports whether lookups should go through the cache
+func (r *CachedDecisionRepository) enabled() bool {
+	return r.client != nil && r.ttl > 0
+}
+
+// Create stores a decision and caches it under its transaction ID
+// A re-analysis of the same transaction replaces the cached decision
+func (r *CachedDecisionRepository) Create(ctx context.Context, decision *fraud.FraudDecision) error {
+	if err := r.DecisionRepository.Create(ctx, decision); err != nil {
+		return err
+	}
+
+	if r.enabled() {
+		r.store(ctx, decision)
+	}
+	return nil
+}
+
+// GetByTransactionID returns the cached decision for a transaction, loading it on a miss
+func (r *CachedDecisionRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*fraud.FraudDecision, error) {
+	if !r.enabled() {
+		return r.DecisionRepository.GetByTransactionID(ctx, transactionID)
+	}
+
+	if data, err := r.client.Get(ctx, decisionCacheKey(transactionID)); err == nil {
+		var decision fraud.FraudDecision
+		if err := json.Unmarshal([]byte(data), &decision); err == nil {
+			metrics.RecordDecisionCacheLookup(true)
+			return &decision, nil
+		}
+	}
+	metrics.RecordDecisionCacheLookup(false)
+
+	decision, err := r.DecisionRepository.GetByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(ctx, decision)
+	return decision, nil
+}
+
+// RecordOverride stores an override and drops the decision's cached copy, which no longer
+// has the effective outcome
+func (r *CachedDecisionRepository) RecordOverride(ctx context.Context, override *fraud.DecisionOverride) error {
+	if err := r.DecisionRepository.RecordOverride(ctx, override); err != nil {
+		return err
+	}
+
+	if r.enabled() {
+		if err := r.client.Del(ctx, decisionCacheKey(override.TransactionID)); err != nil {
+			// Log but don't fail - the stale copy expires with the TTL
+		}
+	}
+	return nil
+}
+
+// store caches a decision under its transaction ID
+func (r *CachedDecisionRepository) store(ctx context.Context, decision *fraud.FraudDecision) {
+	data, err := json.Marshal(decision)
+	if err != nil {
+		return
+	}
+	if err := r.client.Set(ctx, decisionCacheKey(decision.TransactionID), data, r.ttl); err != nil {
+		// Log but don't fail - the repository still has the decision
+	}
+}
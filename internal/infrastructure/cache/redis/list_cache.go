@@ -0,0 +1,131 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// CachedListRepository caches allowlist and denylist lookups by entity
+// Every analysis checks the lists, so FindMatches is served from Redis, including
+// entities that are on no list. Adding or deleting an entry clears the cached
+// lookup for its entity. Redis errors fall back to the repository, and a nil
+// client disables caching entirely
+type CachedListRepository struct {
+	fraud.ListRepository
+	client *Client
+	ttl    time.Duration
+}
+
+// NewCachedListRepository wraps a list repository with a Redis cache
+func NewCachedListRepository(repo fraud.ListRepository, client *Client, ttl time.Duration) *CachedListRepository {
+	return &CachedListRepository{
+		ListRepository: repo,
+		client:         client,
+		ttl:            ttl,
+	}
+}
+
+func listCacheKey(entityType fraud.ListEntityType, value string) string {
+	return fmt.Sprintf("list:%s:%s", entityType, value)
+}
+
+// enabled reports whether lookups should go through the cache
+func (r *CachedListRepository) enabled() bool {
+	return r.client != nil && r.ttl > 0
+}
+
+// Create stores an entry and clears the cached lookup for its entity
+func (r *CachedListRepository) Create(ctx context.Context, entry *fraud.ListEntry) error {
+	if err := r.ListRepository.Create(ctx, entry); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entry)
+	return nil
+}
+
+// Delete removes an entry and clears the cached lookup for its entity
+func (r *CachedListRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	entry, err := r.ListRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.ListRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, entry)
+	return nil
+}
+
+// FindMatches returns cached matches for each key, loading the keys that missed
+func (r *CachedListRepository) FindMatches(ctx context.Context, keys []fraud.ListKey) ([]*fraud.ListEntry, error) {
+	if !r.enabled() || len(keys) == 0 {
+		return r.ListRepository.FindMatches(ctx, keys)
+	}
+
+	cacheKeys := make([]string, len(keys))
+	for i, key := range keys {
+		cacheKeys[i] = listCacheKey(key.EntityType, key.Value)
+	}
+
+	matches := make([]*fraud.ListEntry, 0)
+	missed := make([]fraud.ListKey, 0, len(keys))
+	values, err := r.client.MGet(ctx, cacheKeys...)
+	if err != nil {
+		missed = keys
+	} else {
+		for i, value := range values {
+			var cached []*fraud.ListEntry
+			data, ok := value.(string)
+			if !ok || json.Unmarshal([]byte(data), &cached) != nil {
+				missed = append(missed, keys[i])
+				continue
+			}
+			matches = append(matches, cached...)
+		}
+	}
+	if len(missed) == 0 {
+		return matches, nil
+	}
+
+	loaded, err := r.ListRepository.FindMatches(ctx, missed)
+	if err != nil {
+		return nil, err
+	}
+	matches = append(matches, loaded...)
+
+	// Cache every missed key, with an empty list for entities on no list
+	byKey := make(map[string][]*fraud.ListEntry, len(missed))
+	for _, key := range missed {
+		byKey[listCacheKey(key.EntityType, key.Value)] = []*fraud.ListEntry{}
+	}
+	for _, entry := range loaded {
+		cacheKey := listCacheKey(entry.EntityType, entry.Value)
+		byKey[cacheKey] = append(byKey[cacheKey], entry)
+	}
+	for cacheKey, entries := range byKey {
+		data, err := json.Marshal(entries)
+		if err != nil {
+			continue
+		}
+		if err := r.client.Set(ctx, cacheKey, data, r.ttl); err != nil {
+			// Log but don't fail - the next lookup reads the repository again
+		}
+	}
+	return matches, nil
+}
+
+// invalidate clears the cached lookup for an entry's entity
+func (r *CachedListRepository) invalidate(ctx context.Context, entry *fraud.ListEntry) {
+	if !r.enabled() {
+		return
+	}
+	if err := r.client.Del(ctx, listCacheKey(entry.EntityType, entry.Value)); err != nil {
+		// Log but don't fail - the cached lookup expires after the TTL
+	}
+}
@@ -0,0 +1,120 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/infrastructure/cache/redis"
+	"fraud-detecction-system/internal/infrastructure/cache/redis/redistest"
+)
+
+// countingLists is a list repository that counts the keys it is asked to match
+type countingLists struct {
+	fraud.ListRepository
+	entries map[uuid.UUID]*fraud.ListEntry
+	lookups int
+}
+
+func (r *countingLists) Create(ctx context.Context, entry *fraud.ListEntry) error {
+	r.entries[entry.ID] = entry
+	return nil
+}
+
+func (r *countingLists) GetByID(ctx context.Context, id uuid.UUID) (*fraud.ListEntry, error) {
+	if entry, ok := r.entries[id]; ok {
+		return entry, nil
+	}
+	return nil, fraud.ErrListEntryNotFound
+}
+
+func (r *countingLists) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.entries, id)
+	return nil
+}
+
+func (r *countingLists) FindMatches(ctx context.Context, keys []fraud.ListKey) ([]*fraud.ListEntry, error) {
+	r.lookups += len(keys)
+	var matches []*fraud.ListEntry
+	for _, entry := range r.entries {
+		for _, key := range keys {
+			if entry.EntityType == key.EntityType && entry.Value == key.Value {
+				matches = append(matches, entry)
+			}
+		}
+	}
+	return matches, nil
+}
+
+func TestCachedListRepository(t *testing.T) {
+	ctx := context.Background()
+	const ttl = 200 * time.Millisecond
+	repo := &countingLists{entries: make(map[uuid.UUID]*fraud.ListEntry)}
+	cached := redis.NewCachedListRepository(repo, redistest.NewClient(t), ttl)
+
+	denied := fraud.NewListEntry(fraud.ListTypeDeny, fraud.ListEntityDeviceID, "device-1", "", uuid.Nil)
+	if err := cached.Create(ctx, denied); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	keys := []fraud.ListKey{
+		{EntityType: fraud.ListEntityDeviceID, Value: "device-1"},
+		{EntityType: fraud.ListEntityIP, Value: "203.0.113.7"}, // On no list
+	}
+
+	// find looks the keys up and checks how many matched and how many reached the repository
+	find := func(step string, wantMatches, wantLookups int) {
+		t.Helper()
+		repo.lookups = 0
+		matches, err := cached.FindMatches(ctx, keys)
+		if err != nil {
+			t.Fatalf("%s: %v", step, err)
+		}
+		if len(matches) != wantMatches || repo.lookups != wantLookups {
+			t.Errorf("%s: %d matches and %d repository lookups, want %d and %d", step, len(matches), repo.lookups, wantMatches, wantLookups)
+		}
+	}
+
+	find("first lookup", 1, 2)
+	find("cached lookup", 1, 0) // Including the entity on no list
+
+	allowed := fraud.NewListEntry(fraud.ListTypeAllow, fraud.ListEntityIP, "203.0.113.7", "", uuid.Nil)
+	if err := cached.Create(ctx, allowed); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	find("after adding an entry", 2, 1)
+
+	if err := cached.Delete(ctx, denied.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	find("after deleting an entry", 1, 1)
+
+	// An entry changed behind the cache shows once the cached lookups expire
+	delete(repo.entries, allowed.ID)
+	find("before expiry", 1, 0)
+	time.Sleep(ttl + 50*time.Millisecond)
+	find("after expiry", 0, 2)
+}
+
+func TestCachedListRepositoryDisabled(t *testing.T) {
+	ctx := context.Background()
+	repo := &countingLists{entries: make(map[uuid.UUID]*fraud.ListEntry)}
+	keys := []fraud.ListKey{{EntityType: fraud.ListEntityDeviceID, Value: "device-1"}}
+
+	for _, cached := range []*redis.CachedListRepository{
+		redis.NewCachedListRepository(repo, nil, time.Minute),
+		redis.NewCachedListRepository(repo, redistest.NewClient(t), 0),
+	} {
+		repo.lookups = 0
+		for i := 0; i < 2; i++ {
+			if _, err := cached.FindMatches(ctx, keys); err != nil {
+				t.Fatalf("find: %v", err)
+			}
+		}
+		if repo.lookups != 2 {
+			t.Errorf("%d repository lookups, want every lookup to reach the repository", repo.lookups)
+		}
+	}
+}
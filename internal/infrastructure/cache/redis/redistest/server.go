@@ -0,0 +1,314 @@
+// Package redistest provides an in-memory Redis server for cache and rule tests
+package redistest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fraud-detecction-system/internal/infrastructure/cache/redis"
+)
+
+// server is an in-memory server speaking enough RESP2 for the string, set and
+// sorted set commands the caches use; it lets tests run without a Redis server
+type server struct {
+	mu      sync.Mutex
+	sorted  map[string]map[string]float64
+	members map[string]map[string]struct{}
+	values  map[string]value
+}
+
+// value is a string key; a zero expiresAt never expires
+type value struct {
+	data      string
+	expiresAt time.Time
+}
+
+// NewClient starts a fake server and returns a client connected to it; both
+// are shut down when the test finishes
+func NewClient(t testing.TB) *redis.Client {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	s := &server{
+		sorted:  make(map[string]map[string]float64),
+		members: make(map[string]map[string]struct{}),
+		values:  make(map[string]value),
+	}
+	var connsMu sync.Mutex
+	var conns []net.Conn
+	t.Cleanup(func() {
+		ln.Close()
+		connsMu.Lock()
+		defer connsMu.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			connsMu.Lock()
+			conns = append(conns, conn)
+			connsMu.Unlock()
+			go s.serve(conn)
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	client, err := redis.NewClient(context.Background(), redis.Config{Host: addr.IP.String(), Port: addr.Port})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func (s *server) serve(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		args, err := ReadCommand(r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.exec(w, args)
+		s.mu.Unlock()
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *server) exec(w *bufio.Writer, args []string) {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		w.WriteString("+PONG\r\n")
+	case "HELLO", "CLIENT":
+		// Refusing RESP3 makes the client fall back to RESP2
+		w.WriteString("-ERR unknown command\r\n")
+	case "EXPIRE":
+		w.WriteString(":1\r\n")
+	case "SET":
+		v := value{data: args[2]}
+		for i := 3; i+1 < len(args); i += 2 {
+			n, _ := strconv.Atoi(args[i+1])
+			switch strings.ToUpper(args[i]) {
+			case "EX":
+				v.expiresAt = time.Now().Add(time.Duration(n) * time.Second)
+			case "PX":
+				v.expiresAt = time.Now().Add(time.Duration(n) * time.Millisecond)
+			}
+		}
+		s.values[args[1]] = v
+		w.WriteString("+OK\r\n")
+	case "GET":
+		if data, ok := s.get(args[1]); ok {
+			writeBulk(w, data)
+		} else {
+			w.WriteString("$-1\r\n")
+		}
+	case "MGET":
+		fmt.Fprintf(w, "*%d\r\n", len(args)-1)
+		for _, key := range args[1:] {
+			if data, ok := s.get(key); ok {
+				writeBulk(w, data)
+			} else {
+				w.WriteString("$-1\r\n")
+			}
+		}
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := s.get(key); ok {
+				delete(s.values, key)
+				deleted++
+			}
+		}
+		fmt.Fprintf(w, ":%d\r\n", deleted)
+	case "SADD":
+		set := s.set(args[1])
+		added := 0
+		for _, m := range args[2:] {
+			if _, ok := set[m]; !ok {
+				set[m] = struct{}{}
+				added++
+			}
+		}
+		fmt.Fprintf(w, ":%d\r\n", added)
+	case "SISMEMBER":
+		if _, ok := s.members[args[1]][args[2]]; ok {
+			w.WriteString(":1\r\n")
+		} else {
+			w.WriteString(":0\r\n")
+		}
+	case "SCARD":
+		fmt.Fprintf(w, ":%d\r\n", len(s.members[args[1]]))
+	case "SMEMBERS":
+		fmt.Fprintf(w, "*%d\r\n", len(s.members[args[1]]))
+		for m := range s.members[args[1]] {
+			writeBulk(w, m)
+		}
+	case "ZADD":
+		set := s.zset(args[1])
+		nx := strings.EqualFold(args[2], "NX")
+		rest := args[2:]
+		if nx {
+			rest = rest[1:]
+		}
+		added := 0
+		for i := 0; i+1 < len(rest); i += 2 {
+			score, _ := strconv.ParseFloat(rest[i], 64)
+			if _, ok := set[rest[i+1]]; ok && nx {
+				continue
+			}
+			set[rest[i+1]] = score
+			added++
+		}
+		fmt.Fprintf(w, ":%d\r\n", added)
+	case "ZCOUNT":
+		fmt.Fprintf(w, ":%d\r\n", len(s.inRange(args[1], args[2], args[3])))
+	case "ZRANGEBYSCORE", "ZREVRANGEBYSCORE":
+		reverse := strings.EqualFold(args[0], "ZREVRANGEBYSCORE")
+		lo, hi := args[2], args[3]
+		if reverse {
+			lo, hi = hi, lo
+		}
+		members := s.inRange(args[1], lo, hi)
+		if reverse {
+			for i, j := 0, len(members)-1; i < j; i, j = i+1, j-1 {
+				members[i], members[j] = members[j], members[i]
+			}
+		}
+		withScores := false
+		for i := 4; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "WITHSCORES":
+				withScores = true
+			case "LIMIT":
+				offset, _ := strconv.Atoi(args[i+1])
+				count, _ := strconv.Atoi(args[i+2])
+				members = members[min(offset, len(members)):]
+				if count >= 0 && count < len(members) {
+					members = members[:count]
+				}
+				i += 2
+			}
+		}
+		n := len(members)
+		if withScores {
+			n *= 2
+		}
+		fmt.Fprintf(w, "*%d\r\n", n)
+		for _, m := range members {
+			writeBulk(w, m)
+			if withScores {
+				writeBulk(w, strconv.FormatFloat(s.sorted[args[1]][m], 'f', -1, 64))
+			}
+		}
+	case "ZREMRANGEBYSCORE":
+		removed := s.inRange(args[1], args[2], args[3])
+		for _, m := range removed {
+			delete(s.sorted[args[1]], m)
+		}
+		fmt.Fprintf(w, ":%d\r\n", len(removed))
+	case "ZREMRANGEBYRANK":
+		// Sets in these tests stay under the caps, so nothing is ever trimmed
+		w.WriteString(":0\r\n")
+	default:
+		fmt.Fprintf(w, "-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+// get returns the string at key, dropping it once it has expired
+func (s *server) get(key string) (string, bool) {
+	v, ok := s.values[key]
+	if ok && !v.expiresAt.IsZero() && !time.Now().Before(v.expiresAt) {
+		delete(s.values, key)
+		return "", false
+	}
+	return v.data, ok
+}
+
+func (s *server) set(key string) map[string]struct{} {
+	if s.members[key] == nil {
+		s.members[key] = make(map[string]struct{})
+	}
+	return s.members[key]
+}
+
+func (s *server) zset(key string) map[string]float64 {
+	if s.sorted[key] == nil {
+		s.sorted[key] = make(map[string]float64)
+	}
+	return s.sorted[key]
+}
+
+// inRange returns the members of key scored within [min, max], lowest score first
+func (s *server) inRange(key, min, max string) []string {
+	lo, hi := parseBound(min), parseBound(max)
+	var members []string
+	for m, score := range s.sorted[key] {
+		if score >= lo && score <= hi {
+			members = append(members, m)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return s.sorted[key][members[i]] < s.sorted[key][members[j]]
+	})
+	return members
+}
+
+func parseBound(s string) float64 {
+	switch s {
+	case "-inf":
+		return math.Inf(-1)
+	case "+inf", "inf":
+		return math.Inf(1)
+	}
+	v, _ := strconv.ParseFloat(strings.TrimPrefix(s, "("), 64)
+	return v
+}
+
+func writeBulk(w *bufio.Writer, s string) {
+	fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s)
+}
+
+// ReadCommand reads one RESP array of bulk strings
+func ReadCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil { // $<length>
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
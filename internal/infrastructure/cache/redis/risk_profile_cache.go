@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// RiskProfileCache implements fraud.RiskProfileCache
+// Building a profile takes several database queries, so live decisions read it from
+// Redis for a short TTL. A profile can be up to one TTL out of date. A nil client
+// or a zero TTL disables caching
+type RiskProfileCache struct {
+	client *Client
+	ttl    time.Duration
+}
+
+// NewRiskProfileCache creates a new risk profile cache
+func NewRiskProfileCache(client *Client, ttl time.Duration) *RiskProfileCache {
+	return &RiskProfileCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func riskProfileCacheKey(userID uuid.UUID) string {
+	return fmt.Sprintf("risk_profile:%s", userID.String())
+}
+
+// enabled reports whether profiles should be cached
+func (c *RiskProfileCache) enabled() bool {
+	return c.client != nil && c.ttl > 0
+}
+
+// Get returns the cached profile for a user
+func (c *RiskProfileCache) Get(ctx context.Context, userID uuid.UUID) (*fraud.UserRiskProfile, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+
+	data, err := c.client.Get(ctx, riskProfileCacheKey(userID))
+	if err != nil {
+		return nil, false
+	}
+	var profile fraud.UserRiskProfile
+	if err := json.Unmarshal([]byte(data), &profile); err != nil {
+		return nil, false
+	}
+	return &profile, true
+}
+
+// Set caches a profile for the TTL
+func (c *RiskProfileCache) Set(ctx context.Context, profile *fraud.UserRiskProfile) {
+	if !c.enabled() {
+		return
+	}
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return
+	}
+	if err := c.client.Set(ctx, riskProfileCacheKey(profile.UserID), data, c.ttl); err != nil {
+		// Log but don't fail - the next decision rebuilds the profile
+	}
+}
+
+// Delete drops a user's cached profile
+func (c *RiskProfileCache) Delete(ctx context.Context, userID uuid.UUID) {
+	if !c.enabled() {
+		return
+	}
+	if err := c.client.Del(ctx, riskProfileCacheKey(userID)); err != nil {
+		// Log but don't fail - the stale profile expires with the TTL
+	}
+}
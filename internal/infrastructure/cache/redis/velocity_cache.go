@@ -2,8 +2,11 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,14 +14,62 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+const (
+	// DefaultVelocityRetention is how long velocity history is kept unless configured otherwise
+	DefaultVelocityRetention = 24 * time.Hour
+
+	// MaxVelocityRetention caps the retention however it was set, so a misconfigured
+	// value can't keep months of history per user in memory
+	MaxVelocityRetention = 90 * 24 * time.Hour
+
+	// maxVelocityEntries caps the transactions kept per user; the oldest go first
+	maxVelocityEntries = 10000
+)
+
 // VelocityCache handles velocity tracking for fraud detection
 type VelocityCache struct {
-	client *Client
+	client    *Client
+	retention atomic.Int64 // time.Duration; raised at runtime by EnsureRetention
 }
 
 // NewVelocityCache creates a new velocity cache
 func NewVelocityCache(client *Client) *VelocityCache {
-	return &VelocityCache{client: client}
+	c := &VelocityCache{client: client}
+	c.retention.Store(int64(DefaultVelocityRetention))
+	return c
+}
+
+// SetRetention sets how long velocity history is kept, capped at MaxVelocityRetention
+// A non-positive retention restores DefaultVelocityRetention
+func (c *VelocityCache) SetRetention(retention time.Duration) {
+	if retention <= 0 {
+		retention = DefaultVelocityRetention
+	}
+	c.retention.Store(int64(min(retention, MaxVelocityRetention)))
+}
+
+// EnsureRetention raises the retention to cover window, capped at MaxVelocityRetention
+// It never lowers the retention. Only transactions recorded from now on are kept
+// longer; history already trimmed under the old retention is gone.
+func (c *VelocityCache) EnsureRetention(window time.Duration) {
+	raiseRetention(&c.retention, window)
+}
+
+// raiseRetention raises retention to window, capped at MaxVelocityRetention; it never lowers it
+func raiseRetention(retention *atomic.Int64, window time.Duration) {
+	window = min(window, MaxVelocityRetention)
+	for {
+		current := retention.Load()
+		if int64(window) <= current || retention.CompareAndSwap(current, int64(window)) {
+			return
+		}
+	}
+}
+
+// Retention returns how long velocity history is kept
+// Count and sum queries cover any window up to it
+func (c *VelocityCache) Retention() time.Duration {
+	return time.Duration(c.retention.Load())
 }
 
 // TransactionRecord represents a cached transaction for velocity checks
@@ -33,25 +84,29 @@ func (c *VelocityCache) RecordTransaction(ctx context.Context, userID uuid.UUID,
 	key := fmt.Sprintf("velocity:user:%s", userID.String())
 
 	// Use sorted set with timestamp as score for efficient range queries
+	// The member carries the transaction ID, so recording a retried transaction
+	// again is a no-op and keeps its original timestamp
 	member := redis.Z{
 		Score:  float64(timestamp.Unix()),
-		Member: fmt.Sprintf("%s|%s", txID.String(), amount.String()),
+		Member: velocityMember(txID, amount),
 	}
 
-	if err := c.client.ZAdd(ctx, key, member); err != nil {
+	if err := c.client.ZAddNX(ctx, key, member); err != nil {
 		return fmt.Errorf("failed to record transaction: %w", err)
 	}
 
-	// Set expiration on the key (24 hours of data is enough for most velocity checks)
-	if err := c.client.Expire(ctx, key, 24*time.Hour); err != nil {
+	// Keep the key as long as the longest window velocity rules look back over
+	retention := c.Retention()
+	if err := c.client.Expire(ctx, key, retention); err != nil {
 		return fmt.Errorf("failed to set expiration: %w", err)
 	}
 
-	// Clean up old entries (older than 24 hours)
-	cutoff := time.Now().Add(-24 * time.Hour).Unix()
+	// Clean up entries older than the retention, and the oldest past the per-user cap
+	cutoff := time.Now().Add(-retention).Unix()
 	if err := c.client.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(cutoff, 10)); err != nil {
 		// Log but don't fail - cleanup is best effort
 	}
+	_ = c.client.ZRemRangeByRank(ctx, key, 0, -maxVelocityEntries-1)
 
 	return nil
 }
@@ -88,19 +143,7 @@ func (c *VelocityCache) GetTransactionSum(ctx context.Context, userID uuid.UUID,
 
 	total := decimal.Zero
 	for _, member := range members {
-		// Parse "txID|amount" format
-		var txID, amountStr string
-		fmt.Sscanf(member, "%s|%s", &txID, &amountStr)
-
-		// Find the | separator properly
-		for i := len(member) - 1; i >= 0; i-- {
-			if member[i] == '|' {
-				amountStr = member[i+1:]
-				break
-			}
-		}
-
-		if amount, err := decimal.NewFromString(amountStr); err == nil {
+		if _, amount, ok := parseVelocityMember(member); ok {
 			total = total.Add(amount)
 		}
 	}
@@ -115,7 +158,8 @@ func (c *VelocityCache) GetRecentTransactions(ctx context.Context, userID uuid.U
 	minTime := time.Now().Add(-window).Unix()
 	maxTime := time.Now().Unix()
 
-	members, err := c.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+	// Scores carry the transaction's Unix timestamp
+	entries, err := c.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
 		Min: strconv.FormatInt(minTime, 10),
 		Max: strconv.FormatInt(maxTime, 10),
 	})
@@ -123,41 +167,52 @@ func (c *VelocityCache) GetRecentTransactions(ctx context.Context, userID uuid.U
 		return nil, fmt.Errorf("failed to get transactions: %w", err)
 	}
 
-	records := make([]TransactionRecord, 0, len(members))
-	for _, member := range members {
-		// Find separator
-		sepIdx := -1
-		for i := len(member) - 1; i >= 0; i-- {
-			if member[i] == '|' {
-				sepIdx = i
-				break
-			}
-		}
-		if sepIdx == -1 {
-			continue
-		}
-
-		txIDStr := member[:sepIdx]
-		amountStr := member[sepIdx+1:]
-
-		txID, err := uuid.Parse(txIDStr)
-		if err != nil {
+	records := make([]TransactionRecord, 0, len(entries))
+	for _, entry := range entries {
+		member, ok := entry.Member.(string)
+		if !ok {
 			continue
 		}
-		amount, err := decimal.NewFromString(amountStr)
-		if err != nil {
+		txID, amount, ok := parseVelocityMember(member)
+		if !ok {
 			continue
 		}
 
 		records = append(records, TransactionRecord{
 			TransactionID: txID,
 			Amount:        amount,
+			Timestamp:     time.Unix(int64(entry.Score), 0),
 		})
 	}
 
 	return records, nil
 }
 
+// velocityMember encodes a sorted set member as "txID|amount"
+// Velocity and card testing entries share this format
+func velocityMember(txID uuid.UUID, amount decimal.Decimal) string {
+	return txID.String() + "|" + amount.String()
+}
+
+// parseVelocityMember decodes a member written by velocityMember
+// ok is false for a malformed member, which callers skip rather than count
+func parseVelocityMember(member string) (txID uuid.UUID, amount decimal.Decimal, ok bool) {
+	parts := strings.SplitN(member, "|", 2)
+	if len(parts) != 2 {
+		return uuid.Nil, decimal.Zero, false
+	}
+
+	txID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return uuid.Nil, decimal.Zero, false
+	}
+	amount, err = decimal.NewFromString(parts[1])
+	if err != nil {
+		return uuid.Nil, decimal.Zero, false
+	}
+	return txID, amount, true
+}
+
 // DeviceCache tracks device usage patterns
 type DeviceCache struct {
 	client *Client
@@ -169,6 +224,7 @@ func NewDeviceCache(client *Client) *DeviceCache {
 }
 
 // RecordDeviceUsage records device usage for a user
+// The user is also added to the device's own set, so sharing across accounts can be counted
 func (c *DeviceCache) RecordDeviceUsage(ctx context.Context, userID uuid.UUID, deviceID string) error {
 	key := fmt.Sprintf("devices:user:%s", userID.String())
 
@@ -181,6 +237,15 @@ func (c *DeviceCache) RecordDeviceUsage(ctx context.Context, userID uuid.UUID, d
 		return fmt.Errorf("failed to set expiration: %w", err)
 	}
 
+	usersKey := fmt.Sprintf("users:device:%s", deviceID)
+	if err := c.client.rdb.SAdd(ctx, usersKey, userID.String()).Err(); err != nil {
+		return fmt.Errorf("failed to record device user: %w", err)
+	}
+
+	if err := c.client.Expire(ctx, usersKey, 30*24*time.Hour); err != nil {
+		return fmt.Errorf("failed to set expiration: %w", err)
+	}
+
 	return nil
 }
 
@@ -190,6 +255,12 @@ func (c *DeviceCache) GetDeviceCount(ctx context.Context, userID uuid.UUID) (int
 	return c.client.rdb.SCard(ctx, key).Result()
 }
 
+// GetUserCountForDevice returns the number of unique users seen on a device
+func (c *DeviceCache) GetUserCountForDevice(ctx context.Context, deviceID string) (int64, error) {
+	key := fmt.Sprintf("users:device:%s", deviceID)
+	return c.client.rdb.SCard(ctx, key).Result()
+}
+
 // IsKnownDevice checks if a device is known for a user
 func (c *DeviceCache) IsKnownDevice(ctx context.Context, userID uuid.UUID, deviceID string) (bool, error) {
 	key := fmt.Sprintf("devices:user:%s", userID.String())
@@ -207,7 +278,9 @@ func NewLocationCache(client *Client) *LocationCache {
 }
 
 // RecordLocation records a location for a user
-func (c *LocationCache) RecordLocation(ctx context.Context, userID uuid.UUID, country, city string) error {
+// The region (state/province) is tracked in its own set so it can be checked
+// independently of the city; an empty region is not recorded
+func (c *LocationCache) RecordLocation(ctx context.Context, userID uuid.UUID, country, region, city string) error {
 	key := fmt.Sprintf("locations:user:%s", userID.String())
 	location := fmt.Sprintf("%s:%s", country, city)
 
@@ -219,6 +292,19 @@ func (c *LocationCache) RecordLocation(ctx context.Context, userID uuid.UUID, co
 		return fmt.Errorf("failed to set expiration: %w", err)
 	}
 
+	if region == "" {
+		return nil
+	}
+
+	regionKey := fmt.Sprintf("regions:user:%s", userID.String())
+	if err := c.client.rdb.SAdd(ctx, regionKey, fmt.Sprintf("%s:%s", country, region)).Err(); err != nil {
+		return fmt.Errorf("failed to record region: %w", err)
+	}
+
+	if err := c.client.Expire(ctx, regionKey, 90*24*time.Hour); err != nil {
+		return fmt.Errorf("failed to set expiration: %w", err)
+	}
+
 	return nil
 }
 
@@ -229,9 +315,331 @@ func (c *LocationCache) IsKnownLocation(ctx context.Context, userID uuid.UUID, c
 	return c.client.rdb.SIsMember(ctx, key, location).Result()
 }
 
+// IsKnownCountry checks if the user has transacted from any city in a country
+func (c *LocationCache) IsKnownCountry(ctx context.Context, userID uuid.UUID, country string) (bool, error) {
+	locations, err := c.GetKnownLocations(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	prefix := country + ":"
+	for _, location := range locations {
+		if strings.HasPrefix(location, prefix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsKnownRegion checks if the user has transacted from a region of a country
+func (c *LocationCache) IsKnownRegion(ctx context.Context, userID uuid.UUID, country, region string) (bool, error) {
+	key := fmt.Sprintf("regions:user:%s", userID.String())
+	return c.client.rdb.SIsMember(ctx, key, fmt.Sprintf("%s:%s", country, region)).Result()
+}
+
 // GetKnownLocations returns all known locations for a user
 func (c *LocationCache) GetKnownLocations(ctx context.Context, userID uuid.UUID) ([]string, error) {
 	key := fmt.Sprintf("locations:user:%s", userID.String())
 	return c.client.rdb.SMembers(ctx, key).Result()
 }
 
+// maxLocationPoints caps how many located transactions are kept per user
+const maxLocationPoints = 20
+
+// LocationPoint is where and when a transaction with coordinates took place
+type LocationPoint struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Country       string    `json:"country"`
+	City          string    `json:"city"`
+	Latitude      float64   `json:"latitude"`
+	Longitude     float64   `json:"longitude"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// RecordLocationPoint records a transaction's coordinates for impossible-travel checks
+// Points are kept in a sorted set scored by timestamp; only the latest few are retained
+func (c *LocationCache) RecordLocationPoint(ctx context.Context, userID uuid.UUID, point LocationPoint) error {
+	key := fmt.Sprintf("geo:user:%s", userID.String())
+
+	data, err := json.Marshal(point)
+	if err != nil {
+		return fmt.Errorf("failed to marshal location point: %w", err)
+	}
+
+	if err := c.client.ZAdd(ctx, key, redis.Z{Score: float64(point.Timestamp.Unix()), Member: data}); err != nil {
+		return fmt.Errorf("failed to record location point: %w", err)
+	}
+
+	if err := c.client.Expire(ctx, key, 90*24*time.Hour); err != nil {
+		return fmt.Errorf("failed to set expiration: %w", err)
+	}
+
+	if err := c.client.rdb.ZRemRangeByRank(ctx, key, 0, -maxLocationPoints-1).Err(); err != nil {
+		// Log but don't fail - trimming is best effort
+	}
+
+	return nil
+}
+
+// GetLastLocationPoint returns the user's most recent located transaction before the given time
+// The transaction being evaluated is skipped; nil is returned when none is known
+func (c *LocationCache) GetLastLocationPoint(ctx context.Context, userID, excludeTxID uuid.UUID, before time.Time) (*LocationPoint, error) {
+	key := fmt.Sprintf("geo:user:%s", userID.String())
+
+	members, err := c.client.rdb.ZRevRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(before.Unix(), 10),
+		Count: maxLocationPoints,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location points: %w", err)
+	}
+
+	// Scores only have second precision, so compare the stored timestamps
+	var last *LocationPoint
+	for _, member := range members {
+		var point LocationPoint
+		if err := json.Unmarshal([]byte(member), &point); err != nil {
+			continue
+		}
+		if point.TransactionID == excludeTxID || point.Timestamp.After(before) {
+			continue
+		}
+		if last == nil || point.Timestamp.After(last.Timestamp) {
+			last = &point
+		}
+	}
+
+	return last, nil
+}
+
+// defaultWindowRetention is how long novel merchant and card testing timelines are
+// kept until a rule needs a longer window
+const defaultWindowRetention = 24 * time.Hour
+
+// MerchantCache tracks which merchants a user has transacted with
+type MerchantCache struct {
+	client    *Client
+	retention atomic.Int64 // time.Duration of the novel merchant timeline; raised by EnsureRetention
+}
+
+// NewMerchantCache creates a new merchant cache
+func NewMerchantCache(client *Client) *MerchantCache {
+	c := &MerchantCache{client: client}
+	c.retention.Store(int64(defaultWindowRetention))
+	return c
+}
+
+// EnsureRetention keeps the novel merchant timeline long enough for window,
+// capped at MaxVelocityRetention; like VelocityCache.EnsureRetention it never lowers it
+func (c *MerchantCache) EnsureRetention(window time.Duration) {
+	raiseRetention(&c.retention, window)
+}
+
+// Retention returns how long the novel merchant timeline is kept
+func (c *MerchantCache) Retention() time.Duration {
+	return time.Duration(c.retention.Load())
+}
+
+// RecordMerchant records merchant usage for a user
+// The first time a merchant is seen it is also added to the user's novel merchant
+// timeline so bursts of never-before-seen merchants can be counted per window
+func (c *MerchantCache) RecordMerchant(ctx context.Context, userID uuid.UUID, merchantID string, timestamp time.Time) error {
+	knownKey := fmt.Sprintf("merchants:user:%s", userID.String())
+	novelKey := fmt.Sprintf("merchants:novel:user:%s", userID.String())
+
+	added, err := c.client.rdb.SAdd(ctx, knownKey, merchantID).Result()
+	if err != nil {
+		return fmt.Errorf("failed to record merchant: %w", err)
+	}
+
+	if err := c.client.Expire(ctx, knownKey, 90*24*time.Hour); err != nil {
+		return fmt.Errorf("failed to set expiration: %w", err)
+	}
+
+	// Already known - nothing novel to record
+	if added == 0 {
+		return nil
+	}
+
+	member := redis.Z{
+		Score:  float64(timestamp.Unix()),
+		Member: merchantID,
+	}
+	if err := c.client.ZAdd(ctx, novelKey, member); err != nil {
+		return fmt.Errorf("failed to record novel merchant: %w", err)
+	}
+
+	retention := c.Retention()
+	if err := c.client.Expire(ctx, novelKey, retention); err != nil {
+		return fmt.Errorf("failed to set expiration: %w", err)
+	}
+
+	// Clean up entries older than the retention
+	cutoff := time.Now().Add(-retention).Unix()
+	_ = c.client.ZRemRangeByScore(ctx, novelKey, "-inf", strconv.FormatInt(cutoff, 10))
+
+	return nil
+}
+
+// IsKnownMerchant checks if a merchant is known for a user
+func (c *MerchantCache) IsKnownMerchant(ctx context.Context, userID uuid.UUID, merchantID string) (bool, error) {
+	key := fmt.Sprintf("merchants:user:%s", userID.String())
+	return c.client.rdb.SIsMember(ctx, key, merchantID).Result()
+}
+
+// GetNewMerchantCount returns how many never-before-seen merchants a user
+// transacted with in a time window
+func (c *MerchantCache) GetNewMerchantCount(ctx context.Context, userID uuid.UUID, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("merchants:novel:user:%s", userID.String())
+
+	minTime := time.Now().Add(-window).Unix()
+	maxTime := time.Now().Unix()
+
+	count, err := c.client.ZCount(ctx, key, strconv.FormatInt(minTime, 10), strconv.FormatInt(maxTime, 10))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get new merchant count: %w", err)
+	}
+
+	return count, nil
+}
+
+// CardTestingCache tracks card attempts per BIN or per user and card network
+// so bursts of small authorizations across many cards can be counted
+type CardTestingCache struct {
+	client    *Client
+	retention atomic.Int64 // time.Duration; raised by EnsureRetention
+}
+
+// NewCardTestingCache creates a new card testing cache
+func NewCardTestingCache(client *Client) *CardTestingCache {
+	c := &CardTestingCache{client: client}
+	c.retention.Store(int64(defaultWindowRetention))
+	return c
+}
+
+// EnsureRetention keeps card attempts long enough for window, capped at MaxVelocityRetention
+// Like VelocityCache.EnsureRetention it never lowers the retention
+func (c *CardTestingCache) EnsureRetention(window time.Duration) {
+	raiseRetention(&c.retention, window)
+}
+
+// Retention returns how long card attempts are kept
+func (c *CardTestingCache) Retention() time.Duration {
+	return time.Duration(c.retention.Load())
+}
+
+// RecordAttempt records a card attempt for a scope (see rules.CardTestingScope)
+func (c *CardTestingCache) RecordAttempt(ctx context.Context, scope string, txID uuid.UUID, amount decimal.Decimal, timestamp time.Time) error {
+	key := fmt.Sprintf("cardtest:%s", scope)
+
+	member := redis.Z{
+		Score:  float64(timestamp.Unix()),
+		Member: velocityMember(txID, amount),
+	}
+
+	// A retried transaction is already recorded under its ID, so keep the original entry
+	if err := c.client.ZAddNX(ctx, key, member); err != nil {
+		return fmt.Errorf("failed to record card attempt: %w", err)
+	}
+
+	retention := c.Retention()
+	if err := c.client.Expire(ctx, key, retention); err != nil {
+		return fmt.Errorf("failed to set expiration: %w", err)
+	}
+
+	// Clean up entries older than the retention
+	cutoff := time.Now().Add(-retention).Unix()
+	_ = c.client.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(cutoff, 10))
+
+	return nil
+}
+
+// GetSmallAttemptCount returns the number of attempts at or below ceiling in a time window
+func (c *CardTestingCache) GetSmallAttemptCount(ctx context.Context, scope string, window time.Duration, ceiling decimal.Decimal) (int64, error) {
+	key := fmt.Sprintf("cardtest:%s", scope)
+
+	minTime := time.Now().Add(-window).Unix()
+	maxTime := time.Now().Unix()
+
+	members, err := c.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(minTime, 10),
+		Max: strconv.FormatInt(maxTime, 10),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get card attempts: %w", err)
+	}
+
+	var count int64
+	for _, member := range members {
+		_, amount, ok := parseVelocityMember(member)
+		if !ok {
+			continue
+		}
+		if amount.LessThanOrEqual(ceiling) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// linkRetention is how long a user stays linked to a device, IP address or card
+const linkRetention = 30 * 24 * time.Hour
+
+// LinkingCache tracks which users have used each device, IP address and card
+// Each attribute value keeps a sorted set of user IDs scored by when the user last
+// used it, so the distinct users sharing it within a window are a single count
+type LinkingCache struct {
+	client *Client
+}
+
+// NewLinkingCache creates a new linking cache
+func NewLinkingCache(client *Client) *LinkingCache {
+	return &LinkingCache{client: client}
+}
+
+// RecordLink records that a user used an attribute value (see rules.LinkedAttributes)
+func (c *LinkingCache) RecordLink(ctx context.Context, attribute, value string, userID uuid.UUID, timestamp time.Time) error {
+	key := fmt.Sprintf("links:%s:%s", attribute, value)
+
+	// Re-adding a user moves them to their latest use
+	member := redis.Z{
+		Score:  float64(timestamp.Unix()),
+		Member: userID.String(),
+	}
+	if err := c.client.ZAdd(ctx, key, member); err != nil {
+		return fmt.Errorf("failed to record link: %w", err)
+	}
+
+	if err := c.client.Expire(ctx, key, linkRetention); err != nil {
+		return fmt.Errorf("failed to set expiration: %w", err)
+	}
+
+	// Clean up users not seen within the retention
+	cutoff := time.Now().Add(-linkRetention).Unix()
+	_ = c.client.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(cutoff, 10))
+
+	return nil
+}
+
+// GetLinkedUserCount returns the number of distinct users that used an attribute value in a time window
+// userID is always counted, whether or not it has been recorded yet
+func (c *LinkingCache) GetLinkedUserCount(ctx context.Context, attribute, value string, userID uuid.UUID, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("links:%s:%s", attribute, value)
+	minTime := time.Now().Add(-window).Unix()
+
+	count, err := c.client.ZCount(ctx, key, strconv.FormatInt(minTime, 10), "+inf")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get linked users: %w", err)
+	}
+
+	lastSeen, err := c.client.rdb.ZScore(ctx, key, userID.String()).Result()
+	if err == redis.Nil || (err == nil && lastSeen < float64(minTime)) {
+		return count + 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get linked users: %w", err)
+	}
+	return count, nil
+}
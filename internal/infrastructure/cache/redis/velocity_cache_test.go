@@ -0,0 +1,139 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/infrastructure/cache/redis"
+	"fraud-detecction-system/internal/infrastructure/cache/redis/redistest"
+)
+
+func TestGetRecentTransactionsKeepsTimestamps(t *testing.T) {
+	cache := redis.NewVelocityCache(redistest.NewClient(t))
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now().Truncate(time.Second)
+
+	recorded := map[uuid.UUID]time.Time{}
+	for _, age := range []time.Duration{30 * time.Minute, 3 * time.Hour, 20 * time.Hour} {
+		txID := uuid.New()
+		recorded[txID] = now.Add(-age)
+		if err := cache.RecordTransaction(ctx, userID, txID, decimal.NewFromInt(25), now.Add(-age)); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		want   int
+	}{
+		{"last hour", time.Hour, 1},
+		{"last 6 hours", 6 * time.Hour, 2},
+		{"last day", 24 * time.Hour, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := cache.GetRecentTransactions(ctx, userID, tt.window)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if len(records) != tt.want {
+				t.Fatalf("%d records, want %d", len(records), tt.want)
+			}
+			for _, record := range records {
+				if want := recorded[record.TransactionID]; !record.Timestamp.Equal(want) {
+					t.Errorf("transaction %s timestamp %s, want %s", record.TransactionID, record.Timestamp, want)
+				}
+				if !record.Amount.Equal(decimal.NewFromInt(25)) {
+					t.Errorf("transaction %s amount %s, want 25", record.TransactionID, record.Amount)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordTransactionTwiceCountsOnce(t *testing.T) {
+	cache := redis.NewVelocityCache(redistest.NewClient(t))
+	ctx := context.Background()
+	userID, txID := uuid.New(), uuid.New()
+	first := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+
+	// A retry records the same transaction again, later
+	for _, at := range []time.Time{first, first.Add(5 * time.Minute)} {
+		if err := cache.RecordTransaction(ctx, userID, txID, decimal.NewFromInt(40), at); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	count, err := cache.GetTransactionCount(ctx, userID, time.Hour)
+	if err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("count %d, want 1", count)
+	}
+	sum, err := cache.GetTransactionSum(ctx, userID, time.Hour)
+	if err != nil {
+		t.Fatalf("sum: %v", err)
+	}
+	if !sum.Equal(decimal.NewFromInt(40)) {
+		t.Errorf("sum %s, want 40", sum)
+	}
+	records, err := cache.GetRecentTransactions(ctx, userID, time.Hour)
+	if err != nil {
+		t.Fatalf("recent: %v", err)
+	}
+	if len(records) != 1 || !records[0].Timestamp.Equal(first) {
+		t.Errorf("records %+v, want one at the first timestamp %s", records, first)
+	}
+}
+
+func TestGetTransactionSumSkipsMalformedMembers(t *testing.T) {
+	client := redistest.NewClient(t)
+	cache := redis.NewVelocityCache(client)
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now()
+
+	for _, amount := range []string{"12.34", "0.01", "1000.999"} {
+		if err := cache.RecordTransaction(ctx, userID, uuid.New(), decimal.RequireFromString(amount), now.Add(-time.Minute)); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	// Members no velocityMember call would write, e.g. left by an older format
+	key := "velocity:user:" + userID.String()
+	for _, member := range []string{
+		"no-separator",
+		"not-a-uuid|5",
+		uuid.NewString() + "|abc",
+		uuid.NewString() + "|",
+		"|7",
+	} {
+		if err := client.Redis().ZAdd(ctx, key, goredis.Z{Score: float64(now.Add(-time.Minute).Unix()), Member: member}).Err(); err != nil {
+			t.Fatalf("add %q: %v", member, err)
+		}
+	}
+
+	sum, err := cache.GetTransactionSum(ctx, userID, time.Hour)
+	if err != nil {
+		t.Fatalf("sum: %v", err)
+	}
+	if want := decimal.RequireFromString("1013.349"); !sum.Equal(want) {
+		t.Errorf("sum %s, want %s", sum, want)
+	}
+
+	records, err := cache.GetRecentTransactions(ctx, userID, time.Hour)
+	if err != nil {
+		t.Fatalf("recent: %v", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("%d records, want the 3 well-formed ones: %+v", len(records), records)
+	}
+}
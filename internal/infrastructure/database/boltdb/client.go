@@ -0,0 +1,125 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets, each keyed by the entity ID unless noted
+var (
+	decisionsBucket         = []byte("decisions")
+	decisionsByTxBucket     = []byte("decisions_by_transaction") // Transaction ID to decision ID
+	decisionFeedbackBucket  = []byte("decision_feedback")
+	decisionOverridesBucket = []byte("decision_overrides")
+	casesBucket             = []byte("cases")
+	rulesBucket             = []byte("rules")
+	ruleVersionsBucket      = []byte("rule_versions") // Rule ID followed by the big-endian version
+	allBuckets              = [][]byte{decisionsBucket, decisionsByTxBucket, decisionFeedbackBucket, decisionOverridesBucket, casesBucket, rulesBucket, ruleVersionsBucket}
+)
+
+// Client wraps an embedded bbolt database file
+// It backs decisions, cases and rules when PostgreSQL isn't available. Entities are
+// stored as JSON and queries scan their bucket, which suits small deployments.
+type Client struct {
+	db *bolt.DB
+}
+
+// Config holds embedded database configuration
+type Config struct {
+	Path        string
+	OpenTimeout time.Duration // How long to wait for another process to release the file
+}
+
+// NewClient opens the database file, creating it and its buckets if needed
+func NewClient(cfg Config) (*Client, error) {
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create embedded database directory: %w", err)
+	}
+
+	db, err := bolt.Open(cfg.Path, 0o600, &bolt.Options{Timeout: cfg.OpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range allBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create embedded database buckets: %w", err)
+	}
+
+	return &Client{db: db}, nil
+}
+
+// Ping reports whether the database is still open
+func (c *Client) Ping(ctx context.Context) error {
+	return c.db.View(func(tx *bolt.Tx) error { return nil })
+}
+
+// Close closes the database file
+func (c *Client) Close() error {
+	return c.db.Close()
+}
+
+// put stores v as JSON under id
+func put(b *bolt.Bucket, id uuid.UUID, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(id[:], data)
+}
+
+// get loads the JSON stored under id into v; false when there is none
+func get(b *bolt.Bucket, id uuid.UUID, v any) (bool, error) {
+	data := b.Get(id[:])
+	if data == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+// scan decodes every value in a bucket and returns those match accepts
+func scan[T any](b *bolt.Bucket, match func(*T) bool) ([]*T, error) {
+	var results []*T
+	err := b.ForEach(func(_, data []byte) error {
+		v := new(T)
+		if err := json.Unmarshal(data, v); err != nil {
+			return err
+		}
+		if match(v) {
+			results = append(results, v)
+		}
+		return nil
+	})
+	return results, err
+}
+
+// page returns the [offset, offset+limit) slice of items
+func page[T any](items []*T, limit, offset int) []*T {
+	if offset >= len(items) {
+		return []*T{}
+	}
+	return items[offset:min(offset+limit, len(items))]
+}
+
+// versionKey orders a rule's versions together, oldest first
+func versionKey(ruleID uuid.UUID, version int) []byte {
+	key := make([]byte, 0, len(ruleID)+8)
+	key = append(key, ruleID[:]...)
+	return binary.BigEndian.AppendUint64(key, uint64(version))
+}
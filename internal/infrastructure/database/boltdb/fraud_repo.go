@@ -0,0 +1,569 @@
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	bolt "go.etcd.io/bbolt"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// DecisionRepository implements fraud.DecisionRepository on the embedded database
+type DecisionRepository struct {
+	db *bolt.DB
+}
+
+var _ fraud.DecisionRepository = (*DecisionRepository)(nil)
+
+// NewDecisionRepository creates a new decision repository
+func NewDecisionRepository(client *Client) *DecisionRepository {
+	return &DecisionRepository{db: client.db}
+}
+
+// Create stores a fraud decision
+// Returns ErrDuplicateDecision if the transaction already has one
+func (r *DecisionRepository) Create(ctx context.Context, decision *fraud.FraudDecision) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		byTx := tx.Bucket(decisionsByTxBucket)
+		if byTx.Get(decision.TransactionID[:]) != nil {
+			return fraud.ErrDuplicateDecision
+		}
+		if err := byTx.Put(decision.TransactionID[:], decision.ID[:]); err != nil {
+			return err
+		}
+		return put(tx.Bucket(decisionsBucket), decision.ID, decision)
+	})
+}
+
+// GetByID retrieves a decision by ID
+func (r *DecisionRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.FraudDecision, error) {
+	var decision fraud.FraudDecision
+	err := r.db.View(func(tx *bolt.Tx) error {
+		found, err := get(tx.Bucket(decisionsBucket), id, &decision)
+		if err == nil && !found {
+			return fraud.ErrDecisionNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+// GetByTransactionID retrieves decision for a transaction
+func (r *DecisionRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*fraud.FraudDecision, error) {
+	decisions, err := r.GetByTransactionIDs(ctx, []uuid.UUID{transactionID})
+	if err != nil {
+		return nil, err
+	}
+	if decision, ok := decisions[transactionID]; ok {
+		return decision, nil
+	}
+	return nil, fraud.ErrDecisionNotFound
+}
+
+// GetByTransactionIDs retrieves the decisions for several transactions through the transaction index
+func (r *DecisionRepository) GetByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) (map[uuid.UUID]*fraud.FraudDecision, error) {
+	decisions := make(map[uuid.UUID]*fraud.FraudDecision, len(transactionIDs))
+	err := r.db.View(func(tx *bolt.Tx) error {
+		byTx := tx.Bucket(decisionsByTxBucket)
+		bucket := tx.Bucket(decisionsBucket)
+		for _, transactionID := range transactionIDs {
+			id := byTx.Get(transactionID[:])
+			if id == nil {
+				continue
+			}
+			var decision fraud.FraudDecision
+			found, err := get(bucket, uuid.UUID(id), &decision)
+			if err != nil {
+				return err
+			}
+			if found {
+				decisions[transactionID] = &decision
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decisions, nil
+}
+
+// ListByUserID gets fraud decisions for a user, newest first
+func (r *DecisionRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*fraud.FraudDecision, error) {
+	decisions, err := r.scan(func(d *fraud.FraudDecision) bool { return d.UserID == userID })
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(decisions, func(a, b *fraud.FraudDecision) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return page(decisions, fraud.DecisionListLimit(limit), max(offset, 0)), nil
+}
+
+// CountByUserID counts all fraud decisions for a user
+func (r *DecisionRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	decisions, err := r.scan(func(d *fraud.FraudDecision) bool { return d.UserID == userID })
+	return int64(len(decisions)), err
+}
+
+// GetBlockedCount counts how many times a user has been blocked since a time, by effective decision
+func (r *DecisionRepository) GetBlockedCount(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	decisions, err := r.scan(func(d *fraud.FraudDecision) bool {
+		return d.UserID == userID && d.EffectiveDecision() == fraud.DecisionBlock && d.CreatedAt.After(since)
+	})
+	return int64(len(decisions)), err
+}
+
+// ListBlockTimes gets when each of a user's blocks since a time was made, newest first
+func (r *DecisionRepository) ListBlockTimes(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	decisions, err := r.scan(func(d *fraud.FraudDecision) bool {
+		return d.UserID == userID && d.EffectiveDecision() == fraud.DecisionBlock && d.CreatedAt.After(since)
+	})
+	if err != nil {
+		return nil, err
+	}
+	times := make([]time.Time, len(decisions))
+	for i, d := range decisions {
+		times[i] = d.CreatedAt
+	}
+	slices.SortFunc(times, func(a, b time.Time) int { return b.Compare(a) })
+	return times, nil
+}
+
+// RecordFeedback stores an analyst's verdict on a decision
+func (r *DecisionRepository) RecordFeedback(ctx context.Context, feedback *fraud.DecisionFeedback) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return put(tx.Bucket(decisionFeedbackBucket), feedback.ID, feedback)
+	})
+}
+
+// ListFeedback gets feedback on decisions made within [from, to), oldest feedback first
+func (r *DecisionRepository) ListFeedback(ctx context.Context, from, to time.Time) ([]*fraud.DecisionFeedback, error) {
+	var feedback []*fraud.DecisionFeedback
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		feedback, err = scan(tx.Bucket(decisionFeedbackBucket), func(f *fraud.DecisionFeedback) bool {
+			return !f.DecidedAt.Before(from) && f.DecidedAt.Before(to)
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(feedback, func(a, b *fraud.DecisionFeedback) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return feedback, nil
+}
+
+// RecordOverride stores an analyst's override and makes it the decision's effective outcome
+// bbolt runs one write transaction at a time, so the check and the write can't interleave
+func (r *DecisionRepository) RecordOverride(ctx context.Context, override *fraud.DecisionOverride) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		decisions := tx.Bucket(decisionsBucket)
+		var decision fraud.FraudDecision
+		found, err := get(decisions, override.DecisionID, &decision)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fraud.ErrDecisionNotFound
+		}
+		if decision.EffectiveDecision() != override.PreviousDecision {
+			return fraud.ErrOverrideConflict
+		}
+
+		decision.OverriddenDecision = override.Decision
+		if err := put(decisions, decision.ID, &decision); err != nil {
+			return err
+		}
+		return put(tx.Bucket(decisionOverridesBucket), override.ID, override)
+	})
+}
+
+// MarkOverrideApplied records that an override's transaction was updated to match it
+func (r *DecisionRepository) MarkOverrideApplied(ctx context.Context, overrideID uuid.UUID) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(decisionOverridesBucket)
+		var override fraud.DecisionOverride
+		found, err := get(bucket, overrideID, &override)
+		if err != nil || !found {
+			return err
+		}
+		override.TransactionUpdated = true
+		return put(bucket, override.ID, &override)
+	})
+}
+
+// ListOverrides gets a decision's overrides, oldest first
+func (r *DecisionRepository) ListOverrides(ctx context.Context, decisionID uuid.UUID) ([]*fraud.DecisionOverride, error) {
+	var overrides []*fraud.DecisionOverride
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		overrides, err = scan(tx.Bucket(decisionOverridesBucket), func(o *fraud.DecisionOverride) bool {
+			return o.DecisionID == decisionID
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(overrides, func(a, b *fraud.DecisionOverride) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return overrides, nil
+}
+
+// ScoreHistogram counts the decisions made within [from, to) at each score, lowest score first
+func (r *DecisionRepository) ScoreHistogram(ctx context.Context, from, to time.Time) ([]fraud.ScoreCount, error) {
+	decisions, err := r.scan(func(d *fraud.FraudDecision) bool {
+		return !d.ProcessedAt.Before(from) && d.ProcessedAt.Before(to)
+	})
+	if err != nil {
+		return nil, err
+	}
+	scores := make([]decimal.Decimal, len(decisions))
+	for i, d := range decisions {
+		scores[i] = d.Score
+	}
+	return fraud.NewScoreHistogram(scores), nil
+}
+
+func (r *DecisionRepository) scan(match func(*fraud.FraudDecision) bool) ([]*fraud.FraudDecision, error) {
+	var decisions []*fraud.FraudDecision
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		decisions, err = scan(tx.Bucket(decisionsBucket), match)
+		return err
+	})
+	return decisions, err
+}
+
+// CaseRepository implements fraud.CaseRepository on the embedded database
+type CaseRepository struct {
+	db *bolt.DB
+}
+
+var _ fraud.CaseRepository = (*CaseRepository)(nil)
+
+// NewCaseRepository creates a new case repository
+func NewCaseRepository(client *Client) *CaseRepository {
+	return &CaseRepository{db: client.db}
+}
+
+// Create stores a new fraud case
+func (r *CaseRepository) Create(ctx context.Context, fraudCase *fraud.FraudCase) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		return put(tx.Bucket(casesBucket), fraudCase.ID, fraudCase)
+	})
+}
+
+// GetByID retrieves a case by ID
+func (r *CaseRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.FraudCase, error) {
+	var fraudCase fraud.FraudCase
+	err := r.db.View(func(tx *bolt.Tx) error {
+		found, err := get(tx.Bucket(casesBucket), id, &fraudCase)
+		if err == nil && !found {
+			return fraud.ErrCaseNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &fraudCase, nil
+}
+
+// Update updates an existing case
+func (r *CaseRepository) Update(ctx context.Context, fraudCase *fraud.FraudCase) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(casesBucket)
+		if bucket.Get(fraudCase.ID[:]) == nil {
+			return fraud.ErrCaseNotFound
+		}
+		return put(bucket, fraudCase.ID, fraudCase)
+	})
+}
+
+// ListByStatus retrieves cases by status, newest first
+func (r *CaseRepository) ListByStatus(ctx context.Context, status fraud.CaseStatus, limit, offset int) ([]*fraud.FraudCase, error) {
+	cases, err := r.scan(func(c *fraud.FraudCase) bool { return c.Status == status })
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(cases, newestCaseFirst)
+	return page(cases, limit, offset), nil
+}
+
+// CountByStatus counts cases in a status
+func (r *CaseRepository) CountByStatus(ctx context.Context, status fraud.CaseStatus) (int64, error) {
+	cases, err := r.scan(func(c *fraud.FraudCase) bool { return c.Status == status })
+	return int64(len(cases)), err
+}
+
+// ListByAssignee retrieves cases assigned to an investigator, newest first
+func (r *CaseRepository) ListByAssignee(ctx context.Context, assigneeID uuid.UUID, limit, offset int) ([]*fraud.FraudCase, error) {
+	cases, err := r.scan(func(c *fraud.FraudCase) bool {
+		return c.AssignedTo != nil && *c.AssignedTo == assigneeID
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(cases, newestCaseFirst)
+	return page(cases, limit, offset), nil
+}
+
+// GetOpenCasesByUser retrieves a user's open or investigating cases
+func (r *CaseRepository) GetOpenCasesByUser(ctx context.Context, userID uuid.UUID) ([]*fraud.FraudCase, error) {
+	return r.scan(func(c *fraud.FraudCase) bool { return c.UserID == userID && c.IsOpen() })
+}
+
+// ListUnassigned retrieves open, unassigned cases in CompareCaseQueue order
+func (r *CaseRepository) ListUnassigned(ctx context.Context, limit, offset int) ([]*fraud.FraudCase, error) {
+	cases, err := r.scan((*fraud.FraudCase).IsQueued)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(cases, fraud.CompareCaseQueue)
+	return page(cases, limit, offset), nil
+}
+
+// CountUnassigned counts open, unassigned cases
+func (r *CaseRepository) CountUnassigned(ctx context.Context) (int64, error) {
+	cases, err := r.scan((*fraud.FraudCase).IsQueued)
+	return int64(len(cases)), err
+}
+
+// ClaimNext assigns the first unassigned case to an investigator
+// The read and the write share one write transaction, which bbolt runs one at a
+// time, so two investigators can't claim the same case.
+func (r *CaseRepository) ClaimNext(ctx context.Context, investigatorID uuid.UUID) (*fraud.FraudCase, error) {
+	var claimed *fraud.FraudCase
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(casesBucket)
+		queue, err := scan(bucket, (*fraud.FraudCase).IsQueued)
+		if err != nil {
+			return err
+		}
+		if len(queue) == 0 {
+			return fraud.ErrCaseQueueEmpty
+		}
+		claimed = slices.MinFunc(queue, fraud.CompareCaseQueue)
+		if err := claimed.Assign(investigatorID); err != nil {
+			return err
+		}
+		return put(bucket, claimed.ID, claimed)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// ListOpenCreatedBefore retrieves open or investigating cases of a risk level created before a time, oldest first
+func (r *CaseRepository) ListOpenCreatedBefore(ctx context.Context, riskLevel fraud.RiskLevel, before time.Time, limit int) ([]*fraud.FraudCase, error) {
+	cases, err := r.scan(func(c *fraud.FraudCase) bool {
+		return c.RiskLevel == riskLevel && c.IsOpen() && c.CreatedAt.Before(before)
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(cases, func(a, b *fraud.FraudCase) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return page(cases, limit, 0), nil
+}
+
+func (r *CaseRepository) scan(match func(*fraud.FraudCase) bool) ([]*fraud.FraudCase, error) {
+	var cases []*fraud.FraudCase
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		cases, err = scan(tx.Bucket(casesBucket), match)
+		return err
+	})
+	return cases, err
+}
+
+func newestCaseFirst(a, b *fraud.FraudCase) int {
+	return b.CreatedAt.Compare(a.CreatedAt)
+}
+
+// RuleRepository implements fraud.RuleRepository on the embedded database
+type RuleRepository struct {
+	db *bolt.DB
+}
+
+var _ fraud.RuleRepository = (*RuleRepository)(nil)
+
+// NewRuleRepository creates a new rule repository
+func NewRuleRepository(client *Client) *RuleRepository {
+	return &RuleRepository{db: client.db}
+}
+
+// Empty reports whether no rules have been stored yet, so defaults can be seeded
+func (r *RuleRepository) Empty(ctx context.Context) (bool, error) {
+	empty := true
+	err := r.db.View(func(tx *bolt.Tx) error {
+		key, _ := tx.Bucket(rulesBucket).Cursor().First()
+		empty = key == nil
+		return nil
+	})
+	return empty, err
+}
+
+// Create adds a new rule and records its first version
+func (r *RuleRepository) Create(ctx context.Context, rule *fraud.Rule) error {
+	return r.CreateBatch(ctx, []*fraud.Rule{rule})
+}
+
+// CreateBatch adds multiple rules in a single transaction
+func (r *RuleRepository) CreateBatch(ctx context.Context, rules []*fraud.Rule) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(rulesBucket)
+		for _, rule := range rules {
+			if bucket.Get(rule.ID[:]) != nil {
+				return fmt.Errorf("rule %s already exists", rule.ID)
+			}
+			if err := put(bucket, rule.ID, rule); err != nil {
+				return err
+			}
+			if err := recordVersion(tx, rule); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a rule by ID
+func (r *RuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.Rule, error) {
+	var rule fraud.Rule
+	err := r.db.View(func(tx *bolt.Tx) error {
+		found, err := get(tx.Bucket(rulesBucket), id, &rule)
+		if err == nil && !found {
+			return fraud.ErrRuleNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Update updates an existing rule and appends its new version to the history
+// A version that was already recorded is rejected rather than overwritten
+func (r *RuleRepository) Update(ctx context.Context, rule *fraud.Rule) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(rulesBucket)
+		if bucket.Get(rule.ID[:]) == nil {
+			return fraud.ErrRuleNotFound
+		}
+		if err := put(bucket, rule.ID, rule); err != nil {
+			return err
+		}
+		return recordVersion(tx, rule)
+	})
+}
+
+// ListActive retrieves enabled rules that are in effect now
+func (r *RuleRepository) ListActive(ctx context.Context) ([]*fraud.Rule, error) {
+	return r.scan((*fraud.Rule).IsActive)
+}
+
+// ListByType retrieves enabled rules of a specific type
+func (r *RuleRepository) ListByType(ctx context.Context, ruleType fraud.RuleType) ([]*fraud.Rule, error) {
+	return r.scan(func(rule *fraud.Rule) bool { return rule.Type == ruleType && rule.Enabled })
+}
+
+// Disable disables a rule
+func (r *RuleRepository) Disable(ctx context.Context, ruleID uuid.UUID) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(rulesBucket)
+		var rule fraud.Rule
+		found, err := get(bucket, ruleID, &rule)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fraud.ErrRuleNotFound
+		}
+		rule.Enabled = false
+		rule.UpdatedAt = time.Now()
+		return put(bucket, rule.ID, &rule)
+	})
+}
+
+// GetVersion retrieves a specific version of a rule from its history
+func (r *RuleRepository) GetVersion(ctx context.Context, ruleID uuid.UUID, version int) (*fraud.Rule, error) {
+	var ruleVersion fraud.RuleVersion
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(ruleVersionsBucket).Get(versionKey(ruleID, version))
+		if data == nil {
+			return fraud.ErrRuleNotFound
+		}
+		return json.Unmarshal(data, &ruleVersion)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ruleVersion.Rule, nil
+}
+
+// ListVersions retrieves every recorded version of a rule, oldest first
+func (r *RuleRepository) ListVersions(ctx context.Context, ruleID uuid.UUID) ([]*fraud.RuleVersion, error) {
+	versions := make([]*fraud.RuleVersion, 0)
+	err := r.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(ruleVersionsBucket).Cursor()
+		for key, data := cursor.Seek(ruleID[:]); key != nil && bytes.HasPrefix(key, ruleID[:]); key, data = cursor.Next() {
+			var version fraud.RuleVersion
+			if err := json.Unmarshal(data, &version); err != nil {
+				return err
+			}
+			versions = append(versions, &version)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *RuleRepository) scan(match func(*fraud.Rule) bool) ([]*fraud.Rule, error) {
+	var rules []*fraud.Rule
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		rules, err = scan(tx.Bucket(rulesBucket), match)
+		return err
+	})
+	return rules, err
+}
+
+// recordVersion appends a snapshot of the rule to its history
+func recordVersion(tx *bolt.Tx, rule *fraud.Rule) error {
+	bucket := tx.Bucket(ruleVersionsBucket)
+	key := versionKey(rule.ID, rule.Version)
+	if bucket.Get(key) != nil {
+		return fmt.Errorf("rule %s version %d already recorded", rule.ID, rule.Version)
+	}
+	data, err := json.Marshal(&fraud.RuleVersion{
+		Rule:      rule,
+		ChangedBy: rule.UpdatedBy,
+		ChangedAt: rule.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
@@ -0,0 +1,57 @@
+package boltdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// newTestClient opens a database in a temporary directory
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	client, err := NewClient(Config{Path: filepath.Join(t.TempDir(), "fraud.db"), OpenTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestDecisionRepositoryListByUserIDLimit(t *testing.T) {
+	repo := NewDecisionRepository(newTestClient(t))
+	userID := uuid.New()
+	for i := 0; i < fraud.DefaultDecisionListLimit+10; i++ {
+		d := fraud.NewFraudDecision(uuid.New(), userID, fraud.DecisionAllow, decimal.NewFromFloat(0.1))
+		if err := repo.Create(context.Background(), d); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"zero limit uses the default", 0, fraud.DefaultDecisionListLimit},
+		{"negative limit uses the default", -1, fraud.DefaultDecisionListLimit},
+		{"explicit limit", 10, 10},
+		{"limit past the end", 100, fraud.DefaultDecisionListLimit + 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisions, err := repo.ListByUserID(context.Background(), userID, tt.limit, 0)
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(decisions) != tt.want {
+				t.Errorf("%d decisions, want %d", len(decisions), tt.want)
+			}
+		})
+	}
+}
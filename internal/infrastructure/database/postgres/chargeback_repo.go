@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// ChargebackModel is the database model for chargebacks
+type ChargebackModel struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	UserID        uuid.UUID       `gorm:"type:uuid;not null;index:idx_fraud_chargebacks_user_occurred,priority:1"`
+	TransactionID *uuid.UUID      `gorm:"type:uuid"`
+	Amount        decimal.Decimal `gorm:"type:decimal(15,2);not null"`
+	Currency      string          `gorm:"type:varchar(3)"`
+	ReasonCode    string          `gorm:"type:varchar(20)"`
+	OccurredAt    time.Time       `gorm:"not null;index:idx_fraud_chargebacks_user_occurred,priority:2"`
+	RecordedBy    uuid.UUID       `gorm:"type:uuid;not null"`
+	CreatedAt     time.Time       `gorm:"not null"`
+}
+
+// TableName returns the table name for chargebacks
+func (ChargebackModel) TableName() string {
+	return "fraud_chargebacks"
+}
+
+// ChargebackRepository implements fraud.ChargebackRepository
+type ChargebackRepository struct {
+	db *gorm.DB
+}
+
+var _ fraud.ChargebackRepository = (*ChargebackRepository)(nil)
+
+// NewChargebackRepository creates a new chargeback repository
+func NewChargebackRepository(client *Client) *ChargebackRepository {
+	return &ChargebackRepository{db: client.DB()}
+}
+
+// Create stores a chargeback
+func (r *ChargebackRepository) Create(ctx context.Context, chargeback *fraud.Chargeback) error {
+	model := &ChargebackModel{
+		ID:         chargeback.ID,
+		UserID:     chargeback.UserID,
+		Amount:     chargeback.Amount,
+		Currency:   chargeback.Currency,
+		ReasonCode: chargeback.ReasonCode,
+		OccurredAt: chargeback.OccurredAt,
+		RecordedBy: chargeback.RecordedBy,
+		CreatedAt:  chargeback.CreatedAt,
+	}
+	if chargeback.TransactionID != uuid.Nil {
+		model.TransactionID = &chargeback.TransactionID
+	}
+
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+// CountByUserID counts a user's chargebacks that occurred at or after since
+func (r *ChargebackRepository) CountByUserID(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&ChargebackModel{}).
+		Where("user_id = ? AND occurred_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
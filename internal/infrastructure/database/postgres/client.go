@@ -3,11 +3,15 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+
+	applog "fraud-detecction-system/internal/pkg/logger"
+	"fraud-detecction-system/internal/pkg/retry"
 )
 
 // Client wraps the GORM database connection
@@ -26,17 +30,23 @@ type Config struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+
+	// Connect retries the first connection, so a database that starts after the API is still used
+	Connect retry.Backoff
+	Logger  *slog.Logger // Logs each failed attempt; defaults to slog.Default()
 }
 
-// NewClient creates a new PostgreSQL client
-func NewClient(cfg Config) (*Client, error) {
+// NewClient creates a new PostgreSQL client, retrying until the database answers a ping
+func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 	dsn := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode,
 	)
 
+	// Connecting is left to the ping below, which can be retried and canceled
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Warn),
+		Logger:               logger.Default.LogMode(logger.Warn),
+		DisableAutomaticPing: true,
 	}
 
 	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
@@ -54,6 +64,23 @@ func NewClient(cfg Config) (*Client, error) {
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
+	log := cfg.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+	err = cfg.Connect.Do(ctx, sqlDB.PingContext, func(attempt int, wait time.Duration, err error) {
+		log.Warn("database not ready, retrying",
+			slog.Int("attempt", attempt),
+			slog.Int("max_attempts", cfg.Connect.Attempts),
+			slog.Duration("retry_in", wait),
+			applog.Err(err),
+		)
+	})
+	if err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
 	return &Client{db: db}, nil
 }
 
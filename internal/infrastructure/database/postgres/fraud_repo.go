@@ -3,11 +3,14 @@ package postgres
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"fraud-detecction-system/internal/domain/fraud"
 )
@@ -15,19 +18,37 @@ import (
 // FraudDecisionModel is the database model for fraud decisions
 type FraudDecisionModel struct {
 	ID            uuid.UUID       `gorm:"type:uuid;primaryKey"`
-	TransactionID uuid.UUID       `gorm:"type:uuid;index;not null"`
-	UserID        uuid.UUID       `gorm:"type:uuid;index;not null"`
-	Decision      string          `gorm:"type:varchar(20);not null"`
+	TransactionID uuid.UUID       `gorm:"type:uuid;uniqueIndex;not null"`
+	UserID        uuid.UUID       `gorm:"type:uuid;index;index:idx_fraud_decisions_user_created,priority:1;index:idx_fraud_decisions_user_decision_created,priority:1;not null"`
+	Decision      string          `gorm:"type:varchar(20);index:idx_fraud_decisions_user_decision_created,priority:2;not null"`
 	Score         decimal.Decimal `gorm:"type:decimal(5,4);not null"`
 	RiskLevel     string          `gorm:"type:varchar(20);not null"`
 	Confidence    decimal.Decimal `gorm:"type:decimal(5,4)"`
 	RulesFired    string          `gorm:"type:jsonb"`
 	Reasons       string          `gorm:"type:jsonb"`
 	ModelVersion  string          `gorm:"type:varchar(50)"`
+	Contributions string          `gorm:"type:jsonb"`
 	ProcessedAt   time.Time       `gorm:"not null"`
 	LatencyMs     int64           `gorm:"not null"`
-	CreatedAt     time.Time       `gorm:"not null"`
+	CreatedAt     time.Time       `gorm:"not null;index:idx_fraud_decisions_user_created,priority:2;index:idx_fraud_decisions_user_decision_created,priority:3"`
 	UpdatedAt     time.Time       `gorm:"not null"`
+
+	MissingContextCount int    `gorm:"not null;default:0"`
+	SkippedRules        string `gorm:"type:jsonb"`
+	ShadowRulesFired    string `gorm:"type:jsonb"`
+	ConfidenceFactors   string `gorm:"type:jsonb"`
+	DowngradedFrom      string `gorm:"type:varchar(20)"`
+	FeatureVector       string `gorm:"type:jsonb"`
+	BreakdownOmitted    bool   `gorm:"not null;default:false"`
+	Degraded            bool   `gorm:"not null;default:false"`
+	DegradedReason      string `gorm:"type:text"`
+
+	BaseAmount   *decimal.Decimal `gorm:"type:decimal(15,2)"`
+	BaseCurrency string           `gorm:"type:varchar(3)"`
+
+	DataCoverage *decimal.Decimal `gorm:"type:decimal(5,4)"`
+
+	OverriddenDecision *string `gorm:"type:varchar(20)"` // NULL until the decision is overridden
 }
 
 // TableName returns the table name for fraud decisions
@@ -35,6 +56,41 @@ func (FraudDecisionModel) TableName() string {
 	return "fraud_decisions"
 }
 
+// DecisionFeedbackModel is the database model for analyst feedback on decisions
+type DecisionFeedbackModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DecisionID uuid.UUID `gorm:"type:uuid;index;not null"`
+	Label      string    `gorm:"type:varchar(10);not null"`
+	Note       string    `gorm:"type:text"`
+	Decision   string    `gorm:"type:varchar(20);not null"`
+	DecidedAt  time.Time `gorm:"index;not null"`
+	CreatedBy  uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+// TableName returns the table name for decision feedback
+func (DecisionFeedbackModel) TableName() string {
+	return "decision_feedback"
+}
+
+// DecisionOverrideModel is the database model for analyst overrides of decisions
+type DecisionOverrideModel struct {
+	ID                 uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DecisionID         uuid.UUID `gorm:"type:uuid;index;not null"`
+	TransactionID      uuid.UUID `gorm:"type:uuid;not null"`
+	PreviousDecision   string    `gorm:"type:varchar(20);not null"`
+	Decision           string    `gorm:"type:varchar(20);not null"`
+	Reason             string    `gorm:"type:text;not null"`
+	TransactionUpdated bool      `gorm:"not null;default:false"`
+	OverriddenBy       uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt          time.Time `gorm:"not null"`
+}
+
+// TableName returns the table name for decision overrides
+func (DecisionOverrideModel) TableName() string {
+	return "decision_overrides"
+}
+
 // FraudCaseModel is the database model for fraud cases
 type FraudCaseModel struct {
 	ID             uuid.UUID        `gorm:"type:uuid;primaryKey"`
@@ -50,6 +106,7 @@ type FraudCaseModel struct {
 	Notes          string           `gorm:"type:jsonb"`
 	Evidence       string           `gorm:"type:jsonb"`
 	Resolution     string           `gorm:"type:text"`
+	Outcome        string           `gorm:"type:varchar(10)"`
 	ResolvedBy     *uuid.UUID       `gorm:"type:uuid"`
 	ResolvedAt     *time.Time
 	CreatedAt      time.Time        `gorm:"not null"`
@@ -69,10 +126,14 @@ type RuleModel struct {
 	Type        string     `gorm:"type:varchar(20);index;not null"`
 	Severity    string     `gorm:"type:varchar(20);not null"`
 	Action      string     `gorm:"type:varchar(20);not null"`
+	FailMode    string     `gorm:"type:varchar(10);not null;default:open"`
+	Mode        string     `gorm:"type:varchar(10);not null;default:active"`
+	Priority    int        `gorm:"not null;default:0"`
 	Config      string     `gorm:"type:jsonb;not null"`
 	Enabled     bool       `gorm:"index;not null"`
 	Version     int        `gorm:"not null"`
 	CreatedBy   uuid.UUID  `gorm:"type:uuid;not null"`
+	UpdatedBy   uuid.UUID  `gorm:"type:uuid;not null"`
 	CreatedAt   time.Time  `gorm:"not null"`
 	UpdatedAt   time.Time  `gorm:"not null"`
 	EffectiveAt time.Time  `gorm:"not null"`
@@ -84,11 +145,41 @@ func (RuleModel) TableName() string {
 	return "fraud_rules"
 }
 
+// RuleVersionModel is the database model for rule version history
+// Rows are keyed by (rule_id, version) and only ever inserted
+type RuleVersionModel struct {
+	RuleID      uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Version     int        `gorm:"primaryKey"`
+	Name        string     `gorm:"type:varchar(100);not null"`
+	Description string     `gorm:"type:text"`
+	Type        string     `gorm:"type:varchar(20);not null"`
+	Severity    string     `gorm:"type:varchar(20);not null"`
+	Action      string     `gorm:"type:varchar(20);not null"`
+	FailMode    string     `gorm:"type:varchar(10);not null;default:open"`
+	Mode        string     `gorm:"type:varchar(10);not null;default:active"`
+	Priority    int        `gorm:"not null;default:0"`
+	Config      string     `gorm:"type:jsonb;not null"`
+	Enabled     bool       `gorm:"not null"`
+	CreatedBy   uuid.UUID  `gorm:"type:uuid;not null"`
+	CreatedAt   time.Time  `gorm:"not null"`
+	EffectiveAt time.Time  `gorm:"not null"`
+	ExpiresAt   *time.Time
+	ChangedBy   uuid.UUID  `gorm:"type:uuid;not null"`
+	ChangedAt   time.Time  `gorm:"not null"`
+}
+
+// TableName returns the table name for rule versions
+func (RuleVersionModel) TableName() string {
+	return "fraud_rule_versions"
+}
+
 // DecisionRepository implements fraud.DecisionRepository
 type DecisionRepository struct {
 	db *gorm.DB
 }
 
+var _ fraud.DecisionRepository = (*DecisionRepository)(nil)
+
 // NewDecisionRepository creates a new decision repository
 func NewDecisionRepository(client *Client) *DecisionRepository {
 	return &DecisionRepository{db: client.DB()}
@@ -98,6 +189,14 @@ func NewDecisionRepository(client *Client) *DecisionRepository {
 func (r *DecisionRepository) Create(ctx context.Context, decision *fraud.FraudDecision) error {
 	rulesFired, _ := json.Marshal(decision.RulesFired)
 	reasons, _ := json.Marshal(decision.Reasons)
+	contributions, _ := json.Marshal(decision.Contributions)
+	skippedRules, _ := json.Marshal(decision.SkippedRules)
+	shadowRulesFired, _ := json.Marshal(decision.ShadowRulesFired)
+	featureVector, _ := json.Marshal(decision.FeatureVector)
+	var confidenceFactors []byte
+	if decision.ConfidenceFactors != nil {
+		confidenceFactors, _ = json.Marshal(decision.ConfidenceFactors)
+	}
 
 	model := &FraudDecisionModel{
 		ID:            decision.ID,
@@ -110,13 +209,41 @@ func (r *DecisionRepository) Create(ctx context.Context, decision *fraud.FraudDe
 		RulesFired:    string(rulesFired),
 		Reasons:       string(reasons),
 		ModelVersion:  decision.ModelVersion,
+		Contributions: string(contributions),
 		ProcessedAt:   decision.ProcessedAt,
 		LatencyMs:     decision.LatencyMs,
 		CreatedAt:     decision.CreatedAt,
 		UpdatedAt:     decision.UpdatedAt,
+
+		MissingContextCount: decision.MissingContextCount,
+		SkippedRules:        string(skippedRules),
+		ShadowRulesFired:    string(shadowRulesFired),
+		ConfidenceFactors:   string(confidenceFactors),
+		DowngradedFrom:      string(decision.DowngradedFrom),
+		FeatureVector:       string(featureVector),
+		BreakdownOmitted:    decision.BreakdownOmitted,
+		Degraded:            decision.Degraded,
+		DegradedReason:      decision.DegradedReason,
+
+		BaseAmount:   decision.BaseAmount,
+		BaseCurrency: decision.BaseCurrency,
+
+		DataCoverage: decision.DataCoverage,
 	}
 
-	return r.db.WithContext(ctx).Create(model).Error
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fraud.ErrDuplicateDecision
+		}
+		return err
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
 }
 
 // GetByID retrieves a decision by ID
@@ -143,13 +270,38 @@ func (r *DecisionRepository) GetByTransactionID(ctx context.Context, transaction
 	return modelToDecision(&model), nil
 }
 
+// decisionLookupChunk bounds the transaction IDs sent in one IN query
+const decisionLookupChunk = 500
+
+// GetByTransactionIDs retrieves the decisions for several transactions
+// One query is made per decisionLookupChunk IDs
+func (r *DecisionRepository) GetByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) (map[uuid.UUID]*fraud.FraudDecision, error) {
+	decisions := make(map[uuid.UUID]*fraud.FraudDecision, len(transactionIDs))
+	for start := 0; start < len(transactionIDs); start += decisionLookupChunk {
+		end := min(start+decisionLookupChunk, len(transactionIDs))
+
+		var models []FraudDecisionModel
+		if err := r.db.WithContext(ctx).
+			Where("transaction_id IN ?", transactionIDs[start:end]).
+			Find(&models).Error; err != nil {
+			return nil, err
+		}
+
+		for i := range models {
+			decision := modelToDecision(&models[i])
+			decisions[decision.TransactionID] = decision
+		}
+	}
+	return decisions, nil
+}
+
 // ListByUserID gets fraud decisions for a user
 func (r *DecisionRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*fraud.FraudDecision, error) {
 	var models []FraudDecisionModel
 	if err := r.db.WithContext(ctx).
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
-		Limit(limit).
+		Limit(fraud.DecisionListLimit(limit)).
 		Offset(offset).
 		Find(&models).Error; err != nil {
 		return nil, err
@@ -162,22 +314,195 @@ func (r *DecisionRepository) ListByUserID(ctx context.Context, userID uuid.UUID,
 	return decisions, nil
 }
 
-// GetBlockedCount counts how many times a user has been blocked
+// CountByUserID counts all fraud decisions for a user
+func (r *DecisionRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&FraudDecisionModel{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// effectivelyBlocked matches decisions whose effective outcome is block
+const effectivelyBlocked = "COALESCE(overridden_decision, decision) = 'block'"
+
+// GetBlockedCount counts how many times a user has been blocked, by effective decision
+// idx_fraud_decisions_user_created narrows the count to the user and window
 func (r *DecisionRepository) GetBlockedCount(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&FraudDecisionModel{}).
-		Where("user_id = ? AND decision = ? AND created_at >= ?", userID, "block", since).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Where(effectivelyBlocked).
 		Count(&count).Error
 	return count, err
 }
 
+// ListBlockTimes gets when each of a user's blocks since a time was made, newest first
+func (r *DecisionRepository) ListBlockTimes(ctx context.Context, userID uuid.UUID, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	err := r.db.WithContext(ctx).
+		Model(&FraudDecisionModel{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Where(effectivelyBlocked).
+		Order("created_at DESC").
+		Pluck("created_at", &times).Error
+	return times, err
+}
+
+// ScoreHistogram counts the decisions made within [from, to) at each score, lowest score first
+// Scores are decimal(5,4), so the database returns at most 10001 rows however many decisions there were
+func (r *DecisionRepository) ScoreHistogram(ctx context.Context, from, to time.Time) ([]fraud.ScoreCount, error) {
+	var histogram []fraud.ScoreCount
+	err := r.db.WithContext(ctx).
+		Model(&FraudDecisionModel{}).
+		Select("score, COUNT(*) AS count").
+		Where("processed_at >= ? AND processed_at < ?", from, to).
+		Group("score").
+		Order("score").
+		Scan(&histogram).Error
+	return histogram, err
+}
+
+// RecordFeedback stores an analyst's verdict on a decision
+func (r *DecisionRepository) RecordFeedback(ctx context.Context, feedback *fraud.DecisionFeedback) error {
+	model := DecisionFeedbackModel{
+		ID:         feedback.ID,
+		DecisionID: feedback.DecisionID,
+		Label:      string(feedback.Label),
+		Note:       feedback.Note,
+		Decision:   string(feedback.Decision),
+		DecidedAt:  feedback.DecidedAt,
+		CreatedBy:  feedback.CreatedBy,
+		CreatedAt:  feedback.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Create(&model).Error
+}
+
+// ListFeedback gets feedback on decisions made within [from, to), oldest feedback first
+func (r *DecisionRepository) ListFeedback(ctx context.Context, from, to time.Time) ([]*fraud.DecisionFeedback, error) {
+	var models []DecisionFeedbackModel
+	if err := r.db.WithContext(ctx).
+		Where("decided_at >= ? AND decided_at < ?", from, to).
+		Order("created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	feedback := make([]*fraud.DecisionFeedback, len(models))
+	for i, m := range models {
+		feedback[i] = &fraud.DecisionFeedback{
+			ID:         m.ID,
+			DecisionID: m.DecisionID,
+			Label:      fraud.FeedbackLabel(m.Label),
+			Note:       m.Note,
+			Decision:   fraud.DecisionType(m.Decision),
+			DecidedAt:  m.DecidedAt,
+			CreatedBy:  m.CreatedBy,
+			CreatedAt:  m.CreatedAt,
+		}
+	}
+	return feedback, nil
+}
+
+// RecordOverride stores an analyst's override and makes it the decision's effective outcome
+// The conditional update locks the decision row, so a concurrent override waits and
+// then matches no row once this one commits
+func (r *DecisionRepository) RecordOverride(ctx context.Context, override *fraud.DecisionOverride) error {
+	model := DecisionOverrideModel{
+		ID:                 override.ID,
+		DecisionID:         override.DecisionID,
+		TransactionID:      override.TransactionID,
+		PreviousDecision:   string(override.PreviousDecision),
+		Decision:           string(override.Decision),
+		Reason:             override.Reason,
+		TransactionUpdated: override.TransactionUpdated,
+		OverriddenBy:       override.OverriddenBy,
+		CreatedAt:          override.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&FraudDecisionModel{}).
+			Where("id = ? AND COALESCE(overridden_decision, decision) = ?", override.DecisionID, string(override.PreviousDecision)).
+			Update("overridden_decision", string(override.Decision))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fraud.ErrOverrideConflict
+		}
+		return tx.Create(&model).Error
+	})
+}
+
+// MarkOverrideApplied records that an override's transaction was updated to match it
+func (r *DecisionRepository) MarkOverrideApplied(ctx context.Context, overrideID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&DecisionOverrideModel{}).
+		Where("id = ?", overrideID).
+		Update("transaction_updated", true).Error
+}
+
+// ListOverrides gets a decision's overrides, oldest first
+func (r *DecisionRepository) ListOverrides(ctx context.Context, decisionID uuid.UUID) ([]*fraud.DecisionOverride, error) {
+	var models []DecisionOverrideModel
+	if err := r.db.WithContext(ctx).
+		Where("decision_id = ?", decisionID).
+		Order("created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	overrides := make([]*fraud.DecisionOverride, len(models))
+	for i, m := range models {
+		overrides[i] = &fraud.DecisionOverride{
+			ID:                 m.ID,
+			DecisionID:         m.DecisionID,
+			TransactionID:      m.TransactionID,
+			PreviousDecision:   fraud.DecisionType(m.PreviousDecision),
+			Decision:           fraud.DecisionType(m.Decision),
+			Reason:             m.Reason,
+			TransactionUpdated: m.TransactionUpdated,
+			OverriddenBy:       m.OverriddenBy,
+			CreatedAt:          m.CreatedAt,
+		}
+	}
+	return overrides, nil
+}
+
 func modelToDecision(m *FraudDecisionModel) *fraud.FraudDecision {
 	var rulesFired []string
 	var reasons []string
 	json.Unmarshal([]byte(m.RulesFired), &rulesFired)
 	json.Unmarshal([]byte(m.Reasons), &reasons)
 
+	// Decisions stored before contributions were recorded have a NULL column
+	contributions := make([]fraud.RuleContribution, 0)
+	if m.Contributions != "" {
+		json.Unmarshal([]byte(m.Contributions), &contributions)
+	}
+	skippedRules := make([]string, 0)
+	if m.SkippedRules != "" {
+		json.Unmarshal([]byte(m.SkippedRules), &skippedRules)
+	}
+	shadowRulesFired := make([]string, 0)
+	if m.ShadowRulesFired != "" {
+		json.Unmarshal([]byte(m.ShadowRulesFired), &shadowRulesFired)
+	}
+	var featureVector []float64
+	if m.FeatureVector != "" {
+		json.Unmarshal([]byte(m.FeatureVector), &featureVector)
+	}
+	var confidenceFactors *fraud.ConfidenceFactors
+	if m.ConfidenceFactors != "" {
+		confidenceFactors = &fraud.ConfidenceFactors{}
+		json.Unmarshal([]byte(m.ConfidenceFactors), confidenceFactors)
+	}
+	var overridden fraud.DecisionType
+	if m.OverriddenDecision != nil {
+		overridden = fraud.DecisionType(*m.OverriddenDecision)
+	}
+
 	return &fraud.FraudDecision{
 		ID:            m.ID,
 		TransactionID: m.TransactionID,
@@ -189,10 +514,28 @@ func modelToDecision(m *FraudDecisionModel) *fraud.FraudDecision {
 		RulesFired:    rulesFired,
 		Reasons:       reasons,
 		ModelVersion:  m.ModelVersion,
+		Contributions: contributions,
 		ProcessedAt:   m.ProcessedAt,
 		LatencyMs:     m.LatencyMs,
 		CreatedAt:     m.CreatedAt,
 		UpdatedAt:     m.UpdatedAt,
+
+		MissingContextCount: m.MissingContextCount,
+		SkippedRules:        skippedRules,
+		ShadowRulesFired:    shadowRulesFired,
+		ConfidenceFactors:   confidenceFactors,
+		DowngradedFrom:      fraud.DecisionType(m.DowngradedFrom),
+		FeatureVector:       featureVector,
+		BreakdownOmitted:    m.BreakdownOmitted,
+		Degraded:            m.Degraded,
+		DegradedReason:      m.DegradedReason,
+
+		BaseAmount:   m.BaseAmount,
+		BaseCurrency: m.BaseCurrency,
+
+		DataCoverage: m.DataCoverage,
+
+		OverriddenDecision: overridden,
 	}
 }
 
@@ -201,6 +544,8 @@ type CaseRepository struct {
 	db *gorm.DB
 }
 
+var _ fraud.CaseRepository = (*CaseRepository)(nil)
+
 // NewCaseRepository creates a new case repository
 func NewCaseRepository(client *Client) *CaseRepository {
 	return &CaseRepository{db: client.DB()}
@@ -226,6 +571,7 @@ func (r *CaseRepository) Create(ctx context.Context, fraudCase *fraud.FraudCase)
 		Notes:          string(notes),
 		Evidence:       string(evidence),
 		Resolution:     fraudCase.Resolution,
+		Outcome:        string(fraudCase.Outcome),
 		ResolvedBy:     fraudCase.ResolvedBy,
 		ResolvedAt:     fraudCase.ResolvedAt,
 		CreatedAt:      fraudCase.CreatedAt,
@@ -266,6 +612,7 @@ func (r *CaseRepository) Update(ctx context.Context, fraudCase *fraud.FraudCase)
 			"notes":           string(notes),
 			"evidence":        string(evidence),
 			"resolution":      fraudCase.Resolution,
+			"outcome":         string(fraudCase.Outcome),
 			"resolved_by":     fraudCase.ResolvedBy,
 			"resolved_at":     fraudCase.ResolvedAt,
 			"updated_at":      time.Now(),
@@ -291,6 +638,16 @@ func (r *CaseRepository) ListByStatus(ctx context.Context, status fraud.CaseStat
 	return cases, nil
 }
 
+// CountByStatus counts cases in a status
+func (r *CaseRepository) CountByStatus(ctx context.Context, status fraud.CaseStatus) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&FraudCaseModel{}).
+		Where("status = ?", string(status)).
+		Count(&count).Error
+	return count, err
+}
+
 // ListByAssignee retrieves cases assigned to an investigator
 func (r *CaseRepository) ListByAssignee(ctx context.Context, assigneeID uuid.UUID, limit, offset int) ([]*fraud.FraudCase, error) {
 	var models []FraudCaseModel
@@ -326,6 +683,96 @@ func (r *CaseRepository) GetOpenCasesByUser(ctx context.Context, userID uuid.UUI
 	return cases, nil
 }
 
+// caseQueueOrder sorts the unassigned queue like fraud.CompareCaseQueue
+const caseQueueOrder = "CASE risk_level WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3 ELSE 4 END, created_at ASC"
+
+// ListUnassigned retrieves open, unassigned cases, highest risk and oldest first
+func (r *CaseRepository) ListUnassigned(ctx context.Context, limit, offset int) ([]*fraud.FraudCase, error) {
+	var models []FraudCaseModel
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND assigned_to IS NULL", string(fraud.CaseStatusOpen)).
+		Order(caseQueueOrder).
+		Limit(limit).
+		Offset(offset).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	cases := make([]*fraud.FraudCase, len(models))
+	for i, m := range models {
+		cases[i] = modelToCase(&m)
+	}
+	return cases, nil
+}
+
+// CountUnassigned counts open, unassigned cases
+func (r *CaseRepository) CountUnassigned(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&FraudCaseModel{}).
+		Where("status = ? AND assigned_to IS NULL", string(fraud.CaseStatusOpen)).
+		Count(&count).Error
+	return count, err
+}
+
+// ClaimNext assigns the first unassigned case to an investigator
+// The row is locked with SKIP LOCKED, so concurrent claims each take a different
+// case instead of waiting on, or double-assigning, the same one
+func (r *CaseRepository) ClaimNext(ctx context.Context, investigatorID uuid.UUID) (*fraud.FraudCase, error) {
+	var claimed *fraud.FraudCase
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var model FraudCaseModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND assigned_to IS NULL", string(fraud.CaseStatusOpen)).
+			Order(caseQueueOrder).
+			Take(&model).Error
+		if err == gorm.ErrRecordNotFound {
+			return fraud.ErrCaseQueueEmpty
+		}
+		if err != nil {
+			return err
+		}
+
+		fraudCase := modelToCase(&model)
+		if err := fraudCase.Assign(investigatorID); err != nil {
+			return err
+		}
+		if err := tx.Model(&FraudCaseModel{}).
+			Where("id = ?", fraudCase.ID).
+			Updates(map[string]interface{}{
+				"assigned_to": fraudCase.AssignedTo,
+				"status":      string(fraudCase.Status),
+				"updated_at":  fraudCase.UpdatedAt,
+			}).Error; err != nil {
+			return err
+		}
+		claimed = fraudCase
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// ListOpenCreatedBefore retrieves open or investigating cases of a risk level created before a time, oldest first
+func (r *CaseRepository) ListOpenCreatedBefore(ctx context.Context, riskLevel fraud.RiskLevel, before time.Time, limit int) ([]*fraud.FraudCase, error) {
+	var models []FraudCaseModel
+	if err := r.db.WithContext(ctx).
+		Where("risk_level = ? AND status IN ? AND created_at < ?", string(riskLevel), []string{"open", "investigating"}, before).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	cases := make([]*fraud.FraudCase, len(models))
+	for i, m := range models {
+		cases[i] = modelToCase(&m)
+	}
+	return cases, nil
+}
+
 func modelToCase(m *FraudCaseModel) *fraud.FraudCase {
 	var transactionIDs []uuid.UUID
 	var notes []fraud.CaseNote
@@ -348,6 +795,7 @@ func modelToCase(m *FraudCaseModel) *fraud.FraudCase {
 		Notes:          notes,
 		Evidence:       evidence,
 		Resolution:     m.Resolution,
+		Outcome:        fraud.ResolutionOutcome(m.Outcome),
 		ResolvedBy:     m.ResolvedBy,
 		ResolvedAt:     m.ResolvedAt,
 		CreatedAt:      m.CreatedAt,
@@ -360,33 +808,36 @@ type RuleRepository struct {
 	db *gorm.DB
 }
 
+var _ fraud.RuleRepository = (*RuleRepository)(nil)
+
 // NewRuleRepository creates a new rule repository
 func NewRuleRepository(client *Client) *RuleRepository {
 	return &RuleRepository{db: client.DB()}
 }
 
-// Create adds a new rule
+// Create adds a new rule and records it as the rule's first version
 func (r *RuleRepository) Create(ctx context.Context, rule *fraud.Rule) error {
-	config, _ := json.Marshal(rule.Config)
-
-	model := &RuleModel{
-		ID:          rule.ID,
-		Name:        rule.Name,
-		Description: rule.Description,
-		Type:        string(rule.Type),
-		Severity:    string(rule.Severity),
-		Action:      string(rule.Action),
-		Config:      string(config),
-		Enabled:     rule.Enabled,
-		Version:     rule.Version,
-		CreatedBy:   rule.CreatedBy,
-		CreatedAt:   rule.CreatedAt,
-		UpdatedAt:   rule.UpdatedAt,
-		EffectiveAt: rule.EffectiveAt,
-		ExpiresAt:   rule.ExpiresAt,
-	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ruleToModel(rule)).Error; err != nil {
+			return err
+		}
+		return tx.Create(ruleToVersionModel(rule)).Error
+	})
+}
 
-	return r.db.WithContext(ctx).Create(model).Error
+// CreateBatch adds multiple rules in a single transaction
+func (r *RuleRepository) CreateBatch(ctx context.Context, rules []*fraud.Rule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, rule := range rules {
+			if err := tx.Create(ruleToModel(rule)).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(ruleToVersionModel(rule)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // GetByID retrieves a rule by ID
@@ -401,25 +852,35 @@ func (r *RuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.Rule
 	return modelToRule(&model), nil
 }
 
-// Update updates an existing rule
+// Update updates an existing rule and appends its new version to the history
+// A version that was already recorded is rejected rather than overwritten
 func (r *RuleRepository) Update(ctx context.Context, rule *fraud.Rule) error {
 	config, _ := json.Marshal(rule.Config)
 
-	return r.db.WithContext(ctx).Model(&RuleModel{}).
-		Where("id = ?", rule.ID).
-		Updates(map[string]interface{}{
-			"name":         rule.Name,
-			"description":  rule.Description,
-			"type":         string(rule.Type),
-			"severity":     string(rule.Severity),
-			"action":       string(rule.Action),
-			"config":       string(config),
-			"enabled":      rule.Enabled,
-			"version":      rule.Version,
-			"updated_at":   time.Now(),
-			"effective_at": rule.EffectiveAt,
-			"expires_at":   rule.ExpiresAt,
-		}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&RuleModel{}).
+			Where("id = ?", rule.ID).
+			Updates(map[string]interface{}{
+				"name":         rule.Name,
+				"description":  rule.Description,
+				"type":         string(rule.Type),
+				"severity":     string(rule.Severity),
+				"action":       string(rule.Action),
+				"fail_mode":    string(rule.FailMode),
+				"mode":         string(rule.Mode),
+				"priority":     rule.Priority,
+				"config":       string(config),
+				"enabled":      rule.Enabled,
+				"version":      rule.Version,
+				"updated_by":   rule.UpdatedBy,
+				"updated_at":   time.Now(),
+				"effective_at": rule.EffectiveAt,
+				"expires_at":   rule.ExpiresAt,
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Create(ruleToVersionModel(rule)).Error
+	})
 }
 
 // ListActive retrieves all enabled rules
@@ -465,16 +926,115 @@ func (r *RuleRepository) Disable(ctx context.Context, ruleID uuid.UUID) error {
 		}).Error
 }
 
-// GetVersion retrieves a specific version of a rule
+// GetVersion retrieves a specific version of a rule from its history
 func (r *RuleRepository) GetVersion(ctx context.Context, ruleID uuid.UUID, version int) (*fraud.Rule, error) {
-	var model RuleModel
-	if err := r.db.WithContext(ctx).First(&model, "id = ? AND version = ?", ruleID, version).Error; err != nil {
+	var model RuleVersionModel
+	if err := r.db.WithContext(ctx).First(&model, "rule_id = ? AND version = ?", ruleID, version).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fraud.ErrRuleNotFound
 		}
 		return nil, err
 	}
-	return modelToRule(&model), nil
+	return versionModelToRule(&model), nil
+}
+
+// ListVersions retrieves every recorded version of a rule, oldest first
+func (r *RuleRepository) ListVersions(ctx context.Context, ruleID uuid.UUID) ([]*fraud.RuleVersion, error) {
+	var models []RuleVersionModel
+	if err := r.db.WithContext(ctx).
+		Where("rule_id = ?", ruleID).
+		Order("version ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	versions := make([]*fraud.RuleVersion, len(models))
+	for i, m := range models {
+		versions[i] = &fraud.RuleVersion{
+			Rule:      versionModelToRule(&m),
+			ChangedBy: m.ChangedBy,
+			ChangedAt: m.ChangedAt,
+		}
+	}
+	return versions, nil
+}
+
+// ruleToModel converts a domain rule to its database model
+func ruleToModel(rule *fraud.Rule) *RuleModel {
+	config, _ := json.Marshal(rule.Config)
+
+	return &RuleModel{
+		ID:          rule.ID,
+		Name:        rule.Name,
+		Description: rule.Description,
+		Type:        string(rule.Type),
+		Severity:    string(rule.Severity),
+		Action:      string(rule.Action),
+		FailMode:    string(rule.FailMode),
+		Mode:        string(rule.Mode),
+		Priority:    rule.Priority,
+		Config:      string(config),
+		Enabled:     rule.Enabled,
+		Version:     rule.Version,
+		CreatedBy:   rule.CreatedBy,
+		UpdatedBy:   rule.UpdatedBy,
+		CreatedAt:   rule.CreatedAt,
+		UpdatedAt:   rule.UpdatedAt,
+		EffectiveAt: rule.EffectiveAt,
+		ExpiresAt:   rule.ExpiresAt,
+	}
+}
+
+// ruleToVersionModel snapshots a rule as a history row for its current version
+func ruleToVersionModel(rule *fraud.Rule) *RuleVersionModel {
+	config, _ := json.Marshal(rule.Config)
+
+	return &RuleVersionModel{
+		RuleID:      rule.ID,
+		Version:     rule.Version,
+		Name:        rule.Name,
+		Description: rule.Description,
+		Type:        string(rule.Type),
+		Severity:    string(rule.Severity),
+		Action:      string(rule.Action),
+		FailMode:    string(rule.FailMode),
+		Mode:        string(rule.Mode),
+		Priority:    rule.Priority,
+		Config:      string(config),
+		Enabled:     rule.Enabled,
+		CreatedBy:   rule.CreatedBy,
+		CreatedAt:   rule.CreatedAt,
+		EffectiveAt: rule.EffectiveAt,
+		ExpiresAt:   rule.ExpiresAt,
+		ChangedBy:   rule.UpdatedBy,
+		ChangedAt:   rule.UpdatedAt,
+	}
+}
+
+func versionModelToRule(m *RuleVersionModel) *fraud.Rule {
+	var config map[string]interface{}
+	json.Unmarshal([]byte(m.Config), &config)
+
+	return &fraud.Rule{
+		ID:          m.RuleID,
+		Name:        m.Name,
+		Description: m.Description,
+		Type:        fraud.RuleType(m.Type),
+		Severity:    fraud.RuleSeverity(m.Severity),
+		Action:      fraud.RuleAction(m.Action),
+		FailMode:    fraud.RuleFailMode(m.FailMode),
+		Mode:        fraud.RuleMode(m.Mode),
+		Priority:    m.Priority,
+		Config:      config,
+		Enabled:     m.Enabled,
+		Version:     m.Version,
+		CreatedBy:   m.CreatedBy,
+		UpdatedBy:   m.ChangedBy,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.ChangedAt,
+		EffectiveAt: m.EffectiveAt,
+		ExpiresAt:   m.ExpiresAt,
+	}
 }
 
 func modelToRule(m *RuleModel) *fraud.Rule {
@@ -488,10 +1048,14 @@ func modelToRule(m *RuleModel) *fraud.Rule {
 		Type:        fraud.RuleType(m.Type),
 		Severity:    fraud.RuleSeverity(m.Severity),
 		Action:      fraud.RuleAction(m.Action),
+		FailMode:    fraud.RuleFailMode(m.FailMode),
+		Mode:        fraud.RuleMode(m.Mode),
+		Priority:    m.Priority,
 		Config:      config,
 		Enabled:     m.Enabled,
 		Version:     m.Version,
 		CreatedBy:   m.CreatedBy,
+		UpdatedBy:   m.UpdatedBy,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
 		EffectiveAt: m.EffectiveAt,
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// ListEntryModel is the database model for allowlist and denylist entries
+type ListEntryModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ListType   string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_fraud_list_entries_entity,priority:3"`
+	EntityType string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_fraud_list_entries_entity,priority:1"`
+	Value      string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_fraud_list_entries_entity,priority:2"`
+	Reason     string    `gorm:"type:text"`
+	CreatedBy  uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+// TableName returns the table name for list entries
+func (ListEntryModel) TableName() string {
+	return "fraud_list_entries"
+}
+
+// ListRepository implements fraud.ListRepository
+type ListRepository struct {
+	db *gorm.DB
+}
+
+var _ fraud.ListRepository = (*ListRepository)(nil)
+
+// NewListRepository creates a new list repository
+func NewListRepository(client *Client) *ListRepository {
+	return &ListRepository{db: client.DB()}
+}
+
+// Create stores a new list entry
+func (r *ListRepository) Create(ctx context.Context, entry *fraud.ListEntry) error {
+	model := &ListEntryModel{
+		ID:         entry.ID,
+		ListType:   string(entry.ListType),
+		EntityType: string(entry.EntityType),
+		Value:      entry.Value,
+		Reason:     entry.Reason,
+		CreatedBy:  entry.CreatedBy,
+		CreatedAt:  entry.CreatedAt,
+	}
+
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		if isUniqueViolation(err) {
+			return fraud.ErrDuplicateListEntry
+		}
+		return err
+	}
+	return nil
+}
+
+// GetByID retrieves a list entry by ID
+func (r *ListRepository) GetByID(ctx context.Context, id uuid.UUID) (*fraud.ListEntry, error) {
+	var model ListEntryModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fraud.ErrListEntryNotFound
+		}
+		return nil, err
+	}
+	return modelToListEntry(&model), nil
+}
+
+// Delete removes a list entry
+func (r *ListRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&ListEntryModel{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fraud.ErrListEntryNotFound
+	}
+	return nil
+}
+
+// List retrieves the entries of a list, or of both lists when listType is empty, newest first
+func (r *ListRepository) List(ctx context.Context, listType fraud.ListType) ([]*fraud.ListEntry, error) {
+	query := r.db.WithContext(ctx).Order("created_at DESC")
+	if listType != "" {
+		query = query.Where("list_type = ?", string(listType))
+	}
+
+	var models []ListEntryModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return modelsToListEntries(models), nil
+}
+
+// FindMatches retrieves the entries on either list matching any of the keys
+func (r *ListRepository) FindMatches(ctx context.Context, keys []fraud.ListKey) ([]*fraud.ListEntry, error) {
+	if len(keys) == 0 {
+		return []*fraud.ListEntry{}, nil
+	}
+
+	query := r.db.WithContext(ctx).Model(&ListEntryModel{})
+	conditions := r.db.Where("entity_type = ? AND value = ?", string(keys[0].EntityType), keys[0].Value)
+	for _, key := range keys[1:] {
+		conditions = conditions.Or("entity_type = ? AND value = ?", string(key.EntityType), key.Value)
+	}
+
+	var models []ListEntryModel
+	if err := query.Where(conditions).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return modelsToListEntries(models), nil
+}
+
+func modelsToListEntries(models []ListEntryModel) []*fraud.ListEntry {
+	entries := make([]*fraud.ListEntry, len(models))
+	for i := range models {
+		entries[i] = modelToListEntry(&models[i])
+	}
+	return entries
+}
+
+func modelToListEntry(m *ListEntryModel) *fraud.ListEntry {
+	return &fraud.ListEntry{
+		ID:         m.ID,
+		ListType:   fraud.ListType(m.ListType),
+		EntityType: fraud.ListEntityType(m.EntityType),
+		Value:      m.Value,
+		Reason:     m.Reason,
+		CreatedBy:  m.CreatedBy,
+		CreatedAt:  m.CreatedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// migrationLockKey keys the advisory lock held while a migration runs, so
+// instances started together don't apply the same migration twice
+const migrationLockKey = 4206150001
+
+// migration is one versioned up migration
+type migration struct {
+	version int64
+	name    string
+	sql     string
+}
+
+// Migrate applies the up migrations in fsys newer than the database's schema version, oldest first
+// The version is kept in schema_migrations the way golang-migrate keeps it, so the
+// migrate CLI and this runner can be used on the same database. Each migration
+// runs in one transaction with its version bump, so a failed one leaves the schema
+// as it was. Returns how many migrations were applied.
+func (c *Client) Migrate(ctx context.Context, fsys fs.FS, log *slog.Logger) (int, error) {
+	migrations, err := readMigrations(fsys)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, m := range migrations {
+		ran := false
+		err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			version, err := lockSchemaVersion(tx)
+			if err != nil {
+				return err
+			}
+			if version >= m.version {
+				return nil
+			}
+			if strings.TrimSpace(m.sql) != "" {
+				if err := tx.Exec(m.sql).Error; err != nil {
+					return err
+				}
+			}
+			ran = true
+			return setSchemaVersion(tx, m.version)
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %s failed: %w", m.name, err)
+		}
+		if ran {
+			applied++
+			log.Info("applied database migration", slog.String("migration", m.name))
+		}
+	}
+	return applied, nil
+}
+
+// lockSchemaVersion takes the migration lock for the transaction and reads the schema version
+// A database never migrated before is at version 0
+func lockSchemaVersion(tx *gorm.DB) (int64, error) {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockKey).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)").Error; err != nil {
+		return 0, err
+	}
+
+	var row struct {
+		Version int64
+		Dirty   bool
+	}
+	result := tx.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&row)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if row.Dirty {
+		return 0, fmt.Errorf("schema version %d is marked dirty by a failed migrate run; fix the schema and force the version", row.Version)
+	}
+	return row.Version, nil
+}
+
+// setSchemaVersion records the version a migration brought the schema to
+func setSchemaVersion(tx *gorm.DB, version int64) error {
+	if err := tx.Exec("DELETE FROM schema_migrations").Error; err != nil {
+		return err
+	}
+	return tx.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (?, false)", version).Error
+}
+
+// readMigrations loads the NNNNNN_name.up.sql files at the root of fsys, ordered by version
+func readMigrations(fsys fs.FS) ([]migration, error) {
+	files, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]migration, 0, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".up.sql")
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has no numeric version", file)
+		}
+		sql, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration{version: version, name: name, sql: string(sql)})
+	}
+
+	slices.SortFunc(migrations, func(a, b migration) int {
+		return int(a.version - b.version)
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version == migrations[i-1].version {
+			return nil, fmt.Errorf("migrations %s and %s share a version", migrations[i-1].name, migrations[i].name)
+		}
+	}
+	return migrations, nil
+}
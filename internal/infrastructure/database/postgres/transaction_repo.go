@@ -12,13 +12,15 @@ import (
 )
 
 // TransactionModel is the database model for transactions
+// The composite indexes on (user_id, created_at) and (status, created_at) serve
+// the time-range and listing queries; migration 000019 creates them.
 type TransactionModel struct {
 	ID          uuid.UUID       `gorm:"type:uuid;primaryKey"`
 	ExternalID  string          `gorm:"type:varchar(100);index"`
-	UserID      uuid.UUID       `gorm:"type:uuid;index;not null"`
+	UserID      uuid.UUID       `gorm:"type:uuid;index;index:idx_transactions_user_created,priority:1;not null"`
 	AccountID   uuid.UUID       `gorm:"type:uuid;index;not null"`
 	Type        string          `gorm:"type:varchar(20);not null"`
-	Status      string          `gorm:"type:varchar(20);index;not null"`
+	Status      string          `gorm:"type:varchar(20);index;index:idx_transactions_status_created,priority:1;not null"`
 	Amount      decimal.Decimal `gorm:"type:decimal(15,2);not null"`
 	Currency    string          `gorm:"type:varchar(3);not null"`
 	Description string          `gorm:"type:text"`
@@ -32,7 +34,7 @@ type TransactionModel struct {
 	FraudReasons string         `gorm:"type:jsonb"`
 	ReviewedBy  *uuid.UUID      `gorm:"type:uuid"`
 	ReviewedAt  *time.Time
-	CreatedAt   time.Time       `gorm:"not null"`
+	CreatedAt   time.Time       `gorm:"not null;index:idx_transactions_user_created,priority:2;index:idx_transactions_status_created,priority:2"`
 	ProcessedAt *time.Time
 	UpdatedAt   time.Time       `gorm:"not null"`
 }
@@ -47,6 +49,8 @@ type TransactionRepository struct {
 	db *gorm.DB
 }
 
+var _ transaction.Repository = (*TransactionRepository)(nil)
+
 // NewTransactionRepository creates a new transaction repository
 func NewTransactionRepository(client *Client) *TransactionRepository {
 	return &TransactionRepository{db: client.DB()}
@@ -101,11 +105,22 @@ func (r *TransactionRepository) Update(ctx context.Context, tx *transaction.Tran
 		}).Error
 }
 
-// ListByUserID retrieves transactions for a user
-func (r *TransactionRepository) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*transaction.Transaction, error) {
+// userQuery selects a user's transactions matching filter
+func (r *TransactionRepository) userQuery(ctx context.Context, userID uuid.UUID, filter transaction.ListFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&TransactionModel{}).Where("user_id = ?", userID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.MinScore != nil {
+		query = query.Where("fraud_score >= ?", *filter.MinScore)
+	}
+	return query
+}
+
+// ListByUserID retrieves transactions for a user matching filter
+func (r *TransactionRepository) ListByUserID(ctx context.Context, userID uuid.UUID, filter transaction.ListFilter, limit, offset int) ([]*transaction.Transaction, error) {
 	var models []TransactionModel
-	if err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
+	if err := r.userQuery(ctx, userID, filter).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -120,6 +135,135 @@ func (r *TransactionRepository) ListByUserID(ctx context.Context, userID uuid.UU
 	return transactions, nil
 }
 
+// CountByUserID counts transactions for a user matching filter
+func (r *TransactionRepository) CountByUserID(ctx context.Context, userID uuid.UUID, filter transaction.ListFilter) (int64, error) {
+	var count int64
+	err := r.userQuery(ctx, userID, filter).Count(&count).Error
+	return count, err
+}
+
+// GetByExternalID retrieves a transaction by external ID
+func (r *TransactionRepository) GetByExternalID(ctx context.Context, externalID string) (*transaction.Transaction, error) {
+	var model TransactionModel
+	if err := r.db.WithContext(ctx).First(&model, "external_id = ?", externalID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, transaction.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return modelToTransaction(&model), nil
+}
+
+// ListByAccountID retrieves transactions for an account
+func (r *TransactionRepository) ListByAccountID(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*transaction.Transaction, error) {
+	var models []TransactionModel
+	if err := r.db.WithContext(ctx).
+		Where("account_id = ?", accountID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return modelsToTransactions(models), nil
+}
+
+// GetRecentByUserID gets a user's transactions created since the given time
+func (r *TransactionRepository) GetRecentByUserID(ctx context.Context, userID uuid.UUID, since time.Time) ([]*transaction.Transaction, error) {
+	var models []TransactionModel
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Order("created_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return modelsToTransactions(models), nil
+}
+
+// GetFirstByUserID retrieves a user's earliest transaction
+func (r *TransactionRepository) GetFirstByUserID(ctx context.Context, userID uuid.UUID) (*transaction.Transaction, error) {
+	var model TransactionModel
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		First(&model).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, transaction.ErrTransactionNotFound
+		}
+		return nil, err
+	}
+	return modelToTransaction(&model), nil
+}
+
+// GetByTimeRange retrieves a user's transactions in a time window
+func (r *TransactionRepository) GetByTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]*transaction.Transaction, error) {
+	var models []TransactionModel
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, start, end).
+		Order("created_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return modelsToTransactions(models), nil
+}
+
+// ListCreatedBetween retrieves up to limit transactions created in a time window, oldest first
+func (r *TransactionRepository) ListCreatedBetween(ctx context.Context, start, end time.Time, limit int) ([]*transaction.Transaction, error) {
+	var models []TransactionModel
+	if err := r.db.WithContext(ctx).
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return modelsToTransactions(models), nil
+}
+
+// CountByUserIDAndTimeRange counts a user's transactions in a time window
+func (r *TransactionRepository) CountByUserIDAndTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&TransactionModel{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, start, end).
+		Count(&count).Error
+	return count, err
+}
+
+// SumAmountByUserIDAndTimeRange sums a user's transaction amounts in a time window
+func (r *TransactionRepository) SumAmountByUserIDAndTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) (decimal.Decimal, error) {
+	var sum decimal.NullDecimal
+	err := r.db.WithContext(ctx).Model(&TransactionModel{}).
+		Select("SUM(amount)").
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, start, end).
+		Scan(&sum).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if !sum.Valid {
+		return decimal.Zero, nil
+	}
+	return sum.Decimal, nil
+}
+
+// GetByStatus retrieves transactions by status
+func (r *TransactionRepository) GetByStatus(ctx context.Context, status transaction.TransactionStatus, limit, offset int) ([]*transaction.Transaction, error) {
+	var models []TransactionModel
+	if err := r.db.WithContext(ctx).
+		Where("status = ?", string(status)).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return modelsToTransactions(models), nil
+}
+
+// GetFlaggedTransactions retrieves transactions flagged for review
+func (r *TransactionRepository) GetFlaggedTransactions(ctx context.Context, limit, offset int) ([]*transaction.Transaction, error) {
+	return r.GetByStatus(ctx, transaction.StatusFlagged, limit, offset)
+}
+
 // ListPending retrieves pending transactions
 func (r *TransactionRepository) ListPending(ctx context.Context, limit int) ([]*transaction.Transaction, error) {
 	var models []TransactionModel
@@ -138,6 +282,14 @@ func (r *TransactionRepository) ListPending(ctx context.Context, limit int) ([]*
 	return transactions, nil
 }
 
+func modelsToTransactions(models []TransactionModel) []*transaction.Transaction {
+	transactions := make([]*transaction.Transaction, len(models))
+	for i, m := range models {
+		transactions[i] = modelToTransaction(&m)
+	}
+	return transactions
+}
+
 func modelToTransaction(m *TransactionModel) *transaction.Transaction {
 	return &transaction.Transaction{
 		ID:          m.ID,
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCredentials is returned when a token does not match any known key
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Principal is an authenticated caller
+type Principal struct {
+	UserID   uuid.UUID
+	Roles    []Role
+	TenantID string // Empty for callers not bound to a tenant
+}
+
+// Authenticator resolves a bearer token or API key to the caller it belongs to
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+// APIKey maps a static API key to a user and its roles
+type APIKey struct {
+	Key      string
+	UserID   uuid.UUID
+	Roles    []Role
+	TenantID string
+}
+
+// StaticKeyAuthenticator authenticates against a fixed set of API keys
+type StaticKeyAuthenticator struct {
+	keys []APIKey
+}
+
+// NewStaticKeyAuthenticator creates an authenticator for the given keys
+func NewStaticKeyAuthenticator(keys []APIKey) *StaticKeyAuthenticator {
+	return &StaticKeyAuthenticator{keys: keys}
+}
+
+// Authenticate implements Authenticator
+// Every key is compared in constant time so response timing doesn't leak key prefixes
+func (a *StaticKeyAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	var match *APIKey
+	for i := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(a.keys[i].Key), []byte(token)) == 1 {
+			match = &a.keys[i]
+		}
+	}
+	if match == nil || token == "" {
+		return nil, ErrInvalidCredentials
+	}
+	return &Principal{UserID: match.UserID, Roles: match.Roles, TenantID: match.TenantID}, nil
+}
+
+type contextKey int
+
+const principalKey contextKey = iota
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, if the request was authenticated
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// UserIDFromContext returns the authenticated user ID, if the request was authenticated
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return uuid.Nil, false
+	}
+	return principal.UserID, true
+}
+
+// TenantIDFromContext returns the authenticated caller's tenant, if it has one
+func TenantIDFromContext(ctx context.Context) (string, bool) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.TenantID == "" {
+		return "", false
+	}
+	return principal.TenantID, true
+}
+
+// RequireAuth rejects requests without valid credentials with 401 and
+// stores the authenticated caller in the request context. Credentials are read
+// from "Authorization: Bearer <token>" or the X-API-Key header.
+// A nil authenticator disables authentication and lets every request through.
+func RequireAuth(auth Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := auth.Authenticate(r.Context(), tokenFromRequest(r))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeUnauthorized(w)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// tokenFromRequest extracts the bearer token or API key from a request
+func tokenFromRequest(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		if scheme, token, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+func writeUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	w.Write([]byte(`{"code":"UNAUTHORIZED","message":"Authentication required"}`))
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTokenFromRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		apiKey        string
+		want          string
+	}{
+		{"bearer token", "Bearer abc", "", "abc"},
+		{"lowercase scheme", "bearer abc", "", "abc"},
+		{"padded token", "Bearer  abc ", "", "abc"},
+		{"API key", "", "abc", "abc"},
+		{"bearer wins over API key", "Bearer abc", "def", "abc"},
+		{"other scheme masks API key", "Basic abc", "def", ""},
+		{"scheme only", "Bearer", "def", ""},
+		{"nothing", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			if got := tokenFromRequest(req); got != tt.want {
+				t.Errorf("token %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaticKeyAuthenticator(t *testing.T) {
+	userID := uuid.New()
+	auth := NewStaticKeyAuthenticator([]APIKey{
+		{Key: "key-a", UserID: userID, Roles: []Role{RoleViewer}, TenantID: "tenant-a"},
+		{Key: "", UserID: uuid.New(), Roles: []Role{RoleAdmin}}, // Misconfigured empty key
+	})
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"known key", "key-a", false},
+		{"wrong key", "key-b", true},
+		{"prefix of a key", "key", true},
+		{"empty token matching an empty key", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := auth.Authenticate(context.Background(), tt.token)
+			if tt.wantErr {
+				if err != ErrInvalidCredentials {
+					t.Fatalf("error %v, want %v", err, ErrInvalidCredentials)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("authenticate: %v", err)
+			}
+			if principal.UserID != userID || principal.TenantID != "tenant-a" || len(principal.Roles) != 1 || principal.Roles[0] != RoleViewer {
+				t.Errorf("principal %+v, want the key's user, tenant and roles", principal)
+			}
+		})
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	userID := uuid.New()
+	auth := NewStaticKeyAuthenticator([]APIKey{{Key: "key-a", UserID: userID, TenantID: "tenant-a"}})
+
+	tests := []struct {
+		name       string
+		auth       Authenticator
+		header     string
+		value      string
+		wantStatus int
+		wantUser   uuid.UUID
+		wantTenant string
+	}{
+		{"missing token", auth, "", "", http.StatusUnauthorized, uuid.Nil, ""},
+		{"wrong key", auth, "X-API-Key", "key-b", http.StatusUnauthorized, uuid.Nil, ""},
+		{"non-bearer scheme", auth, "Authorization", "Basic key-a", http.StatusUnauthorized, uuid.Nil, ""},
+		{"bearer token", auth, "Authorization", "Bearer key-a", http.StatusOK, userID, "tenant-a"},
+		{"API key", auth, "X-API-Key", "key-a", http.StatusOK, userID, "tenant-a"},
+		{"no authenticator", nil, "", "", http.StatusOK, uuid.Nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser uuid.UUID
+			var gotTenant string
+			h := RequireAuth(tt.auth, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUser, _ = UserIDFromContext(r.Context())
+				gotTenant, _ = TenantIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("WWW-Authenticate %q, want Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+			if gotUser != tt.wantUser || gotTenant != tt.wantTenant {
+				t.Errorf("handler saw user %s in tenant %q, want %s in %q", gotUser, gotTenant, tt.wantUser, tt.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// Role is a coarse permission granted to an authenticated caller
+type Role string
+
+const (
+	RoleAdmin        Role = "admin"        // Full access
+	RoleRuleManager  Role = "rule_manager" // Creates, updates and disables rules
+	RoleInvestigator Role = "investigator" // Works fraud cases
+	RoleViewer       Role = "viewer"       // Read-only access
+)
+
+// IsValid checks if the role is known
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleRuleManager, RoleInvestigator, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// RoleSource returns the roles held by the caller of a request
+type RoleSource func(r *http.Request) []Role
+
+// RolesFromContext is the default RoleSource
+// It reads the roles of the principal stored by RequireAuth
+func RolesFromContext(r *http.Request) []Role {
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	return principal.Roles
+}
+
+// RequireRole rejects requests whose caller holds none of the allowed roles with 403
+// A nil source disables authorization and lets every request through
+func RequireRole(source RoleSource, allowed []Role, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if HasRole(source, r, allowed) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		writeForbidden(w)
+	})
+}
+
+// HasRole reports whether the caller of r holds one of the allowed roles
+// Like RequireRole, a nil source lets every caller through
+func HasRole(source RoleSource, r *http.Request, allowed []Role) bool {
+	return source == nil || hasAnyRole(source(r), allowed)
+}
+
+func hasAnyRole(held, allowed []Role) bool {
+	for _, h := range held {
+		for _, a := range allowed {
+			if h == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeForbidden(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	w.Write([]byte(`{"code":"FORBIDDEN","message":"Insufficient role"}`))
+}
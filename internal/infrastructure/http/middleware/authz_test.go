@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireRole(t *testing.T) {
+	allowed := []Role{RoleAdmin, RoleInvestigator}
+
+	tests := []struct {
+		name       string
+		source     RoleSource
+		wantStatus int
+	}{
+		{"no source", nil, http.StatusOK},
+		{"allowed role", func(*http.Request) []Role { return []Role{RoleViewer, RoleInvestigator} }, http.StatusOK},
+		{"other role", func(*http.Request) []Role { return []Role{RoleRuleManager} }, http.StatusForbidden},
+		{"no roles", func(*http.Request) []Role { return nil }, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if got, want := HasRole(tt.source, req, allowed), tt.wantStatus == http.StatusOK; got != want {
+				t.Errorf("HasRole %t, want %t", got, want)
+			}
+
+			called := false
+			h := RequireRole(tt.source, allowed, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if called != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("handler called %t with status %d", called, rec.Code)
+			}
+		})
+	}
+}
+
+func TestRolesFromContext(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if roles := RolesFromContext(req); roles != nil {
+		t.Errorf("roles %v without a principal, want none", roles)
+	}
+
+	req = req.WithContext(WithPrincipal(req.Context(), &Principal{Roles: []Role{RoleViewer}}))
+	if !HasRole(RolesFromContext, req, []Role{RoleViewer}) {
+		t.Error("principal's viewer role not found")
+	}
+	if HasRole(RolesFromContext, req, []Role{RoleAdmin}) {
+		t.Error("viewer counted as admin")
+	}
+}
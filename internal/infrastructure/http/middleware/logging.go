@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/pkg/logger"
+)
+
+// RequestIDHeader carries the correlation ID of a request
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds caller-supplied IDs so they can't bloat every log line
+const maxRequestIDLength = 128
+
+// RequestID tags each request with a correlation ID and echoes it on the response
+// A caller-supplied X-Request-ID is reused so logs can be joined across services;
+// otherwise a new one is generated. The ID is stored in the request context,
+// where loggers built by the logger package pick it up.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), id)))
+	})
+}
+
+// LogRequests writes one log line per request with its route, status and duration
+// Server errors are logged at error level, everything else at info
+func LogRequests(log *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		level := slog.LevelInfo
+		if rec.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		log.LogAttrs(r.Context(), level, "http request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("route", r.Pattern),
+			slog.Int("status", rec.status),
+			slog.Int64("duration_ms", time.Since(start).Milliseconds()),
+		)
+	})
+}
@@ -1,77 +1,187 @@
 package router
 
 import (
+	"log/slog"
 	"net/http"
 
+	"fraud-detecction-system/internal/infrastructure/http/middleware"
 	"fraud-detecction-system/internal/interfaces/http/handler"
 )
 
 // Router holds all HTTP handlers
 type Router struct {
 	mux           *http.ServeMux
+	handler       http.Handler // mux, possibly wrapped in request metrics and logging
 	fraudHandler  *handler.FraudHandler
+	txHandler     *handler.TransactionHandler
 	healthHandler *handler.HealthHandler
+	authenticator middleware.Authenticator
+	roleSource    middleware.RoleSource
 }
 
+// Roles allowed on role-gated endpoints
+// Every mutating route, and every read exposing user or case data, states which of these it requires
+var (
+	ruleManagers = []middleware.Role{middleware.RoleAdmin, middleware.RoleRuleManager}
+	caseWorkers  = []middleware.Role{middleware.RoleAdmin, middleware.RoleInvestigator}
+	admins       = []middleware.Role{middleware.RoleAdmin}
+	listManagers = []middleware.Role{middleware.RoleAdmin, middleware.RoleRuleManager, middleware.RoleInvestigator}
+	writers      = []middleware.Role{middleware.RoleAdmin, middleware.RoleRuleManager, middleware.RoleInvestigator} // Every role but viewer
+	viewers      = []middleware.Role{middleware.RoleAdmin, middleware.RoleRuleManager, middleware.RoleInvestigator, middleware.RoleViewer}
+)
+
 // NewRouter creates a new router with all routes configured
 func NewRouter(
 	fraudHandler *handler.FraudHandler,
+	txHandler *handler.TransactionHandler,
 	healthHandler *handler.HealthHandler,
 ) *Router {
 	r := &Router{
 		mux:           http.NewServeMux(),
 		fraudHandler:  fraudHandler,
+		txHandler:     txHandler,
 		healthHandler: healthHandler,
 	}
+	r.handler = r.mux
 	r.setupRoutes()
 	return r
 }
 
+// SetAuthenticator sets the authenticator required by every endpoint but health checks
+// Without one, every endpoint is open. Roles are read from the authenticated
+// caller unless a role source has been set.
+func (r *Router) SetAuthenticator(auth middleware.Authenticator) {
+	r.authenticator = auth
+	if r.roleSource == nil {
+		r.roleSource = middleware.RolesFromContext
+	}
+}
+
+// SetRoleSource overrides where the caller's roles are read from
+func (r *Router) SetRoleSource(source middleware.RoleSource) {
+	r.roleSource = source
+}
+
+// EnableMetrics serves Prometheus metrics at path and records
+// duration and status code for every request
+func (r *Router) EnableMetrics(path string) {
+	r.mux.Handle("GET "+path, handler.MetricsHandler())
+	r.handler = middleware.RecordMetrics(r.handler)
+}
+
+// EnableRequestLogging writes a log line for every request
+func (r *Router) EnableRequestLogging(log *slog.Logger) {
+	r.handler = middleware.LogRequests(log, r.handler)
+}
+
+// protected requires an authenticated caller, holding one of roles if any are given,
+// before running the handler
+func (r *Router) protected(h http.HandlerFunc, roles ...middleware.Role) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var next http.Handler = h
+		if len(roles) > 0 {
+			next = middleware.RequireRole(r.roleSource, roles, next)
+		}
+		middleware.RequireAuth(r.authenticator, next).ServeHTTP(w, req)
+	})
+}
+
 func (r *Router) setupRoutes() {
-	// Health endpoints
+	// Health endpoints (always open)
 	r.mux.HandleFunc("GET /health", r.healthHandler.Health)
 	r.mux.HandleFunc("GET /ready", r.healthHandler.Ready)
 	r.mux.HandleFunc("GET /live", r.healthHandler.Live)
+	r.mux.HandleFunc("GET /status", r.healthHandler.Status)
+	r.mux.HandleFunc("GET /api/v1/fraud/status", r.healthHandler.Status)
 
 	// Fraud analysis endpoints
-	r.mux.HandleFunc("POST /api/v1/fraud/analyze", r.fraudHandler.AnalyzeTransaction)
-	r.mux.HandleFunc("POST /api/v1/fraud/analyze/batch", r.fraudHandler.BatchAnalyze)
+	// Every endpoint but health is wrapped in protected and requires credentials
+	// Analysis stores decisions, so it needs any role but viewer
+	r.mux.Handle("POST /api/v1/fraud/analyze", r.protected(r.fraudHandler.AnalyzeTransaction, writers...))
+	r.mux.Handle("POST /api/v1/fraud/analyze/batch", r.protected(r.fraudHandler.BatchAnalyze, writers...))
+
+	// v2 endpoints (versioned response envelope)
+	r.mux.Handle("POST /api/v2/fraud/analyze", r.protected(r.fraudHandler.AnalyzeTransaction, writers...))
+
+	// Transactions (stored and scored in one call)
+	r.mux.Handle("POST /api/v1/transactions", r.protected(r.txHandler.CreateTransaction, writers...))
+	r.mux.Handle("POST /api/v1/transactions/{id}/review", r.protected(r.txHandler.ReviewTransaction, caseWorkers...))
 
 	// Fraud decisions
-	r.mux.HandleFunc("GET /api/v1/fraud/decisions/{id}", r.fraudHandler.GetDecision)
-	r.mux.HandleFunc("GET /api/v1/fraud/transactions/{id}/decision", r.fraudHandler.GetDecisionByTransaction)
+	r.mux.Handle("GET /api/v1/fraud/decisions/{id}", r.protected(r.fraudHandler.GetDecision, viewers...))
+	r.mux.Handle("GET /api/v1/fraud/transactions/{id}/decision", r.protected(r.fraudHandler.GetDecisionByTransaction, viewers...))
+	r.mux.Handle("POST /api/v1/fraud/decisions/{id}/feedback", r.protected(r.fraudHandler.RecordDecisionFeedback, caseWorkers...))
+	r.mux.Handle("POST /api/v1/fraud/decisions/{id}/override", r.protected(r.fraudHandler.OverrideDecision, caseWorkers...))
+
+	// Any case worker may override a decision, but only admins may lift a block
+	r.fraudHandler.SetBlockOverrideCheck(func(req *http.Request) bool {
+		return middleware.HasRole(r.roleSource, req, admins)
+	})
+
+	// Scoring metrics
+	r.mux.Handle("GET /api/v1/fraud/metrics/accuracy", r.protected(r.fraudHandler.GetAccuracy, viewers...))
+	r.mux.Handle("POST /api/v1/fraud/thresholds/calibrate", r.protected(r.fraudHandler.CalibrateThresholds, ruleManagers...))
+
+	// ML model
+	r.mux.Handle("POST /api/v1/fraud/ml/model/reload", r.protected(r.fraudHandler.ReloadModel, admins...))
 
 	// User risk profiles
-	r.mux.HandleFunc("GET /api/v1/fraud/users/{id}/risk", r.fraudHandler.GetUserRiskProfile)
+	r.mux.Handle("GET /api/v1/fraud/users/{id}/risk", r.protected(r.fraudHandler.GetUserRiskProfile, viewers...))
+	r.mux.Handle("GET /api/v1/fraud/users/{id}/decisions", r.protected(r.fraudHandler.ListUserDecisions, viewers...))
+	r.mux.Handle("GET /api/v1/fraud/users/{id}/transactions", r.protected(r.txHandler.ListUserTransactions, viewers...))
+	r.mux.Handle("POST /api/v1/fraud/users/{id}/chargebacks", r.protected(r.fraudHandler.RecordChargeback, caseWorkers...))
 
 	// Fraud cases
-	r.mux.HandleFunc("GET /api/v1/fraud/cases", r.fraudHandler.ListCases)
-	r.mux.HandleFunc("GET /api/v1/fraud/cases/{id}", r.fraudHandler.GetCase)
-	r.mux.HandleFunc("PUT /api/v1/fraud/cases/{id}", r.fraudHandler.UpdateCase)
+	// Cases, notes and reports name users and investigators, so reading them needs credentials
+	r.mux.Handle("GET /api/v1/fraud/cases", r.protected(r.fraudHandler.ListCases, viewers...))
+	r.mux.Handle("GET /api/v1/fraud/cases/queue", r.protected(r.fraudHandler.ListCaseQueue, viewers...))
+	r.mux.Handle("GET /api/v1/fraud/cases/aging", r.protected(r.fraudHandler.ListAgingCases, viewers...))
+	r.mux.Handle("POST /api/v1/fraud/cases/queue/claim", r.protected(r.fraudHandler.ClaimNextCase, caseWorkers...))
+	r.mux.Handle("GET /api/v1/fraud/cases/{id}", r.protected(r.fraudHandler.GetCase, viewers...))
+	r.mux.Handle("PUT /api/v1/fraud/cases/{id}", r.protected(r.fraudHandler.UpdateCase, caseWorkers...))
+	r.mux.Handle("GET /api/v1/fraud/cases/{id}/notes", r.protected(r.fraudHandler.ListCaseNotes, viewers...))
+	r.mux.Handle("GET /api/v1/fraud/cases/{id}/report", r.protected(r.fraudHandler.GetCaseReport, viewers...))
 
 	// Fraud rules
-	r.mux.HandleFunc("GET /api/v1/fraud/rules", r.fraudHandler.ListRules)
-	r.mux.HandleFunc("POST /api/v1/fraud/rules", r.fraudHandler.CreateRule)
-	r.mux.HandleFunc("GET /api/v1/fraud/rules/{id}", r.fraudHandler.GetRule)
+	r.mux.Handle("GET /api/v1/fraud/rules", r.protected(r.fraudHandler.ListRules, viewers...))
+	r.mux.Handle("POST /api/v1/fraud/rules", r.protected(r.fraudHandler.CreateRule, ruleManagers...))
+	r.mux.Handle("POST /api/v1/fraud/rules/import", r.protected(r.fraudHandler.ImportRules, ruleManagers...))
+	r.mux.Handle("POST /api/v1/fraud/rules/test", r.protected(r.fraudHandler.TestRule, ruleManagers...))
+	r.mux.Handle("POST /api/v1/fraud/rules/backtest", r.protected(r.fraudHandler.BacktestRule, ruleManagers...))
+	r.mux.Handle("GET /api/v1/fraud/rules/{id}", r.protected(r.fraudHandler.GetRule, viewers...))
+	r.mux.Handle("PUT /api/v1/fraud/rules/{id}", r.protected(r.fraudHandler.UpdateRule, ruleManagers...))
+	r.mux.Handle("PATCH /api/v1/fraud/rules/{id}", r.protected(r.fraudHandler.UpdateRule, ruleManagers...))
+	r.mux.Handle("DELETE /api/v1/fraud/rules/{id}", r.protected(r.fraudHandler.DisableRule, ruleManagers...))
+	r.mux.Handle("POST /api/v1/fraud/rules/{id}/enable", r.protected(r.fraudHandler.EnableRule, ruleManagers...))
+	r.mux.Handle("GET /api/v1/fraud/rules/{id}/versions", r.protected(r.fraudHandler.ListRuleVersions, viewers...))
+	r.mux.Handle("GET /api/v1/fraud/rules/{id}/versions/{version}", r.protected(r.fraudHandler.GetRuleVersion, viewers...))
+
+	// Allowlist and denylist
+	// Listing is protected too, since entries name denylisted users, devices, cards and IPs
+	r.mux.Handle("GET /api/v1/fraud/lists", r.protected(r.fraudHandler.ListEntries, listManagers...))
+	r.mux.Handle("POST /api/v1/fraud/lists", r.protected(r.fraudHandler.AddListEntry, listManagers...))
+	r.mux.Handle("DELETE /api/v1/fraud/lists/{id}", r.protected(r.fraudHandler.RemoveListEntry, listManagers...))
 }
 
 // ServeHTTP implements http.Handler
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// Add CORS headers
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+middleware.RequestIDHeader)
+	w.Header().Set(handler.APIVersionHeader, handler.APIVersion(req))
 
 	if req.Method == "OPTIONS" {
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	r.mux.ServeHTTP(w, req)
+	r.handler.ServeHTTP(w, req)
 }
 
 // Handler returns the http.Handler
+// Every request is tagged with a correlation ID before it reaches the router
 func (r *Router) Handler() http.Handler {
-	return r
+	return middleware.RequestID(r)
 }
 
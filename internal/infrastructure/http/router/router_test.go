@@ -0,0 +1,153 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/infrastructure/http/middleware"
+	"fraud-detecction-system/internal/interfaces/http/handler"
+)
+
+const (
+	testKey    = "test-key"
+	roleHeader = "X-Test-Role"
+)
+
+var (
+	blockedID = uuid.New() // Decision whose outcome is block
+	reviewID  = uuid.New() // Decision whose outcome is review
+)
+
+// noRules reports no active rules; nothing else is implemented
+type noRules struct {
+	fraud.RuleRepository
+}
+
+func (noRules) ListActive(ctx context.Context) ([]*fraud.Rule, error) {
+	return nil, nil
+}
+
+// overridableDecisions holds one blocked and one reviewed decision and accepts any override
+type overridableDecisions struct {
+	fraud.DecisionRepository
+}
+
+func (overridableDecisions) GetByID(ctx context.Context, id uuid.UUID) (*fraud.FraudDecision, error) {
+	switch id {
+	case blockedID:
+		return fraud.NewFraudDecision(uuid.New(), uuid.New(), fraud.DecisionBlock, decimal.RequireFromString("0.9")), nil
+	case reviewID:
+		return fraud.NewFraudDecision(uuid.New(), uuid.New(), fraud.DecisionReview, decimal.RequireFromString("0.6")), nil
+	}
+	return nil, fraud.ErrDecisionNotFound
+}
+
+func (overridableDecisions) RecordOverride(ctx context.Context, override *fraud.DecisionOverride) error {
+	return nil
+}
+
+// staticModel accepts every reload
+type staticModel struct{}
+
+func (staticModel) ReloadModel(path, version string) error { return nil }
+func (staticModel) GetModelVersion() string                { return "v1" }
+
+// newTestRouter returns a router that accepts testKey and reads the caller's
+// roles from roleHeader
+func newTestRouter() http.Handler {
+	fraudHandler := handler.NewFraudHandler(nil, fraud.NewService(overridableDecisions{}, nil, noRules{}, nil, nil))
+	fraudHandler.SetModelReloader(staticModel{})
+
+	r := NewRouter(fraudHandler, nil, nil)
+	r.SetAuthenticator(middleware.NewStaticKeyAuthenticator([]middleware.APIKey{{Key: testKey, UserID: uuid.New()}}))
+	r.SetRoleSource(func(req *http.Request) []middleware.Role {
+		if role := req.Header.Get(roleHeader); role != "" {
+			return []middleware.Role{middleware.Role(role)}
+		}
+		return nil
+	})
+	return r.Handler()
+}
+
+func TestRouterAuthorization(t *testing.T) {
+	const (
+		overrideBody = `{"decision":"allow","reason":"verified with the cardholder"}`
+		reloadBody   = `{"path":"model.json","version":"v2"}`
+	)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		key        string
+		role       middleware.Role
+		wantStatus int
+	}{
+		// Reads are open to every role, but not to anonymous callers
+		{"read without key", http.MethodGet, "/api/v1/fraud/rules", "", "", "", http.StatusUnauthorized},
+		{"read with wrong key", http.MethodGet, "/api/v1/fraud/rules", "", "wrong", middleware.RoleViewer, http.StatusUnauthorized},
+		{"read without role", http.MethodGet, "/api/v1/fraud/rules", "", testKey, "", http.StatusForbidden},
+		{"read as viewer", http.MethodGet, "/api/v1/fraud/rules", "", testKey, middleware.RoleViewer, http.StatusOK},
+		{"decision without key", http.MethodGet, "/api/v1/fraud/decisions/" + reviewID.String(), "", "", "", http.StatusUnauthorized},
+		{"decision by transaction without key", http.MethodGet, "/api/v1/fraud/transactions/" + uuid.NewString() + "/decision", "", "", "", http.StatusUnauthorized},
+		{"accuracy without key", http.MethodGet, "/api/v1/fraud/metrics/accuracy", "", "", "", http.StatusUnauthorized},
+		{"user risk without key", http.MethodGet, "/api/v1/fraud/users/" + uuid.NewString() + "/risk", "", "", "", http.StatusUnauthorized},
+		{"rule without key", http.MethodGet, "/api/v1/fraud/rules/" + uuid.NewString(), "", "", "", http.StatusUnauthorized},
+		{"rule versions without key", http.MethodGet, "/api/v1/fraud/rules/" + uuid.NewString() + "/versions", "", "", "", http.StatusUnauthorized},
+		{"rule version without key", http.MethodGet, "/api/v1/fraud/rules/" + uuid.NewString() + "/versions/1", "", "", "", http.StatusUnauthorized},
+
+		// Analysis needs any role but viewer; the empty body is rejected once the caller is let through
+		{"analyze as viewer", http.MethodPost, "/api/v1/fraud/analyze", "", testKey, middleware.RoleViewer, http.StatusForbidden},
+		{"analyze as investigator", http.MethodPost, "/api/v1/fraud/analyze", "", testKey, middleware.RoleInvestigator, http.StatusBadRequest},
+
+		// Case work needs an investigator or admin
+		{"case update as viewer", http.MethodPut, "/api/v1/fraud/cases/" + uuid.NewString(), "", testKey, middleware.RoleViewer, http.StatusForbidden},
+		{"case update as rule manager", http.MethodPut, "/api/v1/fraud/cases/" + uuid.NewString(), "", testKey, middleware.RoleRuleManager, http.StatusForbidden},
+
+		{"override as viewer", http.MethodPost, "/api/v1/fraud/decisions/" + reviewID.String() + "/override", overrideBody, testKey, middleware.RoleViewer, http.StatusForbidden},
+		{"override as rule manager", http.MethodPost, "/api/v1/fraud/decisions/" + reviewID.String() + "/override", overrideBody, testKey, middleware.RoleRuleManager, http.StatusForbidden},
+		{"override as investigator", http.MethodPost, "/api/v1/fraud/decisions/" + reviewID.String() + "/override", overrideBody, testKey, middleware.RoleInvestigator, http.StatusCreated},
+
+		// Only admins may lift a block
+		{"block override as investigator", http.MethodPost, "/api/v1/fraud/decisions/" + blockedID.String() + "/override", overrideBody, testKey, middleware.RoleInvestigator, http.StatusForbidden},
+		{"block override as admin", http.MethodPost, "/api/v1/fraud/decisions/" + blockedID.String() + "/override", overrideBody, testKey, middleware.RoleAdmin, http.StatusCreated},
+
+		// Rule changes need a rule manager or admin
+		{"rule create as investigator", http.MethodPost, "/api/v1/fraud/rules", "", testKey, middleware.RoleInvestigator, http.StatusForbidden},
+		{"rule create as rule manager", http.MethodPost, "/api/v1/fraud/rules", "", testKey, middleware.RoleRuleManager, http.StatusBadRequest},
+
+		// Model reloads are admin only
+		{"reload without key", http.MethodPost, "/api/v1/fraud/ml/model/reload", reloadBody, "", "", http.StatusUnauthorized},
+		{"reload as rule manager", http.MethodPost, "/api/v1/fraud/ml/model/reload", reloadBody, testKey, middleware.RoleRuleManager, http.StatusForbidden},
+		{"reload as admin", http.MethodPost, "/api/v1/fraud/ml/model/reload", reloadBody, testKey, middleware.RoleAdmin, http.StatusOK},
+
+		// Health checks stay open
+		{"live without key", http.MethodGet, "/live", "", "", "", http.StatusOK},
+	}
+	h := newTestRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			if tt.role != "" {
+				req.Header.Set(roleHeader, string(tt.role))
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	kafkago "github.com/segmentio/kafka-go"
+
+	"fraud-detecction-system/internal/application/dto"
+	fraudapp "fraud-detecction-system/internal/application/fraud"
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/pkg/logger"
+	"fraud-detecction-system/internal/pkg/retry"
+)
+
+// ConsumerConfig holds transaction consumer configuration
+type ConsumerConfig struct {
+	Brokers         []string
+	Topic           string
+	GroupID         string
+	DeadLetterTopic string
+
+	// Analysis attempts per message before it is dead-lettered, with a doubling wait between them
+	MaxAttempts  int
+	RetryBackoff time.Duration
+}
+
+// TransactionConsumer reads transactions from Kafka and runs fraud detection on them
+type TransactionConsumer struct {
+	reader       *kafkago.Reader
+	deadLetter   *kafkago.Writer
+	detectFraud  *fraudapp.DetectFraudUseCase
+	analysis     retry.Backoff
+	retryBackoff time.Duration
+	done         chan struct{}
+	logger       *slog.Logger
+}
+
+// NewTransactionConsumer creates a new transaction consumer
+func NewTransactionConsumer(cfg ConsumerConfig, detectFraud *fraudapp.DetectFraudUseCase) *TransactionConsumer {
+	return &TransactionConsumer{
+		reader: kafkago.NewReader(kafkago.ReaderConfig{
+			Brokers: cfg.Brokers,
+			Topic:   cfg.Topic,
+			GroupID: cfg.GroupID,
+		}),
+		deadLetter: &kafkago.Writer{
+			Addr:         kafkago.TCP(cfg.Brokers...),
+			Topic:        cfg.DeadLetterTopic,
+			RequiredAcks: kafkago.RequireAll,
+		},
+		detectFraud:  detectFraud,
+		analysis:     retry.Backoff{Attempts: cfg.MaxAttempts, Initial: cfg.RetryBackoff},
+		retryBackoff: time.Second,
+		done:         make(chan struct{}),
+		logger:       slog.Default(),
+	}
+}
+
+// SetLogger sets the logger used to report retries and commit failures
+func (c *TransactionConsumer) SetLogger(log *slog.Logger) {
+	c.logger = log
+}
+
+// Run consumes messages until ctx is cancelled
+// Offsets are committed only after the fraud decision is persisted, so a
+// message interrupted by shutdown is redelivered on the next start
+func (c *TransactionConsumer) Run(ctx context.Context) error {
+	defer close(c.done)
+
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch message: %w", err)
+		}
+
+		// Correlate the logs of each message the way HTTP requests are
+		msgCtx := logger.WithRequestID(ctx, uuid.NewString())
+		if err := c.handleMessage(msgCtx, msg); err != nil {
+			// Shutting down before the message was handled; leave it uncommitted
+			return nil
+		}
+
+		if err := c.reader.CommitMessages(context.WithoutCancel(msgCtx), msg); err != nil {
+			c.logger.ErrorContext(msgCtx, "failed to commit offset",
+				slog.Int64("offset", msg.Offset),
+				slog.Int("partition", msg.Partition),
+				logger.Err(err),
+			)
+		}
+	}
+}
+
+// handleMessage processes one message, retrying analysis up to the configured attempts
+// Malformed messages, and messages whose analysis keeps failing, are published to
+// the dead-letter topic instead, so one bad message can't stall its partition
+func (c *TransactionConsumer) handleMessage(ctx context.Context, msg kafkago.Message) error {
+	input, err := decodeTransaction(msg)
+	if err != nil {
+		return c.publishDeadLetter(ctx, msg, err)
+	}
+
+	err = c.analysis.Do(ctx, func(context.Context) error {
+		// Let in-flight analysis finish even if shutdown has started
+		_, err := c.detectFraud.Execute(context.WithoutCancel(ctx), *input)
+		return err
+	}, func(attempt int, wait time.Duration, err error) {
+		c.logger.WarnContext(ctx, "fraud analysis failed, retrying",
+			slog.String(logger.KeyTransactionID, input.TransactionID.String()),
+			slog.Int("attempt", attempt),
+			slog.Duration("wait", wait),
+			logger.Err(err),
+		)
+	})
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	c.logger.ErrorContext(ctx, "fraud analysis failed, dead-lettering message",
+		slog.String(logger.KeyTransactionID, input.TransactionID.String()),
+		logger.Err(err),
+	)
+	return c.publishDeadLetter(ctx, msg, err)
+}
+
+// publishDeadLetter forwards a message that can't be analyzed to the dead-letter topic
+func (c *TransactionConsumer) publishDeadLetter(ctx context.Context, msg kafkago.Message, cause error) error {
+	dlq := kafkago.Message{
+		Key:   msg.Key,
+		Value: msg.Value,
+		Headers: append(msg.Headers,
+			kafkago.Header{Key: "error", Value: []byte(cause.Error())},
+			kafkago.Header{Key: "source_topic", Value: []byte(msg.Topic)},
+			kafkago.Header{Key: "source_offset", Value: []byte(fmt.Sprintf("%d", msg.Offset))},
+		),
+	}
+
+	for {
+		err := c.deadLetter.WriteMessages(context.WithoutCancel(ctx), dlq)
+		if err == nil {
+			return nil
+		}
+		c.logger.WarnContext(ctx, "failed to publish to dead-letter topic, retrying",
+			slog.Int64("offset", msg.Offset),
+			logger.Err(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.retryBackoff):
+		}
+	}
+}
+
+// Close waits for Run to return and releases the reader and writer
+func (c *TransactionConsumer) Close() error {
+	<-c.done
+
+	readerErr := c.reader.Close()
+	writerErr := c.deadLetter.Close()
+	return errors.Join(readerErr, writerErr)
+}
+
+// decodeTransaction deserializes and validates a transaction message
+func decodeTransaction(msg kafkago.Message) (*fraudapp.DetectFraudInput, error) {
+	var req dto.CreateTreansactionRequests
+	if err := json.Unmarshal(msg.Value, &req); err != nil {
+		return nil, fmt.Errorf("invalid message body: %w", err)
+	}
+
+	if req.ExternalID == "" {
+		return nil, errors.New("external_id is required")
+	}
+	if req.UserID == uuid.Nil {
+		return nil, errors.New("user_id is required")
+	}
+	if req.AccountID == uuid.Nil {
+		return nil, errors.New("account_id is required")
+	}
+	if !req.Amount.IsPositive() {
+		return nil, errors.New("amount must be positive")
+	}
+	if len(req.Currency) != 3 {
+		return nil, errors.New("currency must be a 3-letter code")
+	}
+
+	timestamp := msg.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	input := &fraudapp.DetectFraudInput{
+		// Derive the ID from the external ID so redelivered messages map to the same transaction
+		TransactionID: uuid.NewSHA1(uuid.NameSpaceOID, []byte(req.ExternalID)),
+		UserID:        req.UserID,
+		AccountID:     req.AccountID,
+		Amount:        req.Amount,
+		Currency:      req.Currency,
+		Timestamp:     timestamp,
+	}
+
+	// Convert optional fields
+	if req.Location != nil {
+		input.Location = &fraud.GeoLocation{
+			Latitude:  req.Location.Latitude,
+			Longitude: req.Location.Longitude,
+			Country:   req.Location.Country,
+			City:      req.Location.City,
+			Region:    req.Location.Region,
+			IPAddress: req.Location.IPAddress,
+		}
+	}
+
+	if req.Device != nil {
+		input.Device = &fraud.DeviceInfo{
+			DeviceID:        req.Device.DeviceID,
+			DeviceType:      req.Device.Type,
+			OS:              req.Device.OS,
+			Browser:         req.Device.Browser,
+			UserAgent:       req.Device.UserAgent,
+			IsTrustedDevice: req.Device.TrustedDevice,
+			LastSeenAt:      timestamp,
+		}
+	}
+
+	if req.Merchant != nil {
+		input.Merchant = &fraud.MerchantInfo{
+			MerchantID:       req.Merchant.MerchantID,
+			MerchantName:     req.Merchant.Name,
+			MerchantCategory: req.Merchant.Category,
+			Country:          req.Merchant.Country,
+		}
+	}
+
+	if req.Payment != nil {
+		input.Payment = &fraud.PaymentMethod{
+			Type:           req.Payment.Type,
+			Last4:          req.Payment.Last4,
+			BIN:            req.Payment.BIN,
+			Network:        req.Payment.CardType,
+			BankID:         req.Payment.BankID,
+			IssuingCountry: req.Payment.IssuingCountry,
+		}
+	}
+
+	return input, nil
+}
@@ -0,0 +1,73 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// AlertPublisher publishes fraud alerts to Kafka
+// Implements fraud.FraudAlertPublisher
+type AlertPublisher struct {
+	writer       *kafkago.Writer
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+// NewAlertPublisher creates a new Kafka alert publisher
+func NewAlertPublisher(brokers []string, topic string) *AlertPublisher {
+	return &AlertPublisher{
+		writer: &kafkago.Writer{
+			Addr:         kafkago.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafkago.Hash{}, // Keep a user's alerts ordered on one partition
+			RequiredAcks: kafkago.RequireAll,
+			MaxAttempts:  1, // Retries are handled by PublishAlert
+		},
+		maxAttempts:  3,
+		retryBackoff: 200 * time.Millisecond,
+	}
+}
+
+// PublishAlert writes the alert, retrying a bounded number of times
+func (p *AlertPublisher) PublishAlert(ctx context.Context, alert *fraud.FraudAlert) error {
+	value, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	msg := kafkago.Message{
+		Key:   []byte(alert.UserID.String()),
+		Value: value,
+		Headers: []kafkago.Header{
+			{Key: "schema_version", Value: []byte(strconv.Itoa(alert.SchemaVersion))},
+		},
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = p.writer.WriteMessages(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		if attempt >= p.maxAttempts {
+			return fmt.Errorf("failed to publish alert after %d attempts: %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.retryBackoff * time.Duration(attempt)):
+		}
+	}
+}
+
+// Close flushes and closes the underlying writer
+func (p *AlertPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,3 @@
+package kafka
+
+// Topic definitions for Kafka messaging
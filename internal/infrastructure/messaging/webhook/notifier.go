@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// Headers set on every delivery
+const (
+	SignatureHeader = "X-Webhook-Signature" // Hex HMAC-SHA256 of the timestamp and body under the shared secret
+	TimestampHeader = "X-Webhook-Timestamp" // Unix seconds when the attempt was signed
+	EventHeader     = "X-Webhook-Event"     // Event type, e.g. decision.blocked
+	EventIDHeader   = "X-Webhook-ID"        // Event ID, the same on every retry of one event
+)
+
+// Config holds webhook notifier configuration
+type Config struct {
+	URL          string
+	Secret       string
+	Events       []fraud.WebhookEventType
+	Timeout      time.Duration // Per attempt
+	MaxAttempts  int
+	RetryBackoff time.Duration // Wait before the first retry, doubled after each one
+}
+
+// Notifier POSTs signed fraud events to a client's webhook
+// Implements fraud.WebhookNotifier
+type Notifier struct {
+	client       *http.Client
+	url          string
+	secret       []byte
+	events       map[fraud.WebhookEventType]bool
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+// NewNotifier creates a webhook notifier
+func NewNotifier(cfg Config) *Notifier {
+	events := make(map[fraud.WebhookEventType]bool, len(cfg.Events))
+	for _, event := range cfg.Events {
+		events[event] = true
+	}
+	return &Notifier{
+		client:       &http.Client{Timeout: cfg.Timeout},
+		url:          cfg.URL,
+		secret:       []byte(cfg.Secret),
+		events:       events,
+		maxAttempts:  max(cfg.MaxAttempts, 1),
+		retryBackoff: cfg.RetryBackoff,
+	}
+}
+
+// Subscribed implements fraud.WebhookNotifier
+func (n *Notifier) Subscribed(eventType fraud.WebhookEventType) bool {
+	return n.events[eventType]
+}
+
+// Notify POSTs the event, retrying with exponential backoff
+// Network errors, 408, 429 and 5xx responses are retried. Any other
+// non-2xx response is permanent and is not retried.
+func (n *Notifier) Notify(ctx context.Context, event *fraud.WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+	backoff := n.retryBackoff
+	for attempt := 1; ; attempt++ {
+		err = n.deliver(ctx, event, body)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return err
+		}
+		if attempt >= n.maxAttempts {
+			return fmt.Errorf("failed to deliver webhook after %d attempts: %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// deliver makes one delivery attempt, signed with the current time
+func (n *Notifier) deliver(ctx context.Context, event *fraud.WebhookEvent, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(n.secret, timestamp, body))
+	req.Header.Set(EventHeader, string(event.Type))
+	req.Header.Set(EventIDHeader, event.ID.String())
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusRequestTimeout,
+		resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode >= 500:
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	default:
+		return &permanentError{err: fmt.Errorf("webhook returned %d", resp.StatusCode)}
+	}
+}
+
+// permanentError marks a delivery failure that retrying won't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return "permanent webhook failure: " + e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" for a webhook delivery
+// Receivers recompute it from TimestampHeader and the raw body, compare it with
+// SignatureHeader, and reject old timestamps so a captured delivery can't be replayed
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
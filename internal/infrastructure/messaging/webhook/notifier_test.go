@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// delivery is one request received by a test webhook
+type delivery struct {
+	header http.Header
+	body   []byte
+	at     time.Time
+}
+
+// newWebhook starts a server answering each delivery with the next of statuses,
+// repeating the last one, and returns it with the deliveries it received
+func newWebhook(t *testing.T, statuses ...int) (*httptest.Server, func() []delivery) {
+	t.Helper()
+	var mu sync.Mutex
+	var received []delivery
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		status := statuses[min(len(received), len(statuses)-1)]
+		received = append(received, delivery{header: r.Header.Clone(), body: body, at: time.Now()})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []delivery {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]delivery(nil), received...)
+	}
+}
+
+// testEvent is a blocked-decision event with a fresh ID
+func testEvent() *fraud.WebhookEvent {
+	return &fraud.WebhookEvent{
+		ID:         uuid.New(),
+		Type:       fraud.EventDecisionBlocked,
+		OccurredAt: time.Now(),
+		Data:       map[string]string{"decision": "block"},
+	}
+}
+
+func TestNotifyRetries(t *testing.T) {
+	const backoff = 10 * time.Millisecond
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantErr      bool
+		wantAttempts int
+	}{
+		{"success", []int{http.StatusOK}, false, 1},
+		{"accepted", []int{http.StatusAccepted}, false, 1},
+		{"server error then success", []int{http.StatusInternalServerError, http.StatusNoContent}, false, 2},
+		{"request timeout retried", []int{http.StatusRequestTimeout, http.StatusOK}, false, 2},
+		{"rate limited retried", []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK}, false, 3},
+		{"server errors exhaust attempts", []int{http.StatusBadGateway}, true, 3},
+		{"bad request not retried", []int{http.StatusBadRequest}, true, 1},
+		{"not found not retried", []int{http.StatusNotFound}, true, 1},
+		{"not modified not retried", []int{http.StatusNotModified}, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, received := newWebhook(t, tt.statuses...)
+			n := NewNotifier(Config{URL: server.URL, Secret: "secret", Timeout: time.Second, MaxAttempts: 3, RetryBackoff: backoff})
+
+			err := n.Notify(context.Background(), testEvent())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error %v, want error %t", err, tt.wantErr)
+			}
+
+			deliveries := received()
+			if len(deliveries) != tt.wantAttempts {
+				t.Fatalf("%d attempts, want %d", len(deliveries), tt.wantAttempts)
+			}
+			// Each retry waits twice as long as the one before
+			for i := 1; i < len(deliveries); i++ {
+				if wait, want := deliveries[i].at.Sub(deliveries[i-1].at), backoff<<(i-1); wait < want {
+					t.Errorf("retry %d after %s, want at least %s", i, wait, want)
+				}
+				if id := deliveries[i].header.Get(EventIDHeader); id != deliveries[0].header.Get(EventIDHeader) {
+					t.Errorf("retry %d has event ID %s, want the first attempt's %s", i, id, deliveries[0].header.Get(EventIDHeader))
+				}
+			}
+		})
+	}
+}
+
+func TestNotifySignsTimestampAndBody(t *testing.T) {
+	server, received := newWebhook(t, http.StatusOK)
+	event := testEvent()
+	n := NewNotifier(Config{URL: server.URL, Secret: "secret", Timeout: time.Second})
+
+	before := time.Now().Unix()
+	if err := n.Notify(context.Background(), event); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	d := received()[0]
+
+	timestamp := d.header.Get(TimestampHeader)
+	if ts, err := strconv.ParseInt(timestamp, 10, 64); err != nil || ts < before || ts > time.Now().Unix() {
+		t.Errorf("timestamp %q, want the time of delivery", timestamp)
+	}
+	if got, want := d.header.Get(SignatureHeader), Sign([]byte("secret"), timestamp, d.body); got != want {
+		t.Errorf("signature %s, want %s over the timestamp and body", got, want)
+	}
+	if d.header.Get(EventHeader) != string(event.Type) || d.header.Get(EventIDHeader) != event.ID.String() {
+		t.Errorf("event headers %s %s, want %s %s", d.header.Get(EventHeader), d.header.Get(EventIDHeader), event.Type, event.ID)
+	}
+
+	// The signature covers both parts, so neither can be swapped out
+	if Sign([]byte("secret"), timestamp, append(d.body, ' ')) == d.header.Get(SignatureHeader) {
+		t.Error("signature unchanged by a different body")
+	}
+	if Sign([]byte("secret"), "0", d.body) == d.header.Get(SignatureHeader) {
+		t.Error("signature unchanged by a different timestamp")
+	}
+	if Sign([]byte("other"), timestamp, d.body) == d.header.Get(SignatureHeader) {
+		t.Error("signature unchanged by a different secret")
+	}
+}
+
+func TestNotifyStopsWhenContextDone(t *testing.T) {
+	server, received := newWebhook(t, http.StatusServiceUnavailable)
+	n := NewNotifier(Config{URL: server.URL, Secret: "secret", Timeout: time.Second, MaxAttempts: 5, RetryBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := n.Notify(ctx, testEvent()); err != context.DeadlineExceeded {
+		t.Fatalf("error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if attempts := len(received()); attempts != 1 {
+		t.Errorf("%d attempts, want 1 before the context ended", attempts)
+	}
+}
@@ -93,23 +93,9 @@ func (e *FeatureExtractor) Extract(ctx context.Context, evalCtx *fraud.RuleEvalu
 		f.IsNightTime = 1.0
 	}
 
-	// Velocity features from recent transactions
-	hourAgo := evalCtx.Timestamp.Add(-1 * time.Hour)
-	dayAgo := evalCtx.Timestamp.Add(-24 * time.Hour)
-
-	var txCountHour, txCountDay int
-	var txAmountHour, txAmountDay decimal.Decimal
-
-	for _, tx := range evalCtx.RecentTransactions {
-		if tx.Timestamp.After(hourAgo) {
-			txCountHour++
-			txAmountHour = txAmountHour.Add(tx.Amount)
-		}
-		if tx.Timestamp.After(dayAgo) {
-			txCountDay++
-			txAmountDay = txAmountDay.Add(tx.Amount)
-		}
-	}
+	// Velocity features from recent transactions, counted the way velocity rules count them
+	txCountHour, txAmountHour := recentActivity(evalCtx, time.Hour)
+	txCountDay, txAmountDay := recentActivity(evalCtx, 24*time.Hour)
 
 	f.TxCountLastHour = txCountHour
 	f.TxCountLastDay = txCountDay
@@ -122,13 +108,14 @@ func (e *FeatureExtractor) Extract(ctx context.Context, evalCtx *fraud.RuleEvalu
 			f.IsBlockedCountry = 1.0
 		}
 
-		// Check if location matches payment issuing country
-		if evalCtx.Payment != nil && evalCtx.Location.Country != evalCtx.Payment.IssuingCountry {
+		// Same test as issuer country mismatch rules, so the two always agree
+		if fraud.IsCrossBorder(evalCtx.Location, evalCtx.Payment) {
 			f.IsCrossBorder = 1.0
 		}
 
 		// Distance from last transaction
-		if len(evalCtx.RecentTransactions) > 0 && evalCtx.RecentTransactions[0].Location != nil {
+		if len(evalCtx.RecentTransactions) > 0 && evalCtx.Location.HasCoordinates() &&
+			evalCtx.RecentTransactions[0].Location.HasCoordinates() {
 			lastLoc := evalCtx.RecentTransactions[0].Location
 			f.DistanceFromLast = haversine(
 				evalCtx.Location.Latitude, evalCtx.Location.Longitude,
@@ -144,15 +131,32 @@ func (e *FeatureExtractor) Extract(ctx context.Context, evalCtx *fraud.RuleEvalu
 			f.IsKnownDevice = 1.0
 		}
 
-		// Check device history
-		for _, d := range evalCtx.DeviceHistory {
-			if d.DeviceID == evalCtx.Device.DeviceID {
+		// Prefer the device history enrichment looked up for the rules
+		known := evalCtx.Known
+		if known != nil && known.Device != nil {
+			if *known.Device {
 				f.IsKnownDevice = 1.0
-				break
+			}
+		} else {
+			for _, d := range evalCtx.DeviceHistory {
+				if d.DeviceID == evalCtx.Device.DeviceID {
+					f.IsKnownDevice = 1.0
+					break
+				}
 			}
 		}
 
 		f.DeviceCount = len(evalCtx.DeviceHistory)
+		if known != nil && known.DeviceCount != nil {
+			f.DeviceCount = int(*known.DeviceCount)
+		}
+	}
+
+	// A known location is a country the user has transacted from, as in training;
+	// it comes from the history enrichment, with the profile's typical countries as the fallback
+	known := evalCtx.Known
+	if known != nil && known.Country != nil && *known.Country {
+		f.IsKnownLocation = 1.0
 	}
 
 	// User features
@@ -167,6 +171,7 @@ func (e *FeatureExtractor) Extract(ctx context.Context, evalCtx *fraud.RuleEvalu
 		}
 
 		// Check if merchant is known
+		// The model was trained on the profile's typical merchants, not merchant history
 		if evalCtx.Merchant != nil {
 			for _, m := range evalCtx.UserProfile.TypicalMerchants {
 				if m == evalCtx.Merchant.MerchantID {
@@ -176,8 +181,8 @@ func (e *FeatureExtractor) Extract(ctx context.Context, evalCtx *fraud.RuleEvalu
 			}
 		}
 
-		// Check if location is typical
-		if evalCtx.Location != nil {
+		// Check if location is typical, when enrichment didn't look it up
+		if evalCtx.Location != nil && (known == nil || known.Country == nil) {
 			for _, loc := range evalCtx.UserProfile.TypicalLocations {
 				if loc == evalCtx.Location.Country {
 					f.IsKnownLocation = 1.0
@@ -195,6 +200,25 @@ func (e *FeatureExtractor) Extract(ctx context.Context, evalCtx *fraud.RuleEvalu
 	return f
 }
 
+// recentActivity counts and sums the user's transactions within window
+// It reads the history the same way velocity rules do, falling back to counting
+// every loaded transaction since window before the transaction when it's too short
+func recentActivity(evalCtx *fraud.RuleEvaluationContext, window time.Duration) (int, decimal.Decimal) {
+	if count, total, ok := evalCtx.RecentActivity(window); ok {
+		return int(count), total
+	}
+
+	since := evalCtx.Timestamp.Add(-window)
+	count, total := 0, decimal.Zero
+	for _, tx := range evalCtx.RecentTransactions {
+		if tx.Timestamp.After(since) {
+			count++
+			total = total.Add(tx.Amount)
+		}
+	}
+	return count, total
+}
+
 // ToVector converts features to a float slice for ML model input
 func (f *Features) ToVector() []float64 {
 	return []float64{
@@ -242,6 +266,7 @@ func haversine(lat1, lon1, lat2, lon2 float64) float64 {
 
 	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
 		math.Cos(lat1Rad)*math.Cos(lat2Rad)*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+	a = math.Min(a, 1) // Rounding can push a past 1 for near-antipodal points
 	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
 
 	return earthRadius * c
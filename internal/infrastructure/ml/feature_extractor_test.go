@@ -0,0 +1,55 @@
+package ml
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+func TestExtractCountsRecentTransactionsByTimestamp(t *testing.T) {
+	now := time.Now()
+	var history []fraud.TransactionSummary
+	for _, age := range []time.Duration{10 * time.Minute, 40 * time.Minute, 3 * time.Hour, 20 * time.Hour, 30 * time.Hour} {
+		history = append(history, fraud.TransactionSummary{
+			ID:        uuid.New(),
+			Amount:    decimal.NewFromInt(10),
+			Timestamp: now.Add(-age),
+		})
+	}
+
+	tests := []struct {
+		name   string
+		window time.Duration // How much history was loaded onto the context
+	}{
+		{"loaded window covers a day", 48 * time.Hour},
+		{"no loaded window", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evalCtx := &fraud.RuleEvaluationContext{
+				TransactionID:      uuid.New(),
+				UserID:             uuid.New(),
+				Amount:             decimal.NewFromInt(50),
+				Currency:           "USD",
+				Timestamp:          now,
+				RecentTransactions: history,
+				RecentWindow:       tt.window,
+			}
+			f := NewFeatureExtractor(decimal.NewFromInt(1000), nil).Extract(context.Background(), evalCtx)
+			if f.TxCountLastHour != 2 {
+				t.Errorf("hour count %d, want 2", f.TxCountLastHour)
+			}
+			if f.TxCountLastDay != 4 {
+				t.Errorf("day count %d, want 4", f.TxCountLastDay)
+			}
+			if f.TxAmountLastDay != 40 {
+				t.Errorf("day amount %v, want 40", f.TxAmountLastDay)
+			}
+		})
+	}
+}
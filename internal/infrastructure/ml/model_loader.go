@@ -1,6 +1,96 @@
 package ml
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
 // ModelLoader handles loading ML models from storage
-// In production, this would load actual trained models
-// Currently the predictor uses built-in heuristic weights
+// Trained gradient-boosted models are exported to ONNX and run through
+// onnxruntime (see model_onnx.go); builds without cgo cannot load them
+
+// ErrONNXUnavailable is returned when the binary was built without ONNX runtime support
+var ErrONNXUnavailable = errors.New("ONNX runtime support requires a cgo build")
+
+// model is a loaded inference model
+type model interface {
+	// predict returns the fraud probability for a feature vector
+	predict(vector []float64) (float64, error)
+
+	// close releases the model's resources
+	close() error
+}
+
+// featureCount is the length of the vector produced by Features.ToVector
+func featureCount() int {
+	return len((&Features{}).ToVector())
+}
+
+// validateVector checks a feature vector against the size a model expects
+func validateVector(vector []float64, expected int) error {
+	if len(vector) != expected {
+		return fmt.Errorf("feature vector has %d features, model expects %d", len(vector), expected)
+	}
+	return nil
+}
+
+// modelFileExists reports whether a model file is present at path
+func modelFileExists(path string) bool {
+	if path == "" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// resolveModelPath resolves a requested model path within dir
+// Relative paths are taken from dir. A path outside dir, including through a
+// symlink or "..", is rejected so a reload can't read arbitrary files.
+func resolveModelPath(dir, path string) (string, error) {
+	if dir == "" {
+		return "", errors.New("model reloading has no model directory configured")
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return "", fmt.Errorf("model directory %s: %w", dir, err)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("model file %s not found", path)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("model file %s is outside the model directory", path)
+	}
+	return resolved, nil
+}
+
+// isONNXModelPath reports whether path names an ONNX model rather than a weights file
+func isONNXModelPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".onnx")
+}
+
+// loadModelWeights reads heuristic model weights from a JSON array, one weight per feature
+func loadModelWeights(path string) ([]float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
 
+	var weights []float64
+	if err := json.Unmarshal(data, &weights); err != nil {
+		return nil, fmt.Errorf("invalid weights file: %w", err)
+	}
+	return weights, nil
+}
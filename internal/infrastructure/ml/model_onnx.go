@@ -0,0 +1,134 @@
+//go:build cgo
+
+package ml
+
+import (
+	"fmt"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+var ortInitMu sync.Mutex
+
+// onnxModel runs a gradient-boosted model exported to ONNX
+// Expects a single float32 input of shape [batch, features] and a float32
+// probability output of shape [batch, classes] (ZipMap disabled on export)
+type onnxModel struct {
+	session      *ort.DynamicAdvancedSession
+	inputName    string
+	outputName   string
+	featureCount int
+}
+
+// loadONNXModel opens an ONNX model and validates its input shape against the feature vector
+func loadONNXModel(path, runtimeLibraryPath string, features int) (model, error) {
+	if err := initONNXRuntime(runtimeLibraryPath); err != nil {
+		return nil, err
+	}
+
+	inputs, outputs, err := ort.GetInputOutputInfo(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model info: %w", err)
+	}
+
+	if len(inputs) != 1 {
+		return nil, fmt.Errorf("model has %d inputs, expected 1", len(inputs))
+	}
+	input := inputs[0]
+	if input.OrtValueType != ort.ONNXTypeTensor || input.DataType != ort.TensorElementDataTypeFloat {
+		return nil, fmt.Errorf("model input %q must be a float32 tensor, got %s", input.Name, input.String())
+	}
+	if len(input.Dimensions) != 2 {
+		return nil, fmt.Errorf("model input %q has shape %v, expected [batch, features]", input.Name, input.Dimensions)
+	}
+	// A negative dimension is dynamic and is checked per prediction instead
+	if dim := input.Dimensions[1]; dim > 0 && int(dim) != features {
+		return nil, fmt.Errorf("model input %q expects %d features, feature extractor produces %d", input.Name, dim, features)
+	}
+
+	outputName := ""
+	for _, output := range outputs {
+		if output.OrtValueType == ort.ONNXTypeTensor && output.DataType == ort.TensorElementDataTypeFloat && len(output.Dimensions) == 2 {
+			outputName = output.Name
+			break
+		}
+	}
+	if outputName == "" {
+		return nil, fmt.Errorf("model has no float32 [batch, classes] probability output")
+	}
+
+	session, err := ort.NewDynamicAdvancedSession(path, []string{input.Name}, []string{outputName}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create model session: %w", err)
+	}
+
+	return &onnxModel{
+		session:      session,
+		inputName:    input.Name,
+		outputName:   outputName,
+		featureCount: features,
+	}, nil
+}
+
+func (m *onnxModel) predict(vector []float64) (float64, error) {
+	if err := validateVector(vector, m.featureCount); err != nil {
+		return 0, err
+	}
+
+	data := make([]float32, len(vector))
+	for i, v := range vector {
+		data[i] = float32(v)
+	}
+
+	input, err := ort.NewTensor(ort.NewShape(1, int64(len(data))), data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create input tensor: %w", err)
+	}
+	defer input.Destroy()
+
+	// A nil output is allocated by the session
+	outputs := []ort.Value{nil}
+	if err := m.session.Run([]ort.Value{input}, outputs); err != nil {
+		return 0, fmt.Errorf("model inference failed: %w", err)
+	}
+	defer outputs[0].Destroy()
+
+	probabilities, ok := outputs[0].(*ort.Tensor[float32])
+	if !ok {
+		return 0, fmt.Errorf("model output %q is not a float32 tensor", m.outputName)
+	}
+
+	values := probabilities.GetData()
+	switch len(values) {
+	case 0:
+		return 0, fmt.Errorf("model output %q is empty", m.outputName)
+	case 1:
+		// Single-column output is already the fraud probability
+		return float64(values[0]), nil
+	default:
+		// [P(legit), P(fraud), ...] - the fraud class is index 1
+		return float64(values[1]), nil
+	}
+}
+
+func (m *onnxModel) close() error {
+	return m.session.Destroy()
+}
+
+// initONNXRuntime initializes the shared onnxruntime environment once
+func initONNXRuntime(runtimeLibraryPath string) error {
+	ortInitMu.Lock()
+	defer ortInitMu.Unlock()
+
+	if ort.IsInitialized() {
+		return nil
+	}
+	if runtimeLibraryPath != "" {
+		ort.SetSharedLibraryPath(runtimeLibraryPath)
+	}
+	if err := ort.InitializeEnvironment(); err != nil {
+		return fmt.Errorf("failed to initialize onnxruntime: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !cgo
+
+package ml
+
+// loadONNXModel is unavailable without cgo
+func loadONNXModel(path, runtimeLibraryPath string, features int) (model, error) {
+	return nil, ErrONNXUnavailable
+}
@@ -2,6 +2,8 @@ package ml
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/shopspring/decimal"
@@ -16,8 +18,16 @@ type Predictor struct {
 	enabled          bool
 	mu               sync.RWMutex
 
-	// In a real implementation, this would be a loaded ML model
-	// For now, we use a rule-based heuristic that mimics ML behavior
+	// Loaded ONNX model; nil falls back to the heuristic weights below
+	model model
+
+	// onnxruntime library used by LoadModel, reused when ReloadModel loads an ONNX file
+	runtimeLibraryPath string
+
+	// Directory ReloadModel may load models from; empty disables reloading
+	modelDir string
+
+	// Heuristic weights that mimic a trained model when no model file is loaded
 	weights []float64
 }
 
@@ -28,11 +38,126 @@ func NewPredictor(extractor *FeatureExtractor, modelVersion string, enabled bool
 		modelVersion:     modelVersion,
 		enabled:          enabled,
 		// These weights simulate a trained model
-		// In production, load actual model weights
+		// Used until LoadModel loads a real model file
 		weights: defaultModelWeights(),
 	}
 }
 
+// LoadModel loads an ONNX model from modelPath, replacing any loaded model
+// A missing model file is not an error - the predictor keeps using its
+// heuristic weights. runtimeLibraryPath optionally points at the onnxruntime
+// shared library.
+func (p *Predictor) LoadModel(modelPath, runtimeLibraryPath string) error {
+	p.mu.Lock()
+	p.runtimeLibraryPath = runtimeLibraryPath
+	p.mu.Unlock()
+
+	if !modelFileExists(modelPath) {
+		return nil
+	}
+
+	loaded, err := loadONNXModel(modelPath, runtimeLibraryPath, featureCount())
+	if err != nil {
+		return fmt.Errorf("failed to load model %s: %w", modelPath, err)
+	}
+
+	p.mu.Lock()
+	previous := p.model
+	p.model = loaded
+	p.mu.Unlock()
+
+	if previous != nil {
+		previous.close()
+	}
+	return nil
+}
+
+// SetModelDir sets the directory ReloadModel loads models from
+func (p *Predictor) SetModelDir(dir string) {
+	p.mu.Lock()
+	p.modelDir = dir
+	p.mu.Unlock()
+}
+
+// ReloadModel replaces the model and its version at runtime
+// path is resolved within the model directory and may not leave it. A path
+// ending in .onnx loads an ONNX model; any other file is read as a JSON array of
+// heuristic weights. Either way the model must take exactly one input per feature.
+// The swap waits for in-flight predictions, so each sees either the old model and
+// version or the new ones, and the old model is only released once unused. On
+// error nothing changes.
+func (p *Predictor) ReloadModel(path, version string) error {
+	if version == "" {
+		return errors.New("model version is required")
+	}
+	p.mu.RLock()
+	modelDir := p.modelDir
+	p.mu.RUnlock()
+
+	path, err := resolveModelPath(modelDir, path)
+	if err != nil {
+		return err
+	}
+	if !modelFileExists(path) {
+		return fmt.Errorf("model file %s not found", path)
+	}
+
+	var loaded model
+	var weights []float64
+	if isONNXModelPath(path) {
+		p.mu.RLock()
+		runtimeLibraryPath := p.runtimeLibraryPath
+		p.mu.RUnlock()
+
+		loaded, err = loadONNXModel(path, runtimeLibraryPath, featureCount())
+		if err != nil {
+			return fmt.Errorf("failed to load model %s: %w", path, err)
+		}
+	} else {
+		weights, err = loadModelWeights(path)
+		if err != nil {
+			return fmt.Errorf("failed to load model weights %s: %w", path, err)
+		}
+		if len(weights) != featureCount() {
+			return fmt.Errorf("model %s has %d weights, feature vector has %d features", path, len(weights), featureCount())
+		}
+	}
+
+	p.mu.Lock()
+	previous := p.model
+	p.model = loaded
+	if weights != nil {
+		p.weights = weights
+	}
+	p.modelVersion = version
+	p.mu.Unlock()
+
+	if previous != nil {
+		previous.close()
+	}
+	return nil
+}
+
+// HasModel returns whether a real model is loaded
+func (p *Predictor) HasModel() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.model != nil
+}
+
+// Close releases the loaded model, if any
+func (p *Predictor) Close() error {
+	p.mu.Lock()
+	loaded := p.model
+	p.model = nil
+	p.mu.Unlock()
+
+	if loaded == nil {
+		return nil
+	}
+	return loaded.close()
+}
+
 // Predict returns a fraud probability score using ML
 func (p *Predictor) Predict(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) (*PredictionResult, error) {
 	p.mu.RLock()
@@ -53,9 +178,23 @@ func (p *Predictor) Predict(ctx context.Context, evalCtx *fraud.RuleEvaluationCo
 	features := p.featureExtractor.Extract(ctx, evalCtx)
 	vector := features.ToVector()
 
-	// Calculate score using linear combination (simplified ML)
-	// In production, this would be a proper ML model inference
-	score := p.calculateScore(vector)
+	// Run the loaded model, or the heuristic weights when none is loaded
+	// The read lock is held through inference so a reload can't release the model mid-prediction
+	var score float64
+	var topFeatures map[string]float64
+	var err error
+	p.mu.RLock()
+	version = p.modelVersion
+	if p.model != nil {
+		score, err = p.model.predict(vector)
+	} else {
+		score, err = calculateScore(vector, p.weights)
+		topFeatures = getTopContributors(vector, p.weights)
+	}
+	p.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
 
 	// Calculate confidence based on feature completeness
 	confidence := p.calculateConfidence(features)
@@ -66,7 +205,7 @@ func (p *Predictor) Predict(ctx context.Context, evalCtx *fraud.RuleEvaluationCo
 		ModelVersion:     version,
 		Enabled:          true,
 		FeatureVector:    vector,
-		TopFeatures:      p.getTopContributors(vector),
+		TopFeatures:      topFeatures,
 	}, nil
 }
 
@@ -101,19 +240,19 @@ func (p *Predictor) GetModelVersion() string {
 	return p.modelVersion
 }
 
-func (p *Predictor) calculateScore(vector []float64) float64 {
-	if len(vector) != len(p.weights) {
-		return 0.0
+func calculateScore(vector, weights []float64) (float64, error) {
+	if err := validateVector(vector, len(weights)); err != nil {
+		return 0, err
 	}
 
 	// Linear combination with sigmoid activation
 	sum := 0.0
 	for i, v := range vector {
-		sum += v * p.weights[i]
+		sum += v * weights[i]
 	}
 
 	// Sigmoid to get probability
-	return sigmoid(sum)
+	return sigmoid(sum), nil
 }
 
 func (p *Predictor) calculateConfidence(features *Features) float64 {
@@ -144,7 +283,7 @@ func (p *Predictor) calculateConfidence(features *Features) float64 {
 	return confidence
 }
 
-func (p *Predictor) getTopContributors(vector []float64) map[string]float64 {
+func getTopContributors(vector, weights []float64) map[string]float64 {
 	featureNames := []string{
 		"amount", "amount_log", "is_high_value",
 		"hour_of_day", "day_of_week", "is_weekend", "is_night_time",
@@ -157,8 +296,8 @@ func (p *Predictor) getTopContributors(vector []float64) map[string]float64 {
 
 	contributions := make(map[string]float64)
 	for i, v := range vector {
-		if i < len(featureNames) && i < len(p.weights) {
-			contrib := v * p.weights[i]
+		if i < len(featureNames) && i < len(weights) {
+			contrib := v * weights[i]
 			if contrib > 0.05 || contrib < -0.05 { // Only significant contributions
 				contributions[featureNames[i]] = contrib
 			}
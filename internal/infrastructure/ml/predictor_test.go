@@ -0,0 +1,68 @@
+package ml
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// writeWeights writes n weights of value to a JSON weights file in dir
+func writeWeights(t *testing.T, dir, name string, n int, value float64) string {
+	t.Helper()
+	data, err := json.Marshal(slices.Repeat([]float64{value}, n))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return path
+}
+
+func TestReloadModel(t *testing.T) {
+	dir := t.TempDir()
+	writeWeights(t, dir, "good.json", featureCount(), 0.01)
+	writeWeights(t, dir, "short.json", featureCount()-1, 0.01)
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	outside := writeWeights(t, t.TempDir(), "outside.json", featureCount(), 0.01)
+
+	tests := []struct {
+		name        string
+		path        string
+		version     string
+		wantErr     bool
+		wantVersion string
+	}{
+		{"matching weights", "good.json", "v2", false, "v2"},
+		{"weights of the wrong length", "short.json", "v2", true, "v1"},
+		{"invalid weights file", "broken.json", "v2", true, "v1"},
+		{"missing file", "missing.json", "v2", true, "v1"},
+		{"outside the model directory", outside, "v2", true, "v1"},
+		{"no version", "good.json", "", true, "v1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPredictor(NewFeatureExtractor(decimal.NewFromInt(1000), nil), "v1", true)
+			p.SetModelDir(dir)
+			before := slices.Clone(p.weights)
+
+			err := p.ReloadModel(tt.path, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reload error %v, want error %t", err, tt.wantErr)
+			}
+			if got := p.GetModelVersion(); got != tt.wantVersion {
+				t.Errorf("version %s, want %s", got, tt.wantVersion)
+			}
+			if changed := !slices.Equal(p.weights, before); changed == tt.wantErr {
+				t.Errorf("weights changed %t, want %t", changed, !tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package rules
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// behavioralScore evaluates the default behavioral rule for an account of age at midday
+func behavioralScore(t *testing.T, age time.Duration) (*fraud.RuleResult, decimal.Decimal) {
+	t.Helper()
+	rule := fraud.NewRule("behavioral", "", fraud.RuleTypeBehavioral, fraud.SeverityMedium, fraud.ActionReview, uuid.Nil)
+	rule.Config = map[string]interface{}{}
+
+	now := time.Now()
+	evalCtx := &fraud.RuleEvaluationContext{
+		TransactionID: uuid.New(),
+		UserID:        uuid.New(),
+		Amount:        decimal.NewFromInt(50),
+		Currency:      "USD",
+		Timestamp:     time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location()),
+		UserProfile:   &fraud.UserProfile{AccountAge: age, LastActivityAt: now},
+	}
+
+	result, err := NewEngine(nil, nil, nil, nil, nil).evaluateBehavioralRule(context.Background(), rule, evalCtx)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	ageRisk, err := decimal.NewFromString(result.Metadata["age_risk"].(string))
+	if err != nil {
+		t.Fatalf("age_risk metadata %v: %v", result.Metadata["age_risk"], err)
+	}
+	return result, ageRisk
+}
+
+func TestBehavioralAccountAgeDecays(t *testing.T) {
+	hour, hourRisk := behavioralScore(t, time.Hour)
+	twoDays, twoDaysRisk := behavioralScore(t, 48*time.Hour)
+	year, yearRisk := behavioralScore(t, 365*24*time.Hour)
+
+	if !hourRisk.GreaterThan(twoDaysRisk) || !twoDaysRisk.GreaterThan(yearRisk) {
+		t.Errorf("age risk 1h %s, 2d %s, 1y %s; want strictly decreasing", hourRisk, twoDaysRisk, yearRisk)
+	}
+	if !hour.Fired || !twoDays.Fired || year.Fired {
+		t.Errorf("fired 1h %t, 2d %t, 1y %t; want the year-old account to pass", hour.Fired, twoDays.Fired, year.Fired)
+	}
+	if !hour.Score.GreaterThan(twoDays.Score) || !twoDays.Score.GreaterThan(year.Score) {
+		t.Errorf("score 1h %s, 2d %s, 1y %s; want the 2-day-old account in between", hour.Score, twoDays.Score, year.Score)
+	}
+}
+
+func TestAccountAgeRisk(t *testing.T) {
+	config := parseBehavioralConfig(map[string]interface{}{
+		"new_account_window_hours": float64(24),
+		"age_half_life_hours":      float64(48),
+		"new_account_score":        0.6,
+	})
+
+	tests := []struct {
+		name     string
+		ageHours float64
+		config   fraud.BehavioralRuleConfig
+		want     string
+	}{
+		{"inside the new-account window", 12, config, "0.6"},
+		{"one half-life past the window", 72, config, "0.3"},
+		{"two half-lives past the window", 120, config, "0.15"},
+		{"no half-life keeps the cliff", 25, fraud.BehavioralRuleConfig{NewAccountWindowHours: 24, NewAccountScore: decimal.NewFromFloat(0.6)}, "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := accountAgeRisk(tt.ageHours, tt.config); !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("risk %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// CurrencyConverter converts amounts between currencies
+type CurrencyConverter = fraud.CurrencyConverter
+
+// StaticCurrencyConverter converts using fixed exchange rates relative to a base currency
+type StaticCurrencyConverter struct {
+	baseCurrency string
+	rates        map[string]decimal.Decimal // Units of base currency per 1 unit of the keyed currency
+}
+
+// NewStaticCurrencyConverter creates a converter from rates expressed in the base currency
+func NewStaticCurrencyConverter(baseCurrency string, rates map[string]decimal.Decimal) *StaticCurrencyConverter {
+	normalized := make(map[string]decimal.Decimal, len(rates)+1)
+	for currency, rate := range rates {
+		normalized[strings.ToUpper(currency)] = rate
+	}
+	normalized[strings.ToUpper(baseCurrency)] = decimal.NewFromInt(1)
+
+	return &StaticCurrencyConverter{
+		baseCurrency: strings.ToUpper(baseCurrency),
+		rates:        normalized,
+	}
+}
+
+// Convert converts amount from one currency to another via the base currency
+func (c *StaticCurrencyConverter) Convert(ctx context.Context, amount decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return amount, nil
+	}
+
+	fromRate, ok := c.rates[from]
+	if !ok || !fromRate.IsPositive() {
+		return decimal.Zero, fmt.Errorf("no exchange rate for %s", from)
+	}
+	toRate, ok := c.rates[to]
+	if !ok || !toRate.IsPositive() {
+		return decimal.Zero, fmt.Errorf("no exchange rate for %s", to)
+	}
+
+	return amount.Mul(fromRate).Div(toRate), nil
+}
+
+// formatAmount writes an amount with its currency code, as in "150.5 EUR"
+// Reasons are returned unconverted next to a report currency amount, so each
+// figure names the currency it is in. An empty currency leaves the amount bare.
+func formatAmount(amount decimal.Decimal, currency string) string {
+	if currency == "" {
+		return amount.String()
+	}
+	return amount.String() + " " + strings.ToUpper(currency)
+}
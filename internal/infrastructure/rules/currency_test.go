@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestStaticCurrencyConverterConvert(t *testing.T) {
+	converter := NewStaticCurrencyConverter("usd", map[string]decimal.Decimal{
+		"EUR": decimal.RequireFromString("1.10"),
+		"jpy": decimal.RequireFromString("0.0067"),
+		"XXX": decimal.Zero,
+	})
+
+	tests := []struct {
+		name     string
+		amount   string
+		from, to string
+		want     string
+		wantErr  bool
+	}{
+		{"same currency", "100", "GBP", "gbp", "100", false},
+		{"to base", "100", "EUR", "USD", "110", false},
+		{"from base", "110", "USD", "EUR", "100", false},
+		{"through base", "1000", "JPY", "EUR", "6.0909090909090909", false},
+		{"lowercase codes", "100", "eur", "usd", "110", false},
+		{"unknown source", "100", "GBP", "USD", "", true},
+		{"unknown target", "100", "USD", "GBP", "", true},
+		{"zero rate", "100", "XXX", "USD", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converter.Convert(context.Background(), decimal.RequireFromString(tt.amount), tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("converted to %s, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("convert: %v", err)
+			}
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("converted to %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineToBaseCurrency(t *testing.T) {
+	converter := NewStaticCurrencyConverter("USD", map[string]decimal.Decimal{"EUR": decimal.RequireFromString("1.10")})
+
+	tests := []struct {
+		name      string
+		converter CurrencyConverter
+		currency  string
+		want      string
+		wantErr   bool
+	}{
+		{"no converter", nil, "EUR", "100", false},
+		{"no currency", converter, "", "100", false},
+		{"converted", converter, "EUR", "110", false},
+		{"no rate", converter, "GBP", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Engine{}
+			if tt.converter != nil {
+				e.SetCurrencyConverter(tt.converter, "USD")
+			}
+			got, err := e.ToBaseCurrency(context.Background(), decimal.NewFromInt(100), tt.currency)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("converted to %s, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("convert: %v", err)
+			}
+			if !got.Equal(decimal.RequireFromString(tt.want)) {
+				t.Errorf("converted to %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
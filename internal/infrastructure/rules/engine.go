@@ -3,8 +3,12 @@ package rules
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"math"
+	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +16,8 @@ import (
 
 	"fraud-detecction-system/internal/domain/fraud"
 	"fraud-detecction-system/internal/infrastructure/cache/redis"
+	"fraud-detecction-system/internal/pkg/logger"
+	"fraud-detecction-system/internal/pkg/metrics"
 )
 
 // Engine implements fraud.RuleEngine
@@ -20,30 +26,170 @@ type Engine struct {
 	velocityCache *redis.VelocityCache
 	deviceCache   *redis.DeviceCache
 	locationCache *redis.LocationCache
+	merchantCache *redis.MerchantCache
+
+	// Optional card testing counters
+	cardTestingCache *redis.CardTestingCache
+
+	// Optional users per device, IP and card for account linking rules
+	linkingCache *redis.LinkingCache
+
+	// Optional chargeback history for chargeback rules
+	chargebackRepo fraud.ChargebackRepository
 
 	// In-memory rule cache for performance
 	rulesCache []*fraud.Rule
 	rulesMu    sync.RWMutex
 	lastRefresh time.Time
 	cacheTTL   time.Duration
+
+	// Refreshable IP blocklists for IP reputation rules
+	ipReputation *ipReputationCache
+
+	// Optional IP geolocation for GPS/IP mismatch checks
+	geoIPResolver GeoIPResolver
+
+	// Optional currency conversion for amount thresholds
+	currencyConverter CurrencyConverter
+	baseCurrency      string
+
+	// Optional per rule type evaluation timeouts
+	ruleTimeouts map[fraud.RuleType]RuleTimeout
+
+	// Optional load shedding, driven by the number of evaluations in flight
+	loadShedding LoadShedding
+	inFlight     atomic.Int64
+
+	// Optional cap on the number of rules evaluated per transaction, 0 means no cap
+	maxRules int
+
+	// Stop evaluating once a critical block rule fires
+	stopOnCriticalBlock bool
+
+	logger *slog.Logger
 }
 
+var _ fraud.RuleEngine = (*Engine)(nil)
+
 // NewEngine creates a new rule engine
 func NewEngine(
 	ruleRepo fraud.RuleRepository,
 	velocityCache *redis.VelocityCache,
 	deviceCache *redis.DeviceCache,
 	locationCache *redis.LocationCache,
+	merchantCache *redis.MerchantCache,
 ) *Engine {
 	return &Engine{
 		ruleRepo:      ruleRepo,
 		velocityCache: velocityCache,
 		deviceCache:   deviceCache,
 		locationCache: locationCache,
+		merchantCache: merchantCache,
 		cacheTTL:      5 * time.Minute,
+		ipReputation:  newIPReputationCache(nil, 5*time.Minute),
+		logger:        slog.Default(),
 	}
 }
 
+// SetLogger sets the logger used to report rule evaluation failures
+func (e *Engine) SetLogger(log *slog.Logger) {
+	e.logger = log
+}
+
+// SetIPReputationProvider sets the source of TOR exit, datacenter and blocked IP lists
+func (e *Engine) SetIPReputationProvider(provider IPReputationProvider) {
+	e.ipReputation = newIPReputationCache(provider, e.cacheTTL)
+}
+
+// SetGeoIPResolver sets the resolver used to geolocate transaction IPs
+func (e *Engine) SetGeoIPResolver(resolver GeoIPResolver) {
+	e.geoIPResolver = resolver
+}
+
+// SetCurrencyConverter sets the converter and base currency used for amount thresholds
+func (e *Engine) SetCurrencyConverter(converter CurrencyConverter, baseCurrency string) {
+	e.currencyConverter = converter
+	e.baseCurrency = baseCurrency
+}
+
+// SetCardTestingCache sets the cache used by card testing rules
+func (e *Engine) SetCardTestingCache(cache *redis.CardTestingCache) {
+	e.cardTestingCache = cache
+}
+
+// SetLinkingCache sets the cache used by account linking rules
+func (e *Engine) SetLinkingCache(cache *redis.LinkingCache) {
+	e.linkingCache = cache
+}
+
+// SetChargebackRepository sets the chargeback history used by chargeback rules
+func (e *Engine) SetChargebackRepository(repo fraud.ChargebackRepository) {
+	e.chargebackRepo = repo
+}
+
+// SetRuleTimeouts sets evaluation timeouts per rule type
+// Rule types without an entry are bounded only by the caller's context
+func (e *Engine) SetRuleTimeouts(timeouts map[fraud.RuleType]RuleTimeout) {
+	e.ruleTimeouts = timeouts
+}
+
+// SetLoadShedding enables sampling of low-priority rules when too many evaluations are in flight
+func (e *Engine) SetLoadShedding(shedding LoadShedding) {
+	e.loadShedding = shedding
+}
+
+// SetMaxRulesPerTransaction caps how many rules are evaluated for one transaction
+// When more rules are active, only the highest priority ones run and the rest are reported as skipped
+func (e *Engine) SetMaxRulesPerTransaction(max int) {
+	e.maxRules = max
+}
+
+// SetStopOnCriticalBlock makes Evaluate stop at the first critical severity block rule that fires
+// Rules then run in priority order so those rules go first, and the rest are reported as skipped.
+// Off by default, so every rule runs and the decision has a full explanation.
+func (e *Engine) SetStopOnCriticalBlock(enabled bool) {
+	e.stopOnCriticalBlock = enabled
+}
+
+// ToBaseCurrency converts an amount to the engine's base currency
+// Amounts are returned unchanged when no converter is configured
+func (e *Engine) ToBaseCurrency(ctx context.Context, amount decimal.Decimal, currency string) (decimal.Decimal, error) {
+	if e.currencyConverter == nil || currency == "" {
+		return amount, nil
+	}
+	return e.currencyConverter.Convert(ctx, amount, currency, e.baseCurrency)
+}
+
+// amountInBaseCurrency returns the transaction amount in the base currency
+// It uses the amount the fraud service already converted when there is one.
+// ok is false when the currency has no exchange rate.
+func (e *Engine) amountInBaseCurrency(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) (amount decimal.Decimal, ok bool) {
+	if evalCtx.BaseCurrency != "" {
+		return evalCtx.BaseAmount, true
+	}
+	amount, err := e.ToBaseCurrency(ctx, evalCtx.Amount, evalCtx.Currency)
+	if err != nil {
+		return evalCtx.Amount, false
+	}
+	return amount, true
+}
+
+// ConvertCurrency converts an amount between any two currencies the converter knows
+func (e *Engine) ConvertCurrency(ctx context.Context, amount decimal.Decimal, from, to string) (decimal.Decimal, error) {
+	if strings.EqualFold(from, to) {
+		return amount, nil
+	}
+	if e.currencyConverter == nil {
+		return decimal.Zero, fmt.Errorf("no currency converter configured")
+	}
+	return e.currencyConverter.Convert(ctx, amount, from, to)
+}
+
+// BaseCurrency returns the currency amount thresholds are expressed in
+func (e *Engine) BaseCurrency() string {
+	return e.baseCurrency
+}
+
 // Evaluate runs all enabled rules against a transaction context
 func (e *Engine) Evaluate(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) ([]fraud.RuleResult, error) {
 	rules, err := e.GetActiveRules(ctx)
@@ -51,13 +197,65 @@ func (e *Engine) Evaluate(ctx context.Context, evalCtx *fraud.RuleEvaluationCont
 		return nil, fmt.Errorf("failed to get active rules: %w", err)
 	}
 
+	inFlight := e.inFlight.Add(1)
+	defer e.inFlight.Add(-1)
+	shedding := e.loadShedding.active(inFlight)
+
 	results := make([]fraud.RuleResult, 0, len(rules))
 
-	for _, rule := range rules {
+	rules, dropped := prioritizeRules(rules, e.maxRules)
+	if len(dropped) > 0 {
+		metrics.RecordRulesTruncated(len(dropped))
+		e.logger.WarnContext(ctx, "active rules exceed per-transaction limit, skipping lowest priority rules",
+			slog.String(logger.KeyTransactionID, evalCtx.TransactionID.String()),
+			slog.Int("active_rules", len(rules)+len(dropped)),
+			slog.Int("max_rules", e.maxRules),
+			slog.Int("skipped_rules", len(dropped)),
+		)
+		for _, rule := range dropped {
+			results = append(results, *limitedResult(rule))
+		}
+	} else if e.stopOnCriticalBlock {
+		rules = orderByPriority(rules)
+	}
+
+	for i, rule := range rules {
+		if shedding && e.loadShedding.shouldSkip(rule) {
+			results = append(results, *shedResult(rule))
+			continue
+		}
+
 		result, err := e.EvaluateRule(ctx, rule, evalCtx)
 		if err != nil {
 			// Log error but continue with other rules
-			continue
+			e.logger.WarnContext(ctx, "rule evaluation failed",
+				slog.String(logger.KeyRuleID, rule.ID.String()),
+				slog.String(logger.KeyRuleName, rule.Name),
+				slog.String("rule_type", string(rule.Type)),
+				slog.String(logger.KeyTransactionID, evalCtx.TransactionID.String()),
+				logger.Err(err),
+			)
+			if !rule.FailsClosed() {
+				continue
+			}
+			result = unavailableResult(rule, "Rule evaluation failed")
+			result.RuleType = rule.Type
+			result.Shadow = rule.IsShadow()
+		}
+		if result.Fired && result.Shadow {
+			metrics.RecordShadowRuleFired(rule.Name)
+		} else if result.Fired {
+			metrics.RecordRuleFired(rule.Name)
+		}
+
+		// A shadow rule never decides, so it can't end evaluation either
+		if e.stopOnCriticalBlock && !result.Shadow && stopsEvaluation(rule, result) {
+			result.StoppedEvaluation = true
+			results = append(results, *result)
+			for _, remaining := range rules[i+1:] {
+				results = append(results, *stoppedResult(remaining, rule.Name))
+			}
+			break
 		}
 		results = append(results, *result)
 	}
@@ -67,8 +265,30 @@ func (e *Engine) Evaluate(ctx context.Context, evalCtx *fraud.RuleEvaluationCont
 
 // EvaluateRule runs a specific rule
 func (e *Engine) EvaluateRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
+	var result *fraud.RuleResult
+	var err error
+	if timeout, ok := e.ruleTimeouts[rule.Type]; ok && timeout.Timeout > 0 {
+		result, err = e.evaluateWithTimeout(ctx, rule, evalCtx, timeout)
+	} else {
+		result, err = e.evaluateRule(ctx, rule, evalCtx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Tag the result with its rule type so scoring can apply per-type weights,
+	// and shadow results so scoring can leave them out
+	result.RuleType = rule.Type
+	result.Shadow = rule.IsShadow()
+	return result, nil
+}
+
+func (e *Engine) evaluateRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
 	if !rule.IsActive() {
-		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Rule not active", fraud.ActionAllow), nil
+		return notRunResult(rule, "Rule not active"), nil
+	}
+	if !rule.ScheduledAt(evalCtx.Timestamp) {
+		return notRunResult(rule, "Outside rule schedule"), nil
 	}
 
 	switch rule.Type {
@@ -84,8 +304,18 @@ func (e *Engine) EvaluateRule(ctx context.Context, rule *fraud.Rule, evalCtx *fr
 		return e.evaluateMerchantRule(ctx, rule, evalCtx)
 	case fraud.RuleTypeBehavioral:
 		return e.evaluateBehavioralRule(ctx, rule, evalCtx)
+	case fraud.RuleTypeCardTesting:
+		return e.evaluateCardTestingRule(ctx, rule, evalCtx)
+	case fraud.RuleTypeIPReputation:
+		return e.evaluateIPReputationRule(ctx, rule, evalCtx)
+	case fraud.RuleTypeChargeback:
+		return e.evaluateChargebackRule(ctx, rule, evalCtx)
+	case fraud.RuleTypeLinking:
+		return e.evaluateLinkingRule(ctx, rule, evalCtx)
+	case fraud.RuleTypeIssuerMismatch:
+		return e.evaluateIssuerMismatchRule(rule, evalCtx)
 	default:
-		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Unknown rule type", fraud.ActionAllow), nil
+		return notRunResult(rule, "Unknown rule type"), nil
 	}
 }
 
@@ -115,15 +345,77 @@ func (e *Engine) GetActiveRules(ctx context.Context) ([]*fraud.Rule, error) {
 
 	e.rulesCache = rules
 	e.lastRefresh = time.Now()
+	e.ensureVelocityRetention(ctx, rules)
+	e.ensureMerchantRetention(rules)
+	e.ensureCardTestingRetention(rules)
 	return rules, nil
 }
 
+// ensureVelocityRetention keeps velocity history for the longest window an active velocity rule uses
+func (e *Engine) ensureVelocityRetention(ctx context.Context, rules []*fraud.Rule) {
+	if e.velocityCache == nil {
+		return
+	}
+
+	var longest time.Duration
+	for _, rule := range rules {
+		if rule.Type != fraud.RuleTypeVelocity {
+			continue
+		}
+		config := parseVelocityConfig(rule.Config)
+		longest = max(longest, time.Duration(config.WindowMinutes)*time.Minute)
+		for _, tier := range config.Tiers {
+			longest = max(longest, time.Duration(tier.WindowMinutes)*time.Minute)
+		}
+	}
+
+	if longest > redis.MaxVelocityRetention {
+		e.logger.WarnContext(ctx, "velocity rule window exceeds the maximum velocity retention and will undercount",
+			slog.Duration("window", longest),
+			slog.Duration("max_retention", redis.MaxVelocityRetention),
+		)
+	}
+	e.velocityCache.EnsureRetention(longest)
+}
+
+// ensureMerchantRetention keeps the novel merchant timeline for the longest window an active merchant rule counts
+func (e *Engine) ensureMerchantRetention(rules []*fraud.Rule) {
+	if e.merchantCache == nil {
+		return
+	}
+
+	var longest time.Duration
+	for _, rule := range rules {
+		if rule.Type == fraud.RuleTypeMerchant {
+			config := parseMerchantConfig(rule.Config)
+			longest = max(longest, time.Duration(config.NewMerchantWindowMinutes)*time.Minute)
+		}
+	}
+	e.merchantCache.EnsureRetention(longest)
+}
+
+// ensureCardTestingRetention keeps card attempts for the longest window an active card testing rule counts
+func (e *Engine) ensureCardTestingRetention(rules []*fraud.Rule) {
+	if e.cardTestingCache == nil {
+		return
+	}
+
+	var longest time.Duration
+	for _, rule := range rules {
+		if rule.Type == fraud.RuleTypeCardTesting {
+			config := parseCardTestingConfig(rule.Config)
+			longest = max(longest, time.Duration(config.WindowMinutes)*time.Minute)
+		}
+	}
+	e.cardTestingCache.EnsureRetention(longest)
+}
+
 // AddRule adds a new rule to the engine
 func (e *Engine) AddRule(ctx context.Context, rule *fraud.Rule) error {
 	if err := e.ruleRepo.Create(ctx, rule); err != nil {
 		return err
 	}
-	e.invalidateCache()
+	e.InvalidateCache()
 	return nil
 }
 
@@ -132,7 +424,7 @@ func (e *Engine) UpdateRule(ctx context.Context, rule *fraud.Rule) error {
 	if err := e.ruleRepo.Update(ctx, rule); err != nil {
 		return err
 	}
-	e.invalidateCache()
+	e.InvalidateCache()
 	return nil
 }
 
@@ -141,85 +433,250 @@ func (e *Engine) DisableRule(ctx context.Context, ruleID uuid.UUID) error {
 	if err := e.ruleRepo.Disable(ctx, ruleID); err != nil {
 		return err
 	}
-	e.invalidateCache()
+	e.InvalidateCache()
 	return nil
 }
 
-func (e *Engine) invalidateCache() {
+// InvalidateCache forces active rules to be reloaded on next evaluation
+func (e *Engine) InvalidateCache() {
 	e.rulesMu.Lock()
 	e.rulesCache = nil
 	e.rulesMu.Unlock()
+	e.ipReputation.invalidate()
 }
 
 // evaluateVelocityRule checks transaction frequency limits
 func (e *Engine) evaluateVelocityRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
 	// Skip if velocity cache is not available
 	if e.velocityCache == nil {
-		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Velocity check skipped (cache unavailable)", fraud.ActionAllow), nil
+		return unavailableResult(rule, "Velocity check skipped (cache unavailable)"), nil
 	}
 
 	config := parseVelocityConfig(rule.Config)
+	if len(config.Tiers) > 0 {
+		return e.evaluateVelocityTiers(ctx, rule, evalCtx, config)
+	}
 
 	windowDuration := time.Duration(config.WindowMinutes) * time.Minute
 
+	// Reuse the history loaded for this request when it covers the window
+	count, recentTotal, fromHistory := evalCtx.RecentActivity(windowDuration)
+
 	// Get transaction count in window
-	count, err := e.velocityCache.GetTransactionCount(ctx, evalCtx.UserID, windowDuration)
-	if err != nil {
-		// Can't evaluate velocity - fail open for availability
-		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Unable to check velocity", fraud.ActionAllow), nil
+	var err error
+	if !fromHistory {
+		count, err = e.velocityCache.GetTransactionCount(ctx, evalCtx.UserID, windowDuration)
+		if err != nil {
+			// Can't evaluate velocity - fail open for availability
+			return unavailableResult(rule, "Unable to check velocity"), nil
+		}
 	}
 
-	// Check if count exceeds limit
+	// Evaluate both dimensions so a count violation doesn't hide an amount violation
+	var reasons []string
+	countScore := decimal.Zero
 	if count >= int64(config.MaxTransactions) {
-		score := calculateVelocityScore(count, config.MaxTransactions)
-		reason := fmt.Sprintf("Velocity limit exceeded: %d transactions in %d minutes (limit: %d)", count, config.WindowMinutes, config.MaxTransactions)
-		result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
-		result.AddMetadata("transaction_count", count)
-		result.AddMetadata("limit", config.MaxTransactions)
-		result.AddMetadata("window_minutes", config.WindowMinutes)
-		return result, nil
+		countScore = calculateVelocityScore(count, config.MaxTransactions)
+		reasons = append(reasons, fmt.Sprintf("Velocity limit exceeded: %d transactions in %d minutes (limit: %d)", count, config.WindowMinutes, config.MaxTransactions))
 	}
 
 	// If amount threshold is configured, also check total amount
+	// The threshold and cached sum are in the base currency, so convert the current amount first
+	amountScore := decimal.Zero
+	var total, amount decimal.Decimal
+	amountChecked := false
 	if !config.AmountThreshold.IsZero() && !config.CountOnly {
-		total, err := e.velocityCache.GetTransactionSum(ctx, evalCtx.UserID, windowDuration)
-		if err == nil && total.Add(evalCtx.Amount).GreaterThan(config.AmountThreshold) {
-			score := decimal.NewFromFloat(0.7)
-			reason := fmt.Sprintf("Amount velocity limit exceeded: %s total in %d minutes (limit: %s)", total.Add(evalCtx.Amount).String(), config.WindowMinutes, config.AmountThreshold.String())
-			result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
-			result.AddMetadata("total_amount", total.String())
-			result.AddMetadata("amount_limit", config.AmountThreshold.String())
-			return result, nil
+		var converted bool
+		amount, converted = e.amountInBaseCurrency(ctx, evalCtx)
+		if fromHistory {
+			total = recentTotal
+		} else {
+			total, err = e.velocityCache.GetTransactionSum(ctx, evalCtx.UserID, windowDuration)
+		}
+		if err == nil && converted {
+			amountChecked = true
+			if total.Add(amount).GreaterThan(config.AmountThreshold) {
+				amountScore = decimal.NewFromFloat(0.7)
+				reasons = append(reasons, fmt.Sprintf("Amount velocity limit exceeded: %s total in %d minutes (limit: %s)", formatAmount(total.Add(amount), e.baseCurrency), config.WindowMinutes, formatAmount(config.AmountThreshold, e.baseCurrency)))
+			}
 		}
 	}
 
-	return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Within velocity limits", fraud.ActionAllow), nil
+	if len(reasons) == 0 {
+		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Within velocity limits", fraud.ActionAllow), nil
+	}
+
+	score := combineVelocityScores(countScore, amountScore, config)
+	result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, strings.Join(reasons, "; "), rule.Action)
+	result.AddMetadata("transaction_count", count)
+	result.AddMetadata("limit", config.MaxTransactions)
+	result.AddMetadata("window_minutes", config.WindowMinutes)
+	result.AddMetadata("count_score", countScore.String())
+	if amountChecked {
+		result.AddMetadata("total_amount", total.String())
+		result.AddMetadata("amount_limit", config.AmountThreshold.String())
+		result.AddMetadata("amount_score", amountScore.String())
+		if e.baseCurrency != "" {
+			result.AddMetadata("base_currency", e.baseCurrency)
+			result.AddMetadata("converted_amount", amount.String())
+		}
+	}
+	result.AddMetadata("combine_mode", string(config.CombineMode))
+	return result, nil
+}
+
+// CardTestingScope returns the key card testing attempts are counted under
+// Attempts share a BIN when one is sent, otherwise they are grouped by user and card network
+func CardTestingScope(userID uuid.UUID, payment *fraud.PaymentMethod) string {
+	if payment == nil {
+		return ""
+	}
+	if payment.BIN != "" {
+		return "bin:" + payment.BIN
+	}
+	if payment.Network == "" {
+		return ""
+	}
+	return fmt.Sprintf("user:%s:network:%s", userID.String(), strings.ToLower(payment.Network))
+}
+
+// evaluateCardTestingRule checks for many small-amount attempts across cards sharing a BIN
+// This catches enumeration attacks spread over cards that per-user velocity misses
+func (e *Engine) evaluateCardTestingRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
+	// Skip if card testing cache is not available
+	if e.cardTestingCache == nil {
+		return unavailableResult(rule, "Card testing check skipped (cache unavailable)"), nil
+	}
+
+	scope := CardTestingScope(evalCtx.UserID, evalCtx.Payment)
+	if scope == "" {
+		return insufficientDataResult(rule, "No card details"), nil
+	}
+
+	config := parseCardTestingConfig(rule.Config)
+
+	// The ceiling is in the base currency, so convert the current amount first
+	amount, converted := e.amountInBaseCurrency(ctx, evalCtx)
+	if !converted {
+		return insufficientDataResult(rule, "Unable to convert amount"), nil
+	}
+	if amount.GreaterThan(config.SmallAmountCeiling) {
+		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Amount above card testing ceiling", fraud.ActionAllow), nil
+	}
+
+	windowDuration := time.Duration(config.WindowMinutes) * time.Minute
+	count, err := e.cardTestingCache.GetSmallAttemptCount(ctx, scope, windowDuration, config.SmallAmountCeiling)
+	if err != nil {
+		// Can't evaluate card testing - fail open for availability
+		return unavailableResult(rule, "Unable to check card testing"), nil
+	}
+
+	// Include the current attempt
+	count++
+	if count <= int64(config.MaxSmallTransactions) {
+		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Within card testing limits", fraud.ActionAllow), nil
+	}
+
+	score := calculateVelocityScore(count, config.MaxSmallTransactions)
+	reason := fmt.Sprintf("Possible card testing: %d attempts at or below %s in %d minutes (limit: %d)", count, formatAmount(config.SmallAmountCeiling, e.baseCurrency), config.WindowMinutes, config.MaxSmallTransactions)
+	result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
+	result.AddMetadata("scope", scope)
+	result.AddMetadata("attempt_count", count)
+	result.AddMetadata("limit", config.MaxSmallTransactions)
+	result.AddMetadata("window_minutes", config.WindowMinutes)
+	result.AddMetadata("small_amount_ceiling", config.SmallAmountCeiling.String())
+	return result, nil
+}
+
+// combineVelocityScores merges the count and amount velocity scores using the rule's weights and mode
+func combineVelocityScores(countScore, amountScore decimal.Decimal, config fraud.VelocityRuleConfig) decimal.Decimal {
+	count := countScore.Mul(decimal.NewFromFloat(config.CountWeight))
+	amount := amountScore.Mul(decimal.NewFromFloat(config.AmountWeight))
+
+	if config.CombineMode == fraud.VelocityCombineMax {
+		return decimal.Max(count, amount)
+	}
+
+	one := decimal.NewFromInt(1)
+	return one.Sub(one.Sub(count).Mul(one.Sub(amount)))
 }
 
 // evaluateAmountRule checks transaction amount thresholds
 func (e *Engine) evaluateAmountRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
 	config := parseAmountConfig(rule.Config)
+	minAmount, maxAmount := config.Thresholds(evalCtx.Currency)
+
+	// min_amount and max_amount are in the base currency, per-currency entries in
+	// the transaction's own. A currency without an exchange rate is compared unconverted.
+	hasMin, hasMax := config.CurrencyThresholds(evalCtx.Currency)
+	baseAmount, converted := e.amountInBaseCurrency(ctx, evalCtx)
+	compared := func(perCurrency bool) decimal.Decimal {
+		if perCurrency {
+			return evalCtx.Amount
+		}
+		return baseAmount
+	}
+	// An unconverted amount stays in its own currency while the base thresholds don't
+	amountCurrency := func(perCurrency bool) string {
+		if perCurrency || !converted {
+			return evalCtx.Currency
+		}
+		return e.baseCurrency
+	}
+	thresholdCurrency := func(perCurrency bool) string {
+		if perCurrency {
+			return evalCtx.Currency
+		}
+		return e.baseCurrency
+	}
+	addAmountMetadata := func(result *fraud.RuleResult, perCurrency bool) {
+		result.AddMetadata("amount", evalCtx.Amount.String())
+		result.AddMetadata("currency", evalCtx.Currency)
+		if perCurrency {
+			return
+		}
+		if converted {
+			result.AddMetadata("converted_amount", baseAmount.String())
+			result.AddMetadata("base_currency", e.baseCurrency)
+		} else {
+			result.AddMetadata("currency_converted", false)
+		}
+	}
 
 	// Check max amount
-	if !config.MaxAmount.IsZero() && evalCtx.Amount.GreaterThan(config.MaxAmount) {
-		score := calculateAmountScore(evalCtx.Amount, config.MaxAmount)
-		reason := fmt.Sprintf("Transaction amount %s exceeds maximum threshold %s", evalCtx.Amount.String(), config.MaxAmount.String())
+	if amount := compared(hasMax); !maxAmount.IsZero() && amount.GreaterThan(maxAmount) {
+		score := calculateAmountScore(amount, maxAmount)
+		reason := fmt.Sprintf("Transaction amount %s exceeds maximum threshold %s",
+			formatAmount(amount, amountCurrency(hasMax)), formatAmount(maxAmount, thresholdCurrency(hasMax)))
 		result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
-		result.AddMetadata("amount", evalCtx.Amount.String())
-		result.AddMetadata("max_amount", config.MaxAmount.String())
+		addAmountMetadata(result, hasMax)
+		result.AddMetadata("max_amount", maxAmount.String())
+		return result, nil
+	}
+
+	// Check min amount - tiny amounts are how stolen cards get tested
+	if amount := compared(hasMin); minAmount.IsPositive() && amount.LessThan(minAmount) {
+		score := decimal.NewFromFloat(0.3)
+		reason := fmt.Sprintf("Transaction amount %s is below minimum threshold %s",
+			formatAmount(amount, amountCurrency(hasMin)), formatAmount(minAmount, thresholdCurrency(hasMin)))
+		result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
+		addAmountMetadata(result, hasMin)
+		result.AddMetadata("min_amount", minAmount.String())
 		return result, nil
 	}
 
 	// Check deviation from user's average
-	if config.DeviationFactor > 0 && evalCtx.UserProfile != nil {
+	// The profile average is in the base currency, so an amount that couldn't be converted isn't compared
+	if config.DeviationFactor > 0 && evalCtx.UserProfile != nil && converted {
 		avgAmount := evalCtx.UserProfile.AverageTransaction
 		if !avgAmount.IsZero() {
 			threshold := avgAmount.Mul(decimal.NewFromFloat(config.DeviationFactor))
-			if evalCtx.Amount.GreaterThan(threshold) {
+			if baseAmount.GreaterThan(threshold) {
 				score := decimal.NewFromFloat(0.65)
-				reason := fmt.Sprintf("Transaction amount %s is %.1fx user's average (%s)", evalCtx.Amount.String(), config.DeviationFactor, avgAmount.String())
+				reason := fmt.Sprintf("Transaction amount %s is %.1fx user's average (%s)",
+					formatAmount(baseAmount, e.baseCurrency), config.DeviationFactor, formatAmount(avgAmount, e.baseCurrency))
 				result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
-				result.AddMetadata("amount", evalCtx.Amount.String())
+				addAmountMetadata(result, false)
 				result.AddMetadata("average", avgAmount.String())
 				result.AddMetadata("deviation_factor", config.DeviationFactor)
 				return result, nil
@@ -233,7 +690,7 @@ func (e *Engine) evaluateAmountRule(ctx context.Context, rule *fraud.Rule, evalC
 // evaluateGeographicRule checks location-based rules
 func (e *Engine) evaluateGeographicRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
 	if evalCtx.Location == nil {
-		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "No location data", fraud.ActionAllow), nil
+		return insufficientDataResult(rule, "No location data"), nil
 	}
 
 	config := parseGeographicConfig(rule.Config)
@@ -267,37 +724,112 @@ func (e *Engine) evaluateGeographicRule(ctx context.Context, rule *fraud.Rule, e
 		}
 	}
 
+	// Check blocked and allowed regions
+	if region := fraud.RegionKey(evalCtx.Location.Country, evalCtx.Location.Region); region != "" {
+		if containsRegion(config.BlockedRegions, region) {
+			reason := fmt.Sprintf("Transaction from blocked region: %s", region)
+			result := fraud.NewRuleResult(rule.ID, rule.Name, true, config.BlockedRegionScore, reason, rule.Action)
+			result.AddMetadata("country", evalCtx.Location.Country)
+			result.AddMetadata("region", region)
+			return result, nil
+		}
+		if restricted := regionsForCountry(config.AllowedRegions, evalCtx.Location.Country); len(restricted) > 0 && !containsRegion(restricted, region) {
+			reason := fmt.Sprintf("Transaction from non-allowed region: %s", region)
+			result := fraud.NewRuleResult(rule.ID, rule.Name, true, config.NonAllowedRegionScore, reason, rule.Action)
+			result.AddMetadata("country", evalCtx.Location.Country)
+			result.AddMetadata("region", region)
+			return result, nil
+		}
+	}
+
+	// Compare IP-derived location to reported GPS coordinates (location spoofing)
+	if config.MaxIPDistanceKm > 0 && e.geoIPResolver != nil && evalCtx.Location.IPAddress != "" &&
+		hasCoordinates(evalCtx.Location) {
+		ipLocation, err := e.geoIPResolver.Resolve(ctx, evalCtx.Location.IPAddress)
+		if err == nil && hasCoordinates(ipLocation) {
+			gap := haversineDistance(
+				evalCtx.Location.Latitude, evalCtx.Location.Longitude,
+				ipLocation.Latitude, ipLocation.Longitude,
+			)
+			if gap > config.MaxIPDistanceKm {
+				score := decimal.NewFromFloat(0.7)
+				reason := fmt.Sprintf("IP location is %.0fkm from reported location (limit: %.0fkm)", gap, config.MaxIPDistanceKm)
+				result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
+				result.AddMetadata("reported_latitude", evalCtx.Location.Latitude)
+				result.AddMetadata("reported_longitude", evalCtx.Location.Longitude)
+				result.AddMetadata("ip_address", evalCtx.Location.IPAddress)
+				result.AddMetadata("ip_latitude", ipLocation.Latitude)
+				result.AddMetadata("ip_longitude", ipLocation.Longitude)
+				result.AddMetadata("ip_country", ipLocation.Country)
+				result.AddMetadata("distance_km", gap)
+				return result, nil
+			}
+		}
+	}
+
 	// Check if location is known for this user
 	if config.RequireConsistent && e.locationCache != nil {
-		isKnown, err := e.locationCache.IsKnownLocation(ctx, evalCtx.UserID, evalCtx.Location.Country, evalCtx.Location.City)
+		isKnown, err := e.knownLocation(ctx, evalCtx)
 		if err == nil && !isKnown {
-			score := decimal.NewFromFloat(0.5)
+			// A new city inside a country the user already transacts from is
+			// domestic travel, which is much lower risk than a new country
+			knownCountry, _ := e.knownCountry(ctx, evalCtx)
+
+			score, action := config.NewLocationScore, config.NewLocationAction
 			reason := fmt.Sprintf("Transaction from new location: %s, %s", evalCtx.Location.City, evalCtx.Location.Country)
-			result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, fraud.ActionChallenge)
+			if knownCountry {
+				score, action = config.KnownCountryScore, config.KnownCountryAction
+				reason = fmt.Sprintf("Transaction from new city in known country: %s, %s", evalCtx.Location.City, evalCtx.Location.Country)
+			}
+
+			result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, action)
 			result.AddMetadata("city", evalCtx.Location.City)
 			result.AddMetadata("country", evalCtx.Location.Country)
+			result.AddMetadata("known_country", knownCountry)
 			return result, nil
 		}
 	}
 
-	// Check distance from last known location (simplified - would need actual geocalc)
-	if config.MaxDistanceKm > 0 && len(evalCtx.RecentTransactions) > 0 {
-		lastTx := evalCtx.RecentTransactions[0]
-		if lastTx.Location != nil {
+	// A never-seen region inside a known country is a sub-national signal the city
+	// check misses, e.g. a city name the user has used before in another state
+	if config.RequireConsistentRegion && e.locationCache != nil && evalCtx.Location.Region != "" {
+		knownRegion, err := e.locationCache.IsKnownRegion(ctx, evalCtx.UserID, evalCtx.Location.Country, evalCtx.Location.Region)
+		if err == nil && !knownRegion {
+			knownCountry, err := e.knownCountry(ctx, evalCtx)
+			if err == nil && knownCountry {
+				reason := fmt.Sprintf("Transaction from new region in known country: %s, %s", evalCtx.Location.Region, evalCtx.Location.Country)
+				result := fraud.NewRuleResult(rule.ID, rule.Name, true, config.NewRegionScore, reason, config.NewRegionAction)
+				result.AddMetadata("city", evalCtx.Location.City)
+				result.AddMetadata("region", evalCtx.Location.Region)
+				result.AddMetadata("country", evalCtx.Location.Country)
+				return result, nil
+			}
+		}
+	}
+
+	// Check distance and speed from the last located transaction (impossible travel)
+	if config.MaxDistanceKm > 0 && hasCoordinates(evalCtx.Location) {
+		if lastTx := e.lastLocatedTransaction(ctx, evalCtx); lastTx != nil {
 			distance := haversineDistance(
 				evalCtx.Location.Latitude, evalCtx.Location.Longitude,
 				lastTx.Location.Latitude, lastTx.Location.Longitude,
 			)
 			if distance > config.MaxDistanceKm {
-				// Check time between transactions (impossible travel)
+				// Floor the gap at a minute so simultaneous transactions give a finite speed
 				timeDiff := evalCtx.Timestamp.Sub(lastTx.Timestamp)
+				if timeDiff < time.Minute {
+					timeDiff = time.Minute
+				}
 				speedKmH := distance / timeDiff.Hours()
-				if speedKmH > 900 { // Faster than a commercial jet
+				if speedKmH > config.MaxTravelSpeedKmH {
 					score := decimal.NewFromFloat(0.85)
 					reason := fmt.Sprintf("Impossible travel: %.0fkm in %v (%.0f km/h)", distance, timeDiff, speedKmH)
 					result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, fraud.ActionBlock)
 					result.AddMetadata("distance_km", distance)
 					result.AddMetadata("speed_kmh", speedKmH)
+					result.AddMetadata("previous_transaction_id", lastTx.ID.String())
+					result.AddMetadata("previous_country", lastTx.Location.Country)
+					result.AddMetadata("previous_city", lastTx.Location.City)
 					return result, nil
 				}
 			}
@@ -310,7 +842,7 @@ func (e *Engine) evaluateGeographicRule(ctx context.Context, rule *fraud.Rule, e
 // evaluateDeviceRule checks device-related rules
 func (e *Engine) evaluateDeviceRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
 	if evalCtx.Device == nil {
-		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "No device data", fraud.ActionAllow), nil
+		return insufficientDataResult(rule, "No device data"), nil
 	}
 
 	config := parseDeviceConfig(rule.Config)
@@ -319,7 +851,7 @@ func (e *Engine) evaluateDeviceRule(ctx context.Context, rule *fraud.Rule, evalC
 	if config.RequireTrustedDevice && !evalCtx.Device.IsTrustedDevice {
 		// If device cache is available, check if it's known
 		if e.deviceCache != nil {
-			isKnown, err := e.deviceCache.IsKnownDevice(ctx, evalCtx.UserID, evalCtx.Device.DeviceID)
+			isKnown, err := e.knownDevice(ctx, evalCtx)
 			if err == nil && !isKnown {
 				if config.BlockNewDevices {
 					score := decimal.NewFromFloat(0.8)
@@ -346,10 +878,10 @@ func (e *Engine) evaluateDeviceRule(ctx context.Context, rule *fraud.Rule, evalC
 
 	// Check device count per user
 	if config.MaxDevicesPerUser > 0 && e.deviceCache != nil {
-		deviceCount, err := e.deviceCache.GetDeviceCount(ctx, evalCtx.UserID)
+		deviceCount, err := e.deviceCount(ctx, evalCtx)
 		if err == nil && int(deviceCount) >= config.MaxDevicesPerUser {
 			// Check if this is a new device
-			isKnown, _ := e.deviceCache.IsKnownDevice(ctx, evalCtx.UserID, evalCtx.Device.DeviceID)
+			isKnown, _ := e.knownDevice(ctx, evalCtx)
 			if !isKnown {
 				score := decimal.NewFromFloat(0.6)
 				reason := fmt.Sprintf("User has %d devices (limit: %d) and this is a new device", deviceCount, config.MaxDevicesPerUser)
@@ -361,13 +893,69 @@ func (e *Engine) evaluateDeviceRule(ctx context.Context, rule *fraud.Rule, evalC
 		}
 	}
 
+	// One device transacting for many accounts points to account takeover or a bot
+	if config.MaxUsersPerDevice > 0 && e.deviceCache != nil && evalCtx.Device.DeviceID != "" {
+		userCount, err := e.deviceCache.GetUserCountForDevice(ctx, evalCtx.Device.DeviceID)
+		if err == nil {
+			// Usage is recorded after analysis, so the current user may not be counted yet
+			isKnown, err := e.knownDevice(ctx, evalCtx)
+			if err == nil && !isKnown {
+				userCount++
+			}
+
+			if userCount > int64(config.MaxUsersPerDevice) {
+				score := decimal.NewFromFloat(0.75)
+				reason := fmt.Sprintf("Device used by %d accounts (limit: %d)", userCount, config.MaxUsersPerDevice)
+				result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
+				result.AddMetadata("device_id", evalCtx.Device.DeviceID)
+				result.AddMetadata("user_count", userCount)
+				result.AddMetadata("max_users", config.MaxUsersPerDevice)
+				return result, nil
+			}
+		}
+	}
+
 	return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Device check passed", fraud.ActionAllow), nil
 }
 
 // evaluateMerchantRule checks merchant-related rules
 func (e *Engine) evaluateMerchantRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
 	if evalCtx.Merchant == nil {
-		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "No merchant data", fraud.ActionAllow), nil
+		return insufficientDataResult(rule, "No merchant data"), nil
+	}
+
+	config := parseMerchantConfig(rule.Config)
+	mcc := evalCtx.Merchant.MerchantCategory
+
+	// Categories the deployment refuses to process at all
+	if containsString(config.BlockedMCCs, mcc) {
+		reason := fmt.Sprintf("Transaction with blocked merchant category: %s", mcc)
+		result := fraud.NewRuleResult(rule.ID, rule.Name, true, config.BlockedMCCScore, reason, config.BlockedAction)
+		result.AddMetadata("merchant_category", mcc)
+		return result, nil
+	}
+
+	// Burst of never-before-seen merchants suggests a compromised account
+	if config.MaxNewMerchants > 0 && e.merchantCache != nil && evalCtx.Merchant.MerchantID != "" {
+		window := time.Duration(config.NewMerchantWindowMinutes) * time.Minute
+		newCount, err := e.merchantCache.GetNewMerchantCount(ctx, evalCtx.UserID, window)
+		if err == nil {
+			// The current merchant counts too if the user has never used it
+			isKnown, err := e.knownMerchant(ctx, evalCtx)
+			if err == nil && !isKnown {
+				newCount++
+			}
+
+			if newCount > int64(config.MaxNewMerchants) {
+				score := decimal.NewFromFloat(0.6)
+				reason := fmt.Sprintf("Transactions with %d new merchants in %d minutes (limit: %d)", newCount, config.NewMerchantWindowMinutes, config.MaxNewMerchants)
+				result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
+				result.AddMetadata("new_merchant_count", newCount)
+				result.AddMetadata("limit", config.MaxNewMerchants)
+				result.AddMetadata("window_minutes", config.NewMerchantWindowMinutes)
+				return result, nil
+			}
+		}
 	}
 
 	// High-risk merchant check
@@ -381,18 +969,10 @@ func (e *Engine) evaluateMerchantRule(ctx context.Context, rule *fraud.Rule, eva
 	}
 
 	// High-risk MCC codes (gambling, crypto, etc.)
-	highRiskMCCs := map[string]bool{
-		"7995": true, // Gambling
-		"7801": true, // Lottery
-		"5967": true, // Direct Marketing
-		"6051": true, // Crypto
-	}
-
-	if highRiskMCCs[evalCtx.Merchant.MerchantCategory] {
-		score := decimal.NewFromFloat(0.4)
-		reason := fmt.Sprintf("Transaction with high-risk merchant category: %s", evalCtx.Merchant.MerchantCategory)
-		result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, fraud.ActionReview)
-		result.AddMetadata("merchant_category", evalCtx.Merchant.MerchantCategory)
+	if containsString(config.HighRiskMCCs, mcc) {
+		reason := fmt.Sprintf("Transaction with high-risk merchant category: %s", mcc)
+		result := fraud.NewRuleResult(rule.ID, rule.Name, true, config.HighRiskScore, reason, config.HighRiskAction)
+		result.AddMetadata("merchant_category", mcc)
 		return result, nil
 	}
 
@@ -402,42 +982,252 @@ func (e *Engine) evaluateMerchantRule(ctx context.Context, rule *fraud.Rule, eva
 // evaluateBehavioralRule checks behavioral patterns
 func (e *Engine) evaluateBehavioralRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
 	if evalCtx.UserProfile == nil {
-		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "No user profile", fraud.ActionAllow), nil
+		return insufficientDataResult(rule, "No user profile"), nil
 	}
 
+	config := parseBehavioralConfig(rule.Config)
+	ageHours := evalCtx.UserProfile.AccountAge.Hours()
+	ageRisk := accountAgeRisk(ageHours, config)
+
 	// Check for unusual timing (outside user's typical activity hours)
 	hour := evalCtx.Timestamp.Hour()
-	if hour >= 2 && hour <= 5 { // 2 AM - 5 AM is unusual
+	if unusual, source := unusualHour(hour, evalCtx.UserProfile, config); unusual {
 		score := decimal.NewFromFloat(0.35)
-		reason := "Transaction at unusual hour (late night)"
+		reason := fmt.Sprintf("Transaction at unusual hour (%02d:00)", hour)
+		if source == "profile" {
+			reason = fmt.Sprintf("Transaction outside user's typical hours (%02d:00)", hour)
+		}
 		result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, fraud.ActionChallenge)
 		result.AddMetadata("hour", hour)
+		result.AddMetadata("hour_source", source)
+		result.AddMetadata("age_risk", ageRisk.String())
 		return result, nil
 	}
 
-	// Check account age - new accounts are higher risk
-	if evalCtx.UserProfile.AccountAge < 24*time.Hour {
-		score := decimal.NewFromFloat(0.5)
-		reason := "Transaction from very new account (< 24 hours)"
-		result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, fraud.ActionReview)
-		result.AddMetadata("account_age_hours", evalCtx.UserProfile.AccountAge.Hours())
+	// Check account age - risk decays smoothly as the account ages
+	if ageRisk.IsPositive() && ageRisk.GreaterThanOrEqual(config.MinAgeRisk) {
+		reason := fmt.Sprintf("Transaction from new account (%.1f hours old)", ageHours)
+		result := fraud.NewRuleResult(rule.ID, rule.Name, true, ageRisk, reason, fraud.ActionReview)
+		result.AddMetadata("account_age_hours", ageHours)
+		result.AddMetadata("age_risk", ageRisk.String())
+		result.AddMetadata("age_half_life_hours", config.AgeHalfLifeHours)
 		return result, nil
 	}
 
 	// Dormant account suddenly active
-	if evalCtx.UserProfile.LastActivityAt.Before(time.Now().AddDate(0, -3, 0)) {
+	if evalCtx.UserProfile.LastActivityAt.Before(time.Now().AddDate(0, 0, -config.DormantDays)) {
 		score := decimal.NewFromFloat(0.55)
-		reason := "Transaction from dormant account (inactive > 3 months)"
+		reason := fmt.Sprintf("Transaction from dormant account (inactive > %d days)", config.DormantDays)
 		result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, fraud.ActionReview)
 		result.AddMetadata("last_activity", evalCtx.UserProfile.LastActivityAt.Format(time.RFC3339))
+		result.AddMetadata("age_risk", ageRisk.String())
 		return result, nil
 	}
 
-	return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Behavioral check passed", fraud.ActionAllow), nil
+	result := fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Behavioral check passed", fraud.ActionAllow)
+	result.AddMetadata("age_risk", ageRisk.String())
+	return result, nil
+}
+
+// unusualHour reports whether an hour is outside normal activity, and which window decided it
+// The user's own typical hours are used once the profile has enough of them,
+// otherwise the rule's static window applies
+func unusualHour(hour int, profile *fraud.UserProfile, config fraud.BehavioralRuleConfig) (bool, string) {
+	if config.MinTypicalHours > 0 && len(profile.TypicalHours) >= config.MinTypicalHours {
+		for _, h := range profile.TypicalHours {
+			if h == hour {
+				return false, "profile"
+			}
+		}
+		return true, "profile"
+	}
+
+	start, end := config.UnusualHourStart, config.UnusualHourEnd
+	if start <= end {
+		return hour >= start && hour <= end, "static"
+	}
+	return hour >= start || hour <= end, "static"
+}
+
+// accountAgeRisk scores an account by age
+// Risk is flat inside the new-account window and halves every half-life after it
+func accountAgeRisk(ageHours float64, config fraud.BehavioralRuleConfig) decimal.Decimal {
+	if ageHours <= config.NewAccountWindowHours {
+		return config.NewAccountScore
+	}
+	if config.AgeHalfLifeHours <= 0 {
+		return decimal.Zero
+	}
+
+	decay := math.Pow(0.5, (ageHours-config.NewAccountWindowHours)/config.AgeHalfLifeHours)
+	return config.NewAccountScore.Mul(decimal.NewFromFloat(decay)).Round(4)
+}
+
+// evaluateIPReputationRule checks the transaction IP against known-bad ranges
+func (e *Engine) evaluateIPReputationRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
+	if evalCtx.Location == nil || evalCtx.Location.IPAddress == "" {
+		return insufficientDataResult(rule, "No IP address"), nil
+	}
+
+	ip := net.ParseIP(evalCtx.Location.IPAddress)
+	if ip == nil {
+		return insufficientDataResult(rule, "Invalid IP address"), nil
+	}
+
+	config := parseIPReputationConfig(rule.Config)
+	lists := e.ipReputation.get(ctx)
+
+	// Rule-specific and previously blocked IPs
+	if containsIP(parseCIDRs(config.BlockedCIDRs), ip) || containsIP(lists.blocked, ip) {
+		reason := fmt.Sprintf("Transaction from blocked IP: %s", evalCtx.Location.IPAddress)
+		result := fraud.NewRuleResult(rule.ID, rule.Name, true, config.BlockedCIDRScore, reason, rule.Action)
+		result.AddMetadata("ip_address", evalCtx.Location.IPAddress)
+		result.AddMetadata("match_type", "blocked")
+		return result, nil
+	}
+
+	if config.BlockTorExits && containsIP(lists.torExits, ip) {
+		reason := fmt.Sprintf("Transaction from TOR exit node: %s", evalCtx.Location.IPAddress)
+		result := fraud.NewRuleResult(rule.ID, rule.Name, true, config.TorExitScore, reason, rule.Action)
+		result.AddMetadata("ip_address", evalCtx.Location.IPAddress)
+		result.AddMetadata("match_type", "tor_exit")
+		return result, nil
+	}
+
+	if config.BlockDatacenterIPs && containsIP(lists.datacenter, ip) {
+		reason := fmt.Sprintf("Transaction from datacenter IP: %s", evalCtx.Location.IPAddress)
+		result := fraud.NewRuleResult(rule.ID, rule.Name, true, config.DatacenterScore, reason, rule.Action)
+		result.AddMetadata("ip_address", evalCtx.Location.IPAddress)
+		result.AddMetadata("match_type", "datacenter")
+		return result, nil
+	}
+
+	return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "IP reputation check passed", fraud.ActionAllow), nil
+}
+
+// evaluateChargebackRule scores the user's chargeback history
+// More chargebacks in the window reach higher thresholds, and recent ones add a boost
+func (e *Engine) evaluateChargebackRule(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
+	// Skip if chargeback history is not available
+	if e.chargebackRepo == nil {
+		return unavailableResult(rule, "Chargeback check skipped (history unavailable)"), nil
+	}
+
+	config := parseChargebackConfig(rule.Config)
+	now := time.Now()
+
+	count, err := e.chargebackRepo.CountByUserID(ctx, evalCtx.UserID, now.AddDate(0, 0, -config.WindowDays))
+	if err != nil {
+		// Can't evaluate chargebacks - fail open for availability
+		return unavailableResult(rule, "Unable to check chargeback history"), nil
+	}
+
+	score, minCount := chargebackScore(config.Thresholds, count)
+	if score.IsZero() {
+		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Within chargeback limits", fraud.ActionAllow), nil
+	}
+
+	// Only look at the recent window when it is shorter than the full one
+	var recentCount int64
+	if config.RecentDays > 0 && config.RecentDays < config.WindowDays && config.RecentBoost.IsPositive() {
+		recentCount, err = e.chargebackRepo.CountByUserID(ctx, evalCtx.UserID, now.AddDate(0, 0, -config.RecentDays))
+		if err == nil && recentCount > 0 {
+			score = decimal.Min(score.Add(config.RecentBoost), decimal.NewFromInt(1))
+		}
+	}
+
+	reason := fmt.Sprintf("User has %d chargebacks in the last %d days (threshold: %d)", count, config.WindowDays, minCount)
+	result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
+	result.AddMetadata("chargeback_count", count)
+	result.AddMetadata("window_days", config.WindowDays)
+	result.AddMetadata("threshold", minCount)
+	if config.RecentDays > 0 {
+		result.AddMetadata("recent_chargeback_count", recentCount)
+		result.AddMetadata("recent_days", config.RecentDays)
+	}
+	return result, nil
+}
+
+// chargebackScore returns the score and minimum count of the highest threshold count reaches
+// A count below every threshold scores zero
+func chargebackScore(thresholds []fraud.ChargebackThreshold, count int64) (decimal.Decimal, int) {
+	score, minCount := decimal.Zero, 0
+	for _, t := range thresholds {
+		if t.MinCount > 0 && count >= int64(t.MinCount) && t.MinCount > minCount {
+			score, minCount = t.Score, t.MinCount
+		}
+	}
+	return score, minCount
 }
 
 // Helper functions
 
+// unavailableResult builds the result for a rule whose backing store is unavailable
+// Rules fail open unless their fail mode is closed, in which case they fire with
+// their configured action. Either way the result is marked degraded, and
+// evaluation_failed tells a fail-closed firing apart from a real match.
+func unavailableResult(rule *fraud.Rule, reason string) *fraud.RuleResult {
+	var result *fraud.RuleResult
+	failMode := fraud.FailModeOpen
+	if rule.FailsClosed() {
+		failMode = fraud.FailModeClosed
+		result = fraud.NewRuleResult(rule.ID, rule.Name, true, severityScore(rule.Severity), reason+"; failing closed", rule.Action)
+	} else {
+		result = fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, reason, fraud.ActionAllow)
+	}
+	result.Degraded = true
+	result.DataStatus = fraud.RuleDataErrored
+	result.AddMetadata("evaluation_failed", true)
+	result.AddMetadata("fail_mode", string(failMode))
+	return result
+}
+
+// insufficientDataResult builds the non-fired result for a rule the transaction lacks the context for
+func insufficientDataResult(rule *fraud.Rule, reason string) *fraud.RuleResult {
+	result := fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, reason, fraud.ActionAllow)
+	result.DataStatus = fraud.RuleDataInsufficient
+	return result
+}
+
+// notRunResult builds the non-fired result for a rule that didn't look at the transaction
+func notRunResult(rule *fraud.Rule, reason string) *fraud.RuleResult {
+	result := fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, reason, fraud.ActionAllow)
+	result.DataStatus = fraud.RuleDataNotRun
+	return result
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRegion reports whether a region key is in a configured region list,
+// ignoring case
+func containsRegion(regions []string, region string) bool {
+	for _, r := range regions {
+		if strings.EqualFold(r, region) {
+			return true
+		}
+	}
+	return false
+}
+
+// regionsForCountry returns the configured region keys that belong to a country
+func regionsForCountry(regions []string, country string) []string {
+	prefix := strings.ToUpper(country) + "-"
+	var matched []string
+	for _, r := range regions {
+		if strings.HasPrefix(strings.ToUpper(r), prefix) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
 func calculateVelocityScore(count int64, limit int) decimal.Decimal {
 	ratio := float64(count) / float64(limit)
 	if ratio >= 2.0 {
@@ -457,6 +1247,40 @@ func calculateAmountScore(amount, limit decimal.Decimal) decimal.Decimal {
 	return decimal.NewFromFloat(0.6 + (ratio-1.0)*0.2)
 }
 
+// hasCoordinates reports whether a location carries a valid latitude and longitude
+func hasCoordinates(location *fraud.GeoLocation) bool {
+	return location.HasCoordinates()
+}
+
+// lastLocatedTransaction returns the user's most recent earlier transaction with coordinates
+// The location cache is consulted first; the in-memory history is the fallback when the
+// cache is unavailable or has no points yet
+func (e *Engine) lastLocatedTransaction(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) *fraud.TransactionSummary {
+	if e.locationCache != nil {
+		point, err := e.locationCache.GetLastLocationPoint(ctx, evalCtx.UserID, evalCtx.TransactionID, evalCtx.Timestamp)
+		if err == nil && point != nil && fraud.ValidCoordinates(point.Latitude, point.Longitude) {
+			return &fraud.TransactionSummary{
+				ID:        point.TransactionID,
+				Timestamp: point.Timestamp,
+				Location: &fraud.GeoLocation{
+					Latitude:  point.Latitude,
+					Longitude: point.Longitude,
+					Country:   point.Country,
+					City:      point.City,
+				},
+			}
+		}
+	}
+
+	for i := range evalCtx.RecentTransactions {
+		tx := &evalCtx.RecentTransactions[i]
+		if tx.ID != evalCtx.TransactionID && hasCoordinates(tx.Location) && !tx.Timestamp.After(evalCtx.Timestamp) {
+			return tx
+		}
+	}
+	return nil
+}
+
 func haversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	const earthRadius = 6371.0 // km
 
@@ -467,6 +1291,8 @@ func haversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
 
 	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
 		math.Cos(lat1Rad)*math.Cos(lat2Rad)*math.Sin(deltaLon/2)*math.Sin(deltaLon/2)
+	// Rounding can push a just past 1 for near-antipodal points, which would make the root NaN
+	a = math.Min(a, 1)
 	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
 
 	return earthRadius * c
@@ -478,6 +1304,9 @@ func parseVelocityConfig(config map[string]interface{}) fraud.VelocityRuleConfig
 	result := fraud.VelocityRuleConfig{
 		MaxTransactions: 10,
 		WindowMinutes:   5,
+		CombineMode:     fraud.VelocityCombineProbabilistic,
+		CountWeight:     1.0,
+		AmountWeight:    1.0,
 	}
 
 	if v, ok := config["max_transactions"].(float64); ok {
@@ -492,6 +1321,36 @@ func parseVelocityConfig(config map[string]interface{}) fraud.VelocityRuleConfig
 	if v, ok := config["count_only"].(bool); ok {
 		result.CountOnly = v
 	}
+	if v, ok := config["combine_mode"].(string); ok {
+		result.CombineMode = fraud.VelocityCombineMode(v)
+	}
+	if v, ok := config["count_weight"].(float64); ok {
+		result.CountWeight = v
+	}
+	if v, ok := config["amount_weight"].(float64); ok {
+		result.AmountWeight = v
+	}
+	if v, ok := config["tiers"].([]interface{}); ok {
+		for _, t := range v {
+			m, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var tier fraud.VelocityTier
+			if w, ok := m["window_minutes"].(float64); ok {
+				tier.WindowMinutes = int(w)
+			}
+			if c, ok := m["max_count"].(float64); ok {
+				tier.MaxCount = int(c)
+			}
+			if a, ok := m["max_amount"].(string); ok {
+				tier.MaxAmount, _ = decimal.NewFromString(a)
+			}
+			if tier.WindowMinutes > 0 && (tier.MaxCount > 0 || tier.MaxAmount.IsPositive()) {
+				result.Tiers = append(result.Tiers, tier)
+			}
+		}
+	}
 
 	return result
 }
@@ -508,12 +1367,44 @@ func parseAmountConfig(config map[string]interface{}) fraud.AmountRuleConfig {
 	if v, ok := config["deviation_factor"].(float64); ok {
 		result.DeviationFactor = v
 	}
+	result.MinAmountByCurrency = parseCurrencyAmounts(config["min_amount_by_currency"])
+	result.MaxAmountByCurrency = parseCurrencyAmounts(config["max_amount_by_currency"])
 
 	return result
 }
 
+// parseCurrencyAmounts reads a {"JPY": "750000"} style map of decimal strings
+func parseCurrencyAmounts(value interface{}) map[string]decimal.Decimal {
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	amounts := make(map[string]decimal.Decimal, len(raw))
+	for currency, v := range raw {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if amount, err := decimal.NewFromString(s); err == nil {
+			amounts[strings.ToUpper(currency)] = amount
+		}
+	}
+	return amounts
+}
+
 func parseGeographicConfig(config map[string]interface{}) fraud.GeographicRuleConfig {
-	result := fraud.GeographicRuleConfig{}
+	result := fraud.GeographicRuleConfig{
+		NewLocationScore:      decimal.NewFromFloat(0.5),
+		NewLocationAction:     fraud.ActionChallenge,
+		KnownCountryScore:     decimal.NewFromFloat(0.25),
+		KnownCountryAction:    fraud.ActionAllow,
+		NewRegionScore:        decimal.NewFromFloat(0.4),
+		NewRegionAction:       fraud.ActionReview,
+		BlockedRegionScore:    decimal.NewFromFloat(0.9),
+		NonAllowedRegionScore: decimal.NewFromFloat(0.75),
+		MaxTravelSpeedKmH:     900, // Faster than a commercial jet
+	}
 
 	if v, ok := config["allowed_countries"].([]interface{}); ok {
 		for _, c := range v {
@@ -532,9 +1423,56 @@ func parseGeographicConfig(config map[string]interface{}) fraud.GeographicRuleCo
 	if v, ok := config["max_distance_km"].(float64); ok {
 		result.MaxDistanceKm = v
 	}
+	if v, ok := config["max_travel_speed_kmh"].(float64); ok && v > 0 {
+		result.MaxTravelSpeedKmH = v
+	}
 	if v, ok := config["require_consistent"].(bool); ok {
 		result.RequireConsistent = v
 	}
+	if v, ok := config["new_location_score"].(float64); ok {
+		result.NewLocationScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["new_location_action"].(string); ok {
+		result.NewLocationAction = fraud.RuleAction(v)
+	}
+	if v, ok := config["known_country_score"].(float64); ok {
+		result.KnownCountryScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["known_country_action"].(string); ok {
+		result.KnownCountryAction = fraud.RuleAction(v)
+	}
+	if v, ok := config["max_ip_distance_km"].(float64); ok {
+		result.MaxIPDistanceKm = v
+	}
+	if v, ok := config["allowed_regions"].([]interface{}); ok {
+		for _, r := range v {
+			if s, ok := r.(string); ok {
+				result.AllowedRegions = append(result.AllowedRegions, s)
+			}
+		}
+	}
+	if v, ok := config["blocked_regions"].([]interface{}); ok {
+		for _, r := range v {
+			if s, ok := r.(string); ok {
+				result.BlockedRegions = append(result.BlockedRegions, s)
+			}
+		}
+	}
+	if v, ok := config["blocked_region_score"].(float64); ok {
+		result.BlockedRegionScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["non_allowed_region_score"].(float64); ok {
+		result.NonAllowedRegionScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["require_consistent_region"].(bool); ok {
+		result.RequireConsistentRegion = v
+	}
+	if v, ok := config["new_region_score"].(float64); ok {
+		result.NewRegionScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["new_region_action"].(string); ok {
+		result.NewRegionAction = fraud.RuleAction(v)
+	}
 
 	return result
 }
@@ -548,6 +1486,9 @@ func parseDeviceConfig(config map[string]interface{}) fraud.DeviceRuleConfig {
 	if v, ok := config["max_devices_per_user"].(float64); ok {
 		result.MaxDevicesPerUser = int(v)
 	}
+	if v, ok := config["max_users_per_device"].(float64); ok {
+		result.MaxUsersPerDevice = int(v)
+	}
 	if v, ok := config["block_new_devices"].(bool); ok {
 		result.BlockNewDevices = v
 	}
@@ -555,3 +1496,195 @@ func parseDeviceConfig(config map[string]interface{}) fraud.DeviceRuleConfig {
 	return result
 }
 
+// defaultHighRiskMCCs is used when a merchant rule doesn't list its own high-risk categories
+var defaultHighRiskMCCs = []string{
+	"7995", // Gambling
+	"7801", // Lottery
+	"5967", // Direct Marketing
+	"6051", // Crypto
+}
+
+func parseMerchantConfig(config map[string]interface{}) fraud.MerchantRuleConfig {
+	result := fraud.MerchantRuleConfig{
+		NewMerchantWindowMinutes: 60,
+		HighRiskMCCs:             defaultHighRiskMCCs,
+		HighRiskScore:            decimal.NewFromFloat(0.4),
+		HighRiskAction:           fraud.ActionReview,
+		BlockedMCCScore:          decimal.NewFromFloat(0.9),
+		BlockedAction:            fraud.ActionBlock,
+	}
+
+	if v, ok := config["max_new_merchants"].(float64); ok {
+		result.MaxNewMerchants = int(v)
+	}
+	if v, ok := config["new_merchant_window_minutes"].(float64); ok {
+		result.NewMerchantWindowMinutes = int(v)
+	}
+	// An explicit empty list turns the high-risk category check off
+	if v, ok := config["high_risk_mccs"].([]interface{}); ok {
+		result.HighRiskMCCs = make([]string, 0, len(v))
+		for _, c := range v {
+			if s, ok := c.(string); ok {
+				result.HighRiskMCCs = append(result.HighRiskMCCs, s)
+			}
+		}
+	}
+	if v, ok := config["high_risk_score"].(float64); ok {
+		result.HighRiskScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["high_risk_action"].(string); ok {
+		result.HighRiskAction = fraud.RuleAction(v)
+	}
+	if v, ok := config["blocked_mccs"].([]interface{}); ok {
+		for _, c := range v {
+			if s, ok := c.(string); ok {
+				result.BlockedMCCs = append(result.BlockedMCCs, s)
+			}
+		}
+	}
+	if v, ok := config["blocked_mcc_score"].(float64); ok {
+		result.BlockedMCCScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["blocked_action"].(string); ok {
+		result.BlockedAction = fraud.RuleAction(v)
+	}
+
+	return result
+}
+
+func parseCardTestingConfig(config map[string]interface{}) fraud.CardTestingRuleConfig {
+	result := fraud.CardTestingRuleConfig{
+		MaxSmallTransactions: 10,
+		WindowMinutes:        10,
+		SmallAmountCeiling:   decimal.NewFromFloat(5),
+	}
+
+	if v, ok := config["max_small_transactions"].(float64); ok {
+		result.MaxSmallTransactions = int(v)
+	}
+	if v, ok := config["window_minutes"].(float64); ok {
+		result.WindowMinutes = int(v)
+	}
+	if v, ok := config["small_amount_ceiling"].(string); ok {
+		if d, err := decimal.NewFromString(v); err == nil {
+			result.SmallAmountCeiling = d
+		}
+	}
+
+	return result
+}
+
+func parseBehavioralConfig(config map[string]interface{}) fraud.BehavioralRuleConfig {
+	result := fraud.BehavioralRuleConfig{
+		NewAccountWindowHours: 24,
+		AgeHalfLifeHours:      72,
+		NewAccountScore:       decimal.NewFromFloat(0.5),
+		MinAgeRisk:            decimal.NewFromFloat(0.1),
+		UnusualHourStart:      2, // 2 AM - 5 AM is unusual
+		UnusualHourEnd:        5,
+		MinTypicalHours:       0, // Profile hours are opt-in, so existing rules keep the static window
+		DormantDays:           90,
+	}
+
+	if v, ok := config["new_account_window_hours"].(float64); ok {
+		result.NewAccountWindowHours = v
+	}
+	if v, ok := config["age_half_life_hours"].(float64); ok {
+		result.AgeHalfLifeHours = v
+	}
+	if v, ok := config["new_account_score"].(float64); ok {
+		result.NewAccountScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["min_age_risk"].(float64); ok {
+		result.MinAgeRisk = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["unusual_hour_start"].(float64); ok {
+		result.UnusualHourStart = int(v)
+	}
+	if v, ok := config["unusual_hour_end"].(float64); ok {
+		result.UnusualHourEnd = int(v)
+	}
+	if v, ok := config["min_typical_hours"].(float64); ok {
+		result.MinTypicalHours = int(v)
+	}
+	if v, ok := config["dormant_days"].(float64); ok {
+		result.DormantDays = int(v)
+	}
+
+	return result
+}
+
+func parseIPReputationConfig(config map[string]interface{}) fraud.IPReputationRuleConfig {
+	result := fraud.IPReputationRuleConfig{
+		TorExitScore:     decimal.NewFromFloat(0.8),
+		DatacenterScore:  decimal.NewFromFloat(0.5),
+		BlockedCIDRScore: decimal.NewFromFloat(0.9),
+	}
+
+	if v, ok := config["block_tor_exits"].(bool); ok {
+		result.BlockTorExits = v
+	}
+	if v, ok := config["block_datacenter_ips"].(bool); ok {
+		result.BlockDatacenterIPs = v
+	}
+	if v, ok := config["blocked_cidrs"].([]interface{}); ok {
+		for _, c := range v {
+			if s, ok := c.(string); ok {
+				result.BlockedCIDRs = append(result.BlockedCIDRs, s)
+			}
+		}
+	}
+	if v, ok := config["tor_exit_score"].(float64); ok {
+		result.TorExitScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["datacenter_score"].(float64); ok {
+		result.DatacenterScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["blocked_cidr_score"].(float64); ok {
+		result.BlockedCIDRScore = decimal.NewFromFloat(v)
+	}
+
+	return result
+}
+
+func parseChargebackConfig(config map[string]interface{}) fraud.ChargebackRuleConfig {
+	result := fraud.ChargebackRuleConfig{
+		WindowDays: 180,
+		Thresholds: []fraud.ChargebackThreshold{
+			{MinCount: 1, Score: decimal.NewFromFloat(0.5)},
+			{MinCount: 2, Score: decimal.NewFromFloat(0.75)},
+			{MinCount: 3, Score: decimal.NewFromFloat(0.9)},
+		},
+		RecentDays:  30,
+		RecentBoost: decimal.NewFromFloat(0.1),
+	}
+
+	if v, ok := config["window_days"].(float64); ok {
+		result.WindowDays = int(v)
+	}
+	if v, ok := config["thresholds"].([]interface{}); ok {
+		result.Thresholds = make([]fraud.ChargebackThreshold, 0, len(v))
+		for _, t := range v {
+			m, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var threshold fraud.ChargebackThreshold
+			if n, ok := m["min_count"].(float64); ok {
+				threshold.MinCount = int(n)
+			}
+			if s, ok := m["score"].(float64); ok {
+				threshold.Score = decimal.NewFromFloat(s)
+			}
+			result.Thresholds = append(result.Thresholds, threshold)
+		}
+	}
+	if v, ok := config["recent_days"].(float64); ok {
+		result.RecentDays = int(v)
+	}
+	if v, ok := config["recent_boost"].(float64); ok {
+		result.RecentBoost = decimal.NewFromFloat(v)
+	}
+
+	return result
+}
@@ -0,0 +1,309 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/infrastructure/cache/redis"
+	"fraud-detecction-system/internal/infrastructure/cache/redis/redistest"
+)
+
+func TestParseGeographicConfigNewLocation(t *testing.T) {
+	tests := []struct {
+		name               string
+		config             map[string]interface{}
+		newLocationScore   string
+		newLocationAction  fraud.RuleAction
+		knownCountryScore  string
+		knownCountryAction fraud.RuleAction
+	}{
+		{
+			name:               "defaults",
+			config:             map[string]interface{}{},
+			newLocationScore:   "0.5",
+			newLocationAction:  fraud.ActionChallenge,
+			knownCountryScore:  "0.25",
+			knownCountryAction: fraud.ActionAllow,
+		},
+		{
+			name: "configured",
+			config: map[string]interface{}{
+				"new_location_score":   0.8,
+				"new_location_action":  "block",
+				"known_country_score":  0.1,
+				"known_country_action": "review",
+			},
+			newLocationScore:   "0.8",
+			newLocationAction:  fraud.ActionBlock,
+			knownCountryScore:  "0.1",
+			knownCountryAction: fraud.ActionReview,
+		},
+		{
+			name:               "wrong types keep defaults",
+			config:             map[string]interface{}{"new_location_score": "high", "new_location_action": 3},
+			newLocationScore:   "0.5",
+			newLocationAction:  fraud.ActionChallenge,
+			knownCountryScore:  "0.25",
+			knownCountryAction: fraud.ActionAllow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseGeographicConfig(tt.config)
+			if !got.NewLocationScore.Equal(decimal.RequireFromString(tt.newLocationScore)) || got.NewLocationAction != tt.newLocationAction {
+				t.Errorf("new location = %s %s, want %s %s", got.NewLocationScore, got.NewLocationAction, tt.newLocationScore, tt.newLocationAction)
+			}
+			if !got.KnownCountryScore.Equal(decimal.RequireFromString(tt.knownCountryScore)) || got.KnownCountryAction != tt.knownCountryAction {
+				t.Errorf("known country = %s %s, want %s %s", got.KnownCountryScore, got.KnownCountryAction, tt.knownCountryScore, tt.knownCountryAction)
+			}
+		})
+	}
+}
+
+// staticGeoIP resolves every IP to the same location
+type staticGeoIP struct {
+	location *fraud.GeoLocation
+	err      error
+}
+
+func (r staticGeoIP) Resolve(ctx context.Context, ipAddress string) (*fraud.GeoLocation, error) {
+	return r.location, r.err
+}
+
+func TestEvaluateGeographicRuleIPMismatch(t *testing.T) {
+	newYork := fraud.GeoLocation{Country: "US", IPAddress: "203.0.113.7", Latitude: 40.7128, Longitude: -74.0060}
+	london := &fraud.GeoLocation{Country: "GB", Latitude: 51.5074, Longitude: -0.1278}
+	newark := &fraud.GeoLocation{Country: "US", Latitude: 40.7357, Longitude: -74.1724}
+
+	tests := []struct {
+		name      string
+		config    map[string]interface{}
+		location  fraud.GeoLocation
+		resolver  GeoIPResolver
+		wantFired bool
+	}{
+		{"IP far from GPS", map[string]interface{}{"max_ip_distance_km": 500.0}, newYork, staticGeoIP{location: london}, true},
+		{"IP near GPS", map[string]interface{}{"max_ip_distance_km": 500.0}, newYork, staticGeoIP{location: newark}, false},
+		{"check off", map[string]interface{}{}, newYork, staticGeoIP{location: london}, false},
+		{"no resolver", map[string]interface{}{"max_ip_distance_km": 500.0}, newYork, nil, false},
+		{"resolver error", map[string]interface{}{"max_ip_distance_km": 500.0}, newYork, staticGeoIP{err: errors.New("lookup failed")}, false},
+		{"IP without coordinates", map[string]interface{}{"max_ip_distance_km": 500.0}, newYork, staticGeoIP{location: &fraud.GeoLocation{Country: "GB"}}, false},
+		{"no IP address", map[string]interface{}{"max_ip_distance_km": 500.0}, fraud.GeoLocation{Country: "US", Latitude: 40.7128, Longitude: -74.0060}, staticGeoIP{location: london}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Engine{}
+			if tt.resolver != nil {
+				e.SetGeoIPResolver(tt.resolver)
+			}
+			rule := &fraud.Rule{Name: "Location", Type: fraud.RuleTypeGeographic, Config: tt.config, Action: fraud.ActionReview}
+			location := tt.location
+
+			result, err := e.evaluateGeographicRule(context.Background(), rule, &fraud.RuleEvaluationContext{Location: &location})
+			if err != nil {
+				t.Fatalf("evaluate: %v", err)
+			}
+			if result.Fired != tt.wantFired {
+				t.Fatalf("fired = %v, want %v (%s)", result.Fired, tt.wantFired, result.Reason)
+			}
+			if tt.wantFired {
+				if result.Action != fraud.ActionReview {
+					t.Errorf("action %s, want %s", result.Action, fraud.ActionReview)
+				}
+				if result.Metadata["ip_country"] != "GB" {
+					t.Errorf("ip_country = %v, want GB", result.Metadata["ip_country"])
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateGeographicRuleRegions(t *testing.T) {
+	consistent := map[string]interface{}{"require_consistent": true, "require_consistent_region": true}
+
+	tests := []struct {
+		name       string
+		config     map[string]interface{}
+		location   fraud.GeoLocation
+		wantFired  bool
+		wantScore  string
+		wantAction fraud.RuleAction
+	}{
+		{"known region", consistent, fraud.GeoLocation{Country: "US", Region: "CA", City: "Springfield"}, false, "0", fraud.ActionAllow},
+		{"known city in new region", consistent, fraud.GeoLocation{Country: "US", Region: "IL", City: "Springfield"}, true, "0.4", fraud.ActionReview},
+		{"configured new region", map[string]interface{}{"require_consistent_region": true, "new_region_score": 0.6, "new_region_action": "challenge"},
+			fraud.GeoLocation{Country: "US", Region: "IL", City: "Springfield"}, true, "0.6", fraud.ActionChallenge},
+		{"region check off", map[string]interface{}{"require_consistent": true}, fraud.GeoLocation{Country: "US", Region: "IL", City: "Springfield"}, false, "0", fraud.ActionAllow},
+		{"new region in new country", map[string]interface{}{"require_consistent_region": true}, fraud.GeoLocation{Country: "MX", Region: "JAL", City: "Guadalajara"}, false, "0", fraud.ActionAllow},
+		{"no region", consistent, fraud.GeoLocation{Country: "US", City: "Springfield"}, false, "0", fraud.ActionAllow},
+		{"blocked region", map[string]interface{}{"blocked_regions": []interface{}{"US-NV"}}, fraud.GeoLocation{Country: "US", Region: "NV", City: "Reno"}, true, "0.9", fraud.ActionReview},
+		{"blocked ISO region", map[string]interface{}{"blocked_regions": []interface{}{"us-nv"}}, fraud.GeoLocation{Country: "US", Region: "US-NV", City: "Reno"}, true, "0.9", fraud.ActionReview},
+		{"non-allowed region", map[string]interface{}{"allowed_regions": []interface{}{"US-CA"}}, fraud.GeoLocation{Country: "US", Region: "TX", City: "Austin"}, true, "0.75", fraud.ActionReview},
+		{"allowed region", map[string]interface{}{"allowed_regions": []interface{}{"US-CA"}}, fraud.GeoLocation{Country: "US", Region: "CA", City: "Fresno"}, false, "0", fraud.ActionAllow},
+		{"other country unrestricted", map[string]interface{}{"allowed_regions": []interface{}{"US-CA"}}, fraud.GeoLocation{Country: "GB", Region: "ENG", City: "London"}, false, "0", fraud.ActionAllow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			locations := redis.NewLocationCache(redistest.NewClient(t))
+			userID := uuid.New()
+			if err := locations.RecordLocation(ctx, userID, "US", "CA", "Springfield"); err != nil {
+				t.Fatalf("record: %v", err)
+			}
+			e := NewEngine(nil, nil, nil, locations, nil)
+			rule := &fraud.Rule{Name: "Location", Type: fraud.RuleTypeGeographic, Config: tt.config, Action: fraud.ActionReview}
+			location := tt.location
+
+			result, err := e.evaluateGeographicRule(ctx, rule, &fraud.RuleEvaluationContext{UserID: userID, Location: &location})
+			if err != nil {
+				t.Fatalf("evaluate: %v", err)
+			}
+			if result.Fired != tt.wantFired {
+				t.Fatalf("fired = %v, want %v (%s)", result.Fired, tt.wantFired, result.Reason)
+			}
+			if !result.Score.Equal(decimal.RequireFromString(tt.wantScore)) || result.Action != tt.wantAction {
+				t.Errorf("result %s %s, want %s %s (%s)", result.Score, result.Action, tt.wantScore, tt.wantAction, result.Reason)
+			}
+		})
+	}
+}
+
+func TestEvaluateGeographicRuleImpossibleTravel(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	london := &fraud.GeoLocation{Country: "GB", City: "London", Latitude: 51.5074, Longitude: -0.1278}
+	newark := &fraud.GeoLocation{Country: "US", City: "Newark", Latitude: 40.7357, Longitude: -74.1724}
+
+	tests := []struct {
+		name      string
+		previous  *fraud.GeoLocation
+		age       time.Duration // How long before the current transaction the previous one was
+		fromCache bool          // Previous point comes from the location cache rather than history
+		wantFired bool
+	}{
+		{"across the Atlantic in minutes", london, 30 * time.Minute, false, true},
+		{"across the Atlantic in minutes from cache", london, 30 * time.Minute, true, true},
+		{"across the Atlantic overnight", london, 12 * time.Hour, false, false},
+		{"nearby city", newark, 5 * time.Minute, false, false},
+		{"previous transaction is later", london, -30 * time.Minute, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			locations := redis.NewLocationCache(redistest.NewClient(t))
+			e := NewEngine(nil, nil, nil, locations, nil)
+			evalCtx := &fraud.RuleEvaluationContext{
+				TransactionID: uuid.New(),
+				UserID:        uuid.New(),
+				Timestamp:     now,
+				Location:      &fraud.GeoLocation{Country: "US", City: "New York", Latitude: 40.7128, Longitude: -74.0060},
+			}
+
+			previousID := uuid.New()
+			if tt.fromCache {
+				point := redis.LocationPoint{
+					TransactionID: previousID,
+					Country:       tt.previous.Country,
+					City:          tt.previous.City,
+					Latitude:      tt.previous.Latitude,
+					Longitude:     tt.previous.Longitude,
+					Timestamp:     now.Add(-tt.age),
+				}
+				if err := locations.RecordLocationPoint(ctx, evalCtx.UserID, point); err != nil {
+					t.Fatalf("record: %v", err)
+				}
+			} else {
+				evalCtx.RecentTransactions = []fraud.TransactionSummary{{ID: previousID, Timestamp: now.Add(-tt.age), Location: tt.previous}}
+			}
+
+			rule := &fraud.Rule{Name: "Travel", Type: fraud.RuleTypeGeographic, Config: map[string]interface{}{"max_distance_km": 500.0}, Action: fraud.ActionReview}
+			result, err := e.evaluateGeographicRule(ctx, rule, evalCtx)
+			if err != nil {
+				t.Fatalf("evaluate: %v", err)
+			}
+			if result.Fired != tt.wantFired {
+				t.Fatalf("fired = %v, want %v (%s)", result.Fired, tt.wantFired, result.Reason)
+			}
+			if !tt.wantFired {
+				return
+			}
+			if !result.Score.Equal(decimal.NewFromFloat(0.85)) || result.Action != fraud.ActionBlock {
+				t.Errorf("result %s %s, want 0.85 block", result.Score, result.Action)
+			}
+			if result.Metadata["previous_transaction_id"] != previousID.String() {
+				t.Errorf("previous transaction %v, want %s", result.Metadata["previous_transaction_id"], previousID)
+			}
+		})
+	}
+}
+
+func TestHaversineDistance(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		minKm, maxKm           float64
+	}{
+		{"across the antimeridian", -18.1416, 178.4419, -13.8333, -171.7667, 1100, 1200}, // Suva to Apia
+		{"across the antimeridian westbound", -13.8333, -171.7667, -18.1416, 178.4419, 1100, 1200},
+		{"over the pole", 89.9, 0, 89.9, 180, 20, 25},
+		{"antipodal points", 0, 0.1, 0, -179.9, 20000, 20020},
+		{"same point", 51.5074, -0.1278, 51.5074, -0.1278, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := haversineDistance(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if math.IsNaN(got) || got < tt.minKm || got > tt.maxKm {
+				t.Errorf("distance %.1fkm, want %.0f-%.0fkm", got, tt.minKm, tt.maxKm)
+			}
+		})
+	}
+}
+
+func TestEvaluateGeographicRuleOutOfRangeCoordinates(t *testing.T) {
+	now := time.Now()
+	newYork := fraud.GeoLocation{Country: "US", City: "New York", Latitude: 40.7128, Longitude: -74.0060}
+	london := fraud.GeoLocation{Country: "GB", City: "London", Latitude: 51.5074, Longitude: -0.1278}
+
+	tests := []struct {
+		name      string
+		current   fraud.GeoLocation
+		previous  fraud.GeoLocation
+		wantFired bool
+	}{
+		{"valid coordinates", newYork, london, true},
+		{"current latitude out of range", fraud.GeoLocation{Country: "US", Latitude: 999, Longitude: -74.0060}, london, false},
+		{"current longitude out of range", fraud.GeoLocation{Country: "US", Latitude: 40.7128, Longitude: -200}, london, false},
+		{"previous latitude out of range", newYork, fraud.GeoLocation{Country: "GB", Latitude: -91, Longitude: -0.1278}, false},
+		{"previous at null island", newYork, fraud.GeoLocation{Country: "GB"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, previous := tt.current, tt.previous
+			evalCtx := &fraud.RuleEvaluationContext{
+				TransactionID:      uuid.New(),
+				UserID:             uuid.New(),
+				Timestamp:          now,
+				Location:           &current,
+				RecentTransactions: []fraud.TransactionSummary{{ID: uuid.New(), Timestamp: now.Add(-30 * time.Minute), Location: &previous}},
+			}
+			rule := &fraud.Rule{Name: "Travel", Type: fraud.RuleTypeGeographic, Config: map[string]interface{}{"max_distance_km": 500.0}, Action: fraud.ActionReview}
+
+			result, err := (&Engine{}).evaluateGeographicRule(context.Background(), rule, evalCtx)
+			if err != nil {
+				t.Fatalf("evaluate: %v", err)
+			}
+			if result.Fired != tt.wantFired {
+				t.Errorf("fired = %v, want %v (%s)", result.Fired, tt.wantFired, result.Reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package rules
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// GeoIPResolver resolves an IP address to its approximate geographic location
+// Implementations typically wrap a MaxMind/IP2Location database or lookup API
+type GeoIPResolver interface {
+	Resolve(ctx context.Context, ipAddress string) (*fraud.GeoLocation, error)
+}
+
+// ErrIPNotLocated is returned for an IP address outside every known network
+var ErrIPNotLocated = errors.New("ip address not located")
+
+// CIDRGeoIPResolver resolves IPs from a table of network ranges
+// Overlapping networks resolve to the most specific one.
+type CIDRGeoIPResolver struct {
+	networks map[int]map[netip.Prefix]fraud.GeoLocation // Keyed by prefix length, then masked prefix
+	lengths  []int                                      // Prefix lengths present, longest first
+}
+
+// LoadCIDRGeoIPResolver reads a resolver from a CSV file; see NewCIDRGeoIPResolver
+func LoadCIDRGeoIPResolver(path string) (*CIDRGeoIPResolver, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open geoip database: %w", err)
+	}
+	defer f.Close()
+	return NewCIDRGeoIPResolver(f)
+}
+
+// NewCIDRGeoIPResolver reads rows of network,country,region,city,latitude,longitude
+// An optional header row starting with "network" is skipped.
+func NewCIDRGeoIPResolver(r io.Reader) (*CIDRGeoIPResolver, error) {
+	resolver := &CIDRGeoIPResolver{networks: make(map[int]map[netip.Prefix]fraud.GeoLocation)}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 6
+	reader.TrimLeadingSpace = true
+	for line := 1; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read geoip database: %w", err)
+		}
+		if line == 1 && strings.EqualFold(row[0], "network") {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(row[0])
+		if err != nil {
+			return nil, fmt.Errorf("geoip database line %d: %w", line, err)
+		}
+		lat, latErr := strconv.ParseFloat(row[4], 64)
+		lon, lonErr := strconv.ParseFloat(row[5], 64)
+		if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return nil, fmt.Errorf("geoip database line %d: invalid coordinates %q, %q", line, row[4], row[5])
+		}
+
+		prefix = prefix.Masked()
+		byPrefix, ok := resolver.networks[prefix.Bits()]
+		if !ok {
+			byPrefix = make(map[netip.Prefix]fraud.GeoLocation)
+			resolver.networks[prefix.Bits()] = byPrefix
+			resolver.lengths = append(resolver.lengths, prefix.Bits())
+		}
+		byPrefix[prefix] = fraud.GeoLocation{
+			Country:   strings.ToUpper(row[1]),
+			Region:    row[2],
+			City:      row[3],
+			Latitude:  lat,
+			Longitude: lon,
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(resolver.lengths)))
+	return resolver, nil
+}
+
+// Resolve implements GeoIPResolver
+func (r *CIDRGeoIPResolver) Resolve(ctx context.Context, ipAddress string) (*fraud.GeoLocation, error) {
+	addr, err := netip.ParseAddr(ipAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ip address %q: %w", ipAddress, err)
+	}
+	addr = addr.Unmap()
+
+	for _, bits := range r.lengths {
+		if bits > addr.BitLen() {
+			continue
+		}
+		prefix, err := addr.Prefix(bits)
+		if err != nil {
+			continue
+		}
+		if location, ok := r.networks[bits][prefix]; ok {
+			location.IPAddress = ipAddress
+			return &location, nil
+		}
+	}
+	return nil, ErrIPNotLocated
+}
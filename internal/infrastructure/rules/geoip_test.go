@@ -0,0 +1,74 @@
+package rules
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCIDRGeoIPResolver(t *testing.T) {
+	const database = `network,country,region,city,latitude,longitude
+203.0.113.0/24,us,NY,New York,40.7128,-74.0060
+203.0.113.128/25,US,NJ,Newark,40.7357,-74.1724
+198.51.100.0/24,GB,ENG,London,51.5074,-0.1278
+2001:db8::/32,DE,BE,Berlin,52.5200,13.4050
+`
+	resolver, err := NewCIDRGeoIPResolver(strings.NewReader(database))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		ip       string
+		wantCity string // Empty when the IP should not resolve
+	}{
+		{"IPv4 network", "203.0.113.7", "New York"},
+		{"most specific network wins", "203.0.113.200", "Newark"},
+		{"other network", "198.51.100.1", "London"},
+		{"IPv6 network", "2001:db8::1", "Berlin"},
+		{"IPv4-mapped IPv6", "::ffff:198.51.100.1", "London"},
+		{"unknown network", "192.0.2.1", ""},
+		{"not an IP", "example.com", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, err := resolver.Resolve(context.Background(), tt.ip)
+			if tt.wantCity == "" {
+				if err == nil {
+					t.Fatalf("resolved to %+v, want an error", location)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if location.City != tt.wantCity || location.IPAddress != tt.ip || location.Latitude == 0 || location.Longitude == 0 {
+				t.Errorf("resolved to %+v, want %s with coordinates", location, tt.wantCity)
+			}
+		})
+	}
+
+	if location, _ := resolver.Resolve(context.Background(), "203.0.113.7"); location.Country != "US" {
+		t.Errorf("country %q, want it upper-cased to US", location.Country)
+	}
+}
+
+func TestNewCIDRGeoIPResolverRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		database string
+	}{
+		{"bad network", "203.0.113.0/33,US,NY,New York,40.7,-74.0\n"},
+		{"bad latitude", "203.0.113.0/24,US,NY,New York,north,-74.0\n"},
+		{"out of range longitude", "203.0.113.0/24,US,NY,New York,40.7,-274.0\n"},
+		{"missing column", "203.0.113.0/24,US,NY,40.7,-74.0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCIDRGeoIPResolver(strings.NewReader(tt.database)); err == nil {
+				t.Error("database loaded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package rules
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IPReputationProvider supplies known-bad IP ranges for IP reputation rules
+// Implementations may read from a threat feed, database, or static file
+type IPReputationProvider interface {
+	// TorExitNodes returns TOR exit node IPs or CIDR ranges
+	TorExitNodes(ctx context.Context) ([]string, error)
+
+	// DatacenterRanges returns CIDR ranges owned by hosting/datacenter ASNs
+	DatacenterRanges(ctx context.Context) ([]string, error)
+
+	// BlockedIPs returns IPs or CIDR ranges previously blocked for fraud
+	BlockedIPs(ctx context.Context) ([]string, error)
+}
+
+// StaticIPReputationProvider serves fixed IP lists, useful for standalone mode
+type StaticIPReputationProvider struct {
+	TorExits   []string
+	Datacenter []string
+	Blocked    []string
+}
+
+// TorExitNodes returns the configured TOR exit nodes
+func (p *StaticIPReputationProvider) TorExitNodes(ctx context.Context) ([]string, error) {
+	return p.TorExits, nil
+}
+
+// DatacenterRanges returns the configured datacenter ranges
+func (p *StaticIPReputationProvider) DatacenterRanges(ctx context.Context) ([]string, error) {
+	return p.Datacenter, nil
+}
+
+// BlockedIPs returns the configured blocked IPs
+func (p *StaticIPReputationProvider) BlockedIPs(ctx context.Context) ([]string, error) {
+	return p.Blocked, nil
+}
+
+// ipReputationLists holds the parsed IP lists
+type ipReputationLists struct {
+	torExits   []*net.IPNet
+	datacenter []*net.IPNet
+	blocked    []*net.IPNet
+}
+
+// ipReputationCache caches parsed provider lists and refreshes them after a TTL
+type ipReputationCache struct {
+	provider    IPReputationProvider
+	lists       *ipReputationLists
+	mu          sync.RWMutex
+	lastRefresh time.Time
+	cacheTTL    time.Duration
+}
+
+func newIPReputationCache(provider IPReputationProvider, cacheTTL time.Duration) *ipReputationCache {
+	return &ipReputationCache{
+		provider: provider,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// get returns the cached lists, refreshing from the provider when stale
+// A failed refresh keeps serving the previous lists
+func (c *ipReputationCache) get(ctx context.Context) *ipReputationLists {
+	c.mu.RLock()
+	if c.lists != nil && time.Since(c.lastRefresh) < c.cacheTTL {
+		lists := c.lists
+		c.mu.RUnlock()
+		return lists
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Double-check after acquiring write lock
+	if c.lists != nil && time.Since(c.lastRefresh) < c.cacheTTL {
+		return c.lists
+	}
+
+	if c.provider == nil {
+		c.lists = &ipReputationLists{}
+		c.lastRefresh = time.Now()
+		return c.lists
+	}
+
+	torExits, err := c.provider.TorExitNodes(ctx)
+	if err != nil {
+		return c.staleOrEmpty()
+	}
+	datacenter, err := c.provider.DatacenterRanges(ctx)
+	if err != nil {
+		return c.staleOrEmpty()
+	}
+	blocked, err := c.provider.BlockedIPs(ctx)
+	if err != nil {
+		return c.staleOrEmpty()
+	}
+
+	c.lists = &ipReputationLists{
+		torExits:   parseCIDRs(torExits),
+		datacenter: parseCIDRs(datacenter),
+		blocked:    parseCIDRs(blocked),
+	}
+	c.lastRefresh = time.Now()
+	return c.lists
+}
+
+func (c *ipReputationCache) staleOrEmpty() *ipReputationLists {
+	if c.lists != nil {
+		return c.lists
+	}
+	return &ipReputationLists{}
+}
+
+func (c *ipReputationCache) invalidate() {
+	c.mu.Lock()
+	c.lists = nil
+	c.mu.Unlock()
+}
+
+// parseCIDRs parses CIDR ranges and bare IPs, skipping invalid entries
+func parseCIDRs(entries []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				continue
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// containsIP checks whether any network contains the IP
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
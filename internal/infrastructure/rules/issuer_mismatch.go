@@ -0,0 +1,92 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// evaluateIssuerMismatchRule checks whether a card is used outside the country that issued it
+// Stolen card numbers are mostly used far from the cardholder, so a card-not-present
+// purchase from another country than the issuer's is a classic fraud signal. A merchant
+// in a third country as well makes it stronger.
+func (e *Engine) evaluateIssuerMismatchRule(rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext) (*fraud.RuleResult, error) {
+	if evalCtx.Location == nil || evalCtx.Location.Country == "" ||
+		evalCtx.Payment == nil || evalCtx.Payment.IssuingCountry == "" {
+		return insufficientDataResult(rule, "No location or issuing country"), nil
+	}
+	if !fraud.IsCrossBorder(evalCtx.Location, evalCtx.Payment) {
+		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Card used in its issuing country", fraud.ActionAllow), nil
+	}
+
+	config := parseIssuerMismatchConfig(rule.Config)
+	location := strings.ToUpper(evalCtx.Location.Country)
+	issuer := strings.ToUpper(evalCtx.Payment.IssuingCountry)
+	merchant := ""
+	if evalCtx.Merchant != nil {
+		merchant = strings.ToUpper(evalCtx.Merchant.Country)
+	}
+
+	// A corridor only excuses the cardholder travelling; a merchant in a third country still fires
+	threeCountry := merchant != "" && merchant != location && merchant != issuer
+	if !threeCountry && inTravelCorridor(config.TravelCorridors, issuer, location) {
+		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, fmt.Sprintf("Known travel corridor: %s-%s", issuer, location), fraud.ActionAllow), nil
+	}
+
+	score := config.Score
+	reason := fmt.Sprintf("Card issued in %s used from %s", issuer, location)
+	if threeCountry {
+		score = config.ThreeCountryScore
+		reason = fmt.Sprintf("Card issued in %s used from %s at a merchant in %s", issuer, location, merchant)
+	}
+
+	result := fraud.NewRuleResult(rule.ID, rule.Name, true, score, reason, rule.Action)
+	result.AddMetadata("issuing_country", issuer)
+	result.AddMetadata("location_country", location)
+	if merchant != "" {
+		result.AddMetadata("merchant_country", merchant)
+	}
+	result.AddMetadata("three_country", threeCountry)
+	return result, nil
+}
+
+// inTravelCorridor reports whether the issuing and location countries form a configured corridor
+// Corridors are written "US-CA" and match in either direction
+func inTravelCorridor(corridors []string, issuer, location string) bool {
+	for _, corridor := range corridors {
+		from, to, ok := strings.Cut(strings.ToUpper(corridor), "-")
+		if !ok {
+			continue
+		}
+		if (from == issuer && to == location) || (from == location && to == issuer) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseIssuerMismatchConfig(config map[string]interface{}) fraud.IssuerMismatchRuleConfig {
+	result := fraud.IssuerMismatchRuleConfig{
+		Score:             decimal.NewFromFloat(0.5),
+		ThreeCountryScore: decimal.NewFromFloat(0.7),
+	}
+
+	if v, ok := config["score"].(float64); ok {
+		result.Score = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["three_country_score"].(float64); ok {
+		result.ThreeCountryScore = decimal.NewFromFloat(v)
+	}
+	if v, ok := config["travel_corridors"].([]interface{}); ok {
+		for _, c := range v {
+			if s, ok := c.(string); ok {
+				result.TravelCorridors = append(result.TravelCorridors, s)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,49 @@
+package rules
+
+import (
+	"context"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// knownDevice reports whether the user has used the transaction's device before
+// Like the helpers below, it reads the answer enrichment looked up for the whole
+// analysis, and only asks the cache when there is none
+func (e *Engine) knownDevice(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) (bool, error) {
+	if evalCtx.Known != nil && evalCtx.Known.Device != nil {
+		return *evalCtx.Known.Device, nil
+	}
+	return e.deviceCache.IsKnownDevice(ctx, evalCtx.UserID, evalCtx.Device.DeviceID)
+}
+
+// deviceCount returns how many distinct devices the user has transacted from
+func (e *Engine) deviceCount(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) (int64, error) {
+	if evalCtx.Known != nil && evalCtx.Known.DeviceCount != nil {
+		return *evalCtx.Known.DeviceCount, nil
+	}
+	return e.deviceCache.GetDeviceCount(ctx, evalCtx.UserID)
+}
+
+// knownLocation reports whether the user has transacted from the transaction's country and city
+func (e *Engine) knownLocation(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) (bool, error) {
+	if evalCtx.Known != nil && evalCtx.Known.Location != nil {
+		return *evalCtx.Known.Location, nil
+	}
+	return e.locationCache.IsKnownLocation(ctx, evalCtx.UserID, evalCtx.Location.Country, evalCtx.Location.City)
+}
+
+// knownCountry reports whether the user has transacted from any city in the transaction's country
+func (e *Engine) knownCountry(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) (bool, error) {
+	if evalCtx.Known != nil && evalCtx.Known.Country != nil {
+		return *evalCtx.Known.Country, nil
+	}
+	return e.locationCache.IsKnownCountry(ctx, evalCtx.UserID, evalCtx.Location.Country)
+}
+
+// knownMerchant reports whether the user has transacted with the transaction's merchant before
+func (e *Engine) knownMerchant(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) (bool, error) {
+	if evalCtx.Known != nil && evalCtx.Known.Merchant != nil {
+		return *evalCtx.Known.Merchant, nil
+	}
+	return e.merchantCache.IsKnownMerchant(ctx, evalCtx.UserID, evalCtx.Merchant.MerchantID)
+}
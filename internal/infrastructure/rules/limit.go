@@ -0,0 +1,80 @@
+package rules
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// severityRank orders severities from most to least important
+var severityRank = map[fraud.RuleSeverity]int{
+	fraud.SeverityCritical: 0,
+	fraud.SeverityHigh:     1,
+	fraud.SeverityMedium:   2,
+	fraud.SeverityLow:      3,
+}
+
+// prioritizeRules splits rules into the top max by priority and the rest
+// Shadow rules never affect a decision, so they don't take a slot and always run.
+// The input slice is shared with the rule cache, so it is copied rather than sorted in place.
+// A max of 0 or less keeps every rule.
+func prioritizeRules(rules []*fraud.Rule, max int) (kept, dropped []*fraud.Rule) {
+	if max <= 0 {
+		return rules, nil
+	}
+
+	var counted, shadow []*fraud.Rule
+	for _, rule := range rules {
+		if rule.IsShadow() {
+			shadow = append(shadow, rule)
+		} else {
+			counted = append(counted, rule)
+		}
+	}
+	if len(counted) <= max {
+		return rules, nil
+	}
+
+	ordered := orderByPriority(counted)
+	return append(ordered[:max:max], shadow...), ordered[max:]
+}
+
+// orderByPriority returns a copy of rules sorted from highest to lowest priority
+// A higher Priority comes first. Ties go to block rules, then rules by descending
+// severity, then the oldest rule.
+func orderByPriority(rules []*fraud.Rule) []*fraud.Rule {
+	ordered := make([]*fraud.Rule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if blockA, blockB := a.Action == fraud.ActionBlock, b.Action == fraud.ActionBlock; blockA != blockB {
+			return blockA
+		}
+		if rankA, rankB := rankSeverity(a.Severity), rankSeverity(b.Severity); rankA != rankB {
+			return rankA < rankB
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return ordered
+}
+
+// rankSeverity returns the sort rank of a severity, placing unknown values last
+func rankSeverity(severity fraud.RuleSeverity) int {
+	if rank, ok := severityRank[severity]; ok {
+		return rank
+	}
+	return len(severityRank)
+}
+
+// limitedResult builds the placeholder result for a rule dropped by the per-transaction limit
+func limitedResult(rule *fraud.Rule) *fraud.RuleResult {
+	result := fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Rule skipped: per-transaction rule limit reached", fraud.ActionAllow)
+	result.RuleType = rule.Type
+	result.Skipped = true
+	return result
+}
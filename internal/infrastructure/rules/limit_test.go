@@ -0,0 +1,59 @@
+package rules
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+func TestEvaluateMaxRulesPerTransaction(t *testing.T) {
+	prioritized := amountRule("prioritized", fraud.SeverityLow, fraud.ActionReview)
+	prioritized.Priority = 5
+	shadow := amountRule("shadow", fraud.SeverityLow, fraud.ActionReview)
+	shadow.Mode = fraud.RuleModeShadow
+	active := []*fraud.Rule{
+		amountRule("low_review", fraud.SeverityLow, fraud.ActionReview),
+		amountRule("critical_review", fraud.SeverityCritical, fraud.ActionReview),
+		amountRule("low_block", fraud.SeverityLow, fraud.ActionBlock),
+		prioritized,
+		shadow,
+	}
+
+	tests := []struct {
+		name    string
+		max     int
+		wantRan []string // Sorted
+	}{
+		{"no limit", 0, []string{"critical_review", "low_block", "low_review", "prioritized", "shadow"}},
+		{"under the limit", 4, []string{"critical_review", "low_block", "low_review", "prioritized", "shadow"}},
+		{"priority first", 1, []string{"prioritized", "shadow"}},
+		{"then block rules", 2, []string{"low_block", "prioritized", "shadow"}},
+		{"then severity", 3, []string{"critical_review", "low_block", "prioritized", "shadow"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEngine(&staticRuleRepo{rules: active}, nil, nil, nil, nil)
+			e.SetMaxRulesPerTransaction(tt.max)
+
+			results, err := e.Evaluate(context.Background(), historyContext("500"))
+			if err != nil {
+				t.Fatalf("evaluate: %v", err)
+			}
+			if len(results) != len(active) {
+				t.Fatalf("%d results, want one per active rule (%d)", len(results), len(active))
+			}
+			var ran []string
+			for _, result := range results {
+				if !result.Skipped {
+					ran = append(ran, result.RuleName)
+				}
+			}
+			slices.Sort(ran)
+			if !slices.Equal(ran, tt.wantRan) {
+				t.Errorf("ran %v, want %v", ran, tt.wantRan)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/infrastructure/cache/redis"
+)
+
+func TestParseMerchantConfigNewMerchants(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     map[string]interface{}
+		maxNew     int
+		windowMins int
+	}{
+		{"defaults", map[string]interface{}{}, 0, 60},
+		{"configured", map[string]interface{}{"max_new_merchants": float64(3), "new_merchant_window_minutes": float64(180)}, 3, 180},
+		{"wrong types ignored", map[string]interface{}{"max_new_merchants": "3", "new_merchant_window_minutes": "180"}, 0, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMerchantConfig(tt.config)
+			if got.MaxNewMerchants != tt.maxNew {
+				t.Errorf("max new merchants %d, want %d", got.MaxNewMerchants, tt.maxNew)
+			}
+			if got.NewMerchantWindowMinutes != tt.windowMins {
+				t.Errorf("window %d minutes, want %d", got.NewMerchantWindowMinutes, tt.windowMins)
+			}
+		})
+	}
+}
+
+func TestEnsureMerchantRetention(t *testing.T) {
+	merchantRule := func(windowMinutes float64) *fraud.Rule {
+		return &fraud.Rule{Type: fraud.RuleTypeMerchant, Config: map[string]interface{}{"new_merchant_window_minutes": windowMinutes}}
+	}
+
+	tests := []struct {
+		name  string
+		rules []*fraud.Rule
+		want  time.Duration
+	}{
+		{"no merchant rules", nil, 24 * time.Hour},
+		{"window under the default", []*fraud.Rule{merchantRule(60)}, 24 * time.Hour},
+		{"longest window wins", []*fraud.Rule{merchantRule(2 * 24 * 60), merchantRule(3 * 24 * 60)}, 3 * 24 * time.Hour},
+		{"other rule types ignored", []*fraud.Rule{{Type: fraud.RuleTypeVelocity, Config: map[string]interface{}{"new_merchant_window_minutes": float64(5 * 24 * 60)}}}, 24 * time.Hour},
+		{"capped at the maximum retention", []*fraud.Rule{merchantRule(365 * 24 * 60)}, redis.MaxVelocityRetention},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Engine{merchantCache: redis.NewMerchantCache(nil)}
+			e.ensureMerchantRetention(tt.rules)
+			if got := e.merchantCache.Retention(); got != tt.want {
+				t.Errorf("retention %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
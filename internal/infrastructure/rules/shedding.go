@@ -0,0 +1,45 @@
+package rules
+
+import (
+	"math/rand/v2"
+
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// LoadShedding controls which rules may be skipped when the engine is overloaded
+// Block rules and high or critical severity rules always run
+type LoadShedding struct {
+	ConcurrencyThreshold int     // Evaluations in flight above which shedding starts, 0 disables
+	SampleRate           float64 // Fraction of sheddable rules still evaluated while shedding
+}
+
+// active reports whether the given number of in-flight evaluations triggers shedding
+func (s LoadShedding) active(inFlight int64) bool {
+	return s.ConcurrencyThreshold > 0 && inFlight > int64(s.ConcurrencyThreshold)
+}
+
+// shouldSkip samples a sheddable rule, keeping it with probability SampleRate
+func (s LoadShedding) shouldSkip(rule *fraud.Rule) bool {
+	if !sheddable(rule) {
+		return false
+	}
+	return rand.Float64() >= s.SampleRate
+}
+
+// sheddable reports whether a rule is low enough priority to be skipped under load
+func sheddable(rule *fraud.Rule) bool {
+	if rule.Action == fraud.ActionBlock {
+		return false
+	}
+	return rule.Severity == fraud.SeverityLow || rule.Severity == fraud.SeverityMedium
+}
+
+// shedResult builds the placeholder result for a rule skipped by load shedding
+func shedResult(rule *fraud.Rule) *fraud.RuleResult {
+	result := fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Rule skipped under load", fraud.ActionAllow)
+	result.RuleType = rule.Type
+	result.Skipped = true
+	return result
+}
@@ -0,0 +1,77 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// amountRule is an amount rule that fires on anything over 10
+func amountRule(name string, severity fraud.RuleSeverity, action fraud.RuleAction) *fraud.Rule {
+	rule := fraud.NewRule(name, "", fraud.RuleTypeAmount, severity, action, uuid.Nil)
+	rule.Config = map[string]interface{}{"max_amount": "10"}
+	return rule
+}
+
+func TestEvaluateLoadShedding(t *testing.T) {
+	critical := []*fraud.Rule{
+		amountRule("low_block", fraud.SeverityLow, fraud.ActionBlock),
+		amountRule("high_review", fraud.SeverityHigh, fraud.ActionReview),
+		amountRule("critical_review", fraud.SeverityCritical, fraud.ActionReview),
+	}
+	sheddable := []*fraud.Rule{
+		amountRule("low_review", fraud.SeverityLow, fraud.ActionReview),
+		amountRule("medium_challenge", fraud.SeverityMedium, fraud.ActionChallenge),
+	}
+
+	tests := []struct {
+		name       string
+		shedding   LoadShedding
+		inFlight   int64 // Other evaluations already in flight
+		runs       int
+		minSampled float64 // Bounds on the share of sheddable rules evaluated
+		maxSampled float64
+	}{
+		{"under the threshold", LoadShedding{ConcurrencyThreshold: 5, SampleRate: 0}, 2, 20, 1, 1},
+		{"disabled", LoadShedding{SampleRate: 0}, 100, 20, 1, 1},
+		{"shedding everything", LoadShedding{ConcurrencyThreshold: 5, SampleRate: 0}, 10, 20, 0, 0},
+		{"sampling half", LoadShedding{ConcurrencyThreshold: 5, SampleRate: 0.5}, 10, 1000, 0.4, 0.6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEngine(&staticRuleRepo{rules: append(append([]*fraud.Rule{}, critical...), sheddable...)}, nil, nil, nil, nil)
+			e.SetLoadShedding(tt.shedding)
+			e.inFlight.Add(tt.inFlight)
+
+			evalCtx := &fraud.RuleEvaluationContext{UserID: uuid.New(), Amount: decimal.NewFromInt(500), Currency: "USD"}
+			ran := make(map[string]int)
+			for i := 0; i < tt.runs; i++ {
+				results, err := e.Evaluate(context.Background(), evalCtx)
+				if err != nil {
+					t.Fatalf("evaluate: %v", err)
+				}
+				for _, result := range results {
+					if !result.Skipped {
+						ran[result.RuleName]++
+					}
+				}
+			}
+
+			for _, rule := range critical {
+				if ran[rule.Name] != tt.runs {
+					t.Errorf("%s ran %d of %d times, want every time", rule.Name, ran[rule.Name], tt.runs)
+				}
+			}
+			for _, rule := range sheddable {
+				share := float64(ran[rule.Name]) / float64(tt.runs)
+				if share < tt.minSampled || share > tt.maxSampled {
+					t.Errorf("%s ran %.2f of the time, want %.2f-%.2f", rule.Name, share, tt.minSampled, tt.maxSampled)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package rules
+
+import (
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// stopsEvaluation reports whether a fired rule is decisive enough to end evaluation
+// Only critical severity block rules qualify, and only when the result itself blocks;
+// a geographic rule that fired with a softer action, e.g. for travel, doesn't
+func stopsEvaluation(rule *fraud.Rule, result *fraud.RuleResult) bool {
+	return rule.Severity == fraud.SeverityCritical && rule.Action == fraud.ActionBlock &&
+		result.Fired && result.Action == fraud.ActionBlock
+}
+
+// stoppedResult builds the placeholder result for a rule left out after a critical block rule fired
+func stoppedResult(rule *fraud.Rule, stoppedBy string) *fraud.RuleResult {
+	result := fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Rule skipped: evaluation stopped by "+stoppedBy, fraud.ActionAllow)
+	result.RuleType = rule.Type
+	result.Skipped = true
+	return result
+}
@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// TimeoutPolicy decides how a rule that exceeds its evaluation timeout is scored
+type TimeoutPolicy string
+
+const (
+	TimeoutFailOpen   TimeoutPolicy = "fail_open"   // Treat the rule as not fired
+	TimeoutFailClosed TimeoutPolicy = "fail_closed" // Fire the rule with its configured action
+)
+
+// RuleTimeout bounds how long rules of one type may take to evaluate
+type RuleTimeout struct {
+	Timeout time.Duration
+	Policy  TimeoutPolicy
+}
+
+// evaluateWithTimeout runs a rule, cutting it off once the timeout elapses
+// The rule runs in its own goroutine so a cache call that ignores cancellation
+// still can't hold up the remaining rules
+func (e *Engine) evaluateWithTimeout(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext, timeout RuleTimeout) (*fraud.RuleResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout.Timeout)
+	defer cancel()
+
+	type outcome struct {
+		result *fraud.RuleResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := e.evaluateRule(ctx, rule, evalCtx)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		// Rules that swallow cache errors return normally once the context expires,
+		// so only trust the result if the deadline hasn't passed
+		if ctx.Err() == nil {
+			return out.result, out.err
+		}
+	case <-ctx.Done():
+	}
+
+	return timeoutResult(rule, timeout), nil
+}
+
+// timeoutResult builds the result for a rule that timed out according to its policy
+func timeoutResult(rule *fraud.Rule, timeout RuleTimeout) *fraud.RuleResult {
+	reason := fmt.Sprintf("Rule evaluation timed out after %s", timeout.Timeout)
+
+	var result *fraud.RuleResult
+	if timeout.Policy == TimeoutFailClosed {
+		result = fraud.NewRuleResult(rule.ID, rule.Name, true, severityScore(rule.Severity), reason, rule.Action)
+	} else {
+		result = fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, reason, fraud.ActionAllow)
+	}
+	result.DataStatus = fraud.RuleDataErrored
+	result.AddMetadata("timed_out", true)
+	result.AddMetadata("timeout_policy", string(timeout.Policy))
+	return result
+}
+
+// severityScore maps a rule's severity to the score used when it fails closed
+func severityScore(severity fraud.RuleSeverity) decimal.Decimal {
+	switch severity {
+	case fraud.SeverityCritical:
+		return decimal.NewFromFloat(0.9)
+	case fraud.SeverityHigh:
+		return decimal.NewFromFloat(0.7)
+	case fraud.SeverityMedium:
+		return decimal.NewFromFloat(0.5)
+	default:
+		return decimal.NewFromFloat(0.3)
+	}
+}
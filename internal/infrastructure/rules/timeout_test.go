@@ -0,0 +1,159 @@
+package rules
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/infrastructure/cache/redis"
+	"fraud-detecction-system/internal/infrastructure/cache/redis/redistest"
+)
+
+// stalledRedis starts a server that completes the Redis handshake and answers
+// PING, but never replies to any other command, like a Redis stuck under load
+func stalledRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	var mu sync.Mutex
+	var conns []net.Conn
+	t.Cleanup(func() {
+		ln.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	})
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+			go serveStalled(conn)
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	client, err := redis.NewClient(context.Background(), redis.Config{Host: addr.IP.String(), Port: addr.Port})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func serveStalled(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	for {
+		args, err := redistest.ReadCommand(r)
+		if err != nil {
+			return
+		}
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			conn.Write([]byte("+PONG\r\n"))
+		case "HELLO", "CLIENT":
+			conn.Write([]byte("-ERR unknown command\r\n"))
+		}
+	}
+}
+
+// staticRuleRepo serves a fixed set of active rules
+type staticRuleRepo struct {
+	fraud.RuleRepository
+	rules []*fraud.Rule
+}
+
+func (r *staticRuleRepo) ListActive(ctx context.Context) ([]*fraud.Rule, error) {
+	return r.rules, nil
+}
+
+func TestEvaluateVelocityTimeoutLeavesOtherRules(t *testing.T) {
+	velocity := fraud.NewRule("velocity", "", fraud.RuleTypeVelocity, fraud.SeverityHigh, fraud.ActionReview, uuid.Nil)
+	velocity.Config = map[string]interface{}{"max_transactions": float64(5), "window_minutes": float64(5)}
+	amount := fraud.NewRule("amount", "", fraud.RuleTypeAmount, fraud.SeverityMedium, fraud.ActionReview, uuid.Nil)
+	amount.Config = map[string]interface{}{"max_amount": "100"}
+
+	tests := []struct {
+		name          string
+		policy        TimeoutPolicy
+		velocityFired bool
+	}{
+		{"fail open", TimeoutFailOpen, false},
+		{"fail closed", TimeoutFailClosed, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEngine(&staticRuleRepo{rules: []*fraud.Rule{velocity, amount}}, redis.NewVelocityCache(stalledRedis(t)), nil, nil, nil)
+			e.SetRuleTimeouts(map[fraud.RuleType]RuleTimeout{
+				fraud.RuleTypeVelocity: {Timeout: 50 * time.Millisecond, Policy: tt.policy},
+			})
+
+			// The analysis deadline is far longer than the velocity timeout
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			evalCtx := &fraud.RuleEvaluationContext{
+				TransactionID: uuid.New(),
+				UserID:        uuid.New(),
+				Amount:        decimal.NewFromInt(500),
+				Currency:      "USD",
+				Timestamp:     time.Now(),
+			}
+
+			start := time.Now()
+			results, err := e.Evaluate(ctx, evalCtx)
+			if err != nil {
+				t.Fatalf("evaluate: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("evaluation took %s, want the velocity rule cut off at its timeout", elapsed)
+			}
+
+			byRule := make(map[uuid.UUID]fraud.RuleResult, len(results))
+			for _, result := range results {
+				byRule[result.RuleID] = result
+			}
+			v, ok := byRule[velocity.ID]
+			if !ok {
+				t.Fatalf("no velocity result in %+v", results)
+			}
+			if v.Metadata["timed_out"] != true || v.Fired != tt.velocityFired || v.DataStatus != fraud.RuleDataErrored {
+				t.Errorf("velocity result %+v, want timed out with fired %t", v, tt.velocityFired)
+			}
+			a, ok := byRule[amount.ID]
+			if !ok || !a.Fired || !a.Score.IsPositive() {
+				t.Errorf("amount result %+v, want fired", a)
+			}
+		})
+	}
+}
+
+func TestTimeoutResultScoresBySeverity(t *testing.T) {
+	rule := fraud.NewRule("slow", "", fraud.RuleTypeVelocity, fraud.SeverityCritical, fraud.ActionBlock, uuid.Nil)
+
+	open := timeoutResult(rule, RuleTimeout{Timeout: time.Second, Policy: TimeoutFailOpen})
+	if open.Fired || !open.Score.IsZero() || open.Action != fraud.ActionAllow {
+		t.Errorf("fail open result %+v, want not fired", open)
+	}
+	closed := timeoutResult(rule, RuleTimeout{Timeout: time.Second, Policy: TimeoutFailClosed})
+	if !closed.Fired || !closed.Score.Equal(decimal.NewFromFloat(0.9)) || closed.Action != fraud.ActionBlock {
+		t.Errorf("fail closed result %+v, want fired at 0.9 with block", closed)
+	}
+}
@@ -0,0 +1,122 @@
+package rules
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/infrastructure/cache/redis"
+)
+
+// historyEngine returns an engine whose velocity rules read the history on the
+// evaluation context; the cache is never queried, so it needs no Redis
+func historyEngine() *Engine {
+	return NewEngine(nil, redis.NewVelocityCache(nil), nil, nil, nil)
+}
+
+// historyContext returns an evaluation context for amount with one recent
+// transaction per entry in history, all inside the loaded window
+func historyContext(amount string, history ...string) *fraud.RuleEvaluationContext {
+	evalCtx := &fraud.RuleEvaluationContext{
+		TransactionID: uuid.New(),
+		UserID:        uuid.New(),
+		Amount:        decimal.RequireFromString(amount),
+		Currency:      "USD",
+		Timestamp:     time.Now(),
+		RecentWindow:  time.Hour,
+	}
+	for _, a := range history {
+		evalCtx.RecentTransactions = append(evalCtx.RecentTransactions, fraud.TransactionSummary{
+			ID:        uuid.New(),
+			Amount:    decimal.RequireFromString(a),
+			Timestamp: time.Now().Add(-time.Minute),
+		})
+	}
+	return evalCtx
+}
+
+func velocityRule(config map[string]interface{}) *fraud.Rule {
+	rule := fraud.NewRule("velocity", "", fraud.RuleTypeVelocity, fraud.SeverityHigh, fraud.ActionBlock, uuid.Nil)
+	rule.Config = config
+	return rule
+}
+
+func TestEvaluateVelocityRuleUsesServiceBaseAmount(t *testing.T) {
+	e := historyEngine()
+	e.SetCurrencyConverter(NewStaticCurrencyConverter("USD", map[string]decimal.Decimal{"EUR": decimal.RequireFromString("1.10")}), "USD")
+	rule := velocityRule(map[string]interface{}{
+		"max_transactions": float64(10),
+		"window_minutes":   float64(5),
+		"amount_threshold": "150",
+	})
+
+	// Converting here would give 110 EUR and stay under the limit; the service's 200 doesn't
+	evalCtx := historyContext("100")
+	evalCtx.Currency = "EUR"
+	evalCtx.BaseAmount = decimal.NewFromInt(200)
+	evalCtx.BaseCurrency = "USD"
+
+	result, err := e.evaluateVelocityRule(context.Background(), rule, evalCtx)
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if !result.Fired {
+		t.Fatalf("rule did not fire: %s", result.Reason)
+	}
+	if got := result.Metadata["converted_amount"]; got != "200" {
+		t.Errorf("converted amount %v, want 200", got)
+	}
+}
+
+func TestEvaluateVelocityRuleCombinesCountAndAmount(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       fraud.VelocityCombineMode
+		history    []string
+		wantCount  bool // Count score is positive
+		wantAmount bool // Amount score is positive
+	}{
+		{"count only", fraud.VelocityCombineProbabilistic, []string{"1", "1", "1", "1", "1"}, true, false},
+		{"amount only", fraud.VelocityCombineProbabilistic, []string{"600"}, false, true},
+		{"both probabilistic", fraud.VelocityCombineProbabilistic, []string{"200", "200", "200", "200", "200"}, true, true},
+		{"both max", fraud.VelocityCombineMax, []string{"200", "200", "200", "200", "200"}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := velocityRule(map[string]interface{}{
+				"max_transactions": float64(3),
+				"window_minutes":   float64(5),
+				"amount_threshold": "500",
+				"combine_mode":     string(tt.mode),
+			})
+
+			result, err := historyEngine().evaluateVelocityRule(context.Background(), rule, historyContext("100", tt.history...))
+			if err != nil {
+				t.Fatalf("evaluate: %v", err)
+			}
+			if !result.Fired {
+				t.Fatalf("rule did not fire: %s", result.Reason)
+			}
+
+			countScore := decimal.RequireFromString(result.Metadata["count_score"].(string))
+			amountScore := decimal.RequireFromString(result.Metadata["amount_score"].(string))
+			if countScore.IsPositive() != tt.wantCount || amountScore.IsPositive() != tt.wantAmount {
+				t.Fatalf("count score %s, amount score %s; want count %t, amount %t", countScore, amountScore, tt.wantCount, tt.wantAmount)
+			}
+
+			highest := decimal.Max(countScore, amountScore)
+			switch {
+			case tt.mode == fraud.VelocityCombineMax || !tt.wantCount || !tt.wantAmount:
+				if !result.Score.Equal(highest) {
+					t.Errorf("score %s, want %s", result.Score, highest)
+				}
+			case !result.Score.GreaterThan(highest):
+				t.Errorf("score %s, want above both count %s and amount %s", result.Score, countScore, amountScore)
+			}
+		})
+	}
+}
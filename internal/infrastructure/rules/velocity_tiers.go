@@ -0,0 +1,118 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// tierOutcome is the result of checking one velocity tier
+type tierOutcome struct {
+	index       int
+	tier        fraud.VelocityTier
+	count       int64
+	total       decimal.Decimal
+	countScore  decimal.Decimal
+	amountScore decimal.Decimal
+	score       decimal.Decimal
+	reasons     []string
+}
+
+// evaluateVelocityTiers checks every tier of a multi-tier velocity rule
+// The rule fires if any tier is breached. Its score comes from the tier that is
+// furthest over its limit, and that tier is reported in the metadata
+func (e *Engine) evaluateVelocityTiers(ctx context.Context, rule *fraud.Rule, evalCtx *fraud.RuleEvaluationContext, config fraud.VelocityRuleConfig) (*fraud.RuleResult, error) {
+	// The amount limits and cached sums are in the base currency
+	amount, convErr := e.ToBaseCurrency(ctx, evalCtx.Amount, evalCtx.Currency)
+
+	var worst *tierOutcome
+	var breached []int
+	checked := 0
+	for i, tier := range config.Tiers {
+		outcome, ok := e.checkVelocityTier(ctx, evalCtx, tier, amount, convErr == nil)
+		if !ok {
+			continue
+		}
+		checked++
+		outcome.index = i
+		if len(outcome.reasons) == 0 {
+			continue
+		}
+		breached = append(breached, tier.WindowMinutes)
+		outcome.score = combineVelocityScores(outcome.countScore, outcome.amountScore, config)
+		if worst == nil || outcome.score.GreaterThan(worst.score) {
+			worst = outcome
+		}
+	}
+
+	if checked == 0 {
+		// Can't evaluate velocity - fail open for availability
+		return unavailableResult(rule, "Unable to check velocity"), nil
+	}
+	if worst == nil {
+		return fraud.NewRuleResult(rule.ID, rule.Name, false, decimal.Zero, "Within velocity limits", fraud.ActionAllow), nil
+	}
+
+	result := fraud.NewRuleResult(rule.ID, rule.Name, true, worst.score, strings.Join(worst.reasons, "; "), rule.Action)
+	result.AddMetadata("breached_tier", worst.index)
+	result.AddMetadata("window_minutes", worst.tier.WindowMinutes)
+	result.AddMetadata("transaction_count", worst.count)
+	result.AddMetadata("count_score", worst.countScore.String())
+	if worst.tier.MaxCount > 0 {
+		result.AddMetadata("limit", worst.tier.MaxCount)
+	}
+	if worst.tier.MaxAmount.IsPositive() && convErr == nil {
+		result.AddMetadata("total_amount", worst.total.String())
+		result.AddMetadata("amount_limit", worst.tier.MaxAmount.String())
+		result.AddMetadata("amount_score", worst.amountScore.String())
+	}
+	result.AddMetadata("breached_windows_minutes", breached)
+	result.AddMetadata("combine_mode", string(config.CombineMode))
+	return result, nil
+}
+
+// checkVelocityTier counts and sums the user's transactions in one tier's window
+// ok is false when the count couldn't be read; the amount check is skipped if the
+// sum can't be read or the amount couldn't be converted
+func (e *Engine) checkVelocityTier(ctx context.Context, evalCtx *fraud.RuleEvaluationContext, tier fraud.VelocityTier, amount decimal.Decimal, amountOK bool) (*tierOutcome, bool) {
+	window := time.Duration(tier.WindowMinutes) * time.Minute
+
+	// Reuse the history loaded for this request when it covers the window
+	count, total, fromHistory := evalCtx.RecentActivity(window)
+	if !fromHistory {
+		var err error
+		count, err = e.velocityCache.GetTransactionCount(ctx, evalCtx.UserID, window)
+		if err != nil {
+			return nil, false
+		}
+	}
+
+	outcome := &tierOutcome{tier: tier, count: count, countScore: decimal.Zero, amountScore: decimal.Zero}
+
+	if tier.MaxCount > 0 && count >= int64(tier.MaxCount) {
+		outcome.countScore = calculateVelocityScore(count, tier.MaxCount)
+		outcome.reasons = append(outcome.reasons, fmt.Sprintf("Velocity limit exceeded: %d transactions in %d minutes (limit: %d)", count, tier.WindowMinutes, tier.MaxCount))
+	}
+
+	if tier.MaxAmount.IsPositive() && amountOK {
+		if !fromHistory {
+			var err error
+			total, err = e.velocityCache.GetTransactionSum(ctx, evalCtx.UserID, window)
+			if err != nil {
+				return outcome, true
+			}
+		}
+		outcome.total = total
+		if sum := total.Add(amount); sum.GreaterThan(tier.MaxAmount) {
+			outcome.amountScore = calculateAmountScore(sum, tier.MaxAmount)
+			outcome.reasons = append(outcome.reasons, fmt.Sprintf("Amount velocity limit exceeded: %s total in %d minutes (limit: %s)", formatAmount(sum, e.baseCurrency), tier.WindowMinutes, formatAmount(tier.MaxAmount, e.baseCurrency)))
+		}
+	}
+
+	return outcome, true
+}
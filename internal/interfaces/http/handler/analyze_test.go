@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	fraudapp "fraud-detecction-system/internal/application/fraud"
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/infrastructure/rules"
+)
+
+// noRules is a rule repository with no active rules
+type noRules struct {
+	fraud.RuleRepository
+}
+
+func (noRules) ListActive(ctx context.Context) ([]*fraud.Rule, error) {
+	return nil, nil
+}
+
+// newAnalyzeHandler returns a fraud handler that analyzes against no rules
+func newAnalyzeHandler(generateIDs bool) (*FraudHandler, *memoryDecisionRepo) {
+	engine := rules.NewEngine(noRules{}, nil, nil, nil, nil)
+	decisions := &memoryDecisionRepo{decisions: make(map[uuid.UUID]*fraud.FraudDecision)}
+	cases := &memoryCaseRepo{cases: make(map[uuid.UUID]*fraud.FraudCase)}
+	service := fraud.NewService(decisions, cases, nil, engine, nil)
+	h := NewFraudHandler(fraudapp.NewDetectFraudUseCase(service, engine, nil, nil, nil, nil, nil, time.Second), service)
+	h.SetGenerateTransactionIDs(generateIDs)
+	return h, decisions
+}
+
+func TestAnalyzeTransactionID(t *testing.T) {
+	supplied := uuid.New()
+
+	tests := []struct {
+		name        string
+		generateIDs bool
+		txID        string
+		wantStatus  int
+	}{
+		{"missing ID generated", true, "", http.StatusOK},
+		{"supplied ID honored when generating", true, supplied.String(), http.StatusOK},
+		{"supplied ID honored", false, supplied.String(), http.StatusOK},
+		{"missing ID rejected", false, "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, decisions := newAnalyzeHandler(tt.generateIDs)
+			body, _ := json.Marshal(fraudapp.AnalyzeTransactionRequest{
+				TransactionID: tt.txID,
+				UserID:        uuid.NewString(),
+				AccountID:     uuid.NewString(),
+				Amount:        "42.00",
+				Currency:      "USD",
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/fraud/analyze", strings.NewReader(string(body)))
+			rec := httptest.NewRecorder()
+			h.AnalyzeTransaction(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got fraudapp.DetectFraudOutput
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.TransactionID == uuid.Nil {
+				t.Fatal("no transaction ID in the response")
+			}
+			if tt.txID != "" && got.TransactionID != supplied {
+				t.Errorf("transaction ID %s, want the supplied %s", got.TransactionID, supplied)
+			}
+			if _, ok := decisions.decisions[got.TransactionID]; !ok {
+				t.Errorf("no decision stored under the returned transaction ID %s", got.TransactionID)
+			}
+		})
+	}
+}
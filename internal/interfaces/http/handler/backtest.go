@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	txapp "fraud-detecction-system/internal/application/transaction"
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// BacktestRequest is a candidate rule and/or thresholds to replay over past transactions
+// From and To bound the transactions replayed; the last 30 days by default
+type BacktestRequest struct {
+	Rule       *ruleDefinition     `json:"rule,omitempty"`
+	Thresholds *BacktestThresholds `json:"thresholds,omitempty"`
+	From       *time.Time          `json:"from,omitempty"`
+	To         *time.Time          `json:"to,omitempty"`
+}
+
+// BacktestThresholds are candidate decision thresholds, each 0-1
+type BacktestThresholds struct {
+	Block     float64 `json:"block"`
+	Review    float64 `json:"review"`
+	Challenge float64 `json:"challenge"`
+}
+
+// SetBacktestUseCase sets the use case behind the rule backtest endpoint
+func (h *FraudHandler) SetBacktestUseCase(uc *txapp.BacktestUseCase) {
+	h.backtestUseCase = uc
+}
+
+// BacktestRule handles POST /api/v1/fraud/rules/backtest
+// It reports how past decisions would have changed; nothing is saved
+func (h *FraudHandler) BacktestRule(w http.ResponseWriter, r *http.Request) {
+	if h.backtestUseCase == nil {
+		writeError(w, http.StatusServiceUnavailable, CodeInternal, "Backtesting is not configured")
+		return
+	}
+
+	var req BacktestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	to := time.Now()
+	if req.To != nil {
+		to = *req.To
+	}
+	from := to.Add(-defaultAccuracyWindow)
+	if req.From != nil {
+		from = *req.From
+	}
+	if !from.Before(to) {
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, "from must be before to")
+		return
+	}
+
+	var candidate fraud.BacktestCandidate
+	if req.Rule != nil {
+		candidate.Rule = req.Rule.toRule(uuid.Nil)
+		if err := h.fraudService.ValidateRule(candidate.Rule); err != nil {
+			writeError(w, http.StatusBadRequest, CodeValidationError, "Invalid rule: "+err.Error())
+			return
+		}
+	}
+	if req.Thresholds != nil {
+		candidate.Thresholds = &fraud.DecisionThresholds{
+			BlockThreshold:     decimal.NewFromFloat(req.Thresholds.Block),
+			ReviewThreshold:    decimal.NewFromFloat(req.Thresholds.Review),
+			ChallengeThreshold: decimal.NewFromFloat(req.Thresholds.Challenge),
+		}
+	}
+
+	report, err := h.backtestUseCase.Execute(r.Context(), candidate, from, to)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to backtest")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
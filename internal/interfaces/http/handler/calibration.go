@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CalibrateThresholdsRequest asks for thresholds that hit target decision rates
+// From and To bound the historical decisions used; the last 30 days by default
+type CalibrateThresholdsRequest struct {
+	TargetBlockRate  float64    `json:"target_block_rate"`  // Fraction of decisions to block, 0-1
+	TargetReviewRate float64    `json:"target_review_rate"` // Fraction of decisions to review, 0-1
+	From             *time.Time `json:"from,omitempty"`
+	To               *time.Time `json:"to,omitempty"`
+}
+
+// CalibrateThresholds handles POST /api/v1/fraud/thresholds/calibrate
+// It only suggests thresholds; nothing is changed
+func (h *FraudHandler) CalibrateThresholds(w http.ResponseWriter, r *http.Request) {
+	var req CalibrateThresholdsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	to := time.Now()
+	if req.To != nil {
+		to = *req.To
+	}
+	from := to.Add(-defaultAccuracyWindow)
+	if req.From != nil {
+		from = *req.From
+	}
+	if !from.Before(to) {
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, "from must be before to")
+		return
+	}
+
+	calibration, err := h.fraudService.CalibrateThresholds(
+		r.Context(),
+		from,
+		to,
+		decimal.NewFromFloat(req.TargetBlockRate),
+		decimal.NewFromFloat(req.TargetReviewRate),
+	)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to calibrate thresholds")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, calibration)
+}
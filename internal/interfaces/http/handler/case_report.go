@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// ReportSignatureHeader carries the hex HMAC-SHA256 of a report body when a signing key is set
+const ReportSignatureHeader = "X-Report-Signature"
+
+// GetCaseReport handles GET /api/v1/fraud/cases/{id}/report
+func (h *FraudHandler) GetCaseReport(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Case ID is required")
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid case ID")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" {
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, "Unsupported report format: "+format)
+		return
+	}
+
+	report, err := h.fraudService.GetCaseReport(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to build case report")
+		return
+	}
+
+	// Render to a buffer first so a failure can still return a JSON error and the body can be signed
+	var buf bytes.Buffer
+	if err := writeCaseReportCSV(&buf, report); err != nil {
+		writeInternalError(w, r, "Failed to render case report", err)
+		return
+	}
+
+	if len(h.reportSigningKey) > 0 {
+		mac := hmac.New(sha256.New, h.reportSigningKey)
+		mac.Write(buf.Bytes())
+		w.Header().Set(ReportSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="case-`+id.String()+`.csv"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// writeCaseReportCSV renders a case report as CSV
+// The file has case, decision and note sections, each introduced by its own header row
+func writeCaseReportCSV(out io.Writer, report *fraud.CaseReport) error {
+	w := csv.NewWriter(out)
+	c := report.Case
+
+	rows := [][]string{
+		{"section", "field", "value"},
+		{"case", "id", c.ID.String()},
+		{"case", "status", string(c.Status)},
+		{"case", "risk_level", string(c.RiskLevel)},
+		{"case", "user_id", c.UserID.String()},
+		{"case", "account_id", c.AccountID.String()},
+		{"case", "total_amount", c.TotalAmount.String()},
+		{"case", "currency", c.Currency},
+		{"case", "assigned_to", formatOptionalID(c.AssignedTo)},
+		{"case", "description", c.Description},
+		{"case", "transaction_count", strconv.Itoa(len(c.TransactionIDs))},
+		{"case", "created_at", c.CreatedAt.UTC().Format(time.RFC3339)},
+		{"case", "resolution", c.Resolution},
+		{"case", "resolved_by", formatOptionalID(c.ResolvedBy)},
+		{"case", "resolved_at", formatOptionalTime(c.ResolvedAt)},
+		{"case", "report_generated_at", report.GeneratedAt.UTC().Format(time.RFC3339)},
+		{},
+		{"section", "transaction_id", "decision_id", "decision", "score", "risk_level", "rules_fired", "reasons", "processed_at"},
+	}
+	for _, d := range report.Decisions {
+		rows = append(rows, []string{
+			"decision",
+			d.TransactionID.String(),
+			d.ID.String(),
+			string(d.Decision),
+			d.Score.String(),
+			string(d.RiskLevel),
+			strings.Join(d.RulesFired, "; "),
+			strings.Join(d.Reasons, "; "),
+			d.ProcessedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	rows = append(rows, []string{}, []string{"section", "note_id", "author", "created_at", "content"})
+	for _, n := range report.Notes {
+		rows = append(rows, []string{
+			"note",
+			n.ID.String(),
+			n.Author.String(),
+			n.CreatedAt.UTC().Format(time.RFC3339),
+			n.Content,
+		})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+func formatOptionalID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// memoryDecisionRepo keeps decisions by transaction ID; only the methods the handlers use are implemented
+type memoryDecisionRepo struct {
+	fraud.DecisionRepository
+	decisions map[uuid.UUID]*fraud.FraudDecision
+}
+
+func (r *memoryDecisionRepo) Create(ctx context.Context, decision *fraud.FraudDecision) error {
+	r.decisions[decision.TransactionID] = decision
+	return nil
+}
+
+func (r *memoryDecisionRepo) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*fraud.FraudDecision, error) {
+	if d, ok := r.decisions[transactionID]; ok {
+		return d, nil
+	}
+	return nil, fraud.ErrDecisionNotFound
+}
+
+func (r *memoryDecisionRepo) GetByTransactionIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*fraud.FraudDecision, error) {
+	found := make(map[uuid.UUID]*fraud.FraudDecision)
+	for _, id := range ids {
+		if d, ok := r.decisions[id]; ok {
+			found[id] = d
+		}
+	}
+	return found, nil
+}
+
+// getCaseReport requests the report for caseID in format
+func getCaseReport(h *FraudHandler, caseID uuid.UUID, format string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/fraud/cases/"+caseID.String()+"/report?format="+format, nil)
+	req.SetPathValue("id", caseID.String())
+	rec := httptest.NewRecorder()
+	h.GetCaseReport(rec, req)
+	return rec
+}
+
+func TestGetCaseReportCSV(t *testing.T) {
+	txID := uuid.New()
+	c := fraud.NewFraudCase(txID, uuid.New(), uuid.New(), fraud.RiskLevelHigh)
+	c.TotalAmount = decimal.NewFromInt(2500)
+	c.Currency = "USD"
+	c.Description = "Card used in two countries within an hour"
+	author := uuid.New()
+	c.AddNote(author, "Called the cardholder, no answer")
+	c.AddNote(author, "Cardholder confirmed the card was stolen, reissuing")
+
+	decision := fraud.NewFraudDecision(txID, c.UserID, fraud.DecisionBlock, decimal.NewFromFloat(0.92))
+	cases := &memoryCaseRepo{cases: map[uuid.UUID]*fraud.FraudCase{c.ID: c}}
+	decisions := &memoryDecisionRepo{decisions: map[uuid.UUID]*fraud.FraudDecision{txID: decision}}
+	h := NewFraudHandler(nil, fraud.NewService(decisions, cases, nil, nil, nil))
+	key := []byte("report-key")
+	h.SetReportSigningKey(key)
+
+	rec := getCaseReport(h, c.ID, "csv")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200; body %s", rec.Code, rec.Body)
+	}
+	body := rec.Body.Bytes()
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	if got, want := rec.Header().Get(ReportSignatureHeader), hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("signature %q, want %q", got, want)
+	}
+
+	r := csv.NewReader(rec.Body)
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+
+	fields := make(map[string]string)
+	var notes []string
+	var decisionRows int
+	for _, row := range rows {
+		switch {
+		case len(row) == 3 && row[0] == "case":
+			fields[row[1]] = row[2]
+		case len(row) == 5 && row[0] == "note":
+			notes = append(notes, row[4])
+		case len(row) > 3 && row[0] == "decision":
+			decisionRows++
+			if row[1] != txID.String() || row[3] != string(fraud.DecisionBlock) {
+				t.Errorf("decision row %v, want block for %s", row, txID)
+			}
+		}
+	}
+
+	wantFields := map[string]string{
+		"id":           c.ID.String(),
+		"status":       string(fraud.CaseStatusOpen),
+		"risk_level":   string(fraud.RiskLevelHigh),
+		"user_id":      c.UserID.String(),
+		"total_amount": "2500",
+		"currency":     "USD",
+		"description":  c.Description,
+	}
+	for field, want := range wantFields {
+		if fields[field] != want {
+			t.Errorf("case %s %q, want %q", field, fields[field], want)
+		}
+	}
+	if decisionRows != 1 {
+		t.Errorf("%d decision rows, want 1", decisionRows)
+	}
+	if len(notes) != len(c.Notes) {
+		t.Fatalf("notes %q, want %d", notes, len(c.Notes))
+	}
+	for i, note := range c.Notes {
+		if notes[i] != note.Content {
+			t.Errorf("note %d %q, want %q", i, notes[i], note.Content)
+		}
+	}
+}
+
+func TestGetCaseReportErrors(t *testing.T) {
+	c := fraud.NewFraudCase(uuid.New(), uuid.New(), uuid.New(), fraud.RiskLevelLow)
+
+	tests := []struct {
+		name       string
+		caseID     uuid.UUID
+		format     string
+		wantStatus int
+	}{
+		{"unsupported format", c.ID, "pdf", http.StatusBadRequest},
+		{"missing case", uuid.New(), "csv", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getCaseReport(newCaseHandler(c), tt.caseID, tt.format)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// memoryCaseRepo keeps cases in a map; only the methods the case handlers and analysis use are implemented
+type memoryCaseRepo struct {
+	fraud.CaseRepository
+	cases map[uuid.UUID]*fraud.FraudCase
+}
+
+func (r *memoryCaseRepo) GetByID(ctx context.Context, id uuid.UUID) (*fraud.FraudCase, error) {
+	c, ok := r.cases[id]
+	if !ok {
+		return nil, fraud.ErrCaseNotFound
+	}
+	return c, nil
+}
+
+func (r *memoryCaseRepo) Create(ctx context.Context, fraudCase *fraud.FraudCase) error {
+	r.cases[fraudCase.ID] = fraudCase
+	return nil
+}
+
+func (r *memoryCaseRepo) GetOpenCasesByUser(ctx context.Context, userID uuid.UUID) ([]*fraud.FraudCase, error) {
+	var open []*fraud.FraudCase
+	for _, c := range r.cases {
+		if c.UserID == userID && c.Status != fraud.CaseStatusClosed && c.Status != fraud.CaseStatusResolved {
+			open = append(open, c)
+		}
+	}
+	return open, nil
+}
+
+func (r *memoryCaseRepo) Update(ctx context.Context, fraudCase *fraud.FraudCase) error {
+	r.cases[fraudCase.ID] = fraudCase
+	return nil
+}
+
+func (r *memoryCaseRepo) ClaimNext(ctx context.Context, investigatorID uuid.UUID) (*fraud.FraudCase, error) {
+	var queue []*fraud.FraudCase
+	for _, c := range r.cases {
+		if c.IsQueued() {
+			queue = append(queue, c)
+		}
+	}
+	if len(queue) == 0 {
+		return nil, fraud.ErrCaseQueueEmpty
+	}
+	next := slices.MinFunc(queue, fraud.CompareCaseQueue)
+	if err := next.Assign(investigatorID); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// newCaseHandler returns a fraud handler over the given cases
+func newCaseHandler(cases ...*fraud.FraudCase) *FraudHandler {
+	repo := &memoryCaseRepo{cases: make(map[uuid.UUID]*fraud.FraudCase)}
+	for _, c := range cases {
+		repo.cases[c.ID] = c
+	}
+	return NewFraudHandler(nil, fraud.NewService(nil, repo, nil, nil, nil))
+}
+
+// updateCase sends body to the update endpoint for caseID
+func updateCase(h *FraudHandler, caseID uuid.UUID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/fraud/cases/"+caseID.String(), strings.NewReader(body))
+	req.SetPathValue("id", caseID.String())
+	rec := httptest.NewRecorder()
+	h.UpdateCase(rec, req)
+	return rec
+}
+
+func TestUpdateCaseMissingCase(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"assign", `{"action":"assign","assignee_id":"` + uuid.NewString() + `"}`},
+		{"add_note", `{"action":"add_note","note":"called the cardholder"}`},
+		{"close", `{"action":"close"}`},
+		{"escalate", `{"action":"escalate","escalate_reason":"repeat offender"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := updateCase(newCaseHandler(), uuid.New(), tt.body)
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("status %d, want 404; body %s", rec.Code, rec.Body)
+			}
+			var body ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if body.Code != CodeCaseNotFound {
+				t.Errorf("code %s, want %s", body.Code, CodeCaseNotFound)
+			}
+		})
+	}
+}
+
+// claimNextCase asks the queue for the next case on behalf of investigatorID
+func claimNextCase(h *FraudHandler, investigatorID uuid.UUID) *httptest.ResponseRecorder {
+	body := `{"investigator_id":"` + investigatorID.String() + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/fraud/cases/queue/claim", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ClaimNextCase(rec, req)
+	return rec
+}
+
+func TestClaimNextCase(t *testing.T) {
+	high := fraud.NewFraudCase(uuid.New(), uuid.New(), uuid.New(), fraud.RiskLevelHigh)
+	high.CreatedAt = high.CreatedAt.Add(-time.Hour)
+	critical := fraud.NewFraudCase(uuid.New(), uuid.New(), uuid.New(), fraud.RiskLevelCritical)
+	h := newCaseHandler(high, critical)
+
+	for _, want := range []*fraud.FraudCase{critical, high} {
+		investigatorID := uuid.New()
+		rec := claimNextCase(h, investigatorID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status %d, want 200; body %s", rec.Code, rec.Body)
+		}
+		var claimed fraud.FraudCase
+		if err := json.NewDecoder(rec.Body).Decode(&claimed); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if claimed.ID != want.ID || claimed.AssignedTo == nil || *claimed.AssignedTo != investigatorID {
+			t.Errorf("claimed the %s case assigned to %v, want the %s case assigned to %s", claimed.RiskLevel, claimed.AssignedTo, want.RiskLevel, investigatorID)
+		}
+	}
+
+	rec := claimNextCase(h, uuid.New())
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status %d from an empty queue, want 404; body %s", rec.Code, rec.Body)
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Code != CodeCaseQueueEmpty {
+		t.Errorf("error code %q (%v), want %s", resp.Code, err, CodeCaseQueueEmpty)
+	}
+}
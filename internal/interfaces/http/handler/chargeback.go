@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// RecordChargebackRequest is the request body for reporting a chargeback against a user
+type RecordChargebackRequest struct {
+	TransactionID uuid.UUID       `json:"transaction_id,omitempty"`
+	Amount        decimal.Decimal `json:"amount"`
+	Currency      string          `json:"currency"`
+	ReasonCode    string          `json:"reason_code,omitempty"`
+	OccurredAt    time.Time       `json:"occurred_at,omitempty"` // Defaults to now
+}
+
+// RecordChargeback handles POST /api/v1/fraud/users/{id}/chargebacks
+func (h *FraudHandler) RecordChargeback(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "User ID is required")
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid user ID")
+		return
+	}
+
+	var req RecordChargebackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	chargeback := fraud.NewChargeback(id, req.TransactionID, req.Amount, req.Currency, req.ReasonCode, req.OccurredAt, userFromContext(r))
+	if err := h.fraudService.RecordChargeback(r.Context(), chargeback); err != nil {
+		writeServiceError(w, r, err, "Failed to record chargeback")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, chargeback)
+}
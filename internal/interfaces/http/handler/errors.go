@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	fraudapp "fraud-detecction-system/internal/application/fraud"
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/domain/transaction"
+	"fraud-detecction-system/internal/pkg/logger"
+)
+
+// Error codes returned in the code field of error responses
+// Codes are stable; clients should switch on them rather than on the message
+const (
+	CodeInvalidBody         = "INVALID_REQUEST_BODY"
+	CodeMissingParameter    = "MISSING_PARAMETER"
+	CodeInvalidParameter    = "INVALID_PARAMETER"
+	CodeInvalidUUID         = "INVALID_UUID"
+	CodeValidationError     = "VALIDATION_ERROR"
+	CodeUnsupportedCurrency = "UNSUPPORTED_CURRENCY"
+	CodeInsufficientData    = "INSUFFICIENT_DATA"
+	CodeRuleImportRejected  = "RULE_IMPORT_REJECTED"
+	CodeDecisionNotFound    = "DECISION_NOT_FOUND"
+	CodeCaseNotFound        = "CASE_NOT_FOUND"
+	CodeCaseQueueEmpty      = "CASE_QUEUE_EMPTY"
+	CodeRuleNotFound        = "RULE_NOT_FOUND"
+	CodeRuleVersionNotFound = "RULE_VERSION_NOT_FOUND"
+	CodeModelReloadFailed   = "MODEL_RELOAD_FAILED"
+	CodeListEntryNotFound   = "LIST_ENTRY_NOT_FOUND"
+	CodeListEntryExists     = "LIST_ENTRY_EXISTS"
+	CodeTransactionNotFound = "TRANSACTION_NOT_FOUND"
+	CodeInvalidTransition   = "INVALID_STATUS_TRANSITION"
+	CodeForbidden           = "FORBIDDEN"
+	CodeInternal            = "INTERNAL_ERROR"
+)
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// serviceError maps a domain error to the status and code it is reported with
+// Validation errors keep their own text as the message since it is meant for the caller
+type serviceError struct {
+	err     error
+	status  int
+	code    string
+	message string
+}
+
+var serviceErrors = []serviceError{
+	{fraud.ErrDecisionNotFound, http.StatusNotFound, CodeDecisionNotFound, "Decision not found"},
+	{fraud.ErrCaseNotFound, http.StatusNotFound, CodeCaseNotFound, "Case not found"},
+	{fraud.ErrCaseQueueEmpty, http.StatusNotFound, CodeCaseQueueEmpty, "No cases are waiting in the queue"},
+	{fraud.ErrRuleNotFound, http.StatusNotFound, CodeRuleNotFound, "Rule not found"},
+	{fraud.ErrInvalidRuleType, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidRuleSeverity, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidRuleAction, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidRuleFailMode, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidRuleMode, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrRuleConfigInvalid, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidRuleSchedule, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrNoRulesToImport, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidFeedbackLabel, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidCalibrationTarget, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrNoCalibrationData, http.StatusUnprocessableEntity, CodeInsufficientData, ""},
+	{fraud.ErrEmptyBacktest, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrBacktestHistory, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidThresholds, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidResolutionOutcome, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidEvidenceType, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidOverrideDecision, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrOverrideReasonRequired, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrOverrideUnchanged, http.StatusConflict, CodeInvalidTransition, ""},
+	{fraud.ErrBlockOverrideForbidden, http.StatusForbidden, CodeForbidden, ""},
+	{fraud.ErrOverrideConflict, http.StatusConflict, CodeInvalidTransition, ""},
+	{fraud.ErrCaseAlreadyClosed, http.StatusConflict, CodeInvalidTransition, ""},
+	{fraud.ErrListEntryNotFound, http.StatusNotFound, CodeListEntryNotFound, "List entry not found"},
+	{fraud.ErrDuplicateListEntry, http.StatusConflict, CodeListEntryExists, ""},
+	{fraud.ErrInvalidListType, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidListEntity, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidListValue, http.StatusBadRequest, CodeValidationError, ""},
+	{fraud.ErrInvalidChargeback, http.StatusBadRequest, CodeValidationError, ""},
+	{transaction.ErrTransactionNotFound, http.StatusNotFound, CodeTransactionNotFound, "Transaction not found"},
+	{transaction.ErrInvalidStatusTransition, http.StatusConflict, CodeInvalidTransition, ""},
+	{transaction.ErrClaimedByAnotherReviewer, http.StatusConflict, CodeInvalidTransition, ""},
+	{fraudapp.ErrUnsupportedReportCurrency, http.StatusBadRequest, CodeUnsupportedCurrency, ""},
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, ErrorResponse{Code: code, Message: message})
+}
+
+func writeErrorDetails(w http.ResponseWriter, status int, code, message string, details interface{}) {
+	writeJSON(w, status, ErrorResponse{Code: code, Message: message, Details: details})
+}
+
+// writeInternalError reports a server-side failure without exposing the cause
+// The cause is logged with the request ID so it can still be found
+func writeInternalError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	slog.ErrorContext(r.Context(), message,
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		logger.Err(err),
+	)
+	writeError(w, http.StatusInternalServerError, CodeInternal, message)
+}
+
+// writeServiceError reports an error returned by a service or use case
+// Known domain errors get their status and code; anything else is an internal
+// error reported with message
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	var importErr *fraud.RuleImportError
+	if errors.As(err, &importErr) {
+		writeErrorDetails(w, http.StatusUnprocessableEntity, CodeRuleImportRejected, importErr.Error(), importErr.Errors)
+		return
+	}
+	if transaction.IsValidationError(err) {
+		writeError(w, http.StatusBadRequest, CodeValidationError, err.Error())
+		return
+	}
+
+	for _, known := range serviceErrors {
+		if errors.Is(err, known.err) {
+			msg := known.message
+			if msg == "" {
+				msg = err.Error()
+			}
+			writeError(w, known.status, known.code, msg)
+			return
+		}
+	}
+
+	writeInternalError(w, r, message, err)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// defaultAccuracyWindow is the range measured when no from is given
+const defaultAccuracyWindow = 30 * 24 * time.Hour
+
+// DecisionFeedbackRequest represents an analyst's verdict on a decision
+type DecisionFeedbackRequest struct {
+	Label string `json:"label"` // fraud, legit
+	Note  string `json:"note,omitempty"`
+}
+
+// RecordDecisionFeedback handles POST /api/v1/fraud/decisions/{id}/feedback
+func (h *FraudHandler) RecordDecisionFeedback(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Decision ID is required")
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid decision ID")
+		return
+	}
+
+	var req DecisionFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	feedback, err := h.fraudService.RecordFeedback(r.Context(), id, fraud.FeedbackLabel(req.Label), req.Note, userFromContext(r))
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to record feedback")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, feedback)
+}
+
+// GetAccuracy handles GET /api/v1/fraud/metrics/accuracy
+// from and to are RFC 3339 times bounding when decisions were made; the last 30 days by default
+func (h *FraudHandler) GetAccuracy(w http.ResponseWriter, r *http.Request) {
+	to := time.Now()
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, CodeInvalidParameter, "Invalid to: "+v)
+			return
+		}
+		to = t
+	}
+
+	from := to.Add(-defaultAccuracyWindow)
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, CodeInvalidParameter, "Invalid from: "+v)
+			return
+		}
+		from = t
+	}
+
+	if !from.Before(to) {
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, "from must be before to")
+		return
+	}
+
+	report, err := h.fraudService.GetAccuracy(r.Context(), from, to)
+	if err != nil {
+		writeInternalError(w, r, "Failed to compute accuracy", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
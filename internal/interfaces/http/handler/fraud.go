@@ -2,18 +2,42 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/google/uuid"
 
 	fraudapp "fraud-detecction-system/internal/application/fraud"
+	txapp "fraud-detecction-system/internal/application/transaction"
 	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/infrastructure/http/middleware"
+	"fraud-detecction-system/pkg/signature"
 )
 
+// DecisionSignatureHeader carries the hex HMAC-SHA256 of an analysis decision when a signing key is set
+// It covers the fields in signature.Decision; check it with signature.VerifyDecision
+const DecisionSignatureHeader = "X-Decision-Signature"
+
 // FraudHandler handles fraud-related HTTP requests
 type FraudHandler struct {
 	detectFraudUseCase *fraudapp.DetectFraudUseCase
 	fraudService       *fraud.Service
+	reportSigningKey   []byte
+	decisionSigningKey []byte
+
+	// Assign a transaction ID to analysis requests that omit one instead of rejecting them
+	generateTransactionIDs bool
+
+	// Optional runtime ML model reloading
+	modelReloader ModelReloader
+
+	// Replays past transactions for the rule backtest endpoint
+	backtestUseCase *txapp.BacktestUseCase
+
+	// Reports whether the caller may override a block decision; nil allows everyone
+	canOverrideBlock func(r *http.Request) bool
 }
 
 // NewFraudHandler creates a new fraud handler
@@ -24,27 +48,63 @@ func NewFraudHandler(detectFraudUseCase *fraudapp.DetectFraudUseCase, fraudServi
 	}
 }
 
-// AnalyzeTransaction handles POST /api/v1/fraud/analyze
+// SetReportSigningKey sets the HMAC key used to sign downloadable case reports
+func (h *FraudHandler) SetReportSigningKey(key []byte) {
+	h.reportSigningKey = key
+}
+
+// SetDecisionSigningKey sets the HMAC key used to sign analysis decisions
+func (h *FraudHandler) SetDecisionSigningKey(key []byte) {
+	h.decisionSigningKey = key
+}
+
+// SetGenerateTransactionIDs controls whether analysis requests without a transaction ID
+// are given a generated one. Supplied IDs are always used as is
+func (h *FraudHandler) SetGenerateTransactionIDs(enabled bool) {
+	h.generateTransactionIDs = enabled
+}
+
+// toInput converts an analysis request to use case input, filling in a
+// transaction ID first when the request has none and generation is enabled.
+// The tenant is the authenticated caller's, so a caller can't pick another
+// tenant's thresholds.
+func (h *FraudHandler) toInput(r *http.Request, req *fraudapp.AnalyzeTransactionRequest) (*fraudapp.DetectFraudInput, error) {
+	if req.TransactionID == "" && h.generateTransactionIDs {
+		req.TransactionID = uuid.NewString()
+	}
+	input, err := req.ToInput()
+	if err != nil {
+		return nil, err
+	}
+	input.TenantID, _ = middleware.TenantIDFromContext(r.Context())
+	return input, nil
+}
+
+// AnalyzeTransaction handles POST /api/v1/fraud/analyze and POST /api/v2/fraud/analyze
 func (h *FraudHandler) AnalyzeTransaction(w http.ResponseWriter, r *http.Request) {
 	var req fraudapp.AnalyzeTransactionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
 		return
 	}
 
-	input, err := req.ToInput()
+	input, err := h.toInput(r, &req)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, CodeValidationError, err.Error())
 		return
 	}
 
 	result, err := h.detectFraudUseCase.Execute(r.Context(), *input)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "Fraud analysis failed: "+err.Error())
+		writeServiceError(w, r, err, "Fraud analysis failed")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, result)
+	if len(h.decisionSigningKey) > 0 {
+		w.Header().Set(DecisionSignatureHeader, signature.SignDecision(h.decisionSigningKey, result.Signed()))
+	}
+
+	writeJSON(w, http.StatusOK, analyzeResponse(APIVersion(r), result))
 }
 
 // BatchAnalyze handles POST /api/v1/fraud/analyze/batch
@@ -53,25 +113,25 @@ func (h *FraudHandler) BatchAnalyze(w http.ResponseWriter, r *http.Request) {
 		Transactions []fraudapp.AnalyzeTransactionRequest `json:"transactions"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
 		return
 	}
 
 	if len(req.Transactions) == 0 {
-		writeError(w, http.StatusBadRequest, "No transactions provided")
+		writeError(w, http.StatusBadRequest, CodeValidationError, "No transactions provided")
 		return
 	}
 
 	if len(req.Transactions) > 100 {
-		writeError(w, http.StatusBadRequest, "Maximum 100 transactions per batch")
+		writeError(w, http.StatusBadRequest, CodeValidationError, "Maximum 100 transactions per batch")
 		return
 	}
 
 	inputs := make([]fraudapp.DetectFraudInput, 0, len(req.Transactions))
 	for _, txReq := range req.Transactions {
-		input, err := txReq.ToInput()
+		input, err := h.toInput(r, &txReq)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "Invalid transaction: "+err.Error())
+			writeError(w, http.StatusBadRequest, CodeValidationError, "Invalid transaction: "+err.Error())
 			return
 		}
 		inputs = append(inputs, *input)
@@ -81,10 +141,16 @@ func (h *FraudHandler) BatchAnalyze(w http.ResponseWriter, r *http.Request) {
 		Transactions: inputs,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "Batch analysis failed: "+err.Error())
+		writeServiceError(w, r, err, "Batch analysis failed")
 		return
 	}
 
+	if len(h.decisionSigningKey) > 0 {
+		for i := range result.Results {
+			result.Results[i].Signature = signature.SignDecision(h.decisionSigningKey, result.Results[i].Signed())
+		}
+	}
+
 	writeJSON(w, http.StatusOK, result)
 }
 
@@ -92,23 +158,19 @@ func (h *FraudHandler) BatchAnalyze(w http.ResponseWriter, r *http.Request) {
 func (h *FraudHandler) GetDecision(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	if idStr == "" {
-		writeError(w, http.StatusBadRequest, "Decision ID is required")
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Decision ID is required")
 		return
 	}
 
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid decision ID")
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid decision ID")
 		return
 	}
 
 	decision, err := h.fraudService.GetDecision(r.Context(), id)
 	if err != nil {
-		if err == fraud.ErrDecisionNotFound {
-			writeError(w, http.StatusNotFound, "Decision not found")
-			return
-		}
-		writeError(w, http.StatusInternalServerError, "Failed to get decision: "+err.Error())
+		writeServiceError(w, r, err, "Failed to get decision")
 		return
 	}
 
@@ -119,23 +181,23 @@ func (h *FraudHandler) GetDecision(w http.ResponseWriter, r *http.Request) {
 func (h *FraudHandler) GetDecisionByTransaction(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	if idStr == "" {
-		writeError(w, http.StatusBadRequest, "Transaction ID is required")
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Transaction ID is required")
 		return
 	}
 
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid transaction ID")
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid transaction ID")
 		return
 	}
 
 	decision, err := h.fraudService.GetDecisionByTransaction(r.Context(), id)
 	if err != nil {
 		if err == fraud.ErrDecisionNotFound {
-			writeError(w, http.StatusNotFound, "Decision not found for transaction")
+			writeError(w, http.StatusNotFound, CodeDecisionNotFound, "Decision not found for transaction")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "Failed to get decision: "+err.Error())
+		writeInternalError(w, r, "Failed to get decision", err)
 		return
 	}
 
@@ -146,25 +208,54 @@ func (h *FraudHandler) GetDecisionByTransaction(w http.ResponseWriter, r *http.R
 func (h *FraudHandler) GetUserRiskProfile(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	if idStr == "" {
-		writeError(w, http.StatusBadRequest, "User ID is required")
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "User ID is required")
 		return
 	}
 
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid user ID")
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid user ID")
 		return
 	}
 
 	profile, err := h.fraudService.GetUserRiskProfile(r.Context(), id)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "Failed to get risk profile: "+err.Error())
+		writeInternalError(w, r, "Failed to get risk profile", err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, profile)
 }
 
+// ListUserDecisions handles GET /api/v1/fraud/users/{id}/decisions
+func (h *FraudHandler) ListUserDecisions(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "User ID is required")
+		return
+	}
+
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid user ID")
+		return
+	}
+
+	limit, offset, err := parsePagination(r, 50, 200)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, err.Error())
+		return
+	}
+
+	decisions, total, err := h.fraudService.ListUserDecisions(r.Context(), userID, limit, offset)
+	if err != nil {
+		writeInternalError(w, r, "Failed to list decisions", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newPaginatedResponse(decisions, total, limit, offset))
+}
+
 // ListCases handles GET /api/v1/fraud/cases
 func (h *FraudHandler) ListCases(w http.ResponseWriter, r *http.Request) {
 	status := r.URL.Query().Get("status")
@@ -172,130 +263,264 @@ func (h *FraudHandler) ListCases(w http.ResponseWriter, r *http.Request) {
 		status = "open"
 	}
 
-	cases, err := h.fraudService.ListCasesByStatus(r.Context(), fraud.CaseStatus(status), 50, 0)
+	limit, offset, err := parsePagination(r, 50, 200)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, err.Error())
+		return
+	}
+
+	cases, total, err := h.fraudService.ListCasesByStatus(r.Context(), fraud.CaseStatus(status), limit, offset)
+	if err != nil {
+		writeInternalError(w, r, "Failed to list cases", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newPaginatedResponse(cases, total, limit, offset))
+}
+
+// ListCaseQueue handles GET /api/v1/fraud/cases/queue
+// Open, unassigned cases come highest risk first, then oldest first
+func (h *FraudHandler) ListCaseQueue(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePagination(r, 50, 200)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "Failed to list cases: "+err.Error())
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, err.Error())
 		return
 	}
 
+	cases, total, err := h.fraudService.ListCaseQueue(r.Context(), limit, offset)
+	if err != nil {
+		writeInternalError(w, r, "Failed to list case queue", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newPaginatedResponse(cases, total, limit, offset))
+}
+
+// ListAgingCases handles GET /api/v1/fraud/cases/aging
+// Open cases that breached or are nearing their SLA come most overdue first
+func (h *FraudHandler) ListAgingCases(w http.ResponseWriter, r *http.Request) {
+	cases, err := h.fraudService.ListAgingCases(r.Context())
+	if err != nil {
+		writeInternalError(w, r, "Failed to list aging cases", err)
+		return
+	}
+
+	breached := 0
+	for _, c := range cases {
+		if c.Breached {
+			breached++
+		}
+	}
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"cases": cases,
-		"count": len(cases),
+		"cases":    cases,
+		"count":    len(cases),
+		"breached": breached,
 	})
 }
 
+// ClaimCaseRequest names who takes the next queued case
+type ClaimCaseRequest struct {
+	InvestigatorID string `json:"investigator_id,omitempty"` // Defaults to the caller
+}
+
+// ClaimNextCase handles POST /api/v1/fraud/cases/queue/claim
+// The first case in the queue is assigned and returned; an empty queue returns 404
+func (h *FraudHandler) ClaimNextCase(w http.ResponseWriter, r *http.Request) {
+	var req ClaimCaseRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	investigatorID := userFromContext(r)
+	if req.InvestigatorID != "" {
+		id, err := uuid.Parse(req.InvestigatorID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid investigator ID")
+			return
+		}
+		investigatorID = id
+	}
+	if investigatorID == uuid.Nil {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Investigator ID is required")
+		return
+	}
+
+	fraudCase, err := h.fraudService.AssignNextCase(r.Context(), investigatorID)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to claim case")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, fraudCase)
+}
+
 // GetCase handles GET /api/v1/fraud/cases/{id}
 func (h *FraudHandler) GetCase(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	if idStr == "" {
-		writeError(w, http.StatusBadRequest, "Case ID is required")
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Case ID is required")
 		return
 	}
 
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid case ID")
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid case ID")
 		return
 	}
 
 	fraudCase, err := h.fraudService.GetCase(r.Context(), id)
 	if err != nil {
-		if err == fraud.ErrCaseNotFound {
-			writeError(w, http.StatusNotFound, "Case not found")
-			return
-		}
-		writeError(w, http.StatusInternalServerError, "Failed to get case: "+err.Error())
+		writeServiceError(w, r, err, "Failed to get case")
 		return
 	}
 
 	writeJSON(w, http.StatusOK, fraudCase)
 }
 
+// ListCaseNotes handles GET /api/v1/fraud/cases/{id}/notes
+func (h *FraudHandler) ListCaseNotes(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Case ID is required")
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid case ID")
+		return
+	}
+
+	limit, offset, err := parsePagination(r, 50, 500)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, err.Error())
+		return
+	}
+
+	notes, total, err := h.fraudService.ListCaseNotes(r.Context(), id, limit, offset)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to list case notes")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newPaginatedResponse(notes, int64(total), limit, offset))
+}
+
 // UpdateCaseRequest represents the request to update a case
 type UpdateCaseRequest struct {
-	Action        string `json:"action"` // assign, add_note, resolve, close, escalate
-	AssigneeID    string `json:"assignee_id,omitempty"`
-	Note          string `json:"note,omitempty"`
-	Resolution    string `json:"resolution,omitempty"`
-	EscalateReason string `json:"escalate_reason,omitempty"`
+	Action         string               `json:"action"` // assign, add_note, add_evidence, resolve, close, escalate
+	AssigneeID     string               `json:"assignee_id,omitempty"`
+	Note           string               `json:"note,omitempty"`
+	Resolution     string               `json:"resolution,omitempty"`
+	Outcome        string               `json:"outcome,omitempty"` // With resolve: approve or decline the case's transactions awaiting review
+	EscalateReason string               `json:"escalate_reason,omitempty"`
+	Evidence       *CaseEvidenceRequest `json:"evidence,omitempty"` // With add_evidence
+}
+
+// CaseEvidenceRequest describes an artifact attached to a case
+type CaseEvidenceRequest struct {
+	Type        string            `json:"type"` // screenshot, log, document
+	Description string            `json:"description"`
+	URL         string            `json:"url,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
 // UpdateCase handles PUT /api/v1/fraud/cases/{id}
 func (h *FraudHandler) UpdateCase(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	if idStr == "" {
-		writeError(w, http.StatusBadRequest, "Case ID is required")
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Case ID is required")
 		return
 	}
 
 	caseID, err := uuid.Parse(idStr)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid case ID")
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid case ID")
 		return
 	}
 
 	var req UpdateCaseRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
 		return
 	}
 
-	// Get user ID from context (would come from auth middleware)
-	userID := uuid.New() // Placeholder - should come from auth
+	userID := userFromContext(r)
 
 	switch req.Action {
 	case "assign":
 		assigneeID, err := uuid.Parse(req.AssigneeID)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "Invalid assignee ID")
+			writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid assignee ID")
 			return
 		}
 		if err := h.fraudService.AssignCase(r.Context(), caseID, assigneeID); err != nil {
-			writeError(w, http.StatusInternalServerError, "Failed to assign case: "+err.Error())
+			writeServiceError(w, r, err, "Failed to assign case")
 			return
 		}
 
 	case "add_note":
 		if req.Note == "" {
-			writeError(w, http.StatusBadRequest, "Note content is required")
+			writeError(w, http.StatusBadRequest, CodeValidationError, "Note content is required")
 			return
 		}
 		if err := h.fraudService.AddCaseNote(r.Context(), caseID, userID, req.Note); err != nil {
-			writeError(w, http.StatusInternalServerError, "Failed to add note: "+err.Error())
+			writeServiceError(w, r, err, "Failed to add note")
+			return
+		}
+
+	case "add_evidence":
+		evidence := req.Evidence
+		if evidence == nil || evidence.Description == "" {
+			writeError(w, http.StatusBadRequest, CodeValidationError, "Evidence description is required")
+			return
+		}
+		if evidence.URL != "" {
+			if u, err := url.Parse(evidence.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				writeError(w, http.StatusBadRequest, CodeValidationError, "Evidence URL must be an http or https URL")
+				return
+			}
+		}
+		if err := h.fraudService.AddCaseEvidence(r.Context(), caseID, evidence.Type, evidence.Description, evidence.URL, evidence.Metadata); err != nil {
+			writeServiceError(w, r, err, "Failed to add evidence")
 			return
 		}
 
 	case "resolve":
 		if req.Resolution == "" {
-			writeError(w, http.StatusBadRequest, "Resolution is required")
+			writeError(w, http.StatusBadRequest, CodeValidationError, "Resolution is required")
 			return
 		}
-		if err := h.fraudService.ResolveCase(r.Context(), caseID, userID, req.Resolution); err != nil {
-			writeError(w, http.StatusInternalServerError, "Failed to resolve case: "+err.Error())
+		if err := h.fraudService.ResolveCase(r.Context(), caseID, userID, req.Resolution, fraud.ResolutionOutcome(req.Outcome)); err != nil {
+			writeServiceError(w, r, err, "Failed to resolve case")
 			return
 		}
 
 	case "close":
 		if err := h.fraudService.CloseCase(r.Context(), caseID); err != nil {
-			writeError(w, http.StatusInternalServerError, "Failed to close case: "+err.Error())
+			writeServiceError(w, r, err, "Failed to close case")
 			return
 		}
 
 	case "escalate":
 		if err := h.fraudService.EscalateCase(r.Context(), caseID, req.EscalateReason); err != nil {
-			writeError(w, http.StatusInternalServerError, "Failed to escalate case: "+err.Error())
+			writeServiceError(w, r, err, "Failed to escalate case")
 			return
 		}
 
 	default:
-		writeError(w, http.StatusBadRequest, "Invalid action: "+req.Action)
+		writeError(w, http.StatusBadRequest, CodeValidationError, "Invalid action: "+req.Action)
 		return
 	}
 
 	// Return updated case
 	fraudCase, err := h.fraudService.GetCase(r.Context(), caseID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "Failed to get updated case: "+err.Error())
+		writeInternalError(w, r, "Failed to get updated case", err)
 		return
 	}
 
@@ -303,77 +528,336 @@ func (h *FraudHandler) UpdateCase(w http.ResponseWriter, r *http.Request) {
 }
 
 // ListRules handles GET /api/v1/fraud/rules
+// Active rules come ordered by name
 func (h *FraudHandler) ListRules(w http.ResponseWriter, r *http.Request) {
-	rules, err := h.fraudService.ListActiveRules(r.Context())
+	limit, offset, err := parsePagination(r, 50, 200)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "Failed to list rules: "+err.Error())
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, err.Error())
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"rules": rules,
-		"count": len(rules),
-	})
+	rules, total, err := h.fraudService.ListActiveRulesPage(r.Context(), limit, offset)
+	if err != nil {
+		writeInternalError(w, r, "Failed to list rules", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newPaginatedResponse(rules, int64(total), limit, offset))
 }
 
-// CreateRule handles POST /api/v1/fraud/rules
-func (h *FraudHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Name        string                 `json:"name"`
-		Description string                 `json:"description"`
-		Type        string                 `json:"type"`
-		Severity    string                 `json:"severity"`
-		Action      string                 `json:"action"`
-		Config      map[string]interface{} `json:"config"`
+// ruleDefinition is the request body for creating a rule
+type ruleDefinition struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Type        string                 `json:"type"`
+	Severity    string                 `json:"severity"`
+	Action      string                 `json:"action"`
+	FailMode    string                 `json:"fail_mode,omitempty"` // open (default) or closed
+	Mode        string                 `json:"mode,omitempty"`      // active (default) or shadow
+	Priority    *int                   `json:"priority,omitempty"`  // Higher runs first under the rule limit; 0 by default
+	Config      map[string]interface{} `json:"config"`
+}
+
+// toRule builds a domain rule from the definition
+func (d *ruleDefinition) toRule(createdBy uuid.UUID) *fraud.Rule {
+	rule := fraud.NewRule(
+		d.Name,
+		d.Description,
+		fraud.RuleType(d.Type),
+		fraud.RuleSeverity(d.Severity),
+		fraud.RuleAction(d.Action),
+		createdBy,
+	)
+	if d.FailMode != "" {
+		rule.FailMode = fraud.RuleFailMode(d.FailMode)
 	}
+	if d.Mode != "" {
+		rule.Mode = fraud.RuleMode(d.Mode)
+	}
+	if d.Priority != nil {
+		rule.Priority = *d.Priority
+	}
+	rule.Config = d.Config
+	return rule
+}
 
+// applyTo overwrites the rule's fields with those set in the definition
+// Empty fields keep the rule's current values
+func (d *ruleDefinition) applyTo(rule *fraud.Rule) {
+	if d.Name != "" {
+		rule.Name = d.Name
+	}
+	if d.Description != "" {
+		rule.Description = d.Description
+	}
+	if d.Type != "" {
+		rule.Type = fraud.RuleType(d.Type)
+	}
+	if d.Severity != "" {
+		rule.Severity = fraud.RuleSeverity(d.Severity)
+	}
+	if d.Action != "" {
+		rule.Action = fraud.RuleAction(d.Action)
+	}
+	if d.FailMode != "" {
+		rule.FailMode = fraud.RuleFailMode(d.FailMode)
+	}
+	if d.Mode != "" {
+		rule.Mode = fraud.RuleMode(d.Mode)
+	}
+	if d.Priority != nil {
+		rule.Priority = *d.Priority
+	}
+	if d.Config != nil {
+		rule.UpdateConfig(d.Config)
+	}
+}
+
+// CreateRule handles POST /api/v1/fraud/rules
+func (h *FraudHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	var req ruleDefinition
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
 		return
 	}
 
-	// Get user ID from context (would come from auth middleware)
-	userID := uuid.New() // Placeholder
+	userID := userFromContext(r)
 
-	rule := fraud.NewRule(
-		req.Name,
-		req.Description,
-		fraud.RuleType(req.Type),
-		fraud.RuleSeverity(req.Severity),
-		fraud.RuleAction(req.Action),
-		userID,
-	)
-	rule.Config = req.Config
+	rule := req.toRule(userID)
 
 	if err := h.fraudService.CreateRule(r.Context(), rule); err != nil {
-		writeError(w, http.StatusInternalServerError, "Failed to create rule: "+err.Error())
+		writeServiceError(w, r, err, "Failed to create rule")
 		return
 	}
 
 	writeJSON(w, http.StatusCreated, rule)
 }
 
+// ImportRules handles POST /api/v1/fraud/rules/import
+func (h *FraudHandler) ImportRules(w http.ResponseWriter, r *http.Request) {
+	var req []ruleDefinition
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	userID := userFromContext(r)
+
+	rules := make([]*fraud.Rule, len(req))
+	for i := range req {
+		rules[i] = req[i].toRule(userID)
+	}
+
+	if err := h.fraudService.ImportRules(r.Context(), rules); err != nil {
+		writeServiceError(w, r, err, "Failed to import rules")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"rules": rules,
+		"count": len(rules),
+	})
+}
+
+// TestRuleRequest is the request body for a rule dry run
+type TestRuleRequest struct {
+	Rule        ruleDefinition                     `json:"rule"`
+	Transaction fraudapp.AnalyzeTransactionRequest `json:"transaction"`
+}
+
+// TestRule handles POST /api/v1/fraud/rules/test
+// The candidate rule is evaluated against the sample transaction without being saved
+func (h *FraudHandler) TestRule(w http.ResponseWriter, r *http.Request) {
+	var req TestRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	rule := req.Rule.toRule(uuid.Nil)
+	if err := h.fraudService.ValidateRule(rule); err != nil {
+		writeError(w, http.StatusBadRequest, CodeValidationError, "Invalid rule: "+err.Error())
+		return
+	}
+
+	input, err := h.toInput(r, &req.Transaction)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeValidationError, err.Error())
+		return
+	}
+
+	result, err := h.detectFraudUseCase.TestRule(r.Context(), rule, *input)
+	if err != nil {
+		writeInternalError(w, r, "Failed to test rule", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
 // GetRule handles GET /api/v1/fraud/rules/{id}
 func (h *FraudHandler) GetRule(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	if idStr == "" {
-		writeError(w, http.StatusBadRequest, "Rule ID is required")
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Rule ID is required")
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid rule ID")
+		return
+	}
+
+	rule, err := h.fraudService.GetRule(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to get rule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// UpdateRule handles PUT and PATCH /api/v1/fraud/rules/{id}
+func (h *FraudHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Rule ID is required")
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid rule ID")
+		return
+	}
+
+	var req ruleDefinition
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	existing, err := h.fraudService.GetRule(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to get rule")
+		return
+	}
+
+	userID := userFromContext(r)
+
+	// Apply changes to a copy so a rejected update leaves the stored rule untouched
+	rule := *existing
+	req.applyTo(&rule)
+	rule.UpdatedBy = userID
+
+	if err := h.fraudService.UpdateRule(r.Context(), &rule); err != nil {
+		writeServiceError(w, r, err, "Failed to update rule")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &rule)
+}
+
+// DisableRule handles DELETE /api/v1/fraud/rules/{id}
+// Rules are disabled rather than deleted so past decisions stay explainable
+func (h *FraudHandler) DisableRule(w http.ResponseWriter, r *http.Request) {
+	h.setRuleEnabled(w, r, false)
+}
+
+// EnableRule handles POST /api/v1/fraud/rules/{id}/enable
+func (h *FraudHandler) EnableRule(w http.ResponseWriter, r *http.Request) {
+	h.setRuleEnabled(w, r, true)
+}
+
+// setRuleEnabled enables or disables the rule in the path and returns it
+func (h *FraudHandler) setRuleEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Rule ID is required")
 		return
 	}
 
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid rule ID")
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid rule ID")
+		return
+	}
+
+	userID := userFromContext(r)
+
+	if enabled {
+		err = h.fraudService.EnableRule(r.Context(), id, userID)
+	} else {
+		err = h.fraudService.DisableRule(r.Context(), id, userID)
+	}
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to update rule")
 		return
 	}
 
 	rule, err := h.fraudService.GetRule(r.Context(), id)
+	if err != nil {
+		writeInternalError(w, r, "Failed to get updated rule", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rule)
+}
+
+// ListRuleVersions handles GET /api/v1/fraud/rules/{id}/versions
+func (h *FraudHandler) ListRuleVersions(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Rule ID is required")
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid rule ID")
+		return
+	}
+
+	versions, err := h.fraudService.ListRuleVersions(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to list rule versions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"versions": versions,
+		"count":    len(versions),
+	})
+}
+
+// GetRuleVersion handles GET /api/v1/fraud/rules/{id}/versions/{version}
+func (h *FraudHandler) GetRuleVersion(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Rule ID is required")
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid rule ID")
+		return
+	}
+
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil || version <= 0 {
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, "Invalid rule version")
+		return
+	}
+
+	rule, err := h.fraudService.GetRuleVersion(r.Context(), id, version)
 	if err != nil {
 		if err == fraud.ErrRuleNotFound {
-			writeError(w, http.StatusNotFound, "Rule not found")
+			writeError(w, http.StatusNotFound, CodeRuleVersionNotFound, "Rule version not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "Failed to get rule: "+err.Error())
+		writeInternalError(w, r, "Failed to get rule version", err)
 		return
 	}
 
@@ -381,13 +865,63 @@ func (h *FraudHandler) GetRule(w http.ResponseWriter, r *http.Request) {
 }
 
 // Helper functions
-func writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+
+// parsePagination reads limit and offset query parameters, applying a default
+// limit when none is given and capping it at maxLimit
+func parsePagination(r *http.Request, defaultLimit, maxLimit int) (int, int, error) {
+	limit := defaultLimit
+	offset := 0
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return 0, 0, fmt.Errorf("invalid limit: %s", v)
+		}
+		limit = n
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("invalid offset: %s", v)
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
 }
 
-func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]string{"error": message})
+// PaginatedResponse is the body of every paginated list endpoint
+// Total counts every matching item, not just this page
+type PaginatedResponse[T any] struct {
+	Items   []T   `json:"items"`
+	Total   int64 `json:"total"`
+	Limit   int   `json:"limit"`
+	Offset  int   `json:"offset"`
+	HasMore bool  `json:"has_more"`
 }
 
+// newPaginatedResponse wraps a page of items; a nil page is sent as an empty list
+func newPaginatedResponse[T any](items []T, total int64, limit, offset int) *PaginatedResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &PaginatedResponse[T]{
+		Items:   items,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: int64(offset+len(items)) < total,
+	}
+}
+
+// userFromContext returns the authenticated caller, or uuid.Nil when authentication is disabled
+func userFromContext(r *http.Request) uuid.UUID {
+	if userID, ok := middleware.UserIDFromContext(r.Context()); ok {
+		return userID
+	}
+	return uuid.Nil
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"time"
+
+	"fraud-detecction-system/internal/pkg/metrics"
 )
 
 // HealthChecker is an interface for services that can be health-checked
@@ -12,10 +14,18 @@ type HealthChecker interface {
 	Ping(ctx context.Context) error
 }
 
+// MLStatusReporter reports whether ML scoring is on and has a model loaded
+type MLStatusReporter interface {
+	IsEnabled() bool
+	HasModel() bool
+}
+
 // HealthHandler handles health check endpoints
 type HealthHandler struct {
 	dbClient    HealthChecker
 	redisClient HealthChecker
+	embedded    HealthChecker // Standalone store for decisions, cases and rules, if one is open
+	ml          MLStatusReporter
 	version     string
 }
 
@@ -28,12 +38,26 @@ func NewHealthHandler(dbClient, redisClient HealthChecker, version string) *Heal
 	}
 }
 
+// SetMLStatus sets where the status endpoint reads the ML scoring state from
+func (h *HealthHandler) SetMLStatus(ml MLStatusReporter) {
+	h.ml = ml
+}
+
+// SetEmbeddedStore sets the standalone store the status endpoint reports on
+func (h *HealthHandler) SetEmbeddedStore(store HealthChecker) {
+	h.embedded = store
+}
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status    string            `json:"status"`
 	Version   string            `json:"version"`
 	Timestamp string            `json:"timestamp"`
 	Services  map[string]string `json:"services,omitempty"`
+
+	// Readiness only: the API serves requests but with signals disabled
+	Degraded        bool     `json:"degraded,omitempty"`
+	DisabledSignals []string `json:"disabled_signals,omitempty"`
 }
 
 // Health handles GET /health
@@ -54,6 +78,7 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 
 	services := make(map[string]string)
 	allHealthy := true
+	dbConnected, redisConnected := false, false
 
 	// Check database
 	if h.dbClient != nil {
@@ -62,6 +87,7 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 			allHealthy = false
 		} else {
 			services["database"] = "healthy"
+			dbConnected = true
 		}
 	}
 
@@ -72,13 +98,20 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 			allHealthy = false
 		} else {
 			services["redis"] = "healthy"
+			redisConnected = true
 		}
 	}
 
+	// A dependency that was never connected leaves the API ready but degraded
+	disabled := disabledSignals(dbConnected, redisConnected)
+	metrics.SetDegradedMode(len(disabled) > 0)
+
 	response := HealthResponse{
-		Version:   h.version,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		Services:  services,
+		Version:         h.version,
+		Timestamp:       time.Now().UTC().Format(time.RFC3339),
+		Services:        services,
+		Degraded:        len(disabled) > 0,
+		DisabledSignals: disabled,
 	}
 
 	if allHealthy {
@@ -90,6 +123,91 @@ func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// StatusResponse reports which dependencies are connected and what runs without them
+type StatusResponse struct {
+	Status           string            `json:"status"` // ok or degraded
+	Version          string            `json:"version"`
+	Timestamp        string            `json:"timestamp"`
+	Dependencies     map[string]string `json:"dependencies"`
+	MockRepositories bool              `json:"mock_repositories"` // In-memory storage for what the embedded store, if any, doesn't keep
+	DisabledSignals  []string          `json:"disabled_signals"`
+}
+
+// Signals that depend on Redis or the database
+var (
+	redisSignals    = []string{"velocity", "device", "location", "merchant", "card_testing", "account_linking"}
+	databaseSignals = []string{"persistence"}
+)
+
+// disabledSignals lists the signals that are off given which dependencies are connected
+func disabledSignals(dbConnected, redisConnected bool) []string {
+	disabled := []string{}
+	if !redisConnected {
+		disabled = append(disabled, redisSignals...)
+	}
+	if !dbConnected {
+		disabled = append(disabled, databaseSignals...)
+	}
+	return disabled
+}
+
+// mlStatus describes the state of ML scoring
+func (h *HealthHandler) mlStatus() string {
+	switch {
+	case h.ml == nil || !h.ml.IsEnabled():
+		return "disabled"
+	case h.ml.HasModel():
+		return "model loaded"
+	default:
+		return "heuristic weights"
+	}
+}
+
+// Status handles GET /status and GET /api/v1/fraud/status
+func (h *HealthHandler) Status(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	dbStatus, dbConnected := dependencyStatus(ctx, h.dbClient)
+	redisStatus, redisConnected := dependencyStatus(ctx, h.redisClient)
+
+	response := StatusResponse{
+		Status:    "ok",
+		Version:   h.version,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Dependencies: map[string]string{
+			"database": dbStatus,
+			"redis":    redisStatus,
+			"ml":       h.mlStatus(),
+		},
+		// Without a database the API runs on in-memory repositories
+		MockRepositories: h.dbClient == nil,
+		DisabledSignals:  disabledSignals(dbConnected, redisConnected),
+	}
+
+	metrics.SetDegradedMode(len(response.DisabledSignals) > 0)
+	if len(response.DisabledSignals) > 0 {
+		response.Status = "degraded"
+	}
+
+	if h.embedded != nil {
+		response.Dependencies["embedded_store"], _ = dependencyStatus(ctx, h.embedded)
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// dependencyStatus pings a dependency and describes its state
+func dependencyStatus(ctx context.Context, checker HealthChecker) (string, bool) {
+	if checker == nil {
+		return "not configured", false
+	}
+	if err := checker.Ping(ctx); err != nil {
+		return "unhealthy: " + err.Error(), false
+	}
+	return "connected", true
+}
+
 // Live handles GET /live
 func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
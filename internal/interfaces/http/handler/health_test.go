@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// pingStub answers pings with err
+type pingStub struct {
+	err error
+}
+
+func (p pingStub) Ping(ctx context.Context) error {
+	return p.err
+}
+
+func TestStatusReportsDisabledSignals(t *testing.T) {
+	tests := []struct {
+		name         string
+		db           HealthChecker
+		redis        HealthChecker
+		wantStatus   string
+		wantMock     bool
+		wantDisabled []string
+		wantEnabled  []string
+	}{
+		{"all connected", pingStub{}, pingStub{}, "ok", false, nil, []string{"velocity", "device", "location", "persistence"}},
+		{"no redis", pingStub{}, nil, "degraded", false, []string{"velocity", "device", "location"}, []string{"persistence"}},
+		{"redis down", pingStub{}, pingStub{err: errors.New("connection refused")}, "degraded", false, []string{"velocity", "device", "location"}, []string{"persistence"}},
+		{"no database", nil, pingStub{}, "degraded", true, []string{"persistence"}, []string{"velocity", "device", "location"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler(tt.db, tt.redis, "test")
+			rec := httptest.NewRecorder()
+			h.Status(rec, httptest.NewRequest(http.MethodGet, "/api/v1/fraud/status", nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d, want 200; body %s", rec.Code, rec.Body)
+			}
+
+			var body StatusResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if body.Status != tt.wantStatus {
+				t.Errorf("status %q, want %q", body.Status, tt.wantStatus)
+			}
+			if body.MockRepositories != tt.wantMock {
+				t.Errorf("mock repositories %t, want %t", body.MockRepositories, tt.wantMock)
+			}
+			for _, signal := range tt.wantDisabled {
+				if !slices.Contains(body.DisabledSignals, signal) {
+					t.Errorf("disabled signals %v, want %s", body.DisabledSignals, signal)
+				}
+			}
+			for _, signal := range tt.wantEnabled {
+				if slices.Contains(body.DisabledSignals, signal) {
+					t.Errorf("disabled signals %v, want %s enabled", body.DisabledSignals, signal)
+				}
+			}
+		})
+	}
+}
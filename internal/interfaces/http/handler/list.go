@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// AddListEntryRequest is the request body for adding an allowlist or denylist entry
+type AddListEntryRequest struct {
+	ListType   fraud.ListType       `json:"list_type"`   // allow or deny
+	EntityType fraud.ListEntityType `json:"entity_type"` // user, device_id, card_bin or ip
+	Value      string               `json:"value"`
+	Reason     string               `json:"reason"`
+}
+
+// ListEntries handles GET /api/v1/fraud/lists
+// An optional list_type query parameter limits the result to one list
+func (h *FraudHandler) ListEntries(w http.ResponseWriter, r *http.Request) {
+	listType := fraud.ListType(r.URL.Query().Get("list_type"))
+	if listType != "" && !listType.IsValid() {
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, "list_type must be allow or deny")
+		return
+	}
+
+	entries, err := h.fraudService.ListEntries(r.Context(), listType)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to list entries")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// AddListEntry handles POST /api/v1/fraud/lists
+func (h *FraudHandler) AddListEntry(w http.ResponseWriter, r *http.Request) {
+	var req AddListEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	entry := fraud.NewListEntry(req.ListType, req.EntityType, req.Value, req.Reason, userFromContext(r))
+	if err := h.fraudService.AddListEntry(r.Context(), entry); err != nil {
+		writeServiceError(w, r, err, "Failed to add list entry")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+// RemoveListEntry handles DELETE /api/v1/fraud/lists/{id}
+func (h *FraudHandler) RemoveListEntry(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "List entry ID is required")
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid list entry ID")
+		return
+	}
+
+	if err := h.fraudService.RemoveListEntry(r.Context(), id); err != nil {
+		writeServiceError(w, r, err, "Failed to remove list entry")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// ModelReloader swaps the ML model used for scoring without a restart
+type ModelReloader interface {
+	ReloadModel(path, version string) error
+	GetModelVersion() string
+}
+
+// ReloadModelRequest names the model file to load and the version to report for it
+type ReloadModelRequest struct {
+	Path    string `json:"path"` // .onnx model, or a JSON array of weights
+	Version string `json:"version"`
+}
+
+// ReloadModelResponse reports the model version now in use
+type ReloadModelResponse struct {
+	ModelVersion string `json:"model_version"`
+}
+
+// SetModelReloader sets the predictor reloaded by the model reload endpoint
+func (h *FraudHandler) SetModelReloader(reloader ModelReloader) {
+	h.modelReloader = reloader
+}
+
+// ReloadModel handles POST /api/v1/fraud/ml/model/reload
+// A model that fails to load or validate is rejected and the current one keeps serving
+func (h *FraudHandler) ReloadModel(w http.ResponseWriter, r *http.Request) {
+	if h.modelReloader == nil {
+		writeError(w, http.StatusServiceUnavailable, CodeModelReloadFailed, "Model reloading is not configured")
+		return
+	}
+
+	var req ReloadModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+	if req.Path == "" {
+		writeError(w, http.StatusBadRequest, CodeValidationError, "path is required")
+		return
+	}
+	if req.Version == "" {
+		writeError(w, http.StatusBadRequest, CodeValidationError, "version is required")
+		return
+	}
+
+	previous := h.modelReloader.GetModelVersion()
+	if err := h.modelReloader.ReloadModel(req.Path, req.Version); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, CodeModelReloadFailed, err.Error())
+		return
+	}
+
+	slog.InfoContext(r.Context(), "ML model reloaded",
+		slog.String("previous_version", previous),
+		slog.String("model_version", req.Version),
+		slog.String("path", req.Path),
+	)
+
+	writeJSON(w, http.StatusOK, ReloadModelResponse{ModelVersion: h.modelReloader.GetModelVersion()})
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// DecisionOverrideRequest represents an analyst's re-decision of a transaction
+type DecisionOverrideRequest struct {
+	Decision string `json:"decision"` // allow, block, review
+	Reason   string `json:"reason"`
+}
+
+// DecisionOverrideResponse is the decision's outcome after an override
+type DecisionOverrideResponse struct {
+	DecisionID        uuid.UUID               `json:"decision_id"`
+	EffectiveDecision fraud.DecisionType      `json:"effective_decision"`
+	Override          *fraud.DecisionOverride `json:"override"`
+}
+
+// SetBlockOverrideCheck sets how to tell whether a caller may override a block decision
+func (h *FraudHandler) SetBlockOverrideCheck(check func(r *http.Request) bool) {
+	h.canOverrideBlock = check
+}
+
+// OverrideDecision handles POST /api/v1/fraud/decisions/{id}/override
+func (h *FraudHandler) OverrideDecision(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Decision ID is required")
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid decision ID")
+		return
+	}
+
+	var req DecisionOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	canOverrideBlock := h.canOverrideBlock == nil || h.canOverrideBlock(r)
+	override, err := h.fraudService.OverrideDecision(r.Context(), id, fraud.DecisionType(req.Decision), req.Reason, userFromContext(r), canOverrideBlock)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to override decision")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, DecisionOverrideResponse{
+		DecisionID:        override.DecisionID,
+		EffectiveDecision: override.Decision,
+		Override:          override,
+	})
+}
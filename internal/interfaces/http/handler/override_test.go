@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// overridableDecisionRepo holds one decision and applies every override to it
+type overridableDecisionRepo struct {
+	fraud.DecisionRepository
+	decision *fraud.FraudDecision
+}
+
+func (r *overridableDecisionRepo) GetByID(ctx context.Context, id uuid.UUID) (*fraud.FraudDecision, error) {
+	if id != r.decision.ID {
+		return nil, fraud.ErrDecisionNotFound
+	}
+	return r.decision, nil
+}
+
+func (r *overridableDecisionRepo) RecordOverride(ctx context.Context, override *fraud.DecisionOverride) error {
+	r.decision.OverriddenDecision = override.Decision
+	return nil
+}
+
+// overrideDecision posts body to the override endpoint for decisionID
+func overrideDecision(h *FraudHandler, decisionID uuid.UUID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/fraud/decisions/"+decisionID.String()+"/override", strings.NewReader(body))
+	req.SetPathValue("id", decisionID.String())
+	rec := httptest.NewRecorder()
+	h.OverrideDecision(rec, req)
+	return rec
+}
+
+func TestOverrideDecision(t *testing.T) {
+	tests := []struct {
+		name          string
+		current       fraud.DecisionType
+		body          string
+		canLiftBlock  bool
+		unknown       bool
+		wantStatus    int
+		wantEffective fraud.DecisionType
+	}{
+		{"review to allow", fraud.DecisionReview, `{"decision":"allow","reason":"verified"}`, false, false, http.StatusCreated, fraud.DecisionAllow},
+		{"block lifted by an admin", fraud.DecisionBlock, `{"decision":"allow","reason":"verified"}`, true, false, http.StatusCreated, fraud.DecisionAllow},
+		{"block lifted by anyone else", fraud.DecisionBlock, `{"decision":"allow","reason":"verified"}`, false, false, http.StatusForbidden, fraud.DecisionBlock},
+		{"invalid outcome", fraud.DecisionReview, `{"decision":"escalate","reason":"verified"}`, true, false, http.StatusBadRequest, fraud.DecisionReview},
+		{"missing reason", fraud.DecisionReview, `{"decision":"allow"}`, true, false, http.StatusBadRequest, fraud.DecisionReview},
+		{"unchanged", fraud.DecisionReview, `{"decision":"review","reason":"verified"}`, true, false, http.StatusConflict, fraud.DecisionReview},
+		{"unknown decision", fraud.DecisionReview, `{"decision":"allow","reason":"verified"}`, true, true, http.StatusNotFound, fraud.DecisionReview},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := fraud.NewFraudDecision(uuid.New(), uuid.New(), tt.current, decimal.RequireFromString("0.5"))
+			repo := &overridableDecisionRepo{decision: decision}
+			h := NewFraudHandler(nil, fraud.NewService(repo, nil, nil, nil, nil))
+			h.SetBlockOverrideCheck(func(*http.Request) bool { return tt.canLiftBlock })
+
+			id := decision.ID
+			if tt.unknown {
+				id = uuid.New()
+			}
+			rec := overrideDecision(h, id, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if got := decision.EffectiveDecision(); got != tt.wantEffective {
+				t.Errorf("stored effective decision %s, want %s", got, tt.wantEffective)
+			}
+			if rec.Code != http.StatusCreated {
+				return
+			}
+
+			var resp DecisionOverrideResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.DecisionID != decision.ID || resp.EffectiveDecision != tt.wantEffective {
+				t.Errorf("response for %s with effective decision %s, want %s with %s", resp.DecisionID, resp.EffectiveDecision, decision.ID, tt.wantEffective)
+			}
+			if resp.Override == nil || resp.Override.PreviousDecision != tt.current {
+				t.Errorf("override %+v, want one from %s", resp.Override, tt.current)
+			}
+		})
+	}
+}
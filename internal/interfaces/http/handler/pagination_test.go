@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  int
+		wantOffset int
+		wantErr    bool
+	}{
+		{"defaults", "", 50, 0, false},
+		{"explicit", "?limit=10&offset=20", 10, 20, false},
+		{"capped at max", "?limit=900", 500, 0, false},
+		{"zero limit", "?limit=0", 0, 0, true},
+		{"non-numeric limit", "?limit=ten", 0, 0, true},
+		{"negative offset", "?offset=-1", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/v1/fraud/cases/x/notes"+tt.query, nil)
+			limit, offset, err := parsePagination(r, 50, 500)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("limit, offset = %d, %d, want %d, %d", limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// memoryRuleRepo keeps rules in a map; only the methods the rule handlers use are implemented
+type memoryRuleRepo struct {
+	fraud.RuleRepository
+	rules map[uuid.UUID]*fraud.Rule
+}
+
+func (r *memoryRuleRepo) CreateBatch(ctx context.Context, rules []*fraud.Rule) error {
+	for _, rule := range rules {
+		r.rules[rule.ID] = rule
+	}
+	return nil
+}
+
+// countingEngine counts cache invalidations; nothing else is implemented
+type countingEngine struct {
+	fraud.RuleEngine
+	invalidations int
+}
+
+func (e *countingEngine) InvalidateCache() {
+	e.invalidations++
+}
+
+// importRules posts body to the rule import endpoint
+func importRules(h *FraudHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/fraud/rules/import", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ImportRules(rec, req)
+	return rec
+}
+
+func TestImportRules(t *testing.T) {
+	const valid = `{"name":"high_amount","type":"amount","severity":"medium","action":"review","config":{"max_amount":"5000"}}`
+	const valid2 = `{"name":"fast","type":"velocity","severity":"high","action":"block","config":{"max_transactions":5}}`
+
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantStored  int
+		wantInvalid []int // Indexes reported as invalid
+	}{
+		{"all valid", "[" + valid + "," + valid2 + "]", http.StatusCreated, 2, nil},
+		{"one invalid type", "[" + valid + `,{"name":"bad","type":"unknown","severity":"low","action":"allow","config":{"x":1}}]`, http.StatusUnprocessableEntity, 0, []int{1}},
+		{"missing config", `[{"name":"empty","type":"amount","severity":"low","action":"allow"},` + valid2 + "]", http.StatusUnprocessableEntity, 0, []int{0}},
+		{"duplicate names", "[" + valid + "," + valid + "]", http.StatusUnprocessableEntity, 0, []int{1}},
+		{"empty batch", "[]", http.StatusBadRequest, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryRuleRepo{rules: make(map[uuid.UUID]*fraud.Rule)}
+			engine := &countingEngine{}
+			h := NewFraudHandler(nil, fraud.NewService(nil, nil, repo, engine, nil))
+
+			rec := importRules(h, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d; body %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if len(repo.rules) != tt.wantStored {
+				t.Errorf("%d rules stored, want %d", len(repo.rules), tt.wantStored)
+			}
+			if wantInvalidations := min(tt.wantStored, 1); engine.invalidations != wantInvalidations {
+				t.Errorf("cache invalidated %d times, want %d", engine.invalidations, wantInvalidations)
+			}
+			if tt.wantInvalid == nil {
+				return
+			}
+
+			var body struct {
+				Code    string                      `json:"code"`
+				Details []fraud.RuleValidationError `json:"details"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if body.Code != CodeRuleImportRejected {
+				t.Errorf("code %s, want %s", body.Code, CodeRuleImportRejected)
+			}
+			if len(body.Details) != len(tt.wantInvalid) {
+				t.Fatalf("details %+v, want indexes %v", body.Details, tt.wantInvalid)
+			}
+			for i, index := range tt.wantInvalid {
+				if body.Details[i].Index != index || body.Details[i].Error == "" {
+					t.Errorf("detail %d = %+v, want index %d with an error", i, body.Details[i], index)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,176 @@
 package handler
 
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/application/dto"
+	txapp "fraud-detecction-system/internal/application/transaction"
+	"fraud-detecction-system/internal/domain/transaction"
+)
+
 // TransactionHandler handles transaction-related HTTP requests
-// Most transaction fraud analysis is done through the FraudHandler
-// This is a placeholder for direct transaction management
+// Creating a transaction scores it in the same call; use FraudHandler to
+// analyze a transaction without storing it
+type TransactionHandler struct {
+	processTransactionUseCase   *txapp.ProcessTransactionUseCase
+	listUserTransactionsUseCase *txapp.ListUserTransactionsUseCase
+	txService                   *transaction.Service
+}
+
+// NewTransactionHandler creates a new transaction handler
+func NewTransactionHandler(
+	processTransactionUseCase *txapp.ProcessTransactionUseCase,
+	listUserTransactionsUseCase *txapp.ListUserTransactionsUseCase,
+	txService *transaction.Service,
+) *TransactionHandler {
+	return &TransactionHandler{
+		processTransactionUseCase:   processTransactionUseCase,
+		listUserTransactionsUseCase: listUserTransactionsUseCase,
+		txService:                   txService,
+	}
+}
+
+// CreateTransaction handles POST /api/v1/transactions
+// The transaction is stored, scored and moved to approved, declined or flagged
+func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateTreansactionRequests
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	response, err := h.processTransactionUseCase.Execute(r.Context(), &req)
+	if err != nil {
+		// The transaction was stored and flagged for review; report it as created
+		if errors.Is(err, txapp.ErrFraudCheckFailed) && response != nil {
+			writeJSON(w, http.StatusCreated, response)
+			return
+		}
+		writeServiceError(w, r, err, "Failed to process transaction")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// ReviewTransactionRequest represents a reviewer's action on a flagged transaction
+type ReviewTransactionRequest struct {
+	Action string `json:"action"`           // claim, approve, decline
+	Reason string `json:"reason,omitempty"` // Why a transaction was declined, added to its fraud reasons
+}
+
+// ReviewTransaction handles POST /api/v1/transactions/{id}/review
+// A flagged transaction is claimed first, then approved or declined by the same
+// reviewer. Invalid transitions, such as approving a transaction nobody has
+// claimed or someone else claimed, are rejected with 409 and leave the
+// transaction as it was.
+func (h *TransactionHandler) ReviewTransaction(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "Transaction ID is required")
+		return
+	}
+
+	txID, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid transaction ID")
+		return
+	}
+
+	var req ReviewTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidBody, "Invalid request body: "+err.Error())
+		return
+	}
+
+	tx, err := h.txService.GetTransaction(r.Context(), txID)
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to get transaction")
+		return
+	}
+
+	switch req.Action {
+	case "claim":
+		err = h.txService.MarkUnderReview(r.Context(), txID, userFromContext(r))
+
+	case "approve":
+		err = h.txService.ApproveClaimed(r.Context(), txID, userFromContext(r))
+
+	case "decline":
+		reasons := append([]string{}, tx.FraudReasons...)
+		if req.Reason != "" {
+			reasons = append(reasons, "Declined by reviewer: "+req.Reason)
+		}
+		err = h.txService.DeclineClaimed(r.Context(), txID, userFromContext(r), reasons)
+
+	default:
+		writeError(w, http.StatusBadRequest, CodeValidationError, "Invalid action: "+req.Action)
+		return
+	}
+	if err != nil {
+		writeServiceError(w, r, err, "Failed to review transaction")
+		return
+	}
+
+	// Return updated transaction
+	tx, err = h.txService.GetTransaction(r.Context(), txID)
+	if err != nil {
+		writeInternalError(w, r, "Failed to get updated transaction", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tx)
+}
+
+// ListUserTransactions handles GET /api/v1/fraud/users/{id}/transactions
+// Each transaction comes with its fraud decision. Optional status and min_score
+// query parameters narrow the list; pagination uses limit and offset.
+func (h *TransactionHandler) ListUserTransactions(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		writeError(w, http.StatusBadRequest, CodeMissingParameter, "User ID is required")
+		return
+	}
+
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidUUID, "Invalid user ID")
+		return
+	}
+
+	limit, offset, err := parsePagination(r, 50, 200)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidParameter, err.Error())
+		return
+	}
+
+	var filter transaction.ListFilter
+	if v := r.URL.Query().Get("status"); v != "" {
+		filter.Status = transaction.TransactionStatus(v)
+		if !filter.Status.IsValid() {
+			writeError(w, http.StatusBadRequest, CodeInvalidParameter, "invalid status: "+v)
+			return
+		}
+	}
+	if v := r.URL.Query().Get("min_score"); v != "" {
+		minScore, err := decimal.NewFromString(v)
+		if err != nil || minScore.IsNegative() || minScore.GreaterThan(decimal.NewFromInt(1)) {
+			writeError(w, http.StatusBadRequest, CodeInvalidParameter, "invalid min_score: "+v)
+			return
+		}
+		filter.MinScore = &minScore
+	}
+
+	txs, total, err := h.listUserTransactionsUseCase.Execute(r.Context(), userID, filter, limit, offset)
+	if err != nil {
+		writeInternalError(w, r, "Failed to list transactions", err)
+		return
+	}
 
+	writeJSON(w, http.StatusOK, newPaginatedResponse(txs, total, limit, offset))
+}
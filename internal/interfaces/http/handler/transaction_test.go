@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"fraud-detecction-system/internal/application/dto"
+	txapp "fraud-detecction-system/internal/application/transaction"
+	"fraud-detecction-system/internal/domain/fraud"
+	"fraud-detecction-system/internal/domain/transaction"
+	"fraud-detecction-system/internal/infrastructure/http/middleware"
+)
+
+// memoryTransactionRepo keeps transactions in a map; only the methods review and
+// processing use are implemented, and it holds no history for velocity or profiles
+type memoryTransactionRepo struct {
+	transaction.Repository
+	txs map[uuid.UUID]transaction.Transaction
+}
+
+func (r *memoryTransactionRepo) Create(ctx context.Context, tx *transaction.Transaction) error {
+	r.txs[tx.ID] = *tx
+	return nil
+}
+
+func (r *memoryTransactionRepo) GetByID(ctx context.Context, id uuid.UUID) (*transaction.Transaction, error) {
+	tx, ok := r.txs[id]
+	if !ok {
+		return nil, transaction.ErrTransactionNotFound
+	}
+	return &tx, nil
+}
+
+func (r *memoryTransactionRepo) Update(ctx context.Context, tx *transaction.Transaction) error {
+	r.txs[tx.ID] = *tx
+	return nil
+}
+
+func (r *memoryTransactionRepo) GetRecentByUserID(ctx context.Context, userID uuid.UUID, since time.Time) ([]*transaction.Transaction, error) {
+	return nil, nil
+}
+
+func (r *memoryTransactionRepo) GetFirstByUserID(ctx context.Context, userID uuid.UUID) (*transaction.Transaction, error) {
+	return nil, transaction.ErrTransactionNotFound
+}
+
+func (r *memoryTransactionRepo) CountByUserIDAndTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryTransactionRepo) SumAmountByUserIDAndTimeRange(ctx context.Context, userID uuid.UUID, start, end time.Time) (decimal.Decimal, error) {
+	return decimal.Zero, nil
+}
+
+// scoringEngine fires one amount rule at score, or nothing when score is zero
+type scoringEngine struct {
+	fraud.RuleEngine
+	score float64
+}
+
+func (e *scoringEngine) Evaluate(ctx context.Context, evalCtx *fraud.RuleEvaluationContext) ([]fraud.RuleResult, error) {
+	if e.score == 0 {
+		return nil, nil
+	}
+	result := fraud.NewRuleResult(uuid.New(), "amount", true, decimal.NewFromFloat(e.score), "Amount too high", fraud.ActionReview)
+	result.RuleType = fraud.RuleTypeAmount
+	return []fraud.RuleResult{*result}, nil
+}
+
+func TestCreateTransactionAppliesFraudDecision(t *testing.T) {
+	const body = `{"external_id":"order-1","user_id":"%s","account_id":"%s","type":"purchase","amount":"120.50","currency":"USD",
+		"location":{"country":"US","city":"Austin"},"device":{"device_id":"device-1"},"merchant":{"merchant_id":"merchant-1"},"payment":{"type":"card"}}`
+
+	tests := []struct {
+		name       string
+		score      float64
+		wantStatus transaction.TransactionStatus
+		wantReview bool
+	}{
+		{"block declines", 0.9, transaction.StatusDeclined, false},
+		{"review flags", 0.7, transaction.StatusFlagged, true},
+		{"challenge flags", 0.5, transaction.StatusFlagged, true},
+		{"allow approves", 0, transaction.StatusApproved, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryTransactionRepo{txs: make(map[uuid.UUID]transaction.Transaction)}
+			txService := transaction.NewService(repo)
+			decisions := &memoryDecisionRepo{decisions: make(map[uuid.UUID]*fraud.FraudDecision)}
+			cases := &memoryCaseRepo{cases: make(map[uuid.UUID]*fraud.FraudCase)}
+			fraudService := fraud.NewService(decisions, cases, nil, &scoringEngine{score: tt.score}, nil)
+			h := NewTransactionHandler(txapp.NewProcessTransactionUseCase(txService, fraudService), nil, txService)
+
+			userID, accountID := uuid.New(), uuid.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions",
+				strings.NewReader(fmt.Sprintf(body, userID, accountID)))
+			rec := httptest.NewRecorder()
+			h.CreateTransaction(rec, req)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("status %d, want 201; body %s", rec.Code, rec.Body)
+			}
+
+			var got dto.TransactionResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got.Status != string(tt.wantStatus) || got.RequiresReview != tt.wantReview {
+				t.Errorf("status %s, requires review %t; want %s, %t", got.Status, got.RequiresReview, tt.wantStatus, tt.wantReview)
+			}
+			if got.FraudScore == nil {
+				t.Error("no fraud score in the response")
+			}
+
+			stored, ok := repo.txs[got.ID]
+			if !ok {
+				t.Fatalf("transaction %s not stored", got.ID)
+			}
+			if stored.Status != tt.wantStatus || stored.UserID != userID || !stored.Amount.Equal(decimal.RequireFromString("120.50")) {
+				t.Errorf("stored %s for %s of %s, want %s for %s of 120.50", stored.Status, stored.UserID, stored.Amount, tt.wantStatus, userID)
+			}
+			if _, ok := decisions.decisions[got.ID]; !ok {
+				t.Error("no fraud decision stored for the transaction")
+			}
+		})
+	}
+}
+
+// newReviewHandler returns a handler over one transaction in status
+func newReviewHandler(t *testing.T, status transaction.TransactionStatus) (*TransactionHandler, *memoryTransactionRepo, uuid.UUID) {
+	t.Helper()
+	tx := transaction.NewTransaction(uuid.New(), uuid.New(), transaction.TypePurchase, decimal.NewFromInt(100), "USD")
+	tx.Status = status
+	repo := &memoryTransactionRepo{txs: map[uuid.UUID]transaction.Transaction{tx.ID: *tx}}
+	return NewTransactionHandler(nil, nil, transaction.NewService(repo)), repo, tx.ID
+}
+
+// review posts an action to the review endpoint as reviewer
+func review(h *TransactionHandler, txID, reviewer uuid.UUID, action string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/"+txID.String()+"/review",
+		strings.NewReader(`{"action":"`+action+`","reason":"confirmed with cardholder"}`))
+	req.SetPathValue("id", txID.String())
+	req = req.WithContext(middleware.WithPrincipal(req.Context(), &middleware.Principal{UserID: reviewer}))
+
+	rec := httptest.NewRecorder()
+	h.ReviewTransaction(rec, req)
+	return rec
+}
+
+func TestReviewTransactionFlagClaimApprove(t *testing.T) {
+	h, repo, txID := newReviewHandler(t, transaction.StatusPending)
+	if err := h.txService.FlagForReview(context.Background(), txID, []string{"High velocity"}, decimal.NewFromFloat(0.7)); err != nil {
+		t.Fatalf("flag: %v", err)
+	}
+	reviewer := uuid.New()
+
+	steps := []struct {
+		action string
+		want   transaction.TransactionStatus
+	}{
+		{"claim", transaction.StatusReviewing},
+		{"approve", transaction.StatusApproved},
+	}
+	for _, step := range steps {
+		rec := review(h, txID, reviewer, step.action)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d, body %s", step.action, rec.Code, rec.Body)
+		}
+		var got transaction.Transaction
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatalf("%s: decode: %v", step.action, err)
+		}
+		if got.Status != step.want {
+			t.Fatalf("%s: response status %s, want %s", step.action, got.Status, step.want)
+		}
+	}
+
+	stored := repo.txs[txID]
+	if stored.ReviewedBy == nil || *stored.ReviewedBy != reviewer {
+		t.Errorf("reviewed_by = %v, want %s", stored.ReviewedBy, reviewer)
+	}
+}
+
+func TestReviewTransactionRejectsInvalidTransitions(t *testing.T) {
+	claimer := uuid.New()
+	tests := []struct {
+		name     string
+		status   transaction.TransactionStatus
+		claimant *uuid.UUID // Who claimed the transaction, for reviewing ones
+		action   string
+	}{
+		{"approve pending", transaction.StatusPending, nil, "approve"},
+		{"approve unclaimed flagged", transaction.StatusFlagged, nil, "approve"},
+		{"decline unclaimed flagged", transaction.StatusFlagged, nil, "decline"},
+		{"approve claimed by another reviewer", transaction.StatusReviewing, &claimer, "approve"},
+		{"decline claimed by another reviewer", transaction.StatusReviewing, &claimer, "decline"},
+		{"claim approved", transaction.StatusApproved, nil, "claim"},
+		{"approve declined", transaction.StatusDeclined, nil, "approve"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, repo, txID := newReviewHandler(t, tt.status)
+			if tt.claimant != nil {
+				tx := repo.txs[txID]
+				tx.ReviewedBy = tt.claimant
+				repo.txs[txID] = tx
+			}
+
+			rec := review(h, txID, uuid.New(), tt.action)
+			if rec.Code != http.StatusConflict {
+				t.Fatalf("status %d, want 409; body %s", rec.Code, rec.Body)
+			}
+			var body ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if body.Code != CodeInvalidTransition {
+				t.Errorf("code %s, want %s", body.Code, CodeInvalidTransition)
+			}
+			if got := repo.txs[txID].Status; got != tt.status {
+				t.Errorf("status changed to %s, want %s", got, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	fraudapp "fraud-detecction-system/internal/application/fraud"
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+// API versions
+const (
+	APIVersionV1 = "v1"
+	APIVersionV2 = "v2"
+)
+
+// APIVersionHeader is the response header that reports the served API version
+const APIVersionHeader = "X-API-Version"
+
+// APIVersion resolves the requested API version. A /api/v2/ path takes
+// precedence, then a version parameter on the Accept header
+// (e.g. "application/json; version=2"). Defaults to v1.
+func APIVersion(r *http.Request) string {
+	if strings.HasPrefix(r.URL.Path, "/api/v2/") {
+		return APIVersionV2
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch strings.TrimPrefix(params["version"], "v") {
+		case "2":
+			return APIVersionV2
+		case "1":
+			return APIVersionV1
+		}
+	}
+
+	return APIVersionV1
+}
+
+// Envelope wraps v2 responses with version information
+type Envelope struct {
+	APIVersion string      `json:"api_version"`
+	Data       interface{} `json:"data"`
+}
+
+// AnalyzeResponseV2 is the v2 shape of a fraud analysis result
+type AnalyzeResponseV2 struct {
+	TransactionID uuid.UUID          `json:"transaction_id"`
+	Decision      fraud.DecisionType `json:"decision"`
+	Risk          RiskV2             `json:"risk"`
+	Rules         RulesV2            `json:"rules"`
+	Actions       ActionsV2          `json:"actions"`
+	ModelVersion  string             `json:"model_version,omitempty"`
+	LatencyMs     int64              `json:"latency_ms"`
+
+	ReportCurrency *fraudapp.ReportCurrencyAmount `json:"report_currency,omitempty"`
+}
+
+// RiskV2 groups the risk assessment fields
+// Degraded says the score may be understated because some rules could not run
+type RiskV2 struct {
+	Score          decimal.Decimal `json:"score"`
+	Level          fraud.RiskLevel `json:"level"`
+	Confidence     decimal.Decimal `json:"confidence"`
+	Degraded       bool            `json:"degraded"`
+	DegradedReason string          `json:"degraded_reason,omitempty"`
+
+	ConfidenceFactors *fraud.ConfidenceFactors `json:"confidence_factors,omitempty"`
+	DataCoverage      *decimal.Decimal         `json:"data_coverage,omitempty"`
+}
+
+// RulesV2 groups the rule evaluation fields
+type RulesV2 struct {
+	Fired       []string `json:"fired"`
+	ShadowFired []string `json:"shadow_fired,omitempty"` // Shadow mode rules; they don't affect the decision
+	Reasons     []string `json:"reasons"`
+}
+
+// ActionsV2 groups the recommended actions
+type ActionsV2 struct {
+	ShouldBlock    bool `json:"should_block"`
+	RequiresReview bool `json:"requires_review"`
+}
+
+// analyzeResponse converts an analysis result to the shape for the given version
+func analyzeResponse(version string, result *fraudapp.DetectFraudOutput) interface{} {
+	if version != APIVersionV2 {
+		return result
+	}
+
+	return Envelope{
+		APIVersion: APIVersionV2,
+		Data: AnalyzeResponseV2{
+			TransactionID: result.TransactionID,
+			Decision:      result.Decision,
+			Risk: RiskV2{
+				Score:          result.Score,
+				Level:          result.RiskLevel,
+				Confidence:     result.Confidence,
+				Degraded:       result.Degraded,
+				DegradedReason: result.DegradedReason,
+
+				ConfidenceFactors: result.ConfidenceFactors,
+				DataCoverage:      result.DataCoverage,
+			},
+			Rules: RulesV2{
+				Fired:       result.RulesFired,
+				ShadowFired: result.ShadowRulesFired,
+				Reasons:     result.Reasons,
+			},
+			Actions: ActionsV2{
+				ShouldBlock:    result.ShouldBlock,
+				RequiresReview: result.RequiresReview,
+			},
+			ModelVersion:   result.ModelVersion,
+			LatencyMs:      result.LatencyMs,
+			ReportCurrency: result.ReportCurrency,
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	fraudapp "fraud-detecction-system/internal/application/fraud"
+	"fraud-detecction-system/internal/domain/fraud"
+)
+
+func TestAPIVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		accept string
+		want   string
+	}{
+		{"default", "/api/v1/fraud/analyze", "", APIVersionV1},
+		{"v2 path", "/api/v2/fraud/analyze", "", APIVersionV2},
+		{"v2 path wins over accept", "/api/v2/fraud/analyze", "application/json; version=1", APIVersionV2},
+		{"accept version 2", "/api/v1/fraud/analyze", "application/json; version=2", APIVersionV2},
+		{"accept version v2", "/api/v1/fraud/analyze", "application/json; version=v2", APIVersionV2},
+		{"accept version 1", "/api/v1/fraud/analyze", "application/json; version=1", APIVersionV1},
+		{"first versioned media type wins", "/api/v1/fraud/analyze", "text/html, application/json; version=2, application/json; version=1", APIVersionV2},
+		{"unknown version", "/api/v1/fraud/analyze", "application/json; version=3", APIVersionV1},
+		{"malformed accept", "/api/v1/fraud/analyze", ";;;", APIVersionV1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if got := APIVersion(req); got != tt.want {
+				t.Errorf("version %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeResponse(t *testing.T) {
+	result := &fraudapp.DetectFraudOutput{
+		TransactionID:  uuid.New(),
+		Decision:       fraud.DecisionReview,
+		Score:          decimal.NewFromFloat(0.65),
+		RiskLevel:      fraud.RiskLevelHigh,
+		RulesFired:     []string{"High velocity"},
+		Reasons:        []string{"5 transactions in 10 minutes"},
+		RequiresReview: true,
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		wantV2  bool
+	}{
+		{"v1 returns the output unchanged", APIVersionV1, false},
+		{"unknown version returns the output unchanged", "v9", false},
+		{"v2 wraps the output in an envelope", APIVersionV2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := analyzeResponse(tt.version, result)
+			if !tt.wantV2 {
+				if got != result {
+					t.Fatalf("response %T, want the output itself", got)
+				}
+				return
+			}
+
+			envelope, ok := got.(Envelope)
+			if !ok {
+				t.Fatalf("response %T, want Envelope", got)
+			}
+			if envelope.APIVersion != APIVersionV2 {
+				t.Errorf("envelope version %s, want %s", envelope.APIVersion, APIVersionV2)
+			}
+			data, ok := envelope.Data.(AnalyzeResponseV2)
+			if !ok {
+				t.Fatalf("envelope data %T, want AnalyzeResponseV2", envelope.Data)
+			}
+			if data.TransactionID != result.TransactionID || data.Decision != result.Decision {
+				t.Errorf("data %s %s, want %s %s", data.TransactionID, data.Decision, result.TransactionID, result.Decision)
+			}
+			if !data.Risk.Score.Equal(result.Score) || data.Risk.Level != result.RiskLevel {
+				t.Errorf("risk %s %s, want %s %s", data.Risk.Score, data.Risk.Level, result.Score, result.RiskLevel)
+			}
+			if len(data.Rules.Fired) != 1 || data.Rules.Fired[0] != "High velocity" {
+				t.Errorf("fired rules %v, want [High velocity]", data.Rules.Fired)
+			}
+			if !data.Actions.RequiresReview || data.Actions.ShouldBlock {
+				t.Errorf("actions %+v, want review only", data.Actions)
+			}
+		})
+	}
+}
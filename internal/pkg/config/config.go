@@ -16,6 +16,10 @@ type Config struct {
 	ML       MLConfig       `mapstructure:"ml"`
 	Metrics  MetricsConfig  `mapstructure:"metrics"`
 	Log      LogConfig      `mapstructure:"log"`
+	Auth     AuthConfig     `mapstructure:"auth"`
+	Webhook  WebhookConfig  `mapstructure:"webhook"`
+
+	Standalone StandaloneConfig `mapstructure:"standalone"`
 }
 
 // ServerConfig holds HTTP server configuration
@@ -38,6 +42,32 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+
+	Connect ConnectConfig `mapstructure:"connect"`
+
+	// AutoMigrate applies pending migrations from migrations/postgres at startup
+	AutoMigrate bool `mapstructure:"auto_migrate"`
+}
+
+// ConnectConfig sets how long startup waits for a dependency before running without it
+type ConnectConfig struct {
+	Attempts int           `mapstructure:"attempts"` // Including the first
+	Backoff  time.Duration `mapstructure:"backoff"`  // Wait before the first retry, doubled after each one
+	Timeout  time.Duration `mapstructure:"timeout"`  // Gives up once this passes, whatever attempts are left
+}
+
+// StandaloneConfig sets where decisions, cases and rules are kept when PostgreSQL is unavailable
+type StandaloneConfig struct {
+	// Store is "memory", which loses everything on restart, or "embedded", which
+	// keeps decisions, cases and rules in a local bbolt file
+	Store string `mapstructure:"store"`
+
+	// Path is the embedded store's file
+	Path string `mapstructure:"path"`
+
+	// MaxEntries bounds each in-memory repository; the oldest entries are evicted
+	// past it. 0 means no limit.
+	MaxEntries int `mapstructure:"max_entries"`
 }
 
 // RedisConfig holds Redis configuration
@@ -49,14 +79,30 @@ type RedisConfig struct {
 	PoolSize     int           `mapstructure:"pool_size"`
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	Connect      ConnectConfig `mapstructure:"connect"`
+
+	// How long decisions looked up by transaction ID stay cached (0 disables)
+	DecisionCacheTTL time.Duration `mapstructure:"decision_cache_ttl"`
+
+	// How long allowlist and denylist lookups stay cached (0 disables)
+	ListCacheTTL time.Duration `mapstructure:"list_cache_ttl"`
+
+	// How long user risk profiles read by live decisions stay cached (0 disables)
+	RiskProfileCacheTTL time.Duration `mapstructure:"risk_profile_cache_ttl"`
 }
 
 // KafkaConfig holds Kafka configuration
 type KafkaConfig struct {
+	Enabled           bool     `mapstructure:"enabled"` // Consume transactions and publish fraud alerts
 	Brokers           []string `mapstructure:"brokers"`
 	TransactionsTopic string   `mapstructure:"transactions_topic"`
 	FraudAlertsTopic  string   `mapstructure:"fraud_alerts_topic"`
+	DeadLetterTopic   string   `mapstructure:"dead_letter_topic"` // Undecodable or repeatedly failing transaction messages
 	ConsumerGroup     string   `mapstructure:"consumer_group"`
+
+	// Analysis attempts per message before it is dead-lettered, and the first wait between them
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
 }
 
 // FraudConfig holds fraud detection configuration
@@ -75,6 +121,11 @@ type FraudConfig struct {
 	BehavioralWeight float64 `mapstructure:"behavioral_weight"`
 	MLWeight         float64 `mapstructure:"ml_weight"`
 
+	// How rule scores are combined: max_score, weighted_average or bayesian
+	ScoringStrategy string `mapstructure:"scoring_strategy"`
+	// How same-type rules are combined under weighted_average: max or mean
+	TypeAggregation string `mapstructure:"type_aggregation"`
+
 	// Velocity limits
 	MaxTransactionsPerMinute int    `mapstructure:"max_transactions_per_minute"`
 	MaxTransactionsPerHour   int    `mapstructure:"max_transactions_per_hour"`
@@ -85,11 +136,172 @@ type FraudConfig struct {
 	BlockedCountries []string `mapstructure:"blocked_countries"`
 	MaxDistanceKm    float64  `mapstructure:"max_distance_km"`
 
+	// CSV of network,country,region,city,latitude,longitude rows used to geolocate
+	// transaction IPs; geographic rules' max_ip_distance_km check is skipped without it
+	GeoIPDatabase string `mapstructure:"geoip_database"`
+
 	// High-value thresholds
 	HighValueThreshold string `mapstructure:"high_value_threshold"` // String for YAML compatibility
 
+	// Currency settings - amount thresholds are expressed in BaseCurrency
+	BaseCurrency  string            `mapstructure:"base_currency"`
+	ExchangeRates map[string]string `mapstructure:"exchange_rates"` // Units of base currency per 1 unit, as strings for decimal parsing
+
+	// Baseline risk for transactions missing context (device, location, merchant, payment, profile)
+	ContextRiskEnabled             bool    `mapstructure:"context_risk_enabled"`
+	ContextRiskScorePerMissing     float64 `mapstructure:"context_risk_score_per_missing"`
+	ContextRiskMaxScore            float64 `mapstructure:"context_risk_max_score"`
+	ContextRiskChallengeMinMissing int     `mapstructure:"context_risk_challenge_min_missing"` // 0 disables challenge
+
+	// Block and challenge decisions below this confidence go to review instead (0 disables)
+	MinDecisionConfidence float64 `mapstructure:"min_decision_confidence"`
+
+	// How decision confidence weighs data coverage, rule agreement and context signals
+	Confidence ConfidenceConfig `mapstructure:"confidence"`
+
+	// Allow decisions below this share of rules with the data they need go to review instead (0 disables)
+	MinDataCoverage float64 `mapstructure:"min_data_coverage"`
+
 	// Analysis timeout
 	AnalysisTimeout time.Duration `mapstructure:"analysis_timeout"`
+
+	// Transactions of a batch analyzed at once
+	BatchConcurrency int `mapstructure:"batch_concurrency"`
+
+	// Pause before the single retry when active rules fail to load
+	EvaluationRetryBackoff time.Duration `mapstructure:"evaluation_retry_backoff"`
+
+	// Velocity history loaded once per analysis and shared by velocity rules (0 disables)
+	RecentHistoryWindow time.Duration `mapstructure:"recent_history_window"`
+
+	// How long velocity history is kept; raised to the longest active velocity rule window
+	VelocityRetention time.Duration `mapstructure:"velocity_retention"`
+
+	// HMAC key for signing case report downloads (reports are unsigned when empty)
+	ReportSigningKey string `mapstructure:"report_signing_key"`
+
+	// HMAC key for signing analysis decisions (decisions are unsigned when empty)
+	DecisionSigningKey string `mapstructure:"decision_signing_key"`
+
+	// Generate a transaction ID for analysis requests that omit one instead of rejecting them
+	GenerateTransactionIDs bool `mapstructure:"generate_transaction_ids"`
+
+	// Per rule type evaluation timeouts, keyed by rule type (e.g. "velocity")
+	RuleTimeouts map[string]RuleTimeoutConfig `mapstructure:"rule_timeouts"`
+
+	// Sampling of low-priority rules under heavy load
+	LoadShedding LoadSheddingConfig `mapstructure:"load_shedding"`
+
+	// Most rules evaluated per transaction, highest priority first (0 means no limit)
+	MaxRulesPerTransaction int `mapstructure:"max_rules_per_transaction"`
+
+	// Stop evaluating rules, and skip ML scoring, once a critical block rule fires
+	StopOnCriticalBlock bool `mapstructure:"stop_on_critical_block"`
+
+	// Which decisions are stored with their full score breakdown and feature vector
+	BreakdownSampling BreakdownSamplingConfig `mapstructure:"breakdown_sampling"`
+
+	// How typical merchants and trusted devices are derived from transaction history
+	UserProfile UserProfileConfig `mapstructure:"user_profile"`
+
+	// How user risk profiles weigh a user's history
+	RiskProfile RiskProfileConfig `mapstructure:"risk_profile"`
+
+	// How long open cases may wait, by risk level, before they breach their SLA
+	CaseSLA CaseSLAConfig `mapstructure:"case_sla"`
+
+	// Per-tenant overrides of the thresholds and weights above
+	Tenants []TenantFraudConfig `mapstructure:"tenants"`
+}
+
+// TenantFraudConfig overrides decision thresholds and score weights for one tenant
+// Zero values fall back to the global setting
+type TenantFraudConfig struct {
+	ID string `mapstructure:"id"`
+
+	// Settings left unset (nil) use the global value; an explicit 0 is kept
+	BlockThreshold     *float64 `mapstructure:"block_threshold"`
+	ReviewThreshold    *float64 `mapstructure:"review_threshold"`
+	ChallengeThreshold *float64 `mapstructure:"challenge_threshold"`
+
+	VelocityWeight   *float64 `mapstructure:"velocity_weight"`
+	AmountWeight     *float64 `mapstructure:"amount_weight"`
+	GeographicWeight *float64 `mapstructure:"geographic_weight"`
+	DeviceWeight     *float64 `mapstructure:"device_weight"`
+	MerchantWeight   *float64 `mapstructure:"merchant_weight"`
+	BehavioralWeight *float64 `mapstructure:"behavioral_weight"`
+	MLWeight         *float64 `mapstructure:"ml_weight"`
+}
+
+// RuleTimeoutConfig bounds evaluation time for one rule type
+type RuleTimeoutConfig struct {
+	Timeout time.Duration `mapstructure:"timeout"`
+	Policy  string        `mapstructure:"policy"` // fail_open or fail_closed
+}
+
+// LoadSheddingConfig skips low-priority rules once too many analyses run at once
+type LoadSheddingConfig struct {
+	Enabled              bool    `mapstructure:"enabled"`
+	ConcurrencyThreshold int     `mapstructure:"concurrency_threshold"` // In-flight evaluations before shedding starts
+	SampleRate           float64 `mapstructure:"sample_rate"`           // Fraction of low-priority rules still run while shedding
+}
+
+// BreakdownSamplingConfig stores the full breakdown for a sample of decisions
+// Every decision still stores its summary
+type BreakdownSamplingConfig struct {
+	SampleRate float64  `mapstructure:"sample_rate"` // Fraction of other decisions stored in full
+	AlwaysFor  []string `mapstructure:"always_for"`  // Decisions always stored in full, e.g. block, review
+}
+
+// UserProfileConfig controls user profile enrichment from transaction history
+type UserProfileConfig struct {
+	TypicalMerchantLimit int `mapstructure:"typical_merchant_limit"`  // Most frequent merchants kept (0 disables)
+	TrustedDeviceMinUses int `mapstructure:"trusted_device_min_uses"` // Earlier uses before a device is trusted (0 disables)
+}
+
+// RiskProfileConfig controls user risk profiling
+type RiskProfileConfig struct {
+	BlockedHalfLife time.Duration `mapstructure:"blocked_half_life"` // Age at which a past block counts half (0 disables decay)
+	DecisionWeight  float64       `mapstructure:"decision_weight"`   // Most a user's history adds to a live decision's score (0 disables)
+}
+
+// ConfidenceConfig weighs the factors decision confidence is calculated from
+type ConfidenceConfig struct {
+	CoverageWeight        float64       `mapstructure:"coverage_weight"`
+	AgreementWeight       float64       `mapstructure:"agreement_weight"`
+	SignalWeight          float64       `mapstructure:"signal_weight"`
+	EstablishedAccountAge time.Duration `mapstructure:"established_account_age"` // Account age from which an account counts as established
+}
+
+// CaseSLAConfig sets case SLAs by risk level and how often breaches are checked for
+type CaseSLAConfig struct {
+	Critical        time.Duration `mapstructure:"critical"` // 0 leaves the risk level untracked
+	High            time.Duration `mapstructure:"high"`
+	Medium          time.Duration `mapstructure:"medium"`
+	Low             time.Duration `mapstructure:"low"`
+	WarningFraction float64       `mapstructure:"warning_fraction"` // Share of the SLA after which a case is reported as nearing it
+	CheckInterval   time.Duration `mapstructure:"check_interval"`   // How often breached cases are alerted on (0 disables)
+}
+
+// ForTenant returns a copy of the config with the tenant's overrides applied
+func (c *FraudConfig) ForTenant(t TenantFraudConfig) FraudConfig {
+	merged := *c
+	override := func(dst *float64, v *float64) {
+		if v != nil {
+			*dst = *v
+		}
+	}
+	override(&merged.BlockThreshold, t.BlockThreshold)
+	override(&merged.ReviewThreshold, t.ReviewThreshold)
+	override(&merged.ChallengeThreshold, t.ChallengeThreshold)
+	override(&merged.VelocityWeight, t.VelocityWeight)
+	override(&merged.AmountWeight, t.AmountWeight)
+	override(&merged.GeographicWeight, t.GeographicWeight)
+	override(&merged.DeviceWeight, t.DeviceWeight)
+	override(&merged.MerchantWeight, t.MerchantWeight)
+	override(&merged.BehavioralWeight, t.BehavioralWeight)
+	override(&merged.MLWeight, t.MLWeight)
+	return merged
 }
 
 // GetMaxAmountPerDay returns the max amount per day as decimal
@@ -110,12 +322,55 @@ func (c *FraudConfig) GetHighValueThreshold() decimal.Decimal {
 	return d
 }
 
+// GetExchangeRates returns the exchange rates as decimals, skipping invalid entries
+func (c *FraudConfig) GetExchangeRates() map[string]decimal.Decimal {
+	rates := make(map[string]decimal.Decimal, len(c.ExchangeRates))
+	for currency, rate := range c.ExchangeRates {
+		d, err := decimal.NewFromString(rate)
+		if err != nil {
+			continue
+		}
+		rates[currency] = d
+	}
+	return rates
+}
+
 // MLConfig holds ML model configuration
 type MLConfig struct {
-	ModelPath      string        `mapstructure:"model_path"`
-	ModelVersion   string        `mapstructure:"model_version"`
-	FeatureCacheTTL time.Duration `mapstructure:"feature_cache_ttl"`
-	Enabled        bool          `mapstructure:"enabled"`
+	ModelPath          string        `mapstructure:"model_path"`           // ONNX model file; heuristic weights are used if missing
+	RuntimeLibraryPath string        `mapstructure:"runtime_library_path"` // onnxruntime shared library (default: onnxruntime.so)
+	ModelDir           string        `mapstructure:"model_dir"`            // Directory runtime reloads may load models from
+	ModelVersion       string        `mapstructure:"model_version"`
+	FeatureCacheTTL    time.Duration `mapstructure:"feature_cache_ttl"`
+	Enabled            bool          `mapstructure:"enabled"`
+}
+
+// AuthConfig holds API authentication configuration
+type AuthConfig struct {
+	Enabled bool           `mapstructure:"enabled"` // Require credentials on mutating endpoints
+	APIKeys []APIKeyConfig `mapstructure:"api_keys"`
+}
+
+// APIKeyConfig maps an API key to the user it authenticates as
+type APIKeyConfig struct {
+	Key    string   `mapstructure:"key"`
+	UserID string   `mapstructure:"user_id"`
+	Roles  []string `mapstructure:"roles"` // admin, rule_manager, investigator, viewer
+
+	// Tenant whose score weights and thresholds the key's analysis requests use
+	TenantID string `mapstructure:"tenant_id"`
+}
+
+// WebhookConfig holds the client webhook told about decisions and case resolutions
+type WebhookConfig struct {
+	URL          string        `mapstructure:"url"`           // Empty disables the webhook
+	Secret       string        `mapstructure:"secret"`        // HMAC-SHA256 key for signing bodies
+	Events       []string      `mapstructure:"events"`        // e.g. decision.blocked, case.resolved
+	Timeout      time.Duration `mapstructure:"timeout"`       // Per delivery attempt
+	MaxAttempts  int           `mapstructure:"max_attempts"`  // Including the first
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"` // Doubled after each retry
+	Workers      int           `mapstructure:"workers"`       // Events delivered at once
+	QueueSize    int           `mapstructure:"queue_size"`    // Events waiting for a worker; more are dead-lettered
 }
 
 // MetricsConfig holds metrics configuration
@@ -150,44 +405,105 @@ func DefaultConfig() *Config {
 			MaxOpenConns:    25,
 			MaxIdleConns:    5,
 			ConnMaxLifetime: 5 * time.Minute,
+			Connect:         defaultConnectConfig(),
+			AutoMigrate:     false,
+		},
+		Standalone: StandaloneConfig{
+			Store:      "memory",
+			Path:       "./data/fraud.db",
+			MaxEntries: 10000,
 		},
 		Redis: RedisConfig{
-			Host:         "localhost",
-			Port:         6379,
-			Password:     "",
-			DB:           0,
-			PoolSize:     10,
-			ReadTimeout:  3 * time.Second,
-			WriteTimeout: 3 * time.Second,
+			Host:                "localhost",
+			Port:                6379,
+			Password:            "",
+			DB:                  0,
+			PoolSize:            10,
+			ReadTimeout:         3 * time.Second,
+			WriteTimeout:        3 * time.Second,
+			Connect:             defaultConnectConfig(),
+			DecisionCacheTTL:    time.Minute,
+			ListCacheTTL:        time.Minute,
+			RiskProfileCacheTTL: time.Minute,
 		},
 		Kafka: KafkaConfig{
+			Enabled:           false, // HTTP-only by default
 			Brokers:           []string{"localhost:9092"},
 			TransactionsTopic: "transactions",
 			FraudAlertsTopic:  "fraud-alerts",
+			DeadLetterTopic:   "transactions-dlq",
 			ConsumerGroup:     "fraud-detection-service",
+			MaxAttempts:       5,
+			RetryBackoff:      time.Second,
 		},
 		Fraud: FraudConfig{
-			BlockThreshold:           0.80,
-			ReviewThreshold:          0.60,
-			ChallengeThreshold:       0.40,
-			VelocityWeight:           0.25,
-			AmountWeight:             0.15,
-			GeographicWeight:         0.20,
-			DeviceWeight:             0.15,
-			MerchantWeight:           0.10,
-			BehavioralWeight:         0.10,
-			MLWeight:                 0.05,
-			MaxTransactionsPerMinute: 5,
-			MaxTransactionsPerHour:   30,
-			MaxAmountPerDay:          "10000",
-			AllowedCountries:         []string{"US", "CA", "GB", "DE", "FR"},
-			BlockedCountries:         []string{},
-			MaxDistanceKm:            500,
-			HighValueThreshold:       "1000",
-			AnalysisTimeout:          5 * time.Second,
+			BlockThreshold:             0.80,
+			ReviewThreshold:            0.60,
+			ChallengeThreshold:         0.40,
+			VelocityWeight:             0.25,
+			AmountWeight:               0.15,
+			GeographicWeight:           0.20,
+			DeviceWeight:               0.15,
+			MerchantWeight:             0.10,
+			BehavioralWeight:           0.10,
+			MLWeight:                   0.05,
+			ScoringStrategy:            "max_score",
+			TypeAggregation:            "max",
+			MaxTransactionsPerMinute:   5,
+			MaxTransactionsPerHour:     30,
+			MaxAmountPerDay:            "10000",
+			AllowedCountries:           []string{"US", "CA", "GB", "DE", "FR"},
+			BlockedCountries:           []string{},
+			MaxDistanceKm:              500,
+			HighValueThreshold:         "1000",
+			BaseCurrency:               "USD",
+			ContextRiskEnabled:         true,
+			ContextRiskScorePerMissing: 0.1,
+			ContextRiskMaxScore:        0.3,
+			MinDecisionConfidence:      0,
+			MinDataCoverage:            0,
+			AnalysisTimeout:            5 * time.Second,
+			BatchConcurrency:           8,
+			EvaluationRetryBackoff:     50 * time.Millisecond,
+			RecentHistoryWindow:        24 * time.Hour,
+			VelocityRetention:          24 * time.Hour,
+			MaxRulesPerTransaction:     0,
+			StopOnCriticalBlock:        false,
+			BreakdownSampling: BreakdownSamplingConfig{
+				SampleRate: 1,
+				AlwaysFor:  []string{"block", "review"},
+			},
+			LoadShedding: LoadSheddingConfig{
+				Enabled:              false,
+				ConcurrencyThreshold: 200,
+				SampleRate:           0.5,
+			},
+			UserProfile: UserProfileConfig{
+				TypicalMerchantLimit: 5,
+				TrustedDeviceMinUses: 2,
+			},
+			Confidence: ConfidenceConfig{
+				CoverageWeight:        0.5,
+				AgreementWeight:       0.3,
+				SignalWeight:          0.2,
+				EstablishedAccountAge: 30 * 24 * time.Hour,
+			},
+			RiskProfile: RiskProfileConfig{
+				BlockedHalfLife: 0,
+				DecisionWeight:  0,
+			},
+			CaseSLA: CaseSLAConfig{
+				Critical:        2 * time.Hour,
+				High:            8 * time.Hour,
+				Medium:          24 * time.Hour,
+				Low:             72 * time.Hour,
+				WarningFraction: 0.8,
+				CheckInterval:   time.Minute,
+			},
 		},
 		ML: MLConfig{
-			ModelPath:       "./models/fraud_model.bin",
+			ModelPath:       "./models/fraud_model.onnx",
+			ModelDir:        "./models",
 			ModelVersion:    "v1.0.0",
 			FeatureCacheTTL: 5 * time.Minute,
 			Enabled:         false, // Disabled by default, rule-based works without it
@@ -200,6 +516,25 @@ func DefaultConfig() *Config {
 			Level:  "info",
 			Format: "json",
 		},
+		Auth: AuthConfig{
+			Enabled: false, // Open by default for local development
+		},
+		Webhook: WebhookConfig{
+			Events:       []string{"decision.blocked", "decision.review", "case.resolved"},
+			Timeout:      5 * time.Second,
+			MaxAttempts:  5,
+			RetryBackoff: time.Second,
+			Workers:      4,
+			QueueSize:    1000,
+		},
 	}
 }
 
+// defaultConnectConfig waits up to 30s for a dependency, retrying after 1s, 2s, 4s and 8s
+func defaultConnectConfig() ConnectConfig {
+	return ConnectConfig{
+		Attempts: 5,
+		Backoff:  time.Second,
+		Timeout:  30 * time.Second,
+	}
+}
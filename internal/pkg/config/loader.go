@@ -97,16 +97,87 @@ func setDefaults(v *viper.Viper, cfg *Config) {
 	v.SetDefault("database.user", cfg.Database.User)
 	v.SetDefault("database.name", cfg.Database.Name)
 	v.SetDefault("database.ssl_mode", cfg.Database.SSLMode)
+	v.SetDefault("database.connect.attempts", cfg.Database.Connect.Attempts)
+	v.SetDefault("database.connect.backoff", cfg.Database.Connect.Backoff)
+	v.SetDefault("database.connect.timeout", cfg.Database.Connect.Timeout)
+	v.SetDefault("database.auto_migrate", cfg.Database.AutoMigrate)
+
+	// Standalone defaults
+	v.SetDefault("standalone.store", cfg.Standalone.Store)
+	v.SetDefault("standalone.path", cfg.Standalone.Path)
+	v.SetDefault("standalone.max_entries", cfg.Standalone.MaxEntries)
 
 	// Redis defaults
 	v.SetDefault("redis.host", cfg.Redis.Host)
 	v.SetDefault("redis.port", cfg.Redis.Port)
 	v.SetDefault("redis.db", cfg.Redis.DB)
 	v.SetDefault("redis.pool_size", cfg.Redis.PoolSize)
+	v.SetDefault("redis.connect.attempts", cfg.Redis.Connect.Attempts)
+	v.SetDefault("redis.connect.backoff", cfg.Redis.Connect.Backoff)
+	v.SetDefault("redis.connect.timeout", cfg.Redis.Connect.Timeout)
+	v.SetDefault("redis.decision_cache_ttl", cfg.Redis.DecisionCacheTTL)
+	v.SetDefault("redis.list_cache_ttl", cfg.Redis.ListCacheTTL)
+	v.SetDefault("redis.risk_profile_cache_ttl", cfg.Redis.RiskProfileCacheTTL)
+
+	// Kafka defaults
+	v.SetDefault("kafka.enabled", cfg.Kafka.Enabled)
+	v.SetDefault("kafka.dead_letter_topic", cfg.Kafka.DeadLetterTopic)
+	v.SetDefault("kafka.max_attempts", cfg.Kafka.MaxAttempts)
+	v.SetDefault("kafka.retry_backoff", cfg.Kafka.RetryBackoff)
+
+	// Metrics defaults
+	v.SetDefault("metrics.enabled", cfg.Metrics.Enabled)
+	v.SetDefault("metrics.path", cfg.Metrics.Path)
+
+	// Log defaults
+	v.SetDefault("log.level", cfg.Log.Level)
+	v.SetDefault("log.format", cfg.Log.Format)
+
+	// Auth defaults
+	v.SetDefault("auth.enabled", cfg.Auth.Enabled)
+
+	// Webhook defaults
+	v.SetDefault("webhook.events", cfg.Webhook.Events)
+	v.SetDefault("webhook.timeout", cfg.Webhook.Timeout)
+	v.SetDefault("webhook.max_attempts", cfg.Webhook.MaxAttempts)
+	v.SetDefault("webhook.retry_backoff", cfg.Webhook.RetryBackoff)
+	v.SetDefault("webhook.workers", cfg.Webhook.Workers)
+	v.SetDefault("webhook.queue_size", cfg.Webhook.QueueSize)
 
 	// Fraud defaults
 	v.SetDefault("fraud.block_threshold", cfg.Fraud.BlockThreshold)
 	v.SetDefault("fraud.review_threshold", cfg.Fraud.ReviewThreshold)
 	v.SetDefault("fraud.challenge_threshold", cfg.Fraud.ChallengeThreshold)
+	v.SetDefault("fraud.scoring_strategy", cfg.Fraud.ScoringStrategy)
+	v.SetDefault("fraud.type_aggregation", cfg.Fraud.TypeAggregation)
+	v.SetDefault("fraud.base_currency", cfg.Fraud.BaseCurrency)
+	v.SetDefault("fraud.evaluation_retry_backoff", cfg.Fraud.EvaluationRetryBackoff)
+	v.SetDefault("fraud.min_decision_confidence", cfg.Fraud.MinDecisionConfidence)
+	v.SetDefault("fraud.confidence.coverage_weight", cfg.Fraud.Confidence.CoverageWeight)
+	v.SetDefault("fraud.confidence.agreement_weight", cfg.Fraud.Confidence.AgreementWeight)
+	v.SetDefault("fraud.confidence.signal_weight", cfg.Fraud.Confidence.SignalWeight)
+	v.SetDefault("fraud.confidence.established_account_age", cfg.Fraud.Confidence.EstablishedAccountAge)
+	v.SetDefault("fraud.min_data_coverage", cfg.Fraud.MinDataCoverage)
+	v.SetDefault("fraud.recent_history_window", cfg.Fraud.RecentHistoryWindow)
+	v.SetDefault("fraud.velocity_retention", cfg.Fraud.VelocityRetention)
+	v.SetDefault("fraud.batch_concurrency", cfg.Fraud.BatchConcurrency)
+	v.SetDefault("fraud.generate_transaction_ids", cfg.Fraud.GenerateTransactionIDs)
+	v.SetDefault("fraud.load_shedding.enabled", cfg.Fraud.LoadShedding.Enabled)
+	v.SetDefault("fraud.load_shedding.concurrency_threshold", cfg.Fraud.LoadShedding.ConcurrencyThreshold)
+	v.SetDefault("fraud.load_shedding.sample_rate", cfg.Fraud.LoadShedding.SampleRate)
+	v.SetDefault("fraud.max_rules_per_transaction", cfg.Fraud.MaxRulesPerTransaction)
+	v.SetDefault("fraud.stop_on_critical_block", cfg.Fraud.StopOnCriticalBlock)
+	v.SetDefault("fraud.breakdown_sampling.sample_rate", cfg.Fraud.BreakdownSampling.SampleRate)
+	v.SetDefault("fraud.breakdown_sampling.always_for", cfg.Fraud.BreakdownSampling.AlwaysFor)
+	v.SetDefault("fraud.user_profile.typical_merchant_limit", cfg.Fraud.UserProfile.TypicalMerchantLimit)
+	v.SetDefault("fraud.user_profile.trusted_device_min_uses", cfg.Fraud.UserProfile.TrustedDeviceMinUses)
+	v.SetDefault("fraud.risk_profile.blocked_half_life", cfg.Fraud.RiskProfile.BlockedHalfLife)
+	v.SetDefault("fraud.risk_profile.decision_weight", cfg.Fraud.RiskProfile.DecisionWeight)
+	v.SetDefault("fraud.case_sla.critical", cfg.Fraud.CaseSLA.Critical)
+	v.SetDefault("fraud.case_sla.high", cfg.Fraud.CaseSLA.High)
+	v.SetDefault("fraud.case_sla.medium", cfg.Fraud.CaseSLA.Medium)
+	v.SetDefault("fraud.case_sla.low", cfg.Fraud.CaseSLA.Low)
+	v.SetDefault("fraud.case_sla.warning_fraction", cfg.Fraud.CaseSLA.WarningFraction)
+	v.SetDefault("fraud.case_sla.check_interval", cfg.Fraud.CaseSLA.CheckInterval)
 }
 
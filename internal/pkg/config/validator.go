@@ -2,14 +2,47 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
+	"time"
 )
 
+// maxVelocityRetention matches the cap the velocity cache applies at runtime
+const maxVelocityRetention = 90 * 24 * time.Hour
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
 	if c.Server.Port <= 0 || c.Server.Port > 65535 {
 		return errors.New("invalid server port")
 	}
 
+	if err := validateConnect(&c.Database.Connect, "database"); err != nil {
+		return err
+	}
+	if err := validateConnect(&c.Redis.Connect, "redis"); err != nil {
+		return err
+	}
+
+	switch c.Standalone.Store {
+	case "memory":
+	case "embedded":
+		if c.Standalone.Path == "" {
+			return errors.New("standalone.path is required for the embedded store")
+		}
+	default:
+		return errors.New("standalone.store must be memory or embedded")
+	}
+	if c.Standalone.MaxEntries < 0 {
+		return errors.New("standalone.max_entries must not be negative")
+	}
+
+	if c.Kafka.MaxAttempts < 1 {
+		return errors.New("kafka.max_attempts must be at least 1")
+	}
+	if c.Kafka.RetryBackoff < 0 {
+		return errors.New("kafka.retry_backoff must not be negative")
+	}
+
 	if c.Fraud.BlockThreshold < 0 || c.Fraud.BlockThreshold > 1 {
 		return errors.New("block_threshold must be between 0 and 1")
 	}
@@ -31,6 +64,150 @@ func (c *Config) Validate() error {
 		return errors.New("review_threshold should be less than block_threshold")
 	}
 
+	if err := validateWeights(&c.Fraud, "fraud"); err != nil {
+		return err
+	}
+	for _, tenant := range c.Fraud.Tenants {
+		tenantCfg := c.Fraud.ForTenant(tenant)
+		if err := validateWeights(&tenantCfg, "tenant "+tenant.ID); err != nil {
+			return err
+		}
+	}
+
+	switch c.Fraud.ScoringStrategy {
+	case "max_score", "weighted_average", "bayesian":
+	default:
+		return errors.New("scoring_strategy must be max_score, weighted_average or bayesian")
+	}
+
+	switch c.Fraud.TypeAggregation {
+	case "max", "mean":
+	default:
+		return errors.New("type_aggregation must be max or mean")
+	}
+
+	if c.Fraud.BatchConcurrency < 1 {
+		return errors.New("batch_concurrency must be at least 1")
+	}
+
+	if c.Fraud.VelocityRetention <= 0 || c.Fraud.VelocityRetention > maxVelocityRetention {
+		return fmt.Errorf("velocity_retention must be positive and at most %s", maxVelocityRetention)
+	}
+
+	if c.Fraud.MaxRulesPerTransaction < 0 {
+		return errors.New("max_rules_per_transaction must not be negative")
+	}
+
+	if c.Fraud.BreakdownSampling.SampleRate < 0 || c.Fraud.BreakdownSampling.SampleRate > 1 {
+		return errors.New("breakdown_sampling.sample_rate must be between 0 and 1")
+	}
+	for _, decision := range c.Fraud.BreakdownSampling.AlwaysFor {
+		switch decision {
+		case "allow", "block", "review", "challenge":
+		default:
+			return errors.New("breakdown_sampling.always_for must list allow, block, review or challenge")
+		}
+	}
+
+	confidence := c.Fraud.Confidence
+	if confidence.CoverageWeight < 0 || confidence.AgreementWeight < 0 || confidence.SignalWeight < 0 {
+		return errors.New("confidence weights must not be negative")
+	}
+	if confidence.CoverageWeight+confidence.AgreementWeight+confidence.SignalWeight == 0 {
+		return errors.New("confidence weights must not all be zero")
+	}
+	if confidence.EstablishedAccountAge < 0 {
+		return errors.New("confidence.established_account_age must not be negative")
+	}
+	if c.Fraud.MinDataCoverage < 0 || c.Fraud.MinDataCoverage > 1 {
+		return errors.New("min_data_coverage must be between 0 and 1")
+	}
+
+	if c.Fraud.RiskProfile.BlockedHalfLife < 0 {
+		return errors.New("risk_profile.blocked_half_life must not be negative")
+	}
+	if c.Fraud.RiskProfile.DecisionWeight < 0 || c.Fraud.RiskProfile.DecisionWeight > 1 {
+		return errors.New("risk_profile.decision_weight must be between 0 and 1")
+	}
+
+	sla := c.Fraud.CaseSLA
+	if sla.Critical < 0 || sla.High < 0 || sla.Medium < 0 || sla.Low < 0 {
+		return errors.New("case_sla durations must not be negative")
+	}
+	if sla.WarningFraction <= 0 || sla.WarningFraction > 1 {
+		return errors.New("case_sla.warning_fraction must be greater than 0 and at most 1")
+	}
+	if sla.CheckInterval < 0 {
+		return errors.New("case_sla.check_interval must not be negative")
+	}
+
+	if c.Webhook.URL != "" {
+		if err := validateWebhook(&c.Webhook); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateConnect checks a dependency's startup retry settings
+func validateConnect(c *ConnectConfig, scope string) error {
+	if c.Attempts < 1 {
+		return fmt.Errorf("%s.connect.attempts must be at least 1", scope)
+	}
+	if c.Backoff < 0 || c.Timeout < 0 {
+		return fmt.Errorf("%s.connect backoff and timeout must not be negative", scope)
+	}
+	return nil
+}
+
+// validateWebhook checks a configured webhook can be signed and delivered to
+func validateWebhook(c *WebhookConfig) error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook.url must be an http or https URL")
+	}
+	if c.Secret == "" {
+		return errors.New("webhook.secret is required when webhook.url is set")
+	}
+	for _, event := range c.Events {
+		switch event {
+		case "decision.allowed", "decision.blocked", "decision.review", "decision.challenged", "case.resolved", "case.closed", "case.sla_breached":
+		default:
+			return fmt.Errorf("webhook.events: unknown event %q", event)
+		}
+	}
+	if c.Timeout <= 0 {
+		return errors.New("webhook.timeout must be positive")
+	}
+	if c.MaxAttempts < 1 {
+		return errors.New("webhook.max_attempts must be at least 1")
+	}
+	if c.RetryBackoff < 0 {
+		return errors.New("webhook.retry_backoff must not be negative")
+	}
+	if c.Workers < 1 {
+		return errors.New("webhook.workers must be at least 1")
+	}
+	if c.QueueSize < 1 {
+		return errors.New("webhook.queue_size must be at least 1")
+	}
 	return nil
 }
 
+// validateWeights rejects negative score weights and a set that is all zero
+// Weights that don't sum to 1 are accepted; the fraud service normalizes them
+func validateWeights(c *FraudConfig, scope string) error {
+	weights := []float64{c.VelocityWeight, c.AmountWeight, c.GeographicWeight, c.DeviceWeight, c.MerchantWeight, c.BehavioralWeight, c.MLWeight}
+	total := 0.0
+	for _, w := range weights {
+		if w < 0 {
+			return fmt.Errorf("%s: score weights must not be negative", scope)
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("%s: at least one score weight must be positive", scope)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Attribute keys shared across components so one transaction's logs can be joined
+const (
+	KeyRequestID     = "request_id"
+	KeyTransactionID = "transaction_id"
+	KeyDecisionID    = "decision_id"
+	KeyUserID        = "user_id"
+	KeyRuleID        = "rule_id"
+	KeyRuleName      = "rule_name"
+	KeyError         = "error"
+)
+
+type contextKey int
+
+const requestIDKey contextKey = iota
+
+// WithRequestID returns a copy of ctx carrying a request correlation ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromContext returns the request correlation ID, if one was set
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey).(string)
+	return id, ok && id != ""
+}
+
+// Err returns the attribute used to log an error
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// New creates a logger writing to stdout at the configured level and format
+// Levels are debug, info, warn and error; unknown levels fall back to info.
+// Format "text" writes key=value lines, anything else writes JSON.
+func New(level, format string) *slog.Logger {
+	return NewWithWriter(os.Stdout, level, format)
+}
+
+// NewWithWriter creates a logger like New that writes to w
+func NewWithWriter(w io.Writer, level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: ParseLevel(level)}
+
+	var h slog.Handler
+	if strings.EqualFold(format, "text") {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(&contextHandler{Handler: h})
+}
+
+// ParseLevel converts a configured level name to an slog level
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// contextHandler adds the request ID carried by the context to every record
+// so callers only need to pass ctx to the *Context logging methods
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		r.AddAttrs(slog.String(KeyRequestID, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import "time"
+
+// RecordDecision counts a fraud decision and observes how long it took
+func RecordDecision(decision string, latency time.Duration) {
+	decisionsTotal.WithLabelValues(decision).Inc()
+	analysisLatency.Observe(float64(latency) / float64(time.Millisecond))
+}
+
+// RecordRuleFired counts a rule firing
+func RecordRuleFired(ruleName string) {
+	ruleFiredTotal.WithLabelValues(ruleName).Inc()
+}
+
+// RecordShadowRuleFired counts a shadow mode rule that would have fired
+func RecordShadowRuleFired(ruleName string) {
+	shadowRuleFiredTotal.WithLabelValues(ruleName).Inc()
+}
+
+// RecordHTTPRequest counts a served request and observes its duration
+// route is the matched ServeMux pattern, e.g. "POST /api/v1/fraud/analyze"
+func RecordHTTPRequest(route, status string, duration time.Duration) {
+	httpRequestsTotal.WithLabelValues(route, status).Inc()
+	httpRequestDuration.WithLabelValues(route, status).Observe(float64(duration) / float64(time.Millisecond))
+}
+
+// RecordDecisionCacheLookup counts a decision cache lookup by transaction ID
+func RecordDecisionCacheLookup(hit bool) {
+	if hit {
+		decisionCacheLookups.WithLabelValues("hit").Inc()
+	} else {
+		decisionCacheLookups.WithLabelValues("miss").Inc()
+	}
+}
+
+// RecordRulesTruncated counts an evaluation that hit the per-transaction rule limit
+// and the number of rules it skipped
+func RecordRulesTruncated(skipped int) {
+	ruleLimitTruncations.Inc()
+	ruleLimitSkipped.Add(float64(skipped))
+}
+
+// SetMLEnabled reports whether ML scoring is turned on
+func SetMLEnabled(enabled bool) {
+	if enabled {
+		mlEnabled.Set(1)
+	} else {
+		mlEnabled.Set(0)
+	}
+}
+
+// SetDegradedMode reports whether the API is running with signals disabled
+func SetDegradedMode(degraded bool) {
+	if degraded {
+		degradedMode.Set(1)
+	} else {
+		degradedMode.Set(0)
+	}
+}
+
+// RecordDegradedDecision counts a decision made with rules unable to run
+func RecordDegradedDecision() {
+	degradedDecisions.Inc()
+}
+
+// SetCasesBreachingSLA reports how many open cases are past their SLA
+func SetCasesBreachingSLA(count int) {
+	casesBreachingSLA.Set(float64(count))
+}
+
+// RecordCaseSLABreach counts a case that just breached its SLA
+func RecordCaseSLABreach(riskLevel string) {
+	caseSLABreaches.WithLabelValues(riskLevel).Inc()
+}
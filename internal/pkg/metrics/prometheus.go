@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Collectors are registered with the default registry served by handler.MetricsHandler
+var (
+	decisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fraud_decisions_total",
+		Help: "Fraud decisions made, by decision.",
+	}, []string{"decision"})
+
+	analysisLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "fraud_analysis_latency_ms",
+		Help:    "Time taken to analyze a transaction, in milliseconds.",
+		Buckets: latencyBuckets,
+	})
+
+	ruleFiredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fraud_rule_fired_total",
+		Help: "Times each rule fired, by rule name.",
+	}, []string{"rule_name"})
+
+	shadowRuleFiredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fraud_shadow_rule_fired_total",
+		Help: "Times each shadow mode rule would have fired, by rule name. Not counted in fraud_rule_fired_total.",
+	}, []string{"rule_name"})
+
+	mlEnabled = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "fraud_ml_enabled",
+		Help: "1 when ML scoring is enabled, 0 otherwise.",
+	})
+
+	degradedMode = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "fraud_degraded_mode",
+		Help: "1 when the API runs with signals disabled because the database or Redis is unavailable, 0 otherwise.",
+	})
+
+	degradedDecisions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "fraud_degraded_decisions_total",
+		Help: "Decisions made while rules could not run because a dependency was unavailable.",
+	})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_ms",
+		Help:    "HTTP request duration in milliseconds, by route and status code.",
+		Buckets: latencyBuckets,
+	}, []string{"route", "status"})
+
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests served, by route and status code.",
+	}, []string{"route", "status"})
+
+	decisionCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fraud_decision_cache_lookups_total",
+		Help: "Decision lookups by transaction ID, by cache result (hit or miss).",
+	}, []string{"result"})
+
+	ruleLimitTruncations = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "fraud_rule_limit_truncations_total",
+		Help: "Evaluations where active rules exceeded the per-transaction rule limit.",
+	})
+
+	ruleLimitSkipped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "fraud_rule_limit_skipped_rules_total",
+		Help: "Rules skipped because the per-transaction rule limit was reached.",
+	})
+
+	casesBreachingSLA = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "fraud_cases_breaching_sla",
+		Help: "Open fraud cases past their SLA at the last check.",
+	})
+
+	caseSLABreaches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fraud_case_sla_breaches_total",
+		Help: "Fraud cases that breached their SLA, by risk level.",
+	}, []string{"risk_level"})
+)
+
+// latencyBuckets include the 200ms p99 target so the SLO can be read off a single bucket
+var latencyBuckets = []float64{1, 2.5, 5, 10, 25, 50, 100, 200, 250, 500, 1000}
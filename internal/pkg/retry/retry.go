@@ -0,0 +1,50 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Backoff retries an operation, doubling the wait after each failed attempt
+type Backoff struct {
+	Attempts int           // Including the first; below 1 means one attempt
+	Initial  time.Duration // Wait before the first retry
+	Timeout  time.Duration // Bounds all attempts and waits together; 0 means no bound
+}
+
+// Do runs op until it succeeds, the attempts run out or the timeout passes
+// onRetry, if set, is called with each failure that will be retried and the wait
+// before the next attempt. The last error is returned when Do gives up.
+func (b Backoff) Do(ctx context.Context, op func(ctx context.Context) error, onRetry func(attempt int, wait time.Duration, err error)) error {
+	if b.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout)
+		defer cancel()
+	}
+
+	attempts := max(b.Attempts, 1)
+	wait := b.Initial
+	for attempt := 1; ; attempt++ {
+		err := op(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("gave up after %d attempts, timed out: %w", attempt, err)
+		}
+
+		if onRetry != nil {
+			onRetry(attempt, wait, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up after %d attempts, timed out: %w", attempt, err)
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
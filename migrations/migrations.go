@@ -0,0 +1,9 @@
+package migrations
+
+import "embed"
+
+// Postgres holds the PostgreSQL migrations, applied at startup when database.auto_migrate is on
+// Files are named NNNNNN_name.up.sql and NNNNNN_name.down.sql.
+//
+//go:embed postgres/*.sql
+var Postgres embed.FS
@@ -0,0 +1,2 @@
+-- Track how many optional context fields were missing when a decision was made
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS missing_context_count INTEGER NOT NULL DEFAULT 0;
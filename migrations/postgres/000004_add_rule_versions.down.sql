@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS fraud_rule_versions;
+ALTER TABLE fraud_rules DROP COLUMN IF EXISTS updated_by;
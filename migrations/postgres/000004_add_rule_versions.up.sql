@@ -0,0 +1,32 @@
+-- Track who last changed each rule
+ALTER TABLE fraud_rules ADD COLUMN IF NOT EXISTS updated_by UUID;
+UPDATE fraud_rules SET updated_by = created_by WHERE updated_by IS NULL;
+ALTER TABLE fraud_rules ALTER COLUMN updated_by SET NOT NULL;
+
+-- Append-only rule history, one row per (rule_id, version)
+CREATE TABLE IF NOT EXISTS fraud_rule_versions (
+    rule_id UUID NOT NULL REFERENCES fraud_rules(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    type VARCHAR(20) NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    config JSONB NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    effective_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP,
+    changed_by UUID NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (rule_id, version)
+);
+
+-- Record the current state of existing rules as their first known version
+INSERT INTO fraud_rule_versions (rule_id, version, name, description, type, severity, action, config, enabled,
+                                 created_by, created_at, effective_at, expires_at, changed_by, changed_at)
+SELECT id, version, name, description, type, severity, action, config, enabled,
+       created_by, created_at, effective_at, expires_at, updated_by, updated_at
+FROM fraud_rules
+ON CONFLICT (rule_id, version) DO NOTHING;
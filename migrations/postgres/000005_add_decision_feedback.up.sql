@@ -0,0 +1,14 @@
+-- Analyst verdicts on fraud decisions, used to measure precision and recall
+CREATE TABLE IF NOT EXISTS decision_feedback (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    decision_id UUID NOT NULL REFERENCES fraud_decisions(id) ON DELETE CASCADE,
+    label VARCHAR(10) NOT NULL CHECK (label IN ('fraud', 'legit')),
+    note TEXT,
+    decision VARCHAR(20) NOT NULL,
+    decided_at TIMESTAMP NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_feedback_decision_id ON decision_feedback(decision_id);
+CREATE INDEX IF NOT EXISTS idx_decision_feedback_decided_at ON decision_feedback(decided_at);
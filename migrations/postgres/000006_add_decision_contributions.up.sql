@@ -0,0 +1,2 @@
+-- Record how much each rule contributed to a decision's score
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS contributions JSONB;
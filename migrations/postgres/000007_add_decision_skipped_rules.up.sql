@@ -0,0 +1,2 @@
+-- Record rules skipped by load shedding on each decision
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS skipped_rules JSONB;
@@ -0,0 +1,2 @@
+-- Record the original decision when low confidence downgraded it to review
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS downgraded_from VARCHAR(20);
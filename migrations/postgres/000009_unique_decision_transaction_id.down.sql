@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_fraud_decisions_transaction_id;
+CREATE INDEX IF NOT EXISTS idx_fraud_decisions_transaction_id ON fraud_decisions(transaction_id);
@@ -0,0 +1,4 @@
+-- One decision per transaction, so retried analyses reuse the first decision
+-- Any duplicate decisions already stored must be removed before this runs
+DROP INDEX IF EXISTS idx_fraud_decisions_transaction_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fraud_decisions_transaction_id ON fraud_decisions(transaction_id);
@@ -0,0 +1,2 @@
+ALTER TABLE fraud_rule_versions DROP COLUMN IF EXISTS priority;
+ALTER TABLE fraud_rules DROP COLUMN IF EXISTS priority;
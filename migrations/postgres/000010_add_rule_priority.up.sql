@@ -0,0 +1,3 @@
+-- Explicit order for the per-transaction rule limit; higher runs first
+ALTER TABLE fraud_rules ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE fraud_rule_versions ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0;
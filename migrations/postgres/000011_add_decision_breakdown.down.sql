@@ -0,0 +1,2 @@
+ALTER TABLE fraud_decisions DROP COLUMN IF EXISTS breakdown_omitted;
+ALTER TABLE fraud_decisions DROP COLUMN IF EXISTS feature_vector;
@@ -0,0 +1,3 @@
+-- Store the ML feature vector, and flag decisions whose breakdown was not sampled for storage
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS feature_vector JSONB;
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS breakdown_omitted BOOLEAN NOT NULL DEFAULT FALSE;
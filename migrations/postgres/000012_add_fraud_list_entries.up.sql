@@ -0,0 +1,13 @@
+-- Allowlist and denylist entries checked before rule evaluation
+CREATE TABLE IF NOT EXISTS fraud_list_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    list_type VARCHAR(10) NOT NULL CHECK (list_type IN ('allow', 'deny')),
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('user', 'device_id', 'card_bin', 'ip')),
+    value VARCHAR(255) NOT NULL,
+    reason TEXT,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- One entry per entity on each list; also serves the hot-path lookup by entity
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fraud_list_entries_entity ON fraud_list_entries(entity_type, value, list_type);
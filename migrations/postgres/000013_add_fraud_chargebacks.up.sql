@@ -0,0 +1,15 @@
+-- Chargebacks reported against users, counted by chargeback rules and risk profiles
+CREATE TABLE IF NOT EXISTS fraud_chargebacks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    transaction_id UUID,
+    amount DECIMAL(15,2) NOT NULL CHECK (amount >= 0),
+    currency VARCHAR(3),
+    reason_code VARCHAR(20),
+    occurred_at TIMESTAMP NOT NULL,
+    recorded_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Serves the per-user count over a window
+CREATE INDEX IF NOT EXISTS idx_fraud_chargebacks_user_occurred ON fraud_chargebacks(user_id, occurred_at);
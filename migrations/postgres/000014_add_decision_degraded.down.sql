@@ -0,0 +1,2 @@
+ALTER TABLE fraud_decisions DROP COLUMN IF EXISTS degraded_reason;
+ALTER TABLE fraud_decisions DROP COLUMN IF EXISTS degraded;
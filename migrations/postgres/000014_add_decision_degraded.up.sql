@@ -0,0 +1,3 @@
+-- Flag decisions made while rules could not run because a dependency was unavailable
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS degraded BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS degraded_reason TEXT;
@@ -0,0 +1,3 @@
+-- How a rule scores when the data it needs can't be read: open (not fired) or closed (fired)
+ALTER TABLE fraud_rules ADD COLUMN IF NOT EXISTS fail_mode VARCHAR(10) NOT NULL DEFAULT 'open';
+ALTER TABLE fraud_rule_versions ADD COLUMN IF NOT EXISTS fail_mode VARCHAR(10) NOT NULL DEFAULT 'open';
@@ -0,0 +1,2 @@
+ALTER TABLE fraud_decisions DROP COLUMN IF EXISTS base_currency;
+ALTER TABLE fraud_decisions DROP COLUMN IF EXISTS base_amount;
@@ -0,0 +1,3 @@
+-- Record the transaction amount in the base currency that amount rules compared
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS base_amount DECIMAL(15,2);
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS base_currency VARCHAR(3);
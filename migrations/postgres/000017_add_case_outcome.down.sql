@@ -0,0 +1 @@
+ALTER TABLE fraud_cases DROP COLUMN IF EXISTS outcome;
@@ -0,0 +1,2 @@
+-- Record whether resolving a case approved or declined its transactions
+ALTER TABLE fraud_cases ADD COLUMN IF NOT EXISTS outcome VARCHAR(10);
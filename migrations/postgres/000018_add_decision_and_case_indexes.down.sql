@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_fraud_cases_risk_created;
+DROP INDEX IF EXISTS idx_fraud_cases_status_created;
+DROP INDEX IF EXISTS idx_fraud_decisions_user_created;
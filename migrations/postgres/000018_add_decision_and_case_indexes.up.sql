@@ -0,0 +1,6 @@
+-- Serves a user's decision history, newest first
+CREATE INDEX IF NOT EXISTS idx_fraud_decisions_user_created ON fraud_decisions(user_id, created_at);
+
+-- Serve case listings by status, newest first, and the SLA scan of old open cases by risk level
+CREATE INDEX IF NOT EXISTS idx_fraud_cases_status_created ON fraud_cases(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_fraud_cases_risk_created ON fraud_cases(risk_level, created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_fraud_decisions_user_decision_created;
+DROP INDEX IF EXISTS idx_transactions_status_created;
+DROP INDEX IF EXISTS idx_transactions_user_created;
@@ -0,0 +1,12 @@
+-- Velocity and time-range lookups filter a user's transactions on created_at,
+-- which the single-column indexes can only serve one column at a time.
+-- Serves GetRecentByUserID, GetByTimeRange, GetFirstByUserID, the count and sum
+-- over a window, and ListByUserID's newest-first order
+CREATE INDEX IF NOT EXISTS idx_transactions_user_created ON transactions(user_id, created_at);
+
+-- Serves flagged and pending listings, which filter on status and order by created_at
+CREATE INDEX IF NOT EXISTS idx_transactions_status_created ON transactions(status, created_at);
+
+-- Covers GetBlockedCount (user_id = ? AND decision = 'block' AND created_at >= ?)
+-- so the count can be answered from the index alone
+CREATE INDEX IF NOT EXISTS idx_fraud_decisions_user_decision_created ON fraud_decisions(user_id, decision, created_at);
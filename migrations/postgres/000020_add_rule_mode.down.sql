@@ -0,0 +1,3 @@
+ALTER TABLE fraud_decisions DROP COLUMN IF EXISTS shadow_rules_fired;
+ALTER TABLE fraud_rule_versions DROP COLUMN IF EXISTS mode;
+ALTER TABLE fraud_rules DROP COLUMN IF EXISTS mode;
@@ -0,0 +1,6 @@
+-- Whether a rule counts toward decisions: active, or shadow (evaluated and recorded only)
+ALTER TABLE fraud_rules ADD COLUMN IF NOT EXISTS mode VARCHAR(10) NOT NULL DEFAULT 'active';
+ALTER TABLE fraud_rule_versions ADD COLUMN IF NOT EXISTS mode VARCHAR(10) NOT NULL DEFAULT 'active';
+
+-- Shadow rules that would have fired for the decision
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS shadow_rules_fired JSONB;
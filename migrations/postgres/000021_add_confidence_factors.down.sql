@@ -0,0 +1 @@
+ALTER TABLE fraud_decisions DROP COLUMN IF EXISTS confidence_factors;
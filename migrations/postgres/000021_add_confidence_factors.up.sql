@@ -0,0 +1,2 @@
+-- Inputs the decision's confidence was calculated from: data coverage, rule agreement and context signals
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS confidence_factors JSONB;
@@ -0,0 +1,2 @@
+-- Share of evaluated rules that had the data they need; NULL when no rules ran
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS data_coverage DECIMAL(5,4);
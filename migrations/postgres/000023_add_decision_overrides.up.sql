@@ -0,0 +1,14 @@
+-- Analyst overrides of fraud decisions; the latest one is a decision's effective outcome
+CREATE TABLE IF NOT EXISTS decision_overrides (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    decision_id UUID NOT NULL REFERENCES fraud_decisions(id) ON DELETE CASCADE,
+    transaction_id UUID NOT NULL,
+    previous_decision VARCHAR(20) NOT NULL,
+    decision VARCHAR(20) NOT NULL CHECK (decision IN ('allow', 'block', 'review')),
+    reason TEXT NOT NULL,
+    transaction_updated BOOLEAN NOT NULL DEFAULT FALSE,
+    overridden_by UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_overrides_decision_id ON decision_overrides(decision_id, created_at);
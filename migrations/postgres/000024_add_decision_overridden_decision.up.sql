@@ -0,0 +1,11 @@
+-- Latest override's outcome, so reads and block counts use the effective decision
+ALTER TABLE fraud_decisions ADD COLUMN IF NOT EXISTS overridden_decision VARCHAR(20);
+
+UPDATE fraud_decisions d
+SET overridden_decision = (
+    SELECT o.decision FROM decision_overrides o
+    WHERE o.decision_id = d.id
+    ORDER BY o.created_at DESC
+    LIMIT 1
+)
+WHERE EXISTS (SELECT 1 FROM decision_overrides o WHERE o.decision_id = d.id);
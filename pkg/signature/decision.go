@@ -0,0 +1,60 @@
+// Package signature signs and verifies fraud analysis decisions
+// It has no internal dependencies so clients outside this module can import it.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Decision holds the decision fields covered by a decision signature
+// These are the fields a downstream system acts on; reasons, rules and latency are not signed
+type Decision struct {
+	TransactionID  uuid.UUID
+	Decision       string
+	Score          decimal.Decimal
+	RiskLevel      string
+	Confidence     decimal.Decimal
+	ShouldBlock    bool
+	RequiresReview bool
+}
+
+// Canonical returns the bytes a decision signature is computed over
+// One "name=value" line per field in a fixed order. Decimals use the same
+// string form as the JSON response, so a client can rebuild this from the body.
+func (d Decision) Canonical() []byte {
+	return []byte(fmt.Sprintf(
+		"transaction_id=%s\ndecision=%s\nscore=%s\nrisk_level=%s\nconfidence=%s\nshould_block=%t\nrequires_review=%t\n",
+		d.TransactionID.String(),
+		d.Decision,
+		d.Score.String(),
+		d.RiskLevel,
+		d.Confidence.String(),
+		d.ShouldBlock,
+		d.RequiresReview,
+	))
+}
+
+// SignDecision returns the hex HMAC-SHA256 of a decision's canonical form
+func SignDecision(key []byte, d Decision) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(d.Canonical())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyDecision reports whether sig is the valid signature of d under key
+// The comparison is constant time
+func VerifyDecision(key []byte, d Decision, sig string) bool {
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(d.Canonical())
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,60 @@
+package signature
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestSignDecision(t *testing.T) {
+	key := []byte("test-key")
+	base := Decision{
+		TransactionID:  uuid.MustParse("550e8400-e29b-41d4-a716-446655440001"),
+		Decision:       "review",
+		Score:          decimal.RequireFromString("0.65"),
+		RiskLevel:      "high",
+		Confidence:     decimal.RequireFromString("0.8"),
+		RequiresReview: true,
+	}
+	sig := SignDecision(key, base)
+
+	if again := SignDecision(key, base); again != sig {
+		t.Fatalf("signature %s for an identical decision, want %s", again, sig)
+	}
+	if !VerifyDecision(key, base, sig) {
+		t.Fatal("signature does not verify for the decision it was made for")
+	}
+
+	tests := []struct {
+		name   string
+		change func(d *Decision)
+	}{
+		{"transaction ID", func(d *Decision) { d.TransactionID = uuid.New() }},
+		{"decision", func(d *Decision) { d.Decision = "allow" }},
+		{"score", func(d *Decision) { d.Score = decimal.RequireFromString("0.66") }},
+		{"risk level", func(d *Decision) { d.RiskLevel = "medium" }},
+		{"confidence", func(d *Decision) { d.Confidence = decimal.RequireFromString("0.9") }},
+		{"should block", func(d *Decision) { d.ShouldBlock = true }},
+		{"requires review", func(d *Decision) { d.RequiresReview = false }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base
+			tt.change(&changed)
+			if got := SignDecision(key, changed); got == sig {
+				t.Errorf("signature unchanged when the %s changed", tt.name)
+			}
+			if VerifyDecision(key, changed, sig) {
+				t.Errorf("original signature verifies after the %s changed", tt.name)
+			}
+		})
+	}
+
+	if VerifyDecision([]byte("other-key"), base, sig) {
+		t.Error("signature verifies under another key")
+	}
+	if VerifyDecision(key, base, "not-hex") {
+		t.Error("malformed signature verifies")
+	}
+}